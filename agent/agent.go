@@ -35,10 +35,18 @@ type Options struct {
 	HeartbeatInterval  time.Duration
 	AgentSleepInterval time.Duration
 	Cleanup            bool
+
+	// SpotInterruptionURL is the instance metadata URL of the notice EC2
+	// gives before reclaiming a spot host. If set, the agent polls it while
+	// running a task.
+	SpotInterruptionURL      string
+	SpotInterruptionInterval time.Duration
 }
 
 type taskContext struct {
 	currentCommand command.Command
+	commandStep    int
+	functionStep   int
 	logger         client.LoggerProducer
 	statsCollector *StatsCollector
 	task           client.TaskData
@@ -200,6 +208,9 @@ func (a *Agent) runTask(ctx context.Context, tc *taskContext) (err error) {
 	innerCtx, cancel = context.WithCancel(ctx)
 
 	go a.startIdleTimeoutWatch(ctx, tc, cancel)
+	if a.opts.SpotInterruptionURL != "" {
+		go a.startSpotInterruptionWatch(ctx, tc, heartbeat)
+	}
 
 	complete := make(chan string)
 	go a.startTask(innerCtx, tc, complete)
@@ -291,11 +302,14 @@ func (a *Agent) finishTask(ctx context.Context, tc *taskContext, status string)
 }
 
 func (a *Agent) endTaskResponse(tc *taskContext, status string) *apimodels.TaskEndDetail {
+	commandStep, functionStep := tc.getCurrentCommandStep()
 	return &apimodels.TaskEndDetail{
-		Description: tc.getCurrentCommand().DisplayName(),
-		Type:        tc.getCurrentCommand().Type(),
-		TimedOut:    tc.hadTimedOut(),
-		Status:      status,
+		Description:  tc.getCurrentCommand().DisplayName(),
+		Type:         tc.getCurrentCommand().Type(),
+		TimedOut:     tc.hadTimedOut(),
+		Status:       status,
+		CommandStep:  commandStep,
+		FunctionStep: functionStep,
 	}
 }
 
@@ -266,6 +266,12 @@ func (s *AgentSuite) TestEndTaskResponse() {
 	detail = s.a.endTaskResponse(s.tc, evergreen.TaskFailed)
 	s.False(detail.TimedOut)
 	s.Equal(evergreen.TaskFailed, detail.Status)
+	s.Zero(detail.CommandStep)
+
+	s.tc.setCurrentCommandStep(3, 2)
+	detail = s.a.endTaskResponse(s.tc, evergreen.TaskFailed)
+	s.Equal(3, detail.CommandStep)
+	s.Equal(2, detail.FunctionStep)
 }
 
 func (s *AgentSuite) TestAbort() {
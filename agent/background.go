@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -91,6 +92,64 @@ func (a *Agent) startIdleTimeoutWatch(ctx context.Context, tc *taskContext, canc
 	}
 }
 
+// startSpotInterruptionWatch polls for a notice that EC2 is reclaiming the
+// host. On a notice, it tells the API server, which restarts the task, and
+// stops the task without reporting it as finished.
+func (a *Agent) startSpotInterruptionWatch(ctx context.Context, tc *taskContext, heartbeat chan<- string) {
+	defer recovery.LogStackTraceAndContinue("spot interruption watcher")
+	interval := defaultSpotInterruptionInterval
+	if a.opts.SpotInterruptionInterval != 0 {
+		interval = a.opts.SpotInterruptionInterval
+	}
+
+	httpClient := &http.Client{Timeout: interval}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			grip.Info("Spot interruption watch canceled")
+			return
+		case <-ticker.C:
+			if !a.hasSpotInterruption(ctx, httpClient) {
+				continue
+			}
+
+			tc.logger.Execution().Error("Host is being reclaimed by EC2, stopping task")
+			if err := a.comm.SpotInterruption(ctx, tc.task); err != nil {
+				grip.Error(errors.Wrap(err, "problem sending spot interruption"))
+				continue
+			}
+
+			// the API server has already restarted the task
+			select {
+			case heartbeat <- evergreen.TaskConflict:
+			case <-ctx.Done():
+			}
+			return
+		}
+	}
+}
+
+// hasSpotInterruption returns true if the instance metadata service has an
+// interruption notice for the host. The metadata service returns a 404 until
+// the host is marked for interruption.
+func (a *Agent) hasSpotInterruption(ctx context.Context, httpClient *http.Client) bool {
+	req, err := http.NewRequest(http.MethodGet, a.opts.SpotInterruptionURL, nil)
+	if err != nil {
+		grip.Error(errors.Wrap(err, "problem building spot interruption request"))
+		return false
+	}
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		grip.Debug(errors.Wrap(err, "problem checking for spot interruption"))
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
 func (a *Agent) startMaxExecTimeoutWatch(ctx context.Context, tc *taskContext, d time.Duration, cancel context.CancelFunc) {
 	timer := time.NewTimer(d)
 	defer recovery.LogStackTraceAndContinue("exec timeout watcher")
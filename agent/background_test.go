@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -88,6 +90,43 @@ func (s *BackgroundSuite) TestMaxHeartbeats() {
 	s.Equal(evergreen.TaskFailed, beat)
 }
 
+func (s *BackgroundSuite) TestSpotInterruption() {
+	interrupted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !interrupted {
+			w.WriteHeader(http.StatusNotFound)
+			interrupted = true
+			return
+		}
+		_, _ = w.Write([]byte(`{"action": "terminate", "time": "2017-09-18T08:22:00Z"}`))
+	}))
+	defer server.Close()
+
+	s.a.opts.SpotInterruptionURL = server.URL
+	s.a.opts.SpotInterruptionInterval = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	heartbeat := make(chan string)
+	go s.a.startSpotInterruptionWatch(ctx, s.tc, heartbeat)
+	s.Equal(evergreen.TaskConflict, <-heartbeat)
+	s.True(s.mockCommunicator.SpotInterruptionSent)
+}
+
+func (s *BackgroundSuite) TestNoSpotInterruption() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	s.a.opts.SpotInterruptionURL = server.URL
+	s.a.opts.SpotInterruptionInterval = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	heartbeat := make(chan string)
+	s.a.startSpotInterruptionWatch(ctx, s.tc, heartbeat)
+	s.False(s.mockCommunicator.SpotInterruptionSent)
+}
+
 func (s *BackgroundSuite) TestGetCurrentTimeout() {
 	cmdFactory, exists := command.GetCommandFactory("shell.exec")
 	s.True(exists)
@@ -69,6 +69,7 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 
 			if isTaskCommands {
 				tc.setCurrentCommand(cmd)
+				tc.setCurrentCommandStep(i+1, idx+1)
 				tc.setCurrentTimeout(a.getTimeout(cmd))
 				a.comm.UpdateLastMessageTime()
 			} else {
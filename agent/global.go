@@ -35,4 +35,9 @@ const (
 	// maxHeartbeats is the number of failed heartbeats after which an agent
 	// reports an error
 	maxHeartbeats = 10
+
+	// defaultSpotInterruptionInterval is the interval at which the agent
+	// checks for a notice that its spot host is being reclaimed. EC2 gives
+	// two minutes of notice.
+	defaultSpotInterruptionInterval = 5 * time.Second
 )
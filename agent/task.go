@@ -175,6 +175,21 @@ func (tc *taskContext) setCurrentCommand(command command.Command) {
 	tc.logger.Execution().Infof("Current command set to '%s' (%s)", tc.currentCommand.DisplayName(), tc.currentCommand.Type())
 }
 
+// setCurrentCommandStep records the position of the current task command, as
+// reported in the task's end details.
+func (tc *taskContext) setCurrentCommandStep(commandStep, functionStep int) {
+	tc.Lock()
+	defer tc.Unlock()
+	tc.commandStep = commandStep
+	tc.functionStep = functionStep
+}
+
+func (tc *taskContext) getCurrentCommandStep() (int, int) {
+	tc.RLock()
+	defer tc.RUnlock()
+	return tc.commandStep, tc.functionStep
+}
+
 func (tc *taskContext) getCurrentCommand() command.Command {
 	tc.RLock()
 	defer tc.RUnlock()
@@ -12,6 +12,10 @@ type HeartbeatResponse struct {
 	Abort bool `json:"abort,omitempty"`
 }
 
+// SpotInterruptionType is the TaskEndDetail type of a task whose host was
+// reclaimed by EC2 while the task was running.
+const SpotInterruptionType = "spot_interruption"
+
 // TaskEndDetail contains data sent from the agent to the
 // API server after each task run.
 type TaskEndDetail struct {
@@ -19,6 +23,11 @@ type TaskEndDetail struct {
 	Type        string `bson:"type,omitempty" json:"type,omitempty"`
 	Description string `bson:"desc,omitempty" json:"desc,omitempty"`
 	TimedOut    bool   `bson:"timed_out,omitempty" json:"timed_out,omitempty"`
+	// CommandStep is the 1-based position of the command among the task's
+	// commands, and FunctionStep its position among the commands of the
+	// function it is in, or 1 if it is not in a function.
+	CommandStep  int `bson:"command_step,omitempty" json:"command_step,omitempty"`
+	FunctionStep int `bson:"function_step,omitempty" json:"function_step,omitempty"`
 }
 
 type TaskEndDetails struct {
@@ -36,6 +45,13 @@ type TaskEndResponse struct {
 	RunNext    bool   `json:"run_next,omitempty"`
 }
 
+// GenerateTasksRequest holds the files of project configuration generated
+// by a task, which the API server adds to the task's version.
+type GenerateTasksRequest struct {
+	Files             []string `json:"files"`
+	DependOnGenerator bool     `json:"depend_on_generator"`
+}
+
 // ExpansionVars is a map of expansion variables for a project.
 type ExpansionVars map[string]string
 
@@ -0,0 +1,525 @@
+package cloud
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/hostutil"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	azureSpotPriority    = "Spot"
+	azureEvictionPolicy  = "Delete"
+	azureDeleteOption    = "Delete"
+	azureDefaultDiskType = "StandardSSD_LRS"
+
+	// azureCleanupTimeout bounds how long terminating a host waits for its
+	// virtual machine and the resources it used to be deleted.
+	azureCleanupTimeout = 10 * time.Minute
+)
+
+var (
+	azureGalleryImageVersionRegex = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/galleries/[^/]+/images/[^/]+/versions/[^/]+$`)
+	azureImageRegex               = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/images/[^/]+$`)
+	azureSubnetRegex              = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/virtualNetworks/[^/]+/subnets/[^/]+$`)
+
+	azureDiskTypes = []string{"Standard_LRS", "StandardSSD_LRS", "StandardSSD_ZRS", "Premium_LRS", "Premium_ZRS"}
+)
+
+// azureManager implements the CloudManager interface for Microsoft Azure.
+// Each host is a Linux virtual machine with its own network interface, OS
+// disk and, optionally, public IP address, all named after the host.
+type azureManager struct {
+	client azureClient
+}
+
+// azureSettings specifies the settings used to configure a virtual machine.
+type azureSettings struct {
+	// ResourceGroup is the resource group the host's resources are created
+	// in. Location is the Azure region of the resource group e.g. eastus.
+	ResourceGroup string `mapstructure:"resource_group" json:"resource_group" bson:"resource_group"`
+	Location      string `mapstructure:"location" json:"location" bson:"location"`
+
+	// VMSize is the size of the virtual machine e.g. Standard_D2s_v3.
+	VMSize string `mapstructure:"vm_size" json:"vm_size" bson:"vm_size"`
+
+	// Exactly one of ImageID and GalleryImageVersionID must be set. ImageID
+	// is either the resource ID of a managed image or a marketplace image
+	// URN of the form publisher:offer:sku:version.
+	ImageID               string `mapstructure:"image_id" json:"image_id" bson:"image_id"`
+	GalleryImageVersionID string `mapstructure:"gallery_image_version_id" json:"gallery_image_version_id" bson:"gallery_image_version_id"`
+
+	// OSDiskType is the storage account type of the OS disk. Defaults to
+	// StandardSSD_LRS. OSDiskSizeGB defaults to the size of the image.
+	OSDiskType   string `mapstructure:"os_disk_type" json:"os_disk_type" bson:"os_disk_type"`
+	OSDiskSizeGB int    `mapstructure:"os_disk_size_gb" json:"os_disk_size_gb" bson:"os_disk_size_gb"`
+
+	// SubnetID is the resource ID of the subnet the host's network interface
+	// is placed in, and SecurityGroupID the optional network security group
+	// applied to it.
+	SubnetID              string `mapstructure:"subnet_id" json:"subnet_id" bson:"subnet_id"`
+	SecurityGroupID       string `mapstructure:"security_group_id" json:"security_group_id" bson:"security_group_id"`
+	PublicIP              bool   `mapstructure:"public_ip" json:"public_ip" bson:"public_ip"`
+	AcceleratedNetworking bool   `mapstructure:"accelerated_networking" json:"accelerated_networking" bson:"accelerated_networking"`
+
+	// Spot requests a spot virtual machine, which is deleted if Azure
+	// evicts it. MaxSpotPrice is the most to pay per hour; if unset, Azure
+	// never evicts the host for price, only for capacity.
+	Spot         bool    `mapstructure:"spot" json:"spot" bson:"spot"`
+	MaxSpotPrice float64 `mapstructure:"max_spot_price" json:"max_spot_price" bson:"max_spot_price"`
+
+	// SSHPublicKey is installed for the distro's user.
+	SSHPublicKey string `mapstructure:"ssh_public_key" json:"ssh_public_key" bson:"ssh_public_key"`
+}
+
+// Validate checks that the settings from the config file are sane.
+func (s *azureSettings) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if s.ResourceGroup == "" {
+		catcher.Add(errors.New("resource group must not be blank"))
+	}
+	if s.Location == "" {
+		catcher.Add(errors.New("location must not be blank"))
+	}
+	if s.VMSize == "" {
+		catcher.Add(errors.New("VM size must not be blank"))
+	}
+
+	if (s.ImageID == "") == (s.GalleryImageVersionID == "") {
+		catcher.Add(errors.New("exactly one of image ID or gallery image version ID must be set"))
+	} else if s.ImageID != "" && !azureImageRegex.MatchString(s.ImageID) && len(strings.Split(s.ImageID, ":")) != 4 {
+		catcher.Add(errors.Errorf("image ID '%s' is neither an image resource ID nor a publisher:offer:sku:version URN", s.ImageID))
+	} else if s.GalleryImageVersionID != "" && !azureGalleryImageVersionRegex.MatchString(s.GalleryImageVersionID) {
+		catcher.Add(errors.Errorf("gallery image version ID '%s' is not a gallery image version resource ID", s.GalleryImageVersionID))
+	}
+
+	if s.OSDiskType != "" && !util.StringSliceContains(azureDiskTypes, s.OSDiskType) {
+		catcher.Add(errors.Errorf("OS disk type '%s' must be one of %s", s.OSDiskType, strings.Join(azureDiskTypes, ", ")))
+	}
+	if s.OSDiskSizeGB < 0 {
+		catcher.Add(errors.New("OS disk size must not be negative"))
+	}
+
+	if !azureSubnetRegex.MatchString(s.SubnetID) {
+		catcher.Add(errors.Errorf("subnet ID '%s' is not a subnet resource ID", s.SubnetID))
+	}
+
+	if s.MaxSpotPrice < 0 {
+		catcher.Add(errors.New("max spot price must not be negative"))
+	}
+	if s.MaxSpotPrice > 0 && !s.Spot {
+		catcher.Add(errors.New("max spot price can only be set for spot hosts"))
+	}
+
+	if s.SSHPublicKey == "" {
+		catcher.Add(errors.New("SSH public key must not be blank"))
+	}
+
+	return catcher.Resolve()
+}
+
+// imageReference returns the image the virtual machine is created from.
+func (s *azureSettings) imageReference() azureImageReference {
+	if s.GalleryImageVersionID != "" {
+		return azureImageReference{ID: s.GalleryImageVersionID}
+	}
+	if parts := strings.Split(s.ImageID, ":"); len(parts) == 4 {
+		return azureImageReference{
+			Publisher: parts[0],
+			Offer:     parts[1],
+			SKU:       parts[2],
+			Version:   parts[3],
+		}
+	}
+	return azureImageReference{ID: s.ImageID}
+}
+
+func (s *azureSettings) getOSDiskType() string {
+	if s.OSDiskType != "" {
+		return s.OSDiskType
+	}
+	return azureDefaultDiskType
+}
+
+// Names of the resources created for a host.
+func azureNICName(h *host.Host) string      { return h.Id + "-nic" }
+func azurePublicIPName(h *host.Host) string { return h.Id + "-ip" }
+func azureDiskName(h *host.Host) string     { return h.Id + "-osdisk" }
+
+// GetSettings returns an empty azureSettings struct.
+func (*azureManager) GetSettings() ProviderSettings {
+	return &azureSettings{}
+}
+
+// GetInstanceName returns a name to be used for a virtual machine. It is also
+// the machine's host name, which may not contain underscores.
+func (*azureManager) GetInstanceName(d *distro.Distro) string {
+	return strings.Replace(generateName(d), "_", "-", -1)
+}
+
+// Configure loads the credentials for the subscription from the admin
+// settings.
+func (m *azureManager) Configure(s *evergreen.Settings) error {
+	config := s.Providers.Azure
+
+	if m.client == nil {
+		m.client = &azureClientImpl{}
+	}
+
+	if err := m.client.Init(&config); err != nil {
+		return errors.Wrap(err, "Failed to initialize client connection")
+	}
+
+	return nil
+}
+
+// getSettings decodes the provider settings of the host's distro.
+func (m *azureManager) getSettings(h *host.Host) (*azureSettings, error) {
+	settings := &azureSettings{}
+	if err := mapstructure.Decode(h.Distro.ProviderSettings, settings); err != nil {
+		return nil, errors.Wrapf(err, "Error decoding params for distro '%s'", h.Distro.Id)
+	}
+	return settings, nil
+}
+
+// SpawnHost creates a virtual machine for the host, along with its network
+// interface and, if the distro asks for one, its public IP address. The
+// resource group and location are recorded in the host's project and zone so
+// that its resources can be found even if the distro changes.
+func (m *azureManager) SpawnHost(h *host.Host) (*host.Host, error) {
+	if h.Distro.Provider != evergreen.ProviderNameAzure {
+		return nil, errors.Errorf("Can't spawn instance of %s for distro %s: provider is %s",
+			evergreen.ProviderNameAzure, h.Distro.Id, h.Distro.Provider)
+	}
+	if h.Distro.IsWindows() {
+		return nil, errors.Errorf("Can't spawn instance for distro %s: Azure Windows hosts are not supported", h.Distro.Id)
+	}
+
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return nil, err
+	}
+	if err = settings.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "Invalid Azure settings in distro '%s'", h.Distro.Id)
+	}
+
+	h.Project = settings.ResourceGroup
+	h.Zone = settings.Location
+
+	if err = m.createResources(h, settings); err != nil {
+		err = errors.Wrapf(err, "Failed to create virtual machine for host '%s'", h.Id)
+		grip.Error(err)
+		grip.Error(message.WrapError(m.deleteResources(h), message.Fields{
+			"message": "problem cleaning up after failing to create virtual machine",
+			"host":    h.Id,
+		}))
+		return nil, err
+	}
+
+	grip.Info(message.Fields{
+		"message":        "created Azure virtual machine",
+		"host":           h.Id,
+		"distro":         h.Distro.Id,
+		"resource_group": settings.ResourceGroup,
+		"location":       settings.Location,
+		"spot":           settings.Spot,
+	})
+	event.LogHostStarted(h.Id)
+
+	return h, nil
+}
+
+func (m *azureManager) createResources(h *host.Host, settings *azureSettings) error {
+	ctx := context.TODO()
+	tags := makeLabels(h)
+
+	ipConfig := azureIPConfiguration{
+		Name: "ipconfig",
+		Properties: azureIPConfigurationProperties{
+			Subnet:                    azureSubResource{ID: settings.SubnetID},
+			PrivateIPAllocationMethod: "Dynamic",
+		},
+	}
+	if settings.PublicIP {
+		ip, err := m.client.CreatePublicIP(ctx, settings.ResourceGroup, &azurePublicIP{
+			Name:     azurePublicIPName(h),
+			Location: settings.Location,
+			Tags:     tags,
+			SKU:      &azureSKU{Name: "Standard"},
+			Properties: azurePublicIPProperties{
+				PublicIPAllocationMethod: "Static",
+			},
+		})
+		if err != nil {
+			return err
+		}
+		ipConfig.Properties.PublicIPAddress = &azureSubResource{ID: ip.ID}
+	}
+
+	nic := &azureNIC{
+		Name:     azureNICName(h),
+		Location: settings.Location,
+		Tags:     tags,
+		Properties: azureNICProperties{
+			IPConfigurations:            []azureIPConfiguration{ipConfig},
+			EnableAcceleratedNetworking: settings.AcceleratedNetworking,
+		},
+	}
+	if settings.SecurityGroupID != "" {
+		nic.Properties.NetworkSecurityGroup = &azureSubResource{ID: settings.SecurityGroupID}
+	}
+	nic, err := m.client.CreateNIC(ctx, settings.ResourceGroup, nic)
+	if err != nil {
+		return err
+	}
+
+	vm := &azureVM{
+		Name:     h.Id,
+		Location: settings.Location,
+		Tags:     tags,
+		Properties: azureVMProperties{
+			HardwareProfile: azureHardwareProfile{VMSize: settings.VMSize},
+			StorageProfile: azureStorageProfile{
+				ImageReference: settings.imageReference(),
+				OSDisk: azureOSDisk{
+					Name:         azureDiskName(h),
+					CreateOption: "FromImage",
+					DeleteOption: azureDeleteOption,
+					DiskSizeGB:   settings.OSDiskSizeGB,
+					ManagedDisk:  &azureManagedDisk{StorageAccountType: settings.getOSDiskType()},
+				},
+			},
+			OSProfile: azureOSProfile{
+				ComputerName:  h.Id,
+				AdminUsername: h.Distro.User,
+				LinuxConfiguration: &azureLinuxConfiguration{
+					DisablePasswordAuthentication: true,
+					SSH: azureSSHConfiguration{PublicKeys: []azureSSHPublicKey{{
+						Path:    fmt.Sprintf("/home/%s/.ssh/authorized_keys", h.Distro.User),
+						KeyData: settings.SSHPublicKey,
+					}}},
+				},
+			},
+			NetworkProfile: azureNetworkProfile{NetworkInterfaces: []azureNICReference{{
+				ID: nic.ID,
+				Properties: azureNICReferenceProperties{
+					Primary:      true,
+					DeleteOption: azureDeleteOption,
+				},
+			}}},
+		},
+	}
+	if h.BootstrapScript != "" {
+		vm.Properties.OSProfile.CustomData = base64.StdEncoding.EncodeToString([]byte(h.BootstrapScript))
+	}
+	if settings.Spot {
+		// Azure uses a max price of -1 to mean the pay-as-you-go price.
+		maxPrice := float64(-1)
+		if settings.MaxSpotPrice > 0 {
+			maxPrice = settings.MaxSpotPrice
+		}
+		vm.Properties.Priority = azureSpotPriority
+		vm.Properties.EvictionPolicy = azureEvictionPolicy
+		vm.Properties.BillingProfile = &azureBillingProfile{MaxPrice: maxPrice}
+	}
+
+	_, err = m.client.CreateVM(ctx, settings.ResourceGroup, vm)
+	return err
+}
+
+// deleteResources deletes the host's virtual machine and then the network
+// interface, public IP address and OS disk it used. Resources that do not
+// exist are skipped, since a failed spawn may not have created them and
+// Azure deletes some of them along with the virtual machine.
+func (m *azureManager) deleteResources(h *host.Host) error {
+	ctx, cancel := context.WithTimeout(context.TODO(), azureCleanupTimeout)
+	defer cancel()
+	group := h.Project
+
+	if err := m.client.DeleteVM(ctx, group, h.Id); err != nil && err != errAzureNotFound {
+		return errors.Wrapf(err, "API call to delete virtual machine failed")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	if err := m.client.DeleteNIC(ctx, group, azureNICName(h)); err != nil && err != errAzureNotFound {
+		catcher.Add(errors.Wrap(err, "API call to delete network interface failed"))
+	} else if err = m.client.DeletePublicIP(ctx, group, azurePublicIPName(h)); err != nil && err != errAzureNotFound {
+		// the public IP can only be deleted once the interface using it is
+		catcher.Add(errors.Wrap(err, "API call to delete public IP failed"))
+	}
+	if err := m.client.DeleteDisk(ctx, group, azureDiskName(h)); err != nil && err != errAzureNotFound {
+		catcher.Add(errors.Wrap(err, "API call to delete disk failed"))
+	}
+
+	return catcher.Resolve()
+}
+
+// GetInstanceStatus returns a universal status code representing the state
+// of the host's virtual machine. If the machine no longer exists, for
+// instance because Azure evicted a spot host, the resources it left behind
+// are deleted.
+func (m *azureManager) GetInstanceStatus(h *host.Host) (CloudStatus, error) {
+	view, err := m.client.GetVMInstanceView(context.TODO(), h.Project, h.Id)
+	if err == errAzureNotFound {
+		grip.Error(message.WrapError(m.deleteResources(h), message.Fields{
+			"message": "problem cleaning up resources of deleted virtual machine",
+			"host":    h.Id,
+		}))
+		return StatusTerminated, nil
+	}
+	if err != nil {
+		return StatusUnknown, errors.Wrapf(err, "Failed to get virtual machine information for host '%s'", h.Id)
+	}
+
+	return azureToEvgStatus(view), nil
+}
+
+// azureToEvgStatus converts the provisioning and power states of a virtual
+// machine to an Evergreen cloud status. A failed provisioning takes
+// precedence over the power state.
+func azureToEvgStatus(view *azureInstanceView) CloudStatus {
+	provisioning, power := "", ""
+	for _, status := range view.Statuses {
+		// failed provisioning states have the reason appended e.g.
+		// ProvisioningState/failed/AllocationFailed
+		parts := strings.Split(status.Code, "/")
+		if len(parts) < 2 {
+			continue
+		}
+		switch parts[0] {
+		case "ProvisioningState":
+			provisioning = parts[1]
+		case "PowerState":
+			power = parts[1]
+		}
+	}
+
+	switch provisioning {
+	case "failed":
+		return StatusFailed
+	case "deleting":
+		return StatusTerminated
+	}
+
+	switch power {
+	case "starting":
+		return StatusInitializing
+	case "running":
+		return StatusRunning
+	case "stopping", "stopped", "deallocating", "deallocated":
+		return StatusStopped
+	case "":
+		if provisioning == "creating" {
+			return StatusInitializing
+		}
+		return StatusUnknown
+	default:
+		return StatusUnknown
+	}
+}
+
+// GetDNSName returns the public IP address of the host if it has one, and
+// its private IP address otherwise.
+func (m *azureManager) GetDNSName(h *host.Host) (string, error) {
+	ctx := context.TODO()
+	nic, err := m.client.GetNIC(ctx, h.Project, azureNICName(h))
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get network interface for host '%s'", h.Id)
+	}
+	if len(nic.Properties.IPConfigurations) == 0 {
+		return "", errors.Errorf("network interface of host '%s' has no IP configurations", h.Id)
+	}
+
+	ipConfig := nic.Properties.IPConfigurations[0].Properties
+	if ipConfig.PublicIPAddress == nil {
+		return ipConfig.PrivateIPAddress, nil
+	}
+
+	ip, err := m.client.GetPublicIP(ctx, h.Project, azurePublicIPName(h))
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get public IP for host '%s'", h.Id)
+	}
+	return ip.Properties.IPAddress, nil
+}
+
+// CanSpawn returns if a given cloud provider supports spawning a new host
+// dynamically. Always returns true for Azure.
+func (m *azureManager) CanSpawn() (bool, error) {
+	return true, nil
+}
+
+// TerminateInstance deletes the host's virtual machine and its network
+// interface, public IP address and OS disk.
+func (m *azureManager) TerminateInstance(h *host.Host, user string) error {
+	if h.Status == evergreen.HostTerminated {
+		err := errors.Errorf("Can not terminate %s - already marked as terminated!", h.Id)
+		grip.Error(err)
+		return err
+	}
+
+	if err := m.deleteResources(h); err != nil {
+		return errors.Wrapf(err, "Failed to delete resources of host '%s'", h.Id)
+	}
+
+	grip.Info(message.Fields{
+		"message":        "terminated Azure virtual machine",
+		"host":           h.Id,
+		"resource_group": h.Project,
+	})
+
+	return h.Terminate(user)
+}
+
+// IsUp returns true if the host's virtual machine is running.
+func (m *azureManager) IsUp(h *host.Host) (bool, error) {
+	status, err := m.GetInstanceStatus(h)
+	if err != nil {
+		return false, err
+	}
+	return status == StatusRunning, nil
+}
+
+// OnUp does nothing since tags are attached when the host is created.
+func (m *azureManager) OnUp(_ *host.Host) error {
+	return nil
+}
+
+// IsSSHReachable returns true if the host can successfully accept and run an
+// SSH command.
+func (m *azureManager) IsSSHReachable(h *host.Host, keyPath string) (bool, error) {
+	opts, err := m.GetSSHOptions(h, keyPath)
+	if err != nil {
+		return false, err
+	}
+
+	return hostutil.CheckSSHResponse(context.TODO(), h, opts)
+}
+
+// GetSSHOptions generates the command line args to be passed to SSH to allow
+// connection to the machine.
+func (m *azureManager) GetSSHOptions(h *host.Host, keyPath string) ([]string, error) {
+	if keyPath == "" {
+		return []string{}, errors.New("No key specified for host")
+	}
+
+	opts := []string{"-i", keyPath}
+	for _, opt := range h.Distro.SSHOptions {
+		opts = append(opts, "-o", opt)
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,515 @@
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	azureManagementURL = "https://management.azure.com"
+	azureLoginURL      = "https://login.microsoftonline.com"
+	azurePricesURL     = "https://prices.azure.com/api/retail/prices"
+
+	azureComputeAPIVersion = "2021-07-01"
+	azureDiskAPIVersion    = "2021-04-01"
+	azureNetworkAPIVersion = "2021-02-01"
+
+	azureRequestTimeout = 30 * time.Second
+	azurePollInterval   = 5 * time.Second
+
+	// azurePricePages limits how many pages of the retail prices API are read
+	// for one VM size.
+	azurePricePages = 5
+)
+
+// azureVM is the subset of the Azure Resource Manager virtual machine
+// resource that the manager reads and writes.
+type azureVM struct {
+	ID         string            `json:"id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties azureVMProperties `json:"properties"`
+}
+
+type azureVMProperties struct {
+	HardwareProfile   azureHardwareProfile `json:"hardwareProfile"`
+	StorageProfile    azureStorageProfile  `json:"storageProfile"`
+	OSProfile         azureOSProfile       `json:"osProfile"`
+	NetworkProfile    azureNetworkProfile  `json:"networkProfile"`
+	Priority          string               `json:"priority,omitempty"`
+	EvictionPolicy    string               `json:"evictionPolicy,omitempty"`
+	BillingProfile    *azureBillingProfile `json:"billingProfile,omitempty"`
+	ProvisioningState string               `json:"provisioningState,omitempty"`
+}
+
+type azureHardwareProfile struct {
+	VMSize string `json:"vmSize"`
+}
+
+type azureStorageProfile struct {
+	ImageReference azureImageReference `json:"imageReference"`
+	OSDisk         azureOSDisk         `json:"osDisk"`
+}
+
+// azureImageReference refers either to an image resource, such as a managed
+// image or a gallery image version, by ID, or to a marketplace image.
+type azureImageReference struct {
+	ID        string `json:"id,omitempty"`
+	Publisher string `json:"publisher,omitempty"`
+	Offer     string `json:"offer,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+type azureOSDisk struct {
+	Name         string            `json:"name"`
+	CreateOption string            `json:"createOption"`
+	DeleteOption string            `json:"deleteOption,omitempty"`
+	DiskSizeGB   int               `json:"diskSizeGB,omitempty"`
+	ManagedDisk  *azureManagedDisk `json:"managedDisk,omitempty"`
+}
+
+type azureManagedDisk struct {
+	StorageAccountType string `json:"storageAccountType"`
+}
+
+type azureOSProfile struct {
+	ComputerName       string                   `json:"computerName"`
+	AdminUsername      string                   `json:"adminUsername"`
+	CustomData         string                   `json:"customData,omitempty"`
+	LinuxConfiguration *azureLinuxConfiguration `json:"linuxConfiguration,omitempty"`
+}
+
+type azureLinuxConfiguration struct {
+	DisablePasswordAuthentication bool                  `json:"disablePasswordAuthentication"`
+	SSH                           azureSSHConfiguration `json:"ssh"`
+}
+
+type azureSSHConfiguration struct {
+	PublicKeys []azureSSHPublicKey `json:"publicKeys"`
+}
+
+type azureSSHPublicKey struct {
+	Path    string `json:"path"`
+	KeyData string `json:"keyData"`
+}
+
+type azureNetworkProfile struct {
+	NetworkInterfaces []azureNICReference `json:"networkInterfaces"`
+}
+
+type azureNICReference struct {
+	ID         string                      `json:"id"`
+	Properties azureNICReferenceProperties `json:"properties"`
+}
+
+type azureNICReferenceProperties struct {
+	Primary      bool   `json:"primary"`
+	DeleteOption string `json:"deleteOption,omitempty"`
+}
+
+type azureBillingProfile struct {
+	MaxPrice float64 `json:"maxPrice"`
+}
+
+type azureSubResource struct {
+	ID string `json:"id"`
+}
+
+// azureInstanceView holds the run-time status of a virtual machine. Its
+// statuses have codes such as "ProvisioningState/succeeded" and
+// "PowerState/running".
+type azureInstanceView struct {
+	Statuses []azureInstanceViewStatus `json:"statuses"`
+}
+
+type azureInstanceViewStatus struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// azureNIC is the subset of the network interface resource that the manager
+// reads and writes.
+type azureNIC struct {
+	ID         string             `json:"id,omitempty"`
+	Name       string             `json:"name,omitempty"`
+	Location   string             `json:"location"`
+	Tags       map[string]string  `json:"tags,omitempty"`
+	Properties azureNICProperties `json:"properties"`
+}
+
+type azureNICProperties struct {
+	IPConfigurations            []azureIPConfiguration `json:"ipConfigurations"`
+	NetworkSecurityGroup        *azureSubResource      `json:"networkSecurityGroup,omitempty"`
+	EnableAcceleratedNetworking bool                   `json:"enableAcceleratedNetworking,omitempty"`
+}
+
+type azureIPConfiguration struct {
+	Name       string                         `json:"name"`
+	Properties azureIPConfigurationProperties `json:"properties"`
+}
+
+type azureIPConfigurationProperties struct {
+	Subnet                    azureSubResource  `json:"subnet"`
+	PrivateIPAddress          string            `json:"privateIPAddress,omitempty"`
+	PrivateIPAllocationMethod string            `json:"privateIPAllocationMethod,omitempty"`
+	PublicIPAddress           *azureSubResource `json:"publicIPAddress,omitempty"`
+}
+
+// azurePublicIP is the subset of the public IP address resource that the
+// manager reads and writes.
+type azurePublicIP struct {
+	ID         string                  `json:"id,omitempty"`
+	Name       string                  `json:"name,omitempty"`
+	Location   string                  `json:"location"`
+	Tags       map[string]string       `json:"tags,omitempty"`
+	SKU        *azureSKU               `json:"sku,omitempty"`
+	Properties azurePublicIPProperties `json:"properties"`
+}
+
+type azureSKU struct {
+	Name string `json:"name"`
+}
+
+type azurePublicIPProperties struct {
+	PublicIPAllocationMethod string `json:"publicIPAllocationMethod,omitempty"`
+	IPAddress                string `json:"ipAddress,omitempty"`
+}
+
+// azureOperation is the status of an asynchronous Azure operation.
+type azureOperation struct {
+	Status string `json:"status"`
+	Error  struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// azurePriceItem is one price from the Azure retail prices API.
+type azurePriceItem struct {
+	RetailPrice   float64 `json:"retailPrice"`
+	UnitOfMeasure string  `json:"unitOfMeasure"`
+	SKUName       string  `json:"skuName"`
+	ProductName   string  `json:"productName"`
+	Type          string  `json:"type"`
+}
+
+type azurePriceResponse struct {
+	Items        []azurePriceItem `json:"Items"`
+	NextPageLink string           `json:"NextPageLink"`
+}
+
+// errAzureNotFound is returned by the client when the resource does not
+// exist.
+var errAzureNotFound = errors.New("resource not found")
+
+// The azureClient interface wraps interaction with the Azure Resource Manager
+// API. Resources are referred to by their resource group and name.
+type azureClient interface {
+	Init(*evergreen.AzureConfig) error
+
+	CreatePublicIP(ctx context.Context, group string, ip *azurePublicIP) (*azurePublicIP, error)
+	GetPublicIP(ctx context.Context, group, name string) (*azurePublicIP, error)
+	DeletePublicIP(ctx context.Context, group, name string) error
+
+	CreateNIC(ctx context.Context, group string, nic *azureNIC) (*azureNIC, error)
+	GetNIC(ctx context.Context, group, name string) (*azureNIC, error)
+	DeleteNIC(ctx context.Context, group, name string) error
+
+	CreateVM(ctx context.Context, group string, vm *azureVM) (*azureVM, error)
+	GetVMInstanceView(ctx context.Context, group, name string) (*azureInstanceView, error)
+	DeleteVM(ctx context.Context, group, name string) error
+
+	DeleteDisk(ctx context.Context, group, name string) error
+
+	// GetHourlyPrice returns the Linux pay-as-you-go price of the VM size in
+	// the location, or its spot price if spot is true.
+	GetHourlyPrice(ctx context.Context, location, size string, spot bool) (float64, error)
+}
+
+type azureClientImpl struct {
+	subscription string
+	httpClient   *http.Client
+
+	prices map[string]float64
+	sync.Mutex
+}
+
+// Init checks the configuration and sets up an HTTP client that
+// authenticates to Azure as the configured service principal.
+func (c *azureClientImpl) Init(config *evergreen.AzureConfig) error {
+	if config.SubscriptionID == "" || config.TenantID == "" || config.ClientID == "" || config.ClientSecret == "" {
+		return errors.New("Azure subscription, tenant, client ID and client secret must be configured")
+	}
+	c.subscription = config.SubscriptionID
+
+	credentials := &clientcredentials.Config{
+		ClientID:       config.ClientID,
+		ClientSecret:   config.ClientSecret,
+		TokenURL:       fmt.Sprintf("%s/%s/oauth2/token", azureLoginURL, config.TenantID),
+		EndpointParams: url.Values{"resource": []string{azureManagementURL + "/"}},
+	}
+	c.httpClient = credentials.Client(context.Background())
+	c.httpClient.Timeout = azureRequestTimeout
+
+	return nil
+}
+
+func (c *azureClientImpl) resourceURL(group, resourceType, name, apiVersion string) string {
+	return fmt.Sprintf("%s/subscriptions/%s/resourceGroups/%s/providers/%s/%s?api-version=%s",
+		azureManagementURL, c.subscription, group, resourceType, name, apiVersion)
+}
+
+// do sends a request to Azure and decodes the response into out, if out is
+// non-nil. It returns the status code and headers of the response, which
+// refer to the operation's progress if it finishes asynchronously.
+func (c *azureClientImpl) do(ctx context.Context, method, url string, body interface{}, out interface{}) (int, http.Header, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return 0, nil, errors.Wrap(err, "problem marshalling request body")
+		}
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "problem building request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(evergreen.ContentTypeHeader, evergreen.ContentTypeValue)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "problem sending %s request to %s", method, url)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, resp.Header, errors.Wrap(err, "problem reading response body")
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return resp.StatusCode, resp.Header, errAzureNotFound
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.StatusCode, resp.Header, errors.Errorf("%s %s returned %d: %s", method, url, resp.StatusCode, string(respBody))
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return resp.StatusCode, resp.Header, nil
+	}
+	return resp.StatusCode, resp.Header, errors.Wrap(json.Unmarshal(respBody, out), "problem decoding response")
+}
+
+// delete deletes the resource and waits for the deletion to finish, since a
+// resource cannot be deleted while another still refers to it.
+func (c *azureClientImpl) delete(ctx context.Context, url string) error {
+	_, header, err := c.do(ctx, http.MethodDelete, url, nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.wait(ctx, header)
+}
+
+// wait polls an asynchronous operation until it finishes. Azure reports
+// progress either through the Azure-AsyncOperation header, which refers to
+// the operation's status, or through the Location header, which returns 202
+// until the operation is done.
+func (c *azureClientImpl) wait(ctx context.Context, header http.Header) error {
+	asyncURL := header.Get("Azure-AsyncOperation")
+	locationURL := header.Get("Location")
+	if asyncURL == "" && locationURL == "" {
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "operation did not finish")
+		case <-time.After(azurePollInterval):
+		}
+
+		if asyncURL != "" {
+			op := &azureOperation{}
+			if _, _, err := c.do(ctx, http.MethodGet, asyncURL, nil, op); err != nil {
+				return errors.Wrap(err, "problem checking operation status")
+			}
+			switch op.Status {
+			case "Succeeded":
+				return nil
+			case "Failed", "Canceled":
+				return errors.Errorf("operation %s: %s", strings.ToLower(op.Status), op.Error.Message)
+			}
+			continue
+		}
+
+		status, _, err := c.do(ctx, http.MethodGet, locationURL, nil, nil)
+		if err != nil {
+			return errors.Wrap(err, "problem checking operation status")
+		}
+		if status != http.StatusAccepted {
+			return nil
+		}
+	}
+}
+
+func (c *azureClientImpl) CreatePublicIP(ctx context.Context, group string, ip *azurePublicIP) (*azurePublicIP, error) {
+	out := &azurePublicIP{}
+	url := c.resourceURL(group, "Microsoft.Network/publicIPAddresses", ip.Name, azureNetworkAPIVersion)
+	if _, _, err := c.do(ctx, http.MethodPut, url, ip, out); err != nil {
+		return nil, errors.Wrapf(err, "problem creating public IP '%s'", ip.Name)
+	}
+	return out, nil
+}
+
+func (c *azureClientImpl) GetPublicIP(ctx context.Context, group, name string) (*azurePublicIP, error) {
+	out := &azurePublicIP{}
+	url := c.resourceURL(group, "Microsoft.Network/publicIPAddresses", name, azureNetworkAPIVersion)
+	if _, _, err := c.do(ctx, http.MethodGet, url, nil, out); err != nil {
+		if err == errAzureNotFound {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "problem getting public IP '%s'", name)
+	}
+	return out, nil
+}
+
+func (c *azureClientImpl) DeletePublicIP(ctx context.Context, group, name string) error {
+	err := c.delete(ctx, c.resourceURL(group, "Microsoft.Network/publicIPAddresses", name, azureNetworkAPIVersion))
+	if err == errAzureNotFound {
+		return err
+	}
+	return errors.Wrapf(err, "problem deleting public IP '%s'", name)
+}
+
+func (c *azureClientImpl) CreateNIC(ctx context.Context, group string, nic *azureNIC) (*azureNIC, error) {
+	out := &azureNIC{}
+	url := c.resourceURL(group, "Microsoft.Network/networkInterfaces", nic.Name, azureNetworkAPIVersion)
+	if _, _, err := c.do(ctx, http.MethodPut, url, nic, out); err != nil {
+		return nil, errors.Wrapf(err, "problem creating network interface '%s'", nic.Name)
+	}
+	return out, nil
+}
+
+func (c *azureClientImpl) GetNIC(ctx context.Context, group, name string) (*azureNIC, error) {
+	out := &azureNIC{}
+	url := c.resourceURL(group, "Microsoft.Network/networkInterfaces", name, azureNetworkAPIVersion)
+	if _, _, err := c.do(ctx, http.MethodGet, url, nil, out); err != nil {
+		if err == errAzureNotFound {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "problem getting network interface '%s'", name)
+	}
+	return out, nil
+}
+
+func (c *azureClientImpl) DeleteNIC(ctx context.Context, group, name string) error {
+	err := c.delete(ctx, c.resourceURL(group, "Microsoft.Network/networkInterfaces", name, azureNetworkAPIVersion))
+	if err == errAzureNotFound {
+		return err
+	}
+	return errors.Wrapf(err, "problem deleting network interface '%s'", name)
+}
+
+func (c *azureClientImpl) CreateVM(ctx context.Context, group string, vm *azureVM) (*azureVM, error) {
+	out := &azureVM{}
+	url := c.resourceURL(group, "Microsoft.Compute/virtualMachines", vm.Name, azureComputeAPIVersion)
+	if _, _, err := c.do(ctx, http.MethodPut, url, vm, out); err != nil {
+		return nil, errors.Wrapf(err, "problem creating virtual machine '%s'", vm.Name)
+	}
+	return out, nil
+}
+
+func (c *azureClientImpl) GetVMInstanceView(ctx context.Context, group, name string) (*azureInstanceView, error) {
+	out := &azureInstanceView{}
+	url := c.resourceURL(group, "Microsoft.Compute/virtualMachines", name+"/instanceView", azureComputeAPIVersion)
+	if _, _, err := c.do(ctx, http.MethodGet, url, nil, out); err != nil {
+		if err == errAzureNotFound {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "problem getting instance view of virtual machine '%s'", name)
+	}
+	return out, nil
+}
+
+func (c *azureClientImpl) DeleteVM(ctx context.Context, group, name string) error {
+	err := c.delete(ctx, c.resourceURL(group, "Microsoft.Compute/virtualMachines", name, azureComputeAPIVersion))
+	if err == errAzureNotFound {
+		return err
+	}
+	return errors.Wrapf(err, "problem deleting virtual machine '%s'", name)
+}
+
+func (c *azureClientImpl) DeleteDisk(ctx context.Context, group, name string) error {
+	err := c.delete(ctx, c.resourceURL(group, "Microsoft.Compute/disks", name, azureDiskAPIVersion))
+	if err == errAzureNotFound {
+		return err
+	}
+	return errors.Wrapf(err, "problem deleting disk '%s'", name)
+}
+
+// GetHourlyPrice looks up the price in the Azure retail prices API, which
+// does not require authentication. Prices are cached for the life of the
+// client.
+func (c *azureClientImpl) GetHourlyPrice(ctx context.Context, location, size string, spot bool) (float64, error) {
+	key := fmt.Sprintf("%s/%s/%t", location, size, spot)
+
+	c.Lock()
+	defer c.Unlock()
+	if price, ok := c.prices[key]; ok {
+		return price, nil
+	}
+
+	filter := fmt.Sprintf("serviceName eq 'Virtual Machines' and priceType eq 'Consumption' and armRegionName eq '%s' and armSkuName eq '%s'",
+		location, size)
+	next := azurePricesURL + "?" + url.Values{"$filter": []string{filter}}.Encode()
+	client := &http.Client{Timeout: azureRequestTimeout}
+	items := []azurePriceItem{}
+	for page := 0; next != "" && page < azurePricePages; page++ {
+		req, err := http.NewRequest(http.MethodGet, next, nil)
+		if err != nil {
+			return 0, errors.Wrap(err, "problem building price request")
+		}
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return 0, errors.Wrap(err, "problem fetching Azure prices")
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return 0, errors.Errorf("fetching Azure prices returned %d", resp.StatusCode)
+		}
+		prices := &azurePriceResponse{}
+		err = json.NewDecoder(resp.Body).Decode(prices)
+		resp.Body.Close()
+		if err != nil {
+			return 0, errors.Wrap(err, "problem decoding Azure prices")
+		}
+		items = append(items, prices.Items...)
+		next = prices.NextPageLink
+	}
+
+	price, err := azureHourlyPrice(items, spot)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem finding price of %s in %s", size, location)
+	}
+	if c.prices == nil {
+		c.prices = map[string]float64{}
+	}
+	c.prices[key] = price
+	return price, nil
+}
@@ -0,0 +1,138 @@
+package cloud
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// azureClientMock is a fake client which keeps resources in memory.
+type azureClientMock struct {
+	// API call options
+	failInit     bool
+	failCreateVM bool
+	failGet      bool
+	failDelete   bool
+	failPrice    bool
+
+	// Other options
+	statuses  []string
+	privateIP string
+	publicIP  string
+	price     float64
+
+	// Recorded state
+	vms        map[string]*azureVM
+	nics       map[string]*azureNIC
+	publicIPs  map[string]*azurePublicIP
+	disks      map[string]bool
+	deleted    []string
+	priceQuery []interface{}
+}
+
+func (c *azureClientMock) Init(_ *evergreen.AzureConfig) error {
+	if c.failInit {
+		return errors.New("failed to initialize client")
+	}
+	c.vms = map[string]*azureVM{}
+	c.nics = map[string]*azureNIC{}
+	c.publicIPs = map[string]*azurePublicIP{}
+	c.disks = map[string]bool{}
+	return nil
+}
+
+func (c *azureClientMock) CreatePublicIP(_ context.Context, group string, ip *azurePublicIP) (*azurePublicIP, error) {
+	ip.ID = "/publicIPAddresses/" + ip.Name
+	ip.Properties.IPAddress = c.publicIP
+	c.publicIPs[group+"/"+ip.Name] = ip
+	return ip, nil
+}
+
+func (c *azureClientMock) GetPublicIP(_ context.Context, group, name string) (*azurePublicIP, error) {
+	if c.failGet {
+		return nil, errors.New("failed to get public IP")
+	}
+	ip, ok := c.publicIPs[group+"/"+name]
+	if !ok {
+		return nil, errAzureNotFound
+	}
+	return ip, nil
+}
+
+func (c *azureClientMock) DeletePublicIP(_ context.Context, group, name string) error {
+	return c.deleteResource(c.publicIPs[group+"/"+name] != nil, func() { delete(c.publicIPs, group+"/"+name) }, name)
+}
+
+func (c *azureClientMock) CreateNIC(_ context.Context, group string, nic *azureNIC) (*azureNIC, error) {
+	nic.ID = "/networkInterfaces/" + nic.Name
+	nic.Properties.IPConfigurations[0].Properties.PrivateIPAddress = c.privateIP
+	c.nics[group+"/"+nic.Name] = nic
+	return nic, nil
+}
+
+func (c *azureClientMock) GetNIC(_ context.Context, group, name string) (*azureNIC, error) {
+	if c.failGet {
+		return nil, errors.New("failed to get network interface")
+	}
+	nic, ok := c.nics[group+"/"+name]
+	if !ok {
+		return nil, errAzureNotFound
+	}
+	return nic, nil
+}
+
+func (c *azureClientMock) DeleteNIC(_ context.Context, group, name string) error {
+	return c.deleteResource(c.nics[group+"/"+name] != nil, func() { delete(c.nics, group+"/"+name) }, name)
+}
+
+func (c *azureClientMock) CreateVM(_ context.Context, group string, vm *azureVM) (*azureVM, error) {
+	if c.failCreateVM {
+		return nil, errors.New("failed to create virtual machine")
+	}
+	c.vms[group+"/"+vm.Name] = vm
+	c.disks[group+"/"+vm.Properties.StorageProfile.OSDisk.Name] = true
+	return vm, nil
+}
+
+func (c *azureClientMock) GetVMInstanceView(_ context.Context, group, name string) (*azureInstanceView, error) {
+	if c.failGet {
+		return nil, errors.New("failed to get instance view")
+	}
+	if _, ok := c.vms[group+"/"+name]; !ok {
+		return nil, errAzureNotFound
+	}
+	view := &azureInstanceView{}
+	for _, code := range c.statuses {
+		view.Statuses = append(view.Statuses, azureInstanceViewStatus{Code: code})
+	}
+	return view, nil
+}
+
+func (c *azureClientMock) DeleteVM(_ context.Context, group, name string) error {
+	return c.deleteResource(c.vms[group+"/"+name] != nil, func() { delete(c.vms, group+"/"+name) }, name)
+}
+
+func (c *azureClientMock) DeleteDisk(_ context.Context, group, name string) error {
+	return c.deleteResource(c.disks[group+"/"+name], func() { delete(c.disks, group+"/"+name) }, name)
+}
+
+func (c *azureClientMock) deleteResource(exists bool, remove func(), name string) error {
+	if c.failDelete {
+		return errors.New("failed to delete resource")
+	}
+	if !exists {
+		return errAzureNotFound
+	}
+	remove()
+	c.deleted = append(c.deleted, name)
+	return nil
+}
+
+func (c *azureClientMock) GetHourlyPrice(_ context.Context, location, size string, spot bool) (float64, error) {
+	if c.failPrice {
+		return 0, errors.New("failed to get price")
+	}
+	c.priceQuery = []interface{}{location, size, spot}
+	return c.price, nil
+}
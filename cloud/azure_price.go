@@ -0,0 +1,61 @@
+package cloud
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/pkg/errors"
+)
+
+// azureHourlyPrice picks the Linux hourly price of a VM size out of the
+// retail prices for it. The prices of one size include Windows, low priority
+// and spot variants, which are told apart by their product and SKU names.
+func azureHourlyPrice(items []azurePriceItem, spot bool) (float64, error) {
+	for _, item := range items {
+		if item.Type != "Consumption" || item.UnitOfMeasure != "1 Hour" {
+			continue
+		}
+		if strings.Contains(item.ProductName, "Windows") || strings.Contains(item.SKUName, "Low Priority") {
+			continue
+		}
+		if strings.Contains(item.SKUName, "Spot") != spot {
+			continue
+		}
+		return item.RetailPrice, nil
+	}
+
+	return 0, errors.New("no matching price found")
+}
+
+// CostForDuration estimates the cost of the host between start and end from
+// the current price of its VM size. Spot hosts are charged the current spot
+// price, which may differ from what was paid as the spot price changed.
+func (m *azureManager) CostForDuration(h *host.Host, start, end time.Time) (float64, error) {
+	if end.Before(start) || start.IsZero() || end.IsZero() {
+		return 0, errors.New("task timing data is malformed")
+	}
+
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return 0, err
+	}
+	location := h.Zone
+	if location == "" {
+		location = settings.Location
+	}
+
+	price, err := m.client.GetHourlyPrice(context.TODO(), location, settings.VMSize, settings.Spot)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem getting price of host '%s'", h.Id)
+	}
+
+	return price * end.Sub(start).Hours(), nil
+}
+
+// TimeTilNextPayment returns how long until the next payment is due for the
+// host. Azure bills virtual machines by the second.
+func (m *azureManager) TimeTilNextPayment(_ *host.Host) time.Duration {
+	return time.Second
+}
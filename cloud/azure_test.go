@@ -0,0 +1,330 @@
+package cloud
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testAzureSubnet  = "/subscriptions/sub/resourceGroups/net/providers/Microsoft.Network/virtualNetworks/vnet/subnets/ci"
+	testAzureGallery = "/subscriptions/sub/resourceGroups/images/providers/Microsoft.Compute/galleries/ci/images/ubuntu/versions/1.0.0"
+)
+
+type AzureSuite struct {
+	client  *azureClientMock
+	manager *azureManager
+	host    *host.Host
+	suite.Suite
+}
+
+func TestAzureSuite(t *testing.T) {
+	suite.Run(t, new(AzureSuite))
+}
+
+func (s *AzureSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *AzureSuite) SetupTest() {
+	s.client = &azureClientMock{
+		statuses:  []string{"ProvisioningState/succeeded", "PowerState/running"},
+		privateIP: "10.0.0.4",
+		publicIP:  "52.0.0.1",
+		price:     0.1,
+	}
+	s.manager = &azureManager{
+		client: s.client,
+	}
+	s.NoError(s.manager.Configure(&evergreen.Settings{}))
+	s.host = &host.Host{
+		Id: "evg-azure-1",
+		Distro: distro.Distro{
+			Id:       "azure",
+			Provider: evergreen.ProviderNameAzure,
+			Arch:     "linux_amd64",
+			User:     "admin",
+			ProviderSettings: &map[string]interface{}{
+				"resource_group":           "ci",
+				"location":                 "eastus",
+				"vm_size":                  "Standard_D2s_v3",
+				"gallery_image_version_id": testAzureGallery,
+				"subnet_id":                testAzureSubnet,
+				"ssh_public_key":           "ssh-rsa AAAA",
+			},
+		},
+	}
+}
+
+func (s *AzureSuite) TestValidateSettings() {
+	settings := &azureSettings{
+		ResourceGroup:         "ci",
+		Location:              "eastus",
+		VMSize:                "Standard_D2s_v3",
+		GalleryImageVersionID: testAzureGallery,
+		SubnetID:              testAzureSubnet,
+		SSHPublicKey:          "ssh-rsa AAAA",
+	}
+	s.NoError(settings.Validate())
+
+	settings.ImageID = "Canonical:UbuntuServer:18.04-LTS:latest"
+	s.Error(settings.Validate())
+	settings.GalleryImageVersionID = ""
+	s.NoError(settings.Validate())
+	settings.ImageID = "/subscriptions/sub/resourceGroups/images/providers/Microsoft.Compute/images/ubuntu"
+	s.NoError(settings.Validate())
+	settings.ImageID = "ubuntu"
+	s.Error(settings.Validate())
+	settings.ImageID = ""
+	s.Error(settings.Validate())
+	settings.GalleryImageVersionID = "/subscriptions/sub/resourceGroups/images/providers/Microsoft.Compute/galleries/ci/images/ubuntu"
+	s.Error(settings.Validate())
+	settings.GalleryImageVersionID = testAzureGallery
+
+	settings.OSDiskType = "Premium_LRS"
+	s.NoError(settings.Validate())
+	settings.OSDiskType = "Fast"
+	s.Error(settings.Validate())
+	settings.OSDiskType = ""
+
+	settings.SubnetID = "ci"
+	s.Error(settings.Validate())
+	settings.SubnetID = testAzureSubnet
+
+	settings.MaxSpotPrice = 0.05
+	s.Error(settings.Validate())
+	settings.Spot = true
+	s.NoError(settings.Validate())
+
+	s.Error((&azureSettings{}).Validate())
+}
+
+func (s *AzureSuite) TestImageReference() {
+	settings := &azureSettings{GalleryImageVersionID: testAzureGallery}
+	s.Equal(azureImageReference{ID: testAzureGallery}, settings.imageReference())
+
+	settings = &azureSettings{ImageID: "Canonical:UbuntuServer:18.04-LTS:latest"}
+	s.Equal(azureImageReference{
+		Publisher: "Canonical",
+		Offer:     "UbuntuServer",
+		SKU:       "18.04-LTS",
+		Version:   "latest",
+	}, settings.imageReference())
+}
+
+func (s *AzureSuite) TestConfigureAPICall() {
+	s.client.failInit = true
+	s.Error(s.manager.Configure(&evergreen.Settings{}))
+}
+
+func (s *AzureSuite) TestGetInstanceName() {
+	name := s.manager.GetInstanceName(&distro.Distro{Id: "ubuntu_1804"})
+	s.NotContains(name, "_")
+	s.Contains(name, "ubuntu-1804")
+}
+
+func (s *AzureSuite) TestSpawnInvalidSettings() {
+	s.host.Distro.Provider = evergreen.ProviderNameDocker
+	_, err := s.manager.SpawnHost(s.host)
+	s.Error(err)
+
+	s.host.Distro.Provider = evergreen.ProviderNameAzure
+	s.host.Distro.Arch = "windows_amd64"
+	_, err = s.manager.SpawnHost(s.host)
+	s.Error(err)
+
+	s.host.Distro.Arch = "linux_amd64"
+	s.host.Distro.ProviderSettings = &map[string]interface{}{}
+	_, err = s.manager.SpawnHost(s.host)
+	s.Error(err)
+}
+
+func (s *AzureSuite) TestSpawnHost() {
+	s.host.BootstrapScript = "#!/bin/bash\necho hi"
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+	s.Equal("ci", h.Project)
+	s.Equal("eastus", h.Zone)
+
+	vm, ok := s.client.vms["ci/"+h.Id]
+	s.Require().True(ok)
+	s.Equal("Standard_D2s_v3", vm.Properties.HardwareProfile.VMSize)
+	s.Equal(testAzureGallery, vm.Properties.StorageProfile.ImageReference.ID)
+	s.Equal(azureDiskName(h), vm.Properties.StorageProfile.OSDisk.Name)
+	s.Equal(azureDefaultDiskType, vm.Properties.StorageProfile.OSDisk.ManagedDisk.StorageAccountType)
+	s.Equal("admin", vm.Properties.OSProfile.AdminUsername)
+	s.Equal(base64.StdEncoding.EncodeToString([]byte(s.host.BootstrapScript)), vm.Properties.OSProfile.CustomData)
+	s.Require().Len(vm.Properties.NetworkProfile.NetworkInterfaces, 1)
+	s.Equal("/networkInterfaces/"+azureNICName(h), vm.Properties.NetworkProfile.NetworkInterfaces[0].ID)
+	s.Empty(vm.Properties.Priority)
+	s.Nil(vm.Properties.BillingProfile)
+
+	nic, ok := s.client.nics["ci/"+azureNICName(h)]
+	s.Require().True(ok)
+	s.Equal(testAzureSubnet, nic.Properties.IPConfigurations[0].Properties.Subnet.ID)
+	s.Nil(nic.Properties.IPConfigurations[0].Properties.PublicIPAddress)
+	s.Empty(s.client.publicIPs)
+}
+
+func (s *AzureSuite) TestSpawnSpotHostWithPublicIP() {
+	settings := *s.host.Distro.ProviderSettings
+	settings["spot"] = true
+	settings["public_ip"] = true
+	settings["security_group_id"] = "/nsg"
+
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	vm := s.client.vms["ci/"+h.Id]
+	s.Require().NotNil(vm)
+	s.Equal(azureSpotPriority, vm.Properties.Priority)
+	s.Equal(azureEvictionPolicy, vm.Properties.EvictionPolicy)
+	s.Require().NotNil(vm.Properties.BillingProfile)
+	s.Equal(float64(-1), vm.Properties.BillingProfile.MaxPrice)
+
+	nic := s.client.nics["ci/"+azureNICName(h)]
+	s.Require().NotNil(nic)
+	s.Equal("/nsg", nic.Properties.NetworkSecurityGroup.ID)
+	s.Equal("/publicIPAddresses/"+azurePublicIPName(h), nic.Properties.IPConfigurations[0].Properties.PublicIPAddress.ID)
+
+	dns, err := s.manager.GetDNSName(h)
+	s.NoError(err)
+	s.Equal("52.0.0.1", dns)
+}
+
+func (s *AzureSuite) TestSpawnHostCleansUpOnFailure() {
+	settings := *s.host.Distro.ProviderSettings
+	settings["public_ip"] = true
+	s.client.failCreateVM = true
+
+	_, err := s.manager.SpawnHost(s.host)
+	s.Error(err)
+	s.Empty(s.client.vms)
+	s.Empty(s.client.nics)
+	s.Empty(s.client.publicIPs)
+}
+
+func (s *AzureSuite) TestGetInstanceStatus() {
+	for statuses, expected := range map[[2]string]CloudStatus{
+		{"ProvisioningState/creating", ""}:                            StatusInitializing,
+		{"ProvisioningState/succeeded", "PowerState/starting"}:        StatusInitializing,
+		{"ProvisioningState/succeeded", "PowerState/running"}:         StatusRunning,
+		{"ProvisioningState/succeeded", "PowerState/deallocated"}:     StatusStopped,
+		{"ProvisioningState/updating", "PowerState/stopping"}:         StatusStopped,
+		{"ProvisioningState/failed/AllocationFailed", "PowerState/x"}: StatusFailed,
+		{"ProvisioningState/deleting", "PowerState/running"}:          StatusTerminated,
+		{"ProvisioningState/succeeded", "PowerState/unknown"}:         StatusUnknown,
+	} {
+		view := &azureInstanceView{Statuses: []azureInstanceViewStatus{{Code: statuses[0]}, {Code: statuses[1]}}}
+		s.Equal(expected, azureToEvgStatus(view), "%v", statuses)
+	}
+
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+	status, err := s.manager.GetInstanceStatus(h)
+	s.NoError(err)
+	s.Equal(StatusRunning, status)
+	up, err := s.manager.IsUp(h)
+	s.NoError(err)
+	s.True(up)
+
+	s.client.failGet = true
+	_, err = s.manager.GetInstanceStatus(h)
+	s.Error(err)
+	s.client.failGet = false
+
+	// an evicted spot host's leftover resources are deleted
+	delete(s.client.vms, "ci/"+h.Id)
+	status, err = s.manager.GetInstanceStatus(h)
+	s.NoError(err)
+	s.Equal(StatusTerminated, status)
+	s.Empty(s.client.nics)
+	s.Empty(s.client.disks)
+}
+
+func (s *AzureSuite) TestGetDNSName() {
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	dns, err := s.manager.GetDNSName(h)
+	s.NoError(err)
+	s.Equal("10.0.0.4", dns)
+
+	s.client.failGet = true
+	_, err = s.manager.GetDNSName(h)
+	s.Error(err)
+}
+
+func (s *AzureSuite) TestDeleteResources() {
+	settings := *s.host.Distro.ProviderSettings
+	settings["public_ip"] = true
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	s.NoError(s.manager.deleteResources(h))
+	s.Equal([]string{h.Id, azureNICName(h), azurePublicIPName(h), azureDiskName(h)}, s.client.deleted)
+	s.Empty(s.client.vms)
+	s.Empty(s.client.nics)
+	s.Empty(s.client.publicIPs)
+	s.Empty(s.client.disks)
+
+	// deleting again skips resources that are already gone
+	s.NoError(s.manager.deleteResources(h))
+}
+
+func (s *AzureSuite) TestTerminateInstance() {
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	s.client.failDelete = true
+	s.Error(s.manager.TerminateInstance(h, evergreen.User))
+
+	h.Status = evergreen.HostTerminated
+	s.client.failDelete = false
+	s.Error(s.manager.TerminateInstance(h, evergreen.User))
+}
+
+func (s *AzureSuite) TestCostForDuration() {
+	start := time.Now()
+	end := start.Add(3 * time.Hour)
+
+	_, err := s.manager.CostForDuration(s.host, end, start)
+	s.Error(err)
+
+	s.host.Zone = "westus"
+	cost, err := s.manager.CostForDuration(s.host, start, end)
+	s.NoError(err)
+	s.InDelta(0.3, cost, 0.0001)
+	s.Equal([]interface{}{"westus", "Standard_D2s_v3", false}, s.client.priceQuery)
+
+	s.client.failPrice = true
+	_, err = s.manager.CostForDuration(s.host, start, end)
+	s.Error(err)
+}
+
+func (s *AzureSuite) TestHourlyPrice() {
+	items := []azurePriceItem{
+		{RetailPrice: 0.2, UnitOfMeasure: "1 Hour", SKUName: "D2s v3", ProductName: "Virtual Machines DSv3 Series Windows", Type: "Consumption"},
+		{RetailPrice: 0.02, UnitOfMeasure: "1 Hour", SKUName: "D2s v3 Low Priority", ProductName: "Virtual Machines DSv3 Series", Type: "Consumption"},
+		{RetailPrice: 0.01, UnitOfMeasure: "1 Hour", SKUName: "D2s v3 Spot", ProductName: "Virtual Machines DSv3 Series", Type: "Consumption"},
+		{RetailPrice: 0.1, UnitOfMeasure: "1 Hour", SKUName: "D2s v3", ProductName: "Virtual Machines DSv3 Series", Type: "Consumption"},
+	}
+
+	price, err := azureHourlyPrice(items, false)
+	s.NoError(err)
+	s.Equal(0.1, price)
+
+	price, err = azureHourlyPrice(items, true)
+	s.NoError(err)
+	s.Equal(0.01, price)
+
+	_, err = azureHourlyPrice(items[:2], false)
+	s.Error(err)
+}
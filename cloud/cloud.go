@@ -1,6 +1,7 @@
 package cloud
 
 import (
+	"context"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -73,6 +74,67 @@ type CloudCostCalculator interface {
 	CostForDuration(host *host.Host, start time.Time, end time.Time) (float64, error)
 }
 
+// WarmPoolCost returns the cost of the time a warm pool host spent idle in
+// its distro's warm pool, from when it started until it left the pool at end.
+// Hosts whose manager cannot estimate costs, or that never started, cost
+// nothing.
+func WarmPoolCost(h *host.Host, mgr CloudManager, end time.Time) (float64, error) {
+	calc, ok := mgr.(CloudCostCalculator)
+	if !ok || h.StartTime.IsZero() {
+		return 0, nil
+	}
+	cost, err := calc.CostForDuration(h, h.StartTime, end)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error calculating warm pool cost for host %s", h.Id)
+	}
+	return cost, nil
+}
+
+// CommandRunner is an interface for cloud managers whose hosts are not
+// reachable over SSH, and instead run commands through the provider's API.
+// Host setup uses it in place of SSH and SCP when a manager implements it.
+type CommandRunner interface {
+	// RunCommand runs the command on the host and returns its combined
+	// output. The error is non-nil if the command exits non-zero.
+	RunCommand(ctx context.Context, host *host.Host, cmd []string) (string, error)
+}
+
+// HostStopper is an interface for cloud managers that can stop a host
+// without terminating it and later start it again.
+type HostStopper interface {
+	// StopInstance stops the host and marks it as stopped.
+	StopInstance(host *host.Host, user string) error
+
+	// StartInstance starts a stopped host and marks it as running once it
+	// is up.
+	StartInstance(host *host.Host, user string) error
+}
+
+// VolumeManager is an interface for cloud managers that can create
+// persistent volumes and attach them to hosts.
+type VolumeManager interface {
+	// CreateVolume creates a volume in the host's availability zone and
+	// returns it with its ID and zone set.
+	CreateVolume(host *host.Host, volume *host.Volume) (*host.Volume, error)
+
+	// AttachVolume attaches an existing volume to the host.
+	AttachVolume(host *host.Host, volume *host.Volume) error
+}
+
+// SupportsUserData returns whether the provider passes a host's bootstrap
+// script to it as user data, so that the host can provision itself on boot.
+func SupportsUserData(providerName string) bool {
+	switch providerName {
+	case evergreen.ProviderNameEc2Legacy, evergreen.ProviderNameEc2OnDemand,
+		evergreen.ProviderNameEc2Spot, evergreen.ProviderNameEc2Auto,
+		evergreen.ProviderNameGce, evergreen.ProviderNameOpenstack,
+		evergreen.ProviderNameDocker, evergreen.ProviderNameAzure:
+		return true
+	default:
+		return false
+	}
+}
+
 // GetCloudManager returns an implementation of CloudManager for the given provider name.
 // It returns an error if the provider name doesn't have a known implementation.
 func GetCloudManager(providerName string, settings *evergreen.Settings) (CloudManager, error) {
@@ -89,12 +151,18 @@ func GetCloudManager(providerName string, settings *evergreen.Settings) (CloudMa
 		provider = NewEC2Manager(&EC2ManagerOptions{client: &awsClientImpl{}, provider: spotProvider})
 	case evergreen.ProviderNameEc2Auto:
 		provider = NewEC2Manager(&EC2ManagerOptions{client: &awsClientImpl{}, provider: autoProvider})
+	case evergreen.ProviderNameAzure:
+		provider = &azureManager{}
 	case evergreen.ProviderNameDocker:
 		provider = &dockerManager{}
 	case evergreen.ProviderNameOpenstack:
 		provider = &openStackManager{}
 	case evergreen.ProviderNameGce:
 		provider = &gceManager{}
+	case evergreen.ProviderNameKubernetes:
+		provider = &kubernetesManager{}
+	case evergreen.ProviderNameLibvirt:
+		provider = &libvirtManager{}
 	case evergreen.ProviderNameVsphere:
 		provider = &vsphereManager{}
 	default:
@@ -1,10 +1,27 @@
 package cloud
 
 import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/hostutil"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// startInstanceRetries and startInstanceStartPeriod bound how long
+	// providers wait for a started host to be running.
+	startInstanceRetries     = 8
+	startInstanceStartPeriod = 2 * time.Second
 )
 
 // HostOptions is a struct of options that are commonly passed around when creating a
@@ -15,6 +32,7 @@ type HostOptions struct {
 	UserName           string
 	UserData           string
 	UserHost           bool
+	WarmPool           bool
 }
 
 //CloudHost is a provider-agnostic host object that delegates methods
@@ -24,10 +42,15 @@ type CloudHost struct {
 	Host     *host.Host
 	KeyPath  string
 	CloudMgr CloudManager
+
+	// hostKey is true if KeyPath is the host's own SSH key
+	hostKey bool
 }
 
 // GetCloudHost returns an instance of CloudHost wrapping the given model.Host,
 // giving access to the provider-specific methods to manipulate on the host.
+// Hosts with their own installed SSH key are connected to with it rather than
+// the distro's key.
 func GetCloudHost(host *host.Host, settings *evergreen.Settings) (*CloudHost, error) {
 	mgr, err := GetCloudManager(host.Provider, settings)
 	if err != nil {
@@ -35,10 +58,91 @@ func GetCloudHost(host *host.Host, settings *evergreen.Settings) (*CloudHost, er
 	}
 
 	keyPath := ""
+	hostKey := false
 	if host.Distro.SSHKey != "" {
 		keyPath = settings.Keys[host.Distro.SSHKey]
 	}
-	return &CloudHost{host, keyPath, mgr}, nil
+	if host.SSHKey != nil && host.SSHKey.Installed {
+		path, err := writeHostKeyFile(host, settings.HostSSHKeys)
+		if err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message": "problem writing host's SSH key, falling back to distro key",
+				"host":    host.Id,
+				"distro":  host.Distro.Id,
+			}))
+		} else {
+			keyPath = path
+			hostKey = true
+		}
+	}
+	return &CloudHost{Host: host, KeyPath: keyPath, CloudMgr: mgr, hostKey: hostKey}, nil
+}
+
+// hostKeyFile returns the path of the file holding the host's decrypted SSH
+// key. The path includes a hash of the public key, so that a rotated key is
+// written to a new file.
+func hostKeyFile(h *host.Host, conf evergreen.HostSSHKeysConfig) string {
+	sum := sha256.Sum256([]byte(h.SSHKey.PublicKey))
+	return filepath.Join(conf.WithDefaults().KeyDirectory, fmt.Sprintf("%s-%x", h.Id, sum[:8]))
+}
+
+// writeHostKeyFile decrypts the host's SSH key into its key file, unless it
+// has already been written, and returns the file's path.
+func writeHostKeyFile(h *host.Host, conf evergreen.HostSSHKeysConfig) (string, error) {
+	path := hostKeyFile(h, conf)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	encryptionKey, err := conf.Key()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	private, err := h.SSHKey.PrivateKey(encryptionKey)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", errors.Wrap(err, "problem creating key directory")
+	}
+	// write to a temporary file, which is only readable by its owner, and
+	// move it into place so that concurrent readers never see a partial key
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return "", errors.Wrap(err, "problem creating key file")
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(private); err != nil {
+		file.Close()
+		return "", errors.Wrap(err, "problem writing key file")
+	}
+	if err = file.Close(); err != nil {
+		return "", errors.Wrap(err, "problem writing key file")
+	}
+	if err = os.Rename(file.Name(), path); err != nil {
+		return "", errors.Wrap(err, "problem moving key file into place")
+	}
+	return path, nil
+}
+
+// RemoveHostKeyFile removes the file holding the host's decrypted SSH key, if
+// it has one.
+func RemoveHostKeyFile(h *host.Host, settings *evergreen.Settings) error {
+	if h.SSHKey == nil {
+		return nil
+	}
+	err := os.Remove(hostKeyFile(h, settings.HostSSHKeys))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return errors.Wrapf(err, "problem removing SSH key file for host %s", h.Id)
+}
+
+// UsesHostSSHKey returns whether the host is connected to with its own SSH
+// key rather than the distro's.
+func (cloudHost *CloudHost) UsesHostSSHKey() bool {
+	return cloudHost.hostKey
 }
 
 func (cloudHost *CloudHost) IsSSHReachable() (bool, error) {
@@ -49,8 +153,41 @@ func (cloudHost *CloudHost) IsUp() (bool, error) {
 	return cloudHost.CloudMgr.IsUp(cloudHost.Host)
 }
 
+// TerminateInstance terminates the host and removes its own SSH key file,
+// which is no longer needed. A host terminated while still in its distro's
+// warm pool has the cost of its idle time recorded, as it would have been had
+// it been dispatched a task.
 func (cloudHost *CloudHost) TerminateInstance(user string) error {
-	return cloudHost.CloudMgr.TerminateInstance(cloudHost.Host, user)
+	terminateTime := time.Now()
+	if err := cloudHost.CloudMgr.TerminateInstance(cloudHost.Host, user); err != nil {
+		return err
+	}
+	if cloudHost.Host.WarmPool {
+		cloudHost.leaveWarmPool(terminateTime)
+	}
+	if cloudHost.hostKey {
+		grip.Warning(message.WrapError(os.Remove(cloudHost.KeyPath), message.Fields{
+			"message": "problem removing host's SSH key file",
+			"host":    cloudHost.Host.Id,
+		}))
+	}
+	return nil
+}
+
+// leaveWarmPool records the warm pool cost of a host terminated at end. The
+// host is already gone, so errors are logged rather than returned.
+func (cloudHost *CloudHost) leaveWarmPool(end time.Time) {
+	h := cloudHost.Host
+	cost, err := WarmPoolCost(h, cloudHost.CloudMgr, end)
+	grip.Error(message.WrapError(err, message.Fields{
+		"message": "problem calculating warm pool cost for terminated host",
+		"host":    h.Id,
+	}))
+	grip.Error(message.WrapError(h.LeaveWarmPool(cost), message.Fields{
+		"message": "problem recording warm pool cost for terminated host",
+		"host":    h.Id,
+		"cost":    cost,
+	}))
 }
 
 func (cloudHost *CloudHost) GetInstanceStatus() (CloudStatus, error) {
@@ -64,3 +201,46 @@ func (cloudHost *CloudHost) GetDNSName() (string, error) {
 func (cloudHost *CloudHost) GetSSHOptions() ([]string, error) {
 	return cloudHost.CloudMgr.GetSSHOptions(cloudHost.Host, cloudHost.KeyPath)
 }
+
+// RunCommand runs the shell command on the host and returns its output. Hosts
+// whose provider implements CommandRunner are reached through the provider,
+// and all other hosts over SSH.
+func (cloudHost *CloudHost) RunCommand(ctx context.Context, cmd string) (string, error) {
+	if runner, ok := cloudHost.CloudMgr.(CommandRunner); ok {
+		ctx, cancel := context.WithTimeout(ctx, hostutil.SSHTimeout)
+		defer cancel()
+		return runner.RunCommand(ctx, cloudHost.Host, []string{"sh", "-c", cmd})
+	}
+
+	sshOptions, err := cloudHost.GetSSHOptions()
+	if err != nil {
+		return "", errors.Wrapf(err, "error getting ssh options for host %s", cloudHost.Host.Id)
+	}
+	return hostutil.RunSSHCommand(ctx, cmd, sshOptions, *cloudHost.Host)
+}
+
+// UsesCommandRunner returns whether commands on the host are run through its
+// provider rather than over SSH.
+func (cloudHost *CloudHost) UsesCommandRunner() bool {
+	_, ok := cloudHost.CloudMgr.(CommandRunner)
+	return ok
+}
+
+// StopInstance stops the host, if its provider supports stopping hosts.
+func (cloudHost *CloudHost) StopInstance(user string) error {
+	stopper, ok := cloudHost.CloudMgr.(HostStopper)
+	if !ok {
+		return errors.Errorf("provider '%s' does not support stopping hosts", cloudHost.Host.Provider)
+	}
+	return stopper.StopInstance(cloudHost.Host, user)
+}
+
+// StartInstance starts the stopped host, if its provider supports stopping
+// hosts.
+func (cloudHost *CloudHost) StartInstance(user string) error {
+	stopper, ok := cloudHost.CloudMgr.(HostStopper)
+	if !ok {
+		return errors.Errorf("provider '%s' does not support starting hosts", cloudHost.Host.Provider)
+	}
+	return stopper.StartInstance(cloudHost.Host, user)
+}
@@ -0,0 +1,148 @@
+package cloud
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCloudHostSSHKey(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "evergreen-host-keys-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	encryptionKey := []byte("0123456789abcdef0123456789abcdef")
+	settings := &evergreen.Settings{
+		Keys: map[string]string{"shared": "/etc/evergreen/shared.pem"},
+		HostSSHKeys: evergreen.HostSSHKeysConfig{
+			Enabled:       true,
+			EncryptionKey: base64.StdEncoding.EncodeToString(encryptionKey),
+			KeyDirectory:  dir,
+		},
+	}
+	h := &host.Host{
+		Id:       "h1",
+		Provider: evergreen.ProviderNameMock,
+		Distro:   distro.Distro{Id: "d1", SSHKey: "shared"},
+	}
+
+	cloudHost, err := GetCloudHost(h, settings)
+	require.NoError(t, err)
+	assert.Equal("/etc/evergreen/shared.pem", cloudHost.KeyPath)
+
+	// keys are not used until they are installed on the host
+	h.SSHKey, err = host.NewHostSSHKey(encryptionKey)
+	require.NoError(t, err)
+	cloudHost, err = GetCloudHost(h, settings)
+	require.NoError(t, err)
+	assert.Equal("/etc/evergreen/shared.pem", cloudHost.KeyPath)
+
+	h.SSHKey.Installed = true
+	cloudHost, err = GetCloudHost(h, settings)
+	require.NoError(t, err)
+	assert.Equal(hostKeyFile(h, settings.HostSSHKeys), cloudHost.KeyPath)
+	private, err := ioutil.ReadFile(cloudHost.KeyPath)
+	require.NoError(t, err)
+	expected, err := h.SSHKey.PrivateKey(encryptionKey)
+	require.NoError(t, err)
+	assert.Equal(expected, private)
+	info, err := os.Stat(cloudHost.KeyPath)
+	require.NoError(t, err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	assert.NoError(RemoveHostKeyFile(h, settings))
+	_, err = os.Stat(cloudHost.KeyPath)
+	assert.True(os.IsNotExist(err))
+	assert.NoError(RemoveHostKeyFile(h, settings))
+
+	// a key that cannot be decrypted falls back to the distro's key
+	settings.HostSSHKeys.EncryptionKey = base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+	cloudHost, err = GetCloudHost(h, settings)
+	require.NoError(t, err)
+	assert.Equal("/etc/evergreen/shared.pem", cloudHost.KeyPath)
+}
+
+// costMockManager is a mock manager that charges a fixed cost per hour.
+type costMockManager struct {
+	CloudManager
+	hourlyCost float64
+}
+
+func (m *costMockManager) CostForDuration(h *host.Host, start, end time.Time) (float64, error) {
+	if end.Before(start) {
+		return 0, errors.New("end is before start")
+	}
+	return end.Sub(start).Hours() * m.hourlyCost, nil
+}
+
+func TestWarmPoolCost(t *testing.T) {
+	assert := assert.New(t)
+
+	end := time.Now()
+	h := &host.Host{Id: "h1", StartTime: end.Add(-2 * time.Hour)}
+	cost, err := WarmPoolCost(h, &costMockManager{CloudManager: &mockManager{}, hourlyCost: 1.5}, end)
+	assert.NoError(err)
+	assert.InDelta(3.0, cost, 0.0001)
+
+	// managers that cannot estimate costs, and hosts that never started, are free
+	cost, err = WarmPoolCost(h, &mockManager{}, end)
+	assert.NoError(err)
+	assert.Zero(cost)
+	cost, err = WarmPoolCost(&host.Host{Id: "h2"}, &costMockManager{CloudManager: &mockManager{}, hourlyCost: 1.5}, end)
+	assert.NoError(err)
+	assert.Zero(cost)
+
+	_, err = WarmPoolCost(h, &costMockManager{CloudManager: &mockManager{}, hourlyCost: 1.5}, h.StartTime.Add(-time.Hour))
+	assert.Error(err)
+}
+
+func TestTerminateInstanceRecordsWarmPoolCost(t *testing.T) {
+	assert := assert.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(t, db.Clear(host.Collection))
+	defer GetMockProvider().Reset()
+
+	settings := &evergreen.Settings{}
+	mgr, err := GetCloudManager(evergreen.ProviderNameMock, settings)
+	require.NoError(t, err)
+
+	for _, h := range []host.Host{
+		{Id: "warm", WarmPool: true, WarmPoolCost: 0.5},
+		{Id: "dispatched", WarmPoolCost: 0.5},
+	} {
+		h.Provider = evergreen.ProviderNameMock
+		h.Status = evergreen.HostRunning
+		h.StartTime = time.Now().Add(-time.Hour)
+		require.NoError(t, h.Insert())
+		GetMockProvider().Set(h.Id, MockInstance{Status: StatusRunning})
+
+		cloudHost := &CloudHost{
+			Host:     &h,
+			CloudMgr: &costMockManager{CloudManager: mgr, hourlyCost: 2},
+		}
+		require.NoError(t, cloudHost.TerminateInstance(evergreen.User))
+	}
+
+	h, err := host.FindOne(host.ById("warm"))
+	require.NoError(t, err)
+	assert.False(h.WarmPool)
+	assert.InDelta(2.5, h.WarmPoolCost, 0.01)
+
+	// hosts that have already left the warm pool are not charged again
+	h, err = host.FindOne(host.ById("dispatched"))
+	require.NoError(t, err)
+	assert.Equal(0.5, h.WarmPoolCost)
+}
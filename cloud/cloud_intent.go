@@ -31,6 +31,7 @@ func NewIntent(d distro.Distro, instanceName, provider string, options HostOptio
 		Provider:         provider,
 		StartedBy:        options.UserName,
 		UserHost:         options.UserHost,
+		WarmPool:         options.WarmPool,
 	}
 
 	if options.ExpirationDuration != nil {
@@ -121,7 +121,7 @@ func (m *dockerManager) SpawnHost(h *host.Host) (*host.Host, error) {
 	})
 
 	// Create container
-	if err := m.client.CreateContainer(h.Id, &h.Distro, settings); err != nil {
+	if err := m.client.CreateContainer(h, settings); err != nil {
 		err = errors.Wrapf(err, "Failed to create container for host '%s'", settings.HostIP)
 		grip.Error(err)
 		return nil, err
@@ -24,7 +24,7 @@ import (
 // The dockerClient interface wraps the Docker dockerClient interaction.
 type dockerClient interface {
 	Init(string) error
-	CreateContainer(string, *distro.Distro, *dockerSettings) error
+	CreateContainer(*host.Host, *dockerSettings) error
 	GetContainer(*host.Host) (*types.ContainerJSON, error)
 	ListContainers(*distro.Distro) ([]types.Container, error)
 	RemoveContainer(*host.Host) error
@@ -91,7 +91,10 @@ func (c *dockerClientImpl) Init(apiVersion string) error {
 //     3. The image must have the same ~/.ssh/authorized_keys file as the host machine
 //        in order to allow users with SSH access to the host machine to have SSH access
 //        to the container.
-func (c *dockerClientImpl) CreateContainer(id string, d *distro.Distro, s *dockerSettings) error {
+// Hosts that bootstrap from user data run their bootstrap script in place of
+// the image's entrypoint.
+func (c *dockerClientImpl) CreateContainer(h *host.Host, s *dockerSettings) error {
+	id, d := h.Id, &h.Distro
 	dockerClient, err := c.generateClient(d)
 	if err != nil {
 		return errors.Wrap(err, "Failed to generate docker client")
@@ -120,6 +123,11 @@ func (c *dockerClientImpl) CreateContainer(id string, d *distro.Distro, s *docke
 		},
 		Image: s.ImageID,
 	}
+	if h.BootstrapScript != "" {
+		// the bootstrap script starts the agent in the background, so keep
+		// the container running after the script exits
+		containerConf.Entrypoint = []string{"/bin/sh", "-c", h.BootstrapScript + "\nexec tail -f /dev/null\n"}
+	}
 	networkConf := &network.NetworkingConfig{}
 
 	grip.Info(message.Fields{
@@ -39,7 +39,7 @@ func (c *dockerClientMock) Init(_ string) error {
 	return nil
 }
 
-func (c *dockerClientMock) CreateContainer(_ string, _ *distro.Distro, _ *dockerSettings) error {
+func (c *dockerClientMock) CreateContainer(_ *host.Host, _ *dockerSettings) error {
 	if c.failCreate {
 		return errors.New("failed to create container")
 	}
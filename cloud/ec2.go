@@ -2,6 +2,7 @@ package cloud
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
@@ -55,6 +56,11 @@ type EC2ProviderSettings struct {
 
 	// BidPrice is the price we are willing to pay for a spot instance.
 	BidPrice float64 `mapstructure:"bid_price" json:"bid_price,omitempty" bson:"bid_price,omitempty"`
+
+	// FleetOptions are alternative instance types and subnets for the
+	// distro. When set, hosts are started with the cheapest option per unit
+	// of weight, falling back to the next option if EC2 has no capacity.
+	FleetOptions []EC2FleetOption `mapstructure:"fleet_options" json:"fleet_options,omitempty" bson:"fleet_options,omitempty"`
 }
 
 // Validate that essential EC2ProviderSettings fields are not empty.
@@ -71,6 +77,11 @@ func (s *EC2ProviderSettings) Validate() error {
 	if _, err := makeBlockDeviceMappings(s.MountPoints); err != nil {
 		return errors.Wrap(err, "block device mappings invalid")
 	}
+	for _, opt := range s.FleetOptions {
+		if err := opt.Validate(); err != nil {
+			return errors.Wrap(err, "fleet options invalid")
+		}
+	}
 	return nil
 }
 
@@ -140,6 +151,9 @@ func (m *ec2Manager) spawnOnDemandHost(h *host.Host, ec2Settings *EC2ProviderSet
 		InstanceType:        &ec2Settings.InstanceType,
 		BlockDeviceMappings: blockDevices,
 	}
+	if h.BootstrapScript != "" {
+		input.UserData = makeStringPtr(base64.StdEncoding.EncodeToString([]byte(h.BootstrapScript)))
+	}
 
 	if ec2Settings.IsVpc {
 		input.NetworkInterfaces = []*ec2.InstanceNetworkInterfaceSpecification{
@@ -250,6 +264,9 @@ func (m *ec2Manager) spawnSpotHost(h *host.Host, ec2Settings *EC2ProviderSetting
 			BlockDeviceMappings: blockDevices,
 		},
 	}
+	if h.BootstrapScript != "" {
+		spotRequest.LaunchSpecification.UserData = makeStringPtr(base64.StdEncoding.EncodeToString([]byte(h.BootstrapScript)))
+	}
 
 	if ec2Settings.IsVpc {
 		spotRequest.LaunchSpecification.NetworkInterfaces = []*ec2.InstanceNetworkInterfaceSpecification{
@@ -330,42 +347,37 @@ func (m *ec2Manager) SpawnHost(h *host.Host) (*host.Host, error) {
 		}))
 		return nil, errors.Wrap(err, msg)
 	}
-	if provider == onDemandProvider {
-		resources, err = m.spawnOnDemandHost(h, ec2Settings, blockDevices)
-		if err != nil {
-			msg := "error spawning on-demand host"
-			grip.Error(message.WrapError(err, message.Fields{
-				"message":       msg,
-				"host":          h.Id,
-				"host_provider": h.Distro.Provider,
-				"distro":        h.Distro.Id,
-			}))
-			return nil, errors.Wrap(err, msg)
+	candidates := m.getFleetCandidates(h, ec2Settings, provider)
+	candidates, err = m.placeInHomeVolumeZone(h, ec2Settings, candidates)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error placing host %s with its home volume", h.Id)
+	}
+	for idx, c := range candidates {
+		settings := *ec2Settings
+		settings.InstanceType = c.instanceType
+		settings.SubnetId = c.subnetID
+		settings.BidPrice = c.bidPrice
+
+		resources, err = m.spawnHostWithSettings(h, provider, &settings, blockDevices)
+		if err == nil && provider == spotProvider && idx+1 < len(candidates) {
+			err = m.checkSpotRequestCapacity(h.Id)
 		}
-		grip.Debug(message.Fields{
-			"message":       "spawned on-demand host",
-			"host":          h.Id,
-			"host_provider": h.Distro.Provider,
-			"distro":        h.Distro.Id,
-		})
-	} else if provider == spotProvider {
-		resources, err = m.spawnSpotHost(h, ec2Settings, blockDevices)
-		if err != nil {
-			msg := "error spawning spot host"
-			grip.Error(message.WrapError(err, message.Fields{
-				"message":       msg,
+		if err == nil {
+			h.InstanceType = c.instanceType
+			break
+		}
+		if idx+1 < len(candidates) && isEC2CapacityError(err) {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message":       "no capacity for instance type, trying next fleet option",
+				"instance_type": c.instanceType,
+				"subnet":        c.subnetID,
 				"host":          h.Id,
 				"host_provider": h.Distro.Provider,
 				"distro":        h.Distro.Id,
 			}))
-			return nil, errors.Wrap(err, msg)
+			continue
 		}
-		grip.Debug(message.Fields{
-			"message":       "spawned spot host",
-			"host":          h.Id,
-			"host_provider": h.Distro.Provider,
-			"distro":        h.Distro.Id,
-		})
+		return nil, err
 	}
 
 	grip.Debug(message.Fields{
@@ -406,6 +418,55 @@ func (m *ec2Manager) SpawnHost(h *host.Host) (*host.Host, error) {
 	return h, nil
 }
 
+// spawnHostWithSettings starts a single on-demand or spot instance with the
+// given settings.
+func (m *ec2Manager) spawnHostWithSettings(h *host.Host, provider ec2ProviderType, ec2Settings *EC2ProviderSettings, blockDevices []*ec2.BlockDeviceMapping) ([]*string, error) {
+	var resources []*string
+	var err error
+	if provider == onDemandProvider {
+		resources, err = m.spawnOnDemandHost(h, ec2Settings, blockDevices)
+		if err != nil {
+			msg := "error spawning on-demand host"
+			grip.Error(message.WrapError(err, message.Fields{
+				"message":       msg,
+				"host":          h.Id,
+				"host_provider": h.Distro.Provider,
+				"distro":        h.Distro.Id,
+				"instance_type": ec2Settings.InstanceType,
+			}))
+			return nil, errors.Wrap(err, msg)
+		}
+		grip.Debug(message.Fields{
+			"message":       "spawned on-demand host",
+			"host":          h.Id,
+			"host_provider": h.Distro.Provider,
+			"distro":        h.Distro.Id,
+			"instance_type": ec2Settings.InstanceType,
+		})
+	} else if provider == spotProvider {
+		resources, err = m.spawnSpotHost(h, ec2Settings, blockDevices)
+		if err != nil {
+			msg := "error spawning spot host"
+			grip.Error(message.WrapError(err, message.Fields{
+				"message":       msg,
+				"host":          h.Id,
+				"host_provider": h.Distro.Provider,
+				"distro":        h.Distro.Id,
+				"instance_type": ec2Settings.InstanceType,
+			}))
+			return nil, errors.Wrap(err, msg)
+		}
+		grip.Debug(message.Fields{
+			"message":       "spawned spot host",
+			"host":          h.Id,
+			"host_provider": h.Distro.Provider,
+			"distro":        h.Distro.Id,
+			"instance_type": ec2Settings.InstanceType,
+		})
+	}
+	return resources, nil
+}
+
 // CanSpawn indicates if a host can be spawned.
 func (m *ec2Manager) CanSpawn() (bool, error) {
 	return true, nil
@@ -497,6 +558,221 @@ func (m *ec2Manager) TerminateInstance(h *host.Host, user string) error {
 	return errors.Wrap(h.Terminate(user), "failed to terminate instance in db")
 }
 
+// StopInstance stops the EC2 instance. Only on-demand instances can be
+// stopped.
+func (m *ec2Manager) StopInstance(h *host.Host, user string) error {
+	if !isHostOnDemand(h) {
+		return errors.Errorf("can't stop host %s: only on-demand instances can be stopped", h.Id)
+	}
+
+	if err := m.client.Create(m.credentials); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	_, err := m.client.StopInstances(&ec2.StopInstancesInput{
+		InstanceIds: []*string{makeStringPtr(h.Id)},
+	})
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":       "error stopping instance",
+			"user":          user,
+			"host":          h.Id,
+			"host_provider": h.Distro.Provider,
+			"distro":        h.Distro.Id,
+		}))
+		return errors.Wrapf(err, "error stopping instance %s", h.Id)
+	}
+
+	grip.Info(message.Fields{
+		"message":       "stopped instance",
+		"user":          user,
+		"host":          h.Id,
+		"host_provider": h.Distro.Provider,
+		"distro":        h.Distro.Id,
+	})
+
+	return errors.Wrap(h.SetStopped(user), "failed to mark instance as stopped in db")
+}
+
+// StartInstance starts the stopped EC2 instance. An instance gets a new
+// public DNS name when it is started, so this waits for the instance to be
+// running and updates the host's DNS name.
+func (m *ec2Manager) StartInstance(h *host.Host, user string) error {
+	if !isHostOnDemand(h) {
+		return errors.Errorf("can't start host %s: only on-demand instances can be started", h.Id)
+	}
+
+	if err := m.client.Create(m.credentials); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	_, err := m.client.StartInstances(&ec2.StartInstancesInput{
+		InstanceIds: []*string{makeStringPtr(h.Id)},
+	})
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":       "error starting instance",
+			"user":          user,
+			"host":          h.Id,
+			"host_provider": h.Distro.Provider,
+			"distro":        h.Distro.Id,
+		}))
+		return errors.Wrapf(err, "error starting instance %s", h.Id)
+	}
+
+	var instance *ec2.Instance
+	_, err = util.Retry(
+		func() (bool, error) {
+			instance, err = m.client.GetInstanceInfo(h.Id)
+			if err != nil {
+				return false, errors.Wrap(err, "error getting instance info")
+			}
+			if ec2StatusToEvergreenStatus(*instance.State.Name) != StatusRunning || *instance.PublicDnsName == "" {
+				return true, errors.Errorf("instance %s is not yet running", h.Id)
+			}
+			return false, nil
+		}, startInstanceRetries, startInstanceStartPeriod)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = h.ResetDNSName(*instance.PublicDnsName); err != nil {
+		return errors.Wrap(err, "failed to update DNS name in db")
+	}
+
+	grip.Info(message.Fields{
+		"message":       "started instance",
+		"user":          user,
+		"host":          h.Id,
+		"host_provider": h.Distro.Provider,
+		"distro":        h.Distro.Id,
+		"dns_name":      h.Host,
+	})
+
+	return errors.Wrap(h.SetRunning(user), "failed to mark instance as running in db")
+}
+
+// CreateVolume creates an EBS volume in the availability zone of the
+// instance.
+func (m *ec2Manager) CreateVolume(h *host.Host, volume *host.Volume) (*host.Volume, error) {
+	if err := m.client.Create(m.credentials); err != nil {
+		return nil, errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	instance, err := m.client.GetInstanceInfo(h.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting instance info")
+	}
+
+	resp, err := m.client.CreateVolume(&ec2.CreateVolumeInput{
+		AvailabilityZone: instance.Placement.AvailabilityZone,
+		Size:             makeInt64Ptr(int64(volume.Size)),
+		VolumeType:       makeStringPtr(ec2.VolumeTypeGp2),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating volume for host %s", h.Id)
+	}
+
+	volume.ID = *resp.VolumeId
+	volume.AvailabilityZone = *resp.AvailabilityZone
+
+	return volume, nil
+}
+
+// placeInHomeVolumeZone restricts the candidates to subnets in the
+// availability zone of the existing home volume that a spawn host attaches,
+// since EBS volumes can only be attached to instances in the same zone. The
+// subnet in the zone is looked up by the distro's VPC name if it has one, and
+// otherwise only the candidates whose subnets are in the zone are kept.
+func (m *ec2Manager) placeInHomeVolumeZone(h *host.Host, ec2Settings *EC2ProviderSettings, candidates fleetCandidates) (fleetCandidates, error) {
+	if h.ProvisionOptions == nil || h.ProvisionOptions.HomeVolumeSize <= 0 {
+		return candidates, nil
+	}
+	volume, err := host.FindOneVolume(host.VolumeByUser(h.StartedBy, h.Provider))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding home volume for user %s", h.StartedBy)
+	}
+	if volume == nil || volume.AvailabilityZone == "" {
+		// a new volume is created in the host's zone
+		return candidates, nil
+	}
+	if !ec2Settings.IsVpc {
+		return nil, errors.Errorf("hosts of distro %s are not in a VPC and cannot be placed in availability zone %s of volume %s",
+			h.Distro.Id, volume.AvailabilityZone, volume.ID)
+	}
+
+	placed := make(fleetCandidates, 0, len(candidates))
+	if ec2Settings.VpcName != "" {
+		subnetID, err := m.getSubnetForAZ(volume.AvailabilityZone, ec2Settings.VpcName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error finding subnet in availability zone %s", volume.AvailabilityZone)
+		}
+		for _, c := range candidates {
+			c.subnetID = subnetID
+			placed = append(placed, c)
+		}
+		return placed, nil
+	}
+
+	subnetIDs := []*string{}
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if !seen[c.subnetID] {
+			seen[c.subnetID] = true
+			subnetIDs = append(subnetIDs, makeStringPtr(c.subnetID))
+		}
+	}
+	resp, err := m.client.DescribeSubnets(&ec2.DescribeSubnetsInput{SubnetIds: subnetIDs})
+	if err != nil {
+		return nil, errors.Wrap(err, "error describing subnets")
+	}
+	inZone := map[string]bool{}
+	for _, subnet := range resp.Subnets {
+		if subnet.SubnetId != nil && subnet.AvailabilityZone != nil && *subnet.AvailabilityZone == volume.AvailabilityZone {
+			inZone[*subnet.SubnetId] = true
+		}
+	}
+	for _, c := range candidates {
+		if inZone[c.subnetID] {
+			placed = append(placed, c)
+		}
+	}
+	if len(placed) == 0 {
+		return nil, errors.Errorf("distro %s has no subnet in availability zone %s of volume %s",
+			h.Distro.Id, volume.AvailabilityZone, volume.ID)
+	}
+	return placed, nil
+}
+
+// AttachVolume attaches the EBS volume to the instance. EBS volumes can only
+// be attached to instances in the same availability zone.
+func (m *ec2Manager) AttachVolume(h *host.Host, volume *host.Volume) error {
+	if err := m.client.Create(m.credentials); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	instance, err := m.client.GetInstanceInfo(h.Id)
+	if err != nil {
+		return errors.Wrap(err, "error getting instance info")
+	}
+	if *instance.Placement.AvailabilityZone != volume.AvailabilityZone {
+		return errors.Errorf("volume %s is in availability zone %s, but host %s is in %s",
+			volume.ID, volume.AvailabilityZone, h.Id, *instance.Placement.AvailabilityZone)
+	}
+
+	_, err = m.client.AttachVolume(&ec2.AttachVolumeInput{
+		Device:     makeStringPtr(homeVolumeDeviceName),
+		InstanceId: makeStringPtr(h.Id),
+		VolumeId:   makeStringPtr(volume.ID),
+	})
+
+	return errors.Wrapf(err, "error attaching volume %s to host %s", volume.ID, h.Id)
+}
+
 func (m *ec2Manager) cancelSpotRequest(h *host.Host) (string, error) {
 	spotDetails, err := m.client.DescribeSpotInstanceRequests(&ec2.DescribeSpotInstanceRequestsInput{
 		SpotInstanceRequestIds: []*string{makeStringPtr(h.Id)},
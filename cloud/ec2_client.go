@@ -51,6 +51,18 @@ type AWSClient interface {
 	// DescribeVolumes is a wrapper for ec2.DescribeVolumes.
 	DescribeVolumes(*ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
 
+	// StopInstances is a wrapper for ec2.StopInstances.
+	StopInstances(*ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error)
+
+	// StartInstances is a wrapper for ec2.StartInstances.
+	StartInstances(*ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error)
+
+	// CreateVolume is a wrapper for ec2.CreateVolume.
+	CreateVolume(*ec2.CreateVolumeInput) (*ec2.Volume, error)
+
+	// AttachVolume is a wrapper for ec2.AttachVolume.
+	AttachVolume(*ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error)
+
 	// DescribeSpotPriceHistory is a wrapper for ec2.DescribeSpotPriceHistory.
 	DescribeSpotPriceHistory(*ec2.DescribeSpotPriceHistoryInput) (*ec2.DescribeSpotPriceHistoryOutput, error)
 
@@ -354,6 +366,126 @@ func (c *awsClientImpl) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.D
 	return output, nil
 }
 
+// StopInstances is a wrapper for ec2.StopInstances.
+func (c *awsClientImpl) StopInstances(input *ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error) {
+	var output *ec2.StopInstancesOutput
+	var err error
+	grip.Debug(message.Fields{
+		"client":  fmt.Sprintf("%T", c),
+		"message": "running StopInstances",
+		"args":    input,
+	})
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.StopInstances(input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, message.Fields{
+						"client":  fmt.Sprintf("%T", c),
+						"message": "error running StopInstances",
+						"args":    input,
+					}))
+				}
+				return true, err
+			}
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// StartInstances is a wrapper for ec2.StartInstances.
+func (c *awsClientImpl) StartInstances(input *ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error) {
+	var output *ec2.StartInstancesOutput
+	var err error
+	grip.Debug(message.Fields{
+		"client":  fmt.Sprintf("%T", c),
+		"message": "running StartInstances",
+		"args":    input,
+	})
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.StartInstances(input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, message.Fields{
+						"client":  fmt.Sprintf("%T", c),
+						"message": "error running StartInstances",
+						"args":    input,
+					}))
+				}
+				return true, err
+			}
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// CreateVolume is a wrapper for ec2.CreateVolume.
+func (c *awsClientImpl) CreateVolume(input *ec2.CreateVolumeInput) (*ec2.Volume, error) {
+	var output *ec2.Volume
+	var err error
+	grip.Debug(message.Fields{
+		"client":  fmt.Sprintf("%T", c),
+		"message": "running CreateVolume",
+		"args":    input,
+	})
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.CreateVolume(input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, message.Fields{
+						"client":  fmt.Sprintf("%T", c),
+						"message": "error running CreateVolume",
+						"args":    input,
+					}))
+				}
+				return true, err
+			}
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// AttachVolume is a wrapper for ec2.AttachVolume.
+func (c *awsClientImpl) AttachVolume(input *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error) {
+	var output *ec2.VolumeAttachment
+	var err error
+	grip.Debug(message.Fields{
+		"client":  fmt.Sprintf("%T", c),
+		"message": "running AttachVolume",
+		"args":    input,
+	})
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.AttachVolume(input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, message.Fields{
+						"client":  fmt.Sprintf("%T", c),
+						"message": "error running AttachVolume",
+						"args":    input,
+					}))
+				}
+				return true, err
+			}
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
 // DescribeSpotPriceHistory is a wrapper for ec2.DescribeSpotPriceHistory.
 func (c *awsClientImpl) DescribeSpotPriceHistory(input *ec2.DescribeSpotPriceHistoryInput) (*ec2.DescribeSpotPriceHistoryOutput, error) {
 	var output *ec2.DescribeSpotPriceHistoryOutput
@@ -481,9 +613,19 @@ type awsClientMock struct { //nolint
 	*ec2.DescribeSpotInstanceRequestsInput
 	*ec2.CancelSpotInstanceRequestsInput
 	*ec2.DescribeVolumesInput
+	*ec2.StopInstancesInput
+	*ec2.StartInstancesInput
+	*ec2.CreateVolumeInput
+	*ec2.AttachVolumeInput
 	*ec2.DescribeSpotPriceHistoryInput
 	*ec2.DescribeSubnetsInput
 	*ec2.DescribeVpcsInput
+
+	// runInstancesErrors are returned by RunInstances for the instance type.
+	runInstancesErrors map[string]error
+	// spotRequestStatusCodes are the status codes of spot requests for the
+	// instance type.
+	spotRequestStatusCodes map[string]string
 }
 
 // Create a new mock client.
@@ -497,6 +639,9 @@ func (c *awsClientMock) Close() {}
 // RunInstances is a mock for ec2.RunInstances.
 func (c *awsClientMock) RunInstances(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
 	c.RunInstancesInput = input
+	if err, ok := c.runInstancesErrors[*input.InstanceType]; ok {
+		return nil, err
+	}
 	return &ec2.Reservation{
 		Instances: []*ec2.Instance{
 			&ec2.Instance{
@@ -557,7 +702,7 @@ func (c *awsClientMock) RequestSpotInstances(input *ec2.RequestSpotInstancesInpu
 // DescribeSpotInstanceRequests is a mock for ec2.DescribeSpotInstanceRequests.
 func (c *awsClientMock) DescribeSpotInstanceRequests(input *ec2.DescribeSpotInstanceRequestsInput) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
 	c.DescribeSpotInstanceRequestsInput = input
-	return &ec2.DescribeSpotInstanceRequestsOutput{
+	output := &ec2.DescribeSpotInstanceRequestsOutput{
 		SpotInstanceRequests: []*ec2.SpotInstanceRequest{
 			&ec2.SpotInstanceRequest{
 				InstanceId: makeStringPtr("instance_id"),
@@ -565,7 +710,13 @@ func (c *awsClientMock) DescribeSpotInstanceRequests(input *ec2.DescribeSpotInst
 				SpotInstanceRequestId: makeStringPtr("instance_id"),
 			},
 		},
-	}, nil
+	}
+	if c.RequestSpotInstancesInput != nil {
+		if code, ok := c.spotRequestStatusCodes[*c.RequestSpotInstancesInput.LaunchSpecification.InstanceType]; ok {
+			output.SpotInstanceRequests[0].Status = &ec2.SpotInstanceStatus{Code: makeStringPtr(code)}
+		}
+	}
+	return output, nil
 }
 
 // CancelSpotInstanceRequests is a mock for ec2.CancelSpotInstanceRequests.
@@ -580,6 +731,34 @@ func (c *awsClientMock) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.D
 	return &ec2.DescribeVolumesOutput{}, nil
 }
 
+// StopInstances is a mock for ec2.StopInstances.
+func (c *awsClientMock) StopInstances(input *ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error) {
+	c.StopInstancesInput = input
+	return &ec2.StopInstancesOutput{}, nil
+}
+
+// StartInstances is a mock for ec2.StartInstances.
+func (c *awsClientMock) StartInstances(input *ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error) {
+	c.StartInstancesInput = input
+	return &ec2.StartInstancesOutput{}, nil
+}
+
+// CreateVolume is a mock for ec2.CreateVolume.
+func (c *awsClientMock) CreateVolume(input *ec2.CreateVolumeInput) (*ec2.Volume, error) {
+	c.CreateVolumeInput = input
+	return &ec2.Volume{
+		VolumeId:         makeStringPtr("volume_id"),
+		AvailabilityZone: input.AvailabilityZone,
+		Size:             input.Size,
+	}, nil
+}
+
+// AttachVolume is a mock for ec2.AttachVolume.
+func (c *awsClientMock) AttachVolume(input *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error) {
+	c.AttachVolumeInput = input
+	return &ec2.VolumeAttachment{}, nil
+}
+
 // DescribeSpotPriceHistory is a mock for ec2.DescribeSpotPriceHistory.
 func (c *awsClientMock) DescribeSpotPriceHistory(input *ec2.DescribeSpotPriceHistoryInput) (*ec2.DescribeSpotPriceHistoryOutput, error) {
 	c.DescribeSpotPriceHistoryInput = input
@@ -599,7 +778,8 @@ func (c *awsClientMock) DescribeSubnets(input *ec2.DescribeSubnetsInput) (*ec2.D
 	return &ec2.DescribeSubnetsOutput{
 		Subnets: []*ec2.Subnet{
 			&ec2.Subnet{
-				SubnetId: makeStringPtr("subnet-654321"),
+				SubnetId:         makeStringPtr("subnet-654321"),
+				AvailabilityZone: makeStringPtr("us-east-1a"),
 			},
 		},
 	}, nil
@@ -0,0 +1,244 @@
+package cloud
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// EC2FleetOption describes an alternative instance type, and optionally the
+// subnet to start it in, that is acceptable for hosts of a distro.
+type EC2FleetOption struct {
+	// InstanceType is the EC2 instance type.
+	InstanceType string `mapstructure:"instance_type" json:"instance_type" bson:"instance_type"`
+
+	// SubnetId, if set, is used instead of the distro's subnet, which
+	// places the host in that subnet's availability zone.
+	SubnetId string `mapstructure:"subnet_id" json:"subnet_id,omitempty" bson:"subnet_id,omitempty"`
+
+	// AvailabilityZone is the availability zone of the subnet. It is used
+	// to look up the spot price in that zone.
+	AvailabilityZone string `mapstructure:"availability_zone" json:"availability_zone,omitempty" bson:"availability_zone,omitempty"`
+
+	// Weight is the capacity of this instance type relative to the others.
+	// Options are tried in order of price per unit of weight. Defaults to 1.
+	Weight float64 `mapstructure:"weight" json:"weight,omitempty" bson:"weight,omitempty"`
+}
+
+// Validate checks that the fleet option is usable.
+func (o *EC2FleetOption) Validate() error {
+	if o.InstanceType == "" {
+		return errors.New("fleet options must specify an instance type")
+	}
+	if o.Weight < 0 {
+		return errors.Errorf("weight for instance type %s must not be negative", o.InstanceType)
+	}
+	return nil
+}
+
+// ec2CapacityErrorCodes are the EC2 API error codes which indicate that the
+// requested instance type is not available right now, rather than that the
+// request itself is invalid, so that a different instance type may succeed.
+var ec2CapacityErrorCodes = map[string]bool{
+	"InsufficientInstanceCapacity":      true,
+	"InsufficientHostCapacity":          true,
+	"InsufficientCapacity":              true,
+	"InsufficientFreeAddressesInSubnet": true,
+	"InstanceLimitExceeded":             true,
+	"MaxSpotInstanceCountExceeded":      true,
+	"SpotMaxPriceTooLow":                true,
+}
+
+// ec2SpotCapacityStatusCodes are the spot request status codes which indicate
+// that EC2 cannot fulfill the request with its instance type, subnet or bid
+// right now, so that a different instance type may succeed.
+var ec2SpotCapacityStatusCodes = map[string]bool{
+	"capacity-not-available":     true,
+	"capacity-oversubscribed":    true,
+	"price-too-low":              true,
+	"az-group-constraint":        true,
+	"placement-group-constraint": true,
+	"constraint-not-fulfillable": true,
+}
+
+const spotStatusPendingEvaluation = "pending-evaluation"
+
+var (
+	// spotEvaluationPollInterval is how often a spot request is checked
+	// while EC2 evaluates it.
+	spotEvaluationPollInterval = 2 * time.Second
+	// spotEvaluationTimeout is how long to wait for EC2 to evaluate a spot
+	// request before assuming that it will be fulfilled.
+	spotEvaluationTimeout = 30 * time.Second
+)
+
+// spotCapacityError reports that EC2 could not fulfill a spot request.
+type spotCapacityError struct {
+	requestID string
+	code      string
+}
+
+func (e *spotCapacityError) Error() string {
+	return fmt.Sprintf("spot request %s cannot be fulfilled: %s", e.requestID, e.code)
+}
+
+func isEC2CapacityError(err error) bool {
+	switch cause := errors.Cause(err).(type) {
+	case awserr.Error:
+		return ec2CapacityErrorCodes[cause.Code()]
+	case *spotCapacityError:
+		return true
+	}
+	return false
+}
+
+// checkSpotRequestCapacity waits for EC2 to evaluate the spot request, since
+// spot requests report a lack of capacity in their status rather than by
+// failing. If EC2 cannot fulfill the request, it is canceled and a capacity
+// error is returned.
+func (m *ec2Manager) checkSpotRequestCapacity(id string) error {
+	deadline := time.Now().Add(spotEvaluationTimeout)
+	for {
+		resp, err := m.client.DescribeSpotInstanceRequests(&ec2.DescribeSpotInstanceRequestsInput{
+			SpotInstanceRequestIds: []*string{makeStringPtr(id)},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get spot request info for %s", id)
+		}
+		if len(resp.SpotInstanceRequests) == 0 {
+			return errors.Errorf("spot request %s not found", id)
+		}
+
+		var code string
+		if status := resp.SpotInstanceRequests[0].Status; status != nil && status.Code != nil {
+			code = *status.Code
+		}
+		if ec2SpotCapacityStatusCodes[code] {
+			if _, err = m.client.CancelSpotInstanceRequests(&ec2.CancelSpotInstanceRequestsInput{
+				SpotInstanceRequestIds: []*string{makeStringPtr(id)},
+			}); err != nil {
+				return errors.Wrapf(err, "error canceling unfulfillable spot request %s", id)
+			}
+			return &spotCapacityError{requestID: id, code: code}
+		}
+		if code != spotStatusPendingEvaluation || time.Now().After(deadline) {
+			return nil
+		}
+
+		time.Sleep(spotEvaluationPollInterval)
+	}
+}
+
+// fleetCandidate is a concrete instance type and subnet that the manager
+// attempts to start a host with.
+type fleetCandidate struct {
+	instanceType string
+	subnetID     string
+	bidPrice     float64
+	unitPrice    float64
+}
+
+type fleetCandidates []fleetCandidate
+
+func (c fleetCandidates) Len() int           { return len(c) }
+func (c fleetCandidates) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c fleetCandidates) Less(i, j int) bool { return c[i].unitPrice < c[j].unitPrice }
+
+// getFleetCandidates returns the instance types and subnets to try, in
+// order. Without fleet options this is only the distro's instance type. With
+// them, the distro's instance type is treated as an option of weight 1 and
+// all options are ordered by price per unit of weight; options whose price
+// cannot be determined are tried last.
+func (m *ec2Manager) getFleetCandidates(h *host.Host, ec2Settings *EC2ProviderSettings, provider ec2ProviderType) fleetCandidates {
+	primary := fleetCandidate{
+		instanceType: ec2Settings.InstanceType,
+		subnetID:     ec2Settings.SubnetId,
+		bidPrice:     ec2Settings.BidPrice,
+	}
+	if len(ec2Settings.FleetOptions) == 0 {
+		return fleetCandidates{primary}
+	}
+
+	options := append([]EC2FleetOption{{
+		InstanceType: ec2Settings.InstanceType,
+		SubnetId:     ec2Settings.SubnetId,
+		Weight:       1,
+	}}, ec2Settings.FleetOptions...)
+
+	candidates := make(fleetCandidates, 0, len(options))
+	for _, opt := range options {
+		c := fleetCandidate{
+			instanceType: opt.InstanceType,
+			subnetID:     opt.SubnetId,
+			bidPrice:     ec2Settings.BidPrice,
+		}
+		if c.subnetID == "" {
+			c.subnetID = ec2Settings.SubnetId
+		}
+		weight := opt.Weight
+		if weight == 0 {
+			weight = 1
+		}
+
+		price, err := m.getFleetOptionPrice(h, ec2Settings, opt, provider, &c)
+		if err != nil || price <= 0 {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message":       "could not determine price of instance type, trying it last",
+				"instance_type": opt.InstanceType,
+				"host":          h.Id,
+				"distro":        h.Distro.Id,
+			}))
+			c.unitPrice = math.MaxFloat64
+		} else {
+			c.unitPrice = price / weight
+		}
+		candidates = append(candidates, c)
+	}
+
+	sort.Stable(candidates)
+
+	return candidates
+}
+
+// getFleetOptionPrice returns the current hourly price of the option. For spot
+// hosts started by the auto provider it also sets the candidate's bid to the
+// on-demand price of its own instance type, and for spot hosts in a VPC
+// without an explicit subnet it picks the subnet in the cheapest zone.
+func (m *ec2Manager) getFleetOptionPrice(h *host.Host, ec2Settings *EC2ProviderSettings, opt EC2FleetOption, provider ec2ProviderType, c *fleetCandidate) (float64, error) {
+	os := getOsName(h)
+	if provider != spotProvider {
+		return pkgCachingPriceFetcher.getEC2OnDemandCost(os, opt.InstanceType, defaultRegion)
+	}
+
+	if m.provider == autoProvider {
+		onDemandPrice, err := pkgCachingPriceFetcher.getEC2OnDemandCost(os, opt.InstanceType, defaultRegion)
+		if err != nil {
+			return 0, errors.Wrap(err, "error getting ec2 on-demand cost")
+		}
+		c.bidPrice = onDemandPrice
+	}
+
+	settings := *ec2Settings
+	settings.InstanceType = opt.InstanceType
+	spotPrice, az, err := pkgCachingPriceFetcher.getLatestLowestSpotCostForInstance(m.client, &settings, os, opt.AvailabilityZone)
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting latest lowest spot price")
+	}
+	if opt.SubnetId == "" && ec2Settings.VpcName != "" {
+		subnetID, err := m.getSubnetForAZ(az, ec2Settings.VpcName)
+		if err != nil {
+			return 0, errors.Wrap(err, "error finding subnet for spot price availability zone")
+		}
+		c.subnetID = subnetID
+	}
+
+	return spotPrice, nil
+}
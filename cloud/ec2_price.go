@@ -140,7 +140,10 @@ func (cpf *cachingPriceFetcher) cacheEc2Prices() error {
 	return nil
 }
 
-func (cpf *cachingPriceFetcher) getLatestLowestSpotCostForInstance(client AWSClient, settings *EC2ProviderSettings, os osType) (float64, string, error) {
+// getLatestLowestSpotCostForInstance returns the lowest current spot price for
+// the instance type and the availability zone it is offered in. If az is
+// set, only prices in that zone are considered.
+func (cpf *cachingPriceFetcher) getLatestLowestSpotCostForInstance(client AWSClient, settings *EC2ProviderSettings, os osType, az string) (float64, string, error) {
 	cpf.Lock()
 	defer cpf.Unlock()
 	osName := string(os)
@@ -152,12 +155,16 @@ func (cpf *cachingPriceFetcher) getLatestLowestSpotCostForInstance(client AWSCli
 		"instance_type": settings.InstanceType,
 		"os_name":       osName,
 	})
-	prices, err := client.DescribeSpotPriceHistory(&ec2.DescribeSpotPriceHistoryInput{
+	input := &ec2.DescribeSpotPriceHistoryInput{
 		// passing a future start time gets the latest price only
 		StartTime:           makeTimePtr(time.Now().UTC().Add(24 * time.Hour)),
 		InstanceTypes:       []*string{makeStringPtr(settings.InstanceType)},
 		ProductDescriptions: []*string{makeStringPtr(osName)},
-	})
+	}
+	if az != "" {
+		input.AvailabilityZone = makeStringPtr(az)
+	}
+	prices, err := client.DescribeSpotPriceHistory(input)
 	if err != nil {
 		return 0, "", errors.Wrap(err, "error getting spot price history")
 	}
@@ -165,7 +172,7 @@ func (cpf *cachingPriceFetcher) getLatestLowestSpotCostForInstance(client AWSCli
 		return 0, "", errors.New("no prices found")
 	}
 	var min float64
-	var az string
+	var minAZ string
 	for i := range prices.SpotPriceHistory {
 		p, err := strconv.ParseFloat(*prices.SpotPriceHistory[i].SpotPrice, 0)
 		if err != nil {
@@ -173,10 +180,10 @@ func (cpf *cachingPriceFetcher) getLatestLowestSpotCostForInstance(client AWSCli
 		}
 		if min == 0 || p < min {
 			min = p
-			az = *prices.SpotPriceHistory[i].AvailabilityZone
+			minAZ = *prices.SpotPriceHistory[i].AvailabilityZone
 		}
 	}
-	return min, az, nil
+	return min, minAZ, nil
 }
 
 func (m *ec2Manager) getProvider(h *host.Host, ec2settings *EC2ProviderSettings) (ec2ProviderType, error) {
@@ -198,7 +205,7 @@ func (m *ec2Manager) getProvider(h *host.Host, ec2settings *EC2ProviderSettings)
 			return 0, errors.Wrap(err, "error getting ec2 on-demand cost")
 		}
 
-		spotPrice, az, err := pkgCachingPriceFetcher.getLatestLowestSpotCostForInstance(m.client, ec2settings, getOsName(h))
+		spotPrice, az, err := pkgCachingPriceFetcher.getLatestLowestSpotCostForInstance(m.client, ec2settings, getOsName(h), "")
 		if err != nil {
 			return 0, errors.Wrap(err, "error getting latest lowest spot price")
 		}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/distro"
@@ -414,6 +415,113 @@ func (s *EC2Suite) TestTerminateInstance() {
 	s.NoError(err)
 }
 
+func (s *EC2Suite) TestStopInstance() {
+	h := &host.Host{Id: "host_id", Status: evergreen.HostRunning}
+	h.Distro.Provider = evergreen.ProviderNameEc2OnDemand
+	s.NoError(h.Insert())
+	s.NoError(s.impl.StopInstance(h, evergreen.User))
+
+	mock, ok := s.onDemandOpts.client.(*awsClientMock)
+	s.True(ok)
+	s.Require().NotNil(mock.StopInstancesInput)
+	s.Equal("host_id", *mock.StopInstancesInput.InstanceIds[0])
+
+	found, err := host.FindOne(host.ById("host_id"))
+	s.NoError(err)
+	s.Equal(evergreen.HostStopped, found.Status)
+
+	h.Distro.Provider = evergreen.ProviderNameEc2Spot
+	s.Error(s.impl.StopInstance(h, evergreen.User))
+}
+
+func (s *EC2Suite) TestStartInstance() {
+	h := &host.Host{Id: "host_id", Status: evergreen.HostStopped, Host: "old_dns_name"}
+	h.Distro.Provider = evergreen.ProviderNameEc2OnDemand
+	s.NoError(h.Insert())
+	s.NoError(s.impl.StartInstance(h, evergreen.User))
+
+	mock, ok := s.onDemandOpts.client.(*awsClientMock)
+	s.True(ok)
+	s.Require().NotNil(mock.StartInstancesInput)
+	s.Equal("host_id", *mock.StartInstancesInput.InstanceIds[0])
+
+	found, err := host.FindOne(host.ById("host_id"))
+	s.NoError(err)
+	s.Equal(evergreen.HostRunning, found.Status)
+	s.Equal("public_dns_name", found.Host)
+}
+
+func (s *EC2Suite) TestCreateAndAttachVolume() {
+	h := &host.Host{Id: "host_id"}
+	volume, err := s.impl.CreateVolume(h, &host.Volume{Size: 100})
+	s.NoError(err)
+	s.Equal("volume_id", volume.ID)
+	s.Equal("us-east-1a", volume.AvailabilityZone)
+
+	mock, ok := s.onDemandOpts.client.(*awsClientMock)
+	s.True(ok)
+	s.Require().NotNil(mock.CreateVolumeInput)
+	s.EqualValues(100, *mock.CreateVolumeInput.Size)
+
+	s.NoError(s.impl.AttachVolume(h, volume))
+	s.Require().NotNil(mock.AttachVolumeInput)
+	s.Equal("host_id", *mock.AttachVolumeInput.InstanceId)
+	s.Equal("volume_id", *mock.AttachVolumeInput.VolumeId)
+
+	volume.AvailabilityZone = "us-west-1a"
+	s.Error(s.impl.AttachVolume(h, volume))
+}
+
+func (s *EC2Suite) TestSpawnHostInHomeVolumeZone() {
+	s.Require().NoError(db.Clear(host.VolumesCollection))
+	volume := &host.Volume{
+		ID:               "volume_id",
+		CreatedBy:        "user",
+		Provider:         evergreen.ProviderNameEc2OnDemand,
+		AvailabilityZone: "us-west-2b",
+	}
+	s.Require().NoError(volume.Insert())
+
+	settings := map[string]interface{}{
+		"ami":            "ami",
+		"instance_type":  "instanceType",
+		"key_name":       "keyName",
+		"security_group": "sg-123456",
+		"subnet_id":      "subnet-123456",
+		"is_vpc":         true,
+	}
+	h := &host.Host{
+		StartedBy:        "user",
+		Provider:         evergreen.ProviderNameEc2OnDemand,
+		ProvisionOptions: &host.ProvisionOptions{HomeVolumeSize: 10},
+	}
+	h.Distro.Id = "distro_id"
+	h.Distro.Provider = evergreen.ProviderNameEc2OnDemand
+	h.Distro.ProviderSettings = &settings
+
+	mock, ok := s.onDemandOpts.client.(*awsClientMock)
+	s.Require().True(ok)
+
+	// the distro's only subnet is in another zone
+	_, err := s.onDemandManager.SpawnHost(h)
+	s.Error(err)
+	s.Nil(mock.RunInstancesInput)
+
+	// the subnet in the volume's zone is found by the VPC name
+	settings["vpc_name"] = "vpc"
+	_, err = s.onDemandManager.SpawnHost(h)
+	s.Require().NoError(err)
+	s.Equal("us-west-2b", *mock.DescribeSubnetsInput.Filters[1].Values[0])
+	s.Equal("subnet-654321", *mock.RunInstancesInput.NetworkInterfaces[0].SubnetId)
+
+	// hosts without a volume are placed as usual
+	s.Require().NoError(db.Clear(host.VolumesCollection))
+	delete(settings, "vpc_name")
+	_, err = s.onDemandManager.SpawnHost(h)
+	s.Require().NoError(err)
+	s.Equal("subnet-123456", *mock.RunInstancesInput.NetworkInterfaces[0].SubnetId)
+}
+
 func (s *EC2Suite) TestIsUp() {
 	h := &host.Host{
 		Distro: distro.Distro{},
@@ -520,3 +628,139 @@ func (s *EC2Suite) TestGetProvider() {
 	s.NoError(err)
 	s.Equal(onDemandProvider, provider)
 }
+
+func (s *EC2Suite) TestValidateFleetOptions() {
+	p := &EC2ProviderSettings{
+		AMI:           "ami",
+		InstanceType:  "type",
+		SecurityGroup: "sg-123456",
+		KeyName:       "keyName",
+		FleetOptions: []EC2FleetOption{
+			{InstanceType: "other", Weight: 2},
+		},
+	}
+	s.NoError(p.Validate())
+
+	p.FleetOptions = append(p.FleetOptions, EC2FleetOption{Weight: 1})
+	s.Error(p.Validate())
+
+	p.FleetOptions[1] = EC2FleetOption{InstanceType: "third", Weight: -1}
+	s.Error(p.Validate())
+}
+
+func (s *EC2Suite) TestGetFleetCandidates() {
+	h := &host.Host{
+		Distro: distro.Distro{
+			Arch: "Linux/Unix",
+		},
+	}
+	region := "US East (N. Virginia)"
+	pkgCachingPriceFetcher.ec2Prices = map[odInfo]float64{
+		odInfo{os: "Linux", instance: "m4.large", region: region}:   0.10,
+		odInfo{os: "Linux", instance: "m4.xlarge", region: region}:  0.16,
+		odInfo{os: "Linux", instance: "c4.2xlarge", region: region}: 0.40,
+	}
+	ec2Settings := &EC2ProviderSettings{
+		InstanceType: "m4.large",
+		SubnetId:     "subnet-default",
+	}
+
+	// without fleet options only the distro's instance type is used
+	candidates := s.impl.getFleetCandidates(h, ec2Settings, onDemandProvider)
+	s.Require().Len(candidates, 1)
+	s.Equal("m4.large", candidates[0].instanceType)
+
+	ec2Settings.FleetOptions = []EC2FleetOption{
+		{InstanceType: "c4.2xlarge", Weight: 4},
+		{InstanceType: "unpriced"},
+		{InstanceType: "m4.xlarge", Weight: 2, SubnetId: "subnet-other"},
+	}
+	candidates = s.impl.getFleetCandidates(h, ec2Settings, onDemandProvider)
+	s.Require().Len(candidates, 4)
+	s.Equal("m4.xlarge", candidates[0].instanceType)
+	s.Equal("subnet-other", candidates[0].subnetID)
+	s.Equal("m4.large", candidates[1].instanceType)
+	s.Equal("subnet-default", candidates[1].subnetID)
+	s.Equal("c4.2xlarge", candidates[2].instanceType)
+	s.Equal("unpriced", candidates[3].instanceType)
+}
+
+func (s *EC2Suite) TestSpawnHostFleetFallsBackOnCapacityErrors() {
+	h := &host.Host{}
+	h.Distro.Id = "distro_id"
+	h.Distro.Arch = "Linux/Unix"
+	h.Distro.Provider = evergreen.ProviderNameEc2OnDemand
+	h.Distro.ProviderSettings = &map[string]interface{}{
+		"ami":            "ami",
+		"instance_type":  "m4.large",
+		"key_name":       "keyName",
+		"security_group": "sg-123456",
+		"fleet_options": []map[string]interface{}{
+			map[string]interface{}{"instance_type": "m4.xlarge", "weight": 2},
+		},
+	}
+	region := "US East (N. Virginia)"
+	pkgCachingPriceFetcher.ec2Prices = map[odInfo]float64{
+		odInfo{os: "Linux", instance: "m4.large", region: region}:  0.10,
+		odInfo{os: "Linux", instance: "m4.xlarge", region: region}: 0.16,
+	}
+
+	mock, ok := s.impl.client.(*awsClientMock)
+	s.Require().True(ok)
+	mock.runInstancesErrors = map[string]error{
+		"m4.xlarge": awserr.New("InsufficientInstanceCapacity", "no capacity", nil),
+	}
+
+	_, err := s.onDemandManager.SpawnHost(h)
+	s.NoError(err)
+	s.Equal("m4.large", *mock.RunInstancesInput.InstanceType)
+	s.Equal("m4.large", h.InstanceType)
+
+	// errors other than capacity errors are not retried
+	mock.runInstancesErrors["m4.xlarge"] = awserr.New("InvalidParameterValue", "bad request", nil)
+	_, err = s.onDemandManager.SpawnHost(h)
+	s.Error(err)
+	s.Equal("m4.xlarge", *mock.RunInstancesInput.InstanceType)
+}
+
+func (s *EC2Suite) TestSpawnHostFleetFallsBackOnSpotCapacityStatus() {
+	h := &host.Host{}
+	h.Distro.Id = "distro_id"
+	h.Distro.Arch = "Linux/Unix"
+	h.Distro.Provider = evergreen.ProviderNameEc2Spot
+	h.Distro.ProviderSettings = &map[string]interface{}{
+		"ami":            "ami",
+		"instance_type":  "m4.large",
+		"key_name":       "keyName",
+		"security_group": "sg-123456",
+		"bid_price":      0.5,
+		"fleet_options": []map[string]interface{}{
+			map[string]interface{}{"instance_type": "m4.xlarge", "weight": 2},
+		},
+	}
+
+	manager, ok := s.spotManager.(*ec2Manager)
+	s.Require().True(ok)
+	mock, ok := manager.client.(*awsClientMock)
+	s.Require().True(ok)
+	// m4.xlarge is tried first, since it is cheaper per unit of weight
+	mock.spotRequestStatusCodes = map[string]string{
+		"m4.xlarge": "capacity-not-available",
+		"m4.large":  "pending-fulfillment",
+	}
+
+	_, err := s.spotManager.SpawnHost(h)
+	s.NoError(err)
+	s.Equal("m4.large", *mock.RequestSpotInstancesInput.LaunchSpecification.InstanceType)
+	s.Equal("m4.large", h.InstanceType)
+	s.Require().NotNil(mock.CancelSpotInstanceRequestsInput)
+	s.Equal("instance_id", *mock.CancelSpotInstanceRequestsInput.SpotInstanceRequestIds[0])
+
+	// the last option is left to be fulfilled whenever capacity allows
+	mock.spotRequestStatusCodes["m4.large"] = "capacity-not-available"
+	_, err = s.spotManager.SpawnHost(h)
+	s.NoError(err)
+	s.Equal("m4.large", *mock.RequestSpotInstancesInput.LaunchSpecification.InstanceType)
+
+	s.False(isEC2CapacityError(awserr.New("Unsupported", "unsupported instance type", nil)))
+}
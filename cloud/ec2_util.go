@@ -33,6 +33,12 @@ const (
 	EC2ErrorNotFound      = "InvalidInstanceID.NotFound"
 )
 
+const (
+	// homeVolumeDeviceName is the device that users' home volumes are
+	// attached as.
+	homeVolumeDeviceName = "/dev/sdh"
+)
+
 type MountPoint struct {
 	VirtualName string `mapstructure:"virtual_name" json:"virtual_name,omitempty" bson:"virtual_name,omitempty"`
 	DeviceName  string `mapstructure:"device_name" json:"device_name,omitempty" bson:"device_name,omitempty"`
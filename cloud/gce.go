@@ -10,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mitchellh/mapstructure"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -194,6 +195,52 @@ func (m *gceManager) TerminateInstance(host *host.Host, user string) error {
 	return host.Terminate(user)
 }
 
+// StopInstance stops a running instance, keeping its disks so that it can be
+// started again.
+func (m *gceManager) StopInstance(h *host.Host, user string) error {
+	if err := m.client.StopInstance(h); err != nil {
+		return errors.Wrap(err, "API call to stop instance failed")
+	}
+
+	return errors.WithStack(h.SetStopped(user))
+}
+
+// StartInstance starts a stopped instance and waits for it to be assigned an
+// external address, which may differ from the one it had before stopping.
+func (m *gceManager) StartInstance(h *host.Host, user string) error {
+	if err := m.client.StartInstance(h); err != nil {
+		return errors.Wrap(err, "API call to start instance failed")
+	}
+
+	var dnsName string
+	_, err := util.Retry(func() (bool, error) {
+		status, err := m.GetInstanceStatus(h)
+		if err != nil {
+			return true, err
+		}
+		if status != StatusRunning {
+			return true, errors.Errorf("host '%s' is not yet running", h.Id)
+		}
+		dnsName, err = m.GetDNSName(h)
+		if err != nil {
+			return true, err
+		}
+		if dnsName == "" {
+			return true, errors.Errorf("host '%s' has no address yet", h.Id)
+		}
+		return false, nil
+	}, startInstanceRetries, startInstanceStartPeriod)
+	if err != nil {
+		return errors.Wrapf(err, "error waiting for host '%s' to start", h.Id)
+	}
+
+	if err = h.ResetDNSName(dnsName); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(h.SetRunning(user))
+}
+
 // IsUp checks whether the provisioned host is running.
 func (m *gceManager) IsUp(host *host.Host) (bool, error) {
 	status, err := m.GetInstanceStatus(host)
@@ -24,6 +24,8 @@ type gceClient interface {
 	CreateInstance(*host.Host, *GCESettings) (string, error)
 	GetInstance(*host.Host) (*compute.Instance, error)
 	DeleteInstance(*host.Host) error
+	StopInstance(*host.Host) error
+	StartInstance(*host.Host) error
 }
 
 type gceClientImpl struct {
@@ -112,6 +114,10 @@ func (c *gceClientImpl) CreateInstance(h *host.Host, s *GCESettings) (string, er
 			&compute.MetadataItems{Key: "ssh-keys", Value: &keys},
 		},
 	}
+	if h.BootstrapScript != "" {
+		instance.Metadata.Items = append(instance.Metadata.Items,
+			&compute.MetadataItems{Key: "startup-script", Value: &h.BootstrapScript})
+	}
 
 	grip.Debug(message.Fields{
 		"message":  "attaching metadata items",
@@ -145,3 +151,21 @@ func (c *gceClientImpl) DeleteInstance(h *host.Host) error {
 
 	return nil
 }
+
+// StopInstance requests a running instance to be stopped.
+func (c *gceClientImpl) StopInstance(h *host.Host) error {
+	if _, err := c.InstancesService.Stop(h.Project, h.Zone, h.Id).Do(); err != nil {
+		return errors.Wrap(err, "API call to stop instance failed")
+	}
+
+	return nil
+}
+
+// StartInstance requests a stopped instance to be started.
+func (c *gceClientImpl) StartInstance(h *host.Host) error {
+	if _, err := c.InstancesService.Start(h.Project, h.Zone, h.Id).Do(); err != nil {
+		return errors.Wrap(err, "API call to start instance failed")
+	}
+
+	return nil
+}
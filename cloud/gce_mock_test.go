@@ -16,6 +16,8 @@ type gceClientMock struct {
 	failCreate bool
 	failGet    bool
 	failDelete bool
+	failStop   bool
+	failStart  bool
 
 	// Other options
 	isActive        bool
@@ -72,3 +74,19 @@ func (c *gceClientMock) DeleteInstance(_ *host.Host) error {
 
 	return nil
 }
+
+func (c *gceClientMock) StopInstance(_ *host.Host) error {
+	if c.failStop {
+		return errors.New("failed to stop instance")
+	}
+
+	return nil
+}
+
+func (c *gceClientMock) StartInstance(_ *host.Host) error {
+	if c.failStart {
+		return errors.New("failed to start instance")
+	}
+
+	return nil
+}
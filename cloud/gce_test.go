@@ -255,6 +255,35 @@ func (s *GCESuite) TestTerminateInstanceDB() {
 	s.Error(err)
 }
 
+func (s *GCESuite) TestStopStartInstance() {
+	myHost := NewIntent(*s.distro, s.manager.GetInstanceName(s.distro), s.distro.Provider, s.hostOpts)
+	myHost, err := s.manager.SpawnHost(myHost)
+	s.NoError(err)
+	_, err = myHost.Upsert()
+	s.NoError(err)
+
+	mock, ok := s.client.(*gceClientMock)
+	s.True(ok)
+	mock.isActive = true
+	mock.hasAccessConfig = true
+
+	s.NoError(s.manager.StopInstance(myHost, evergreen.User))
+	dbHost, err := host.FindOne(host.ById(myHost.Id))
+	s.NoError(err)
+	s.Equal(evergreen.HostStopped, dbHost.Status)
+
+	s.NoError(s.manager.StartInstance(myHost, evergreen.User))
+	dbHost, err = host.FindOne(host.ById(myHost.Id))
+	s.NoError(err)
+	s.Equal(evergreen.HostRunning, dbHost.Status)
+	s.Equal("0.0.0.0", dbHost.Host)
+
+	mock.failStop = true
+	s.Error(s.manager.StopInstance(myHost, evergreen.User))
+	mock.failStart = true
+	s.Error(s.manager.StartInstance(myHost, evergreen.User))
+}
+
 func (s *GCESuite) TestGetDNSNameAPICall() {
 	mock, ok := s.client.(*gceClientMock)
 	s.True(ok)
@@ -0,0 +1,346 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultKubernetesNamespace = "default"
+	defaultKubernetesContainer = "evergreen"
+
+	kubernetesDistroLabel = "evergreen-distro"
+	kubernetesHostLabel   = "evergreen-host"
+)
+
+// kubernetesManager implements the CloudManager interface for Kubernetes.
+// Each host is a pod, and commands are run in the pod through the API server
+// rather than over SSH.
+type kubernetesManager struct {
+	client    kubernetesClient
+	namespace string
+}
+
+// kubernetesSettings specifies the settings used to configure a pod.
+type kubernetesSettings struct {
+	// Namespace is the namespace to start pods in. Defaults to the namespace
+	// in the admin settings.
+	Namespace string `mapstructure:"namespace" json:"namespace" bson:"namespace"`
+
+	// Image is the container image to run. It is used to build a pod with a
+	// single container when PodSpec is not set.
+	Image string `mapstructure:"image" json:"image" bson:"image"`
+
+	// PodSpec is a JSON Kubernetes pod spec. If set, it is used as is, and
+	// Image is ignored.
+	PodSpec string `mapstructure:"pod_spec" json:"pod_spec" bson:"pod_spec"`
+
+	// Container is the name of the container to run commands in. Defaults to
+	// the container created from Image.
+	Container string `mapstructure:"container" json:"container" bson:"container"`
+}
+
+// Validate checks that the settings from the config file are sane.
+func (s *kubernetesSettings) Validate() error {
+	if s.Image == "" && s.PodSpec == "" {
+		return errors.New("either an image or a pod spec must be specified")
+	}
+
+	if s.PodSpec != "" {
+		if _, err := s.getPodSpec(); err != nil {
+			return errors.Wrap(err, "pod spec is invalid")
+		}
+	}
+
+	return nil
+}
+
+// getPodSpec returns the pod spec of the distro, building a single container
+// pod from the image if no spec is given.
+func (s *kubernetesSettings) getPodSpec() (map[string]interface{}, error) {
+	if s.PodSpec == "" {
+		return map[string]interface{}{
+			"restartPolicy": "Never",
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":    s.getContainer(),
+					"image":   s.Image,
+					"command": []interface{}{"sleep", "infinity"},
+				},
+			},
+		}, nil
+	}
+
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(s.PodSpec), &spec); err != nil {
+		return nil, errors.Wrap(err, "problem parsing pod spec")
+	}
+	containers, ok := spec["containers"].([]interface{})
+	if !ok || len(containers) == 0 {
+		return nil, errors.New("pod spec must have at least one container")
+	}
+	if _, ok := spec["restartPolicy"]; !ok {
+		spec["restartPolicy"] = "Never"
+	}
+
+	return spec, nil
+}
+
+func (s *kubernetesSettings) getContainer() string {
+	if s.Container != "" {
+		return s.Container
+	}
+	if s.PodSpec != "" {
+		// kubectl uses the first container of the pod by default
+		return ""
+	}
+	return defaultKubernetesContainer
+}
+
+// GetSettings returns an empty kubernetesSettings struct.
+func (*kubernetesManager) GetSettings() ProviderSettings {
+	return &kubernetesSettings{}
+}
+
+// GetInstanceName returns a name to be used for a pod. Pod names must be
+// valid DNS labels, so unlike other providers this does not use the distro.
+func (*kubernetesManager) GetInstanceName(_ *distro.Distro) string {
+	return fmt.Sprintf("evg-pod-%d", rand.New(rand.NewSource(time.Now().UnixNano())).Int())
+}
+
+// Configure loads the credentials for the cluster from the admin settings.
+func (m *kubernetesManager) Configure(s *evergreen.Settings) error {
+	config := s.Providers.Kubernetes
+
+	if m.client == nil {
+		m.client = &kubernetesClientImpl{}
+	}
+
+	if err := m.client.Init(&config); err != nil {
+		return errors.Wrap(err, "Failed to initialize client connection")
+	}
+
+	m.namespace = config.Namespace
+	if m.namespace == "" {
+		m.namespace = defaultKubernetesNamespace
+	}
+
+	return nil
+}
+
+// getSettings decodes and validates the provider settings of the host's distro.
+func (m *kubernetesManager) getSettings(h *host.Host) (*kubernetesSettings, error) {
+	settings := &kubernetesSettings{}
+	if err := mapstructure.Decode(h.Distro.ProviderSettings, settings); err != nil {
+		return nil, errors.Wrapf(err, "Error decoding params for distro '%s'", h.Distro.Id)
+	}
+	if settings.Namespace == "" {
+		settings.Namespace = m.namespace
+	}
+	return settings, nil
+}
+
+// SpawnHost creates a pod for the host.
+func (m *kubernetesManager) SpawnHost(h *host.Host) (*host.Host, error) {
+	if h.Distro.Provider != evergreen.ProviderNameKubernetes {
+		return nil, errors.Errorf("Can't spawn instance of %s for distro %s: provider is %s",
+			evergreen.ProviderNameKubernetes, h.Distro.Id, h.Distro.Provider)
+	}
+
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return nil, err
+	}
+	if err = settings.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "Invalid Kubernetes settings in distro '%s'", h.Distro.Id)
+	}
+
+	spec, err := settings.getPodSpec()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid Kubernetes settings in distro '%s'", h.Distro.Id)
+	}
+
+	pod := &kubernetesPod{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Metadata: kubernetesPodMetadata{
+			Name:      h.Id,
+			Namespace: settings.Namespace,
+			Labels: map[string]string{
+				kubernetesDistroLabel: h.Distro.Id,
+				kubernetesHostLabel:   h.Id,
+			},
+		},
+		Spec: spec,
+	}
+
+	if _, err = m.client.CreatePod(context.TODO(), pod); err != nil {
+		err = errors.Wrapf(err, "Failed to create pod for host '%s'", h.Id)
+		grip.Error(err)
+		return nil, err
+	}
+
+	grip.Info(message.Fields{
+		"message":   "created Kubernetes pod",
+		"host":      h.Id,
+		"distro":    h.Distro.Id,
+		"namespace": settings.Namespace,
+	})
+	event.LogHostStarted(h.Id)
+
+	return h, nil
+}
+
+// GetInstanceStatus returns a universal status code representing the phase
+// of the host's pod.
+func (m *kubernetesManager) GetInstanceStatus(h *host.Host) (CloudStatus, error) {
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return StatusUnknown, err
+	}
+
+	pod, err := m.client.GetPod(context.TODO(), settings.Namespace, h.Id)
+	if err == errKubernetesPodNotFound {
+		return StatusTerminated, nil
+	}
+	if err != nil {
+		return StatusUnknown, errors.Wrapf(err, "Failed to get pod information for host '%s'", h.Id)
+	}
+
+	return podPhaseToEvgStatus(pod.Status.Phase), nil
+}
+
+// podPhaseToEvgStatus converts a pod phase to an Evergreen cloud status.
+// Pods run until they are deleted, so a pod that has completed is treated as
+// terminated.
+func podPhaseToEvgStatus(phase string) CloudStatus {
+	switch phase {
+	case "Pending":
+		return StatusInitializing
+	case "Running":
+		return StatusRunning
+	case "Succeeded":
+		return StatusTerminated
+	case "Failed":
+		return StatusFailed
+	default:
+		return StatusUnknown
+	}
+}
+
+// GetDNSName returns the IP address of the host's pod.
+func (m *kubernetesManager) GetDNSName(h *host.Host) (string, error) {
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return "", err
+	}
+
+	pod, err := m.client.GetPod(context.TODO(), settings.Namespace, h.Id)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get pod information for host '%s'", h.Id)
+	}
+
+	return pod.Status.PodIP, nil
+}
+
+// CanSpawn returns if a given cloud provider supports spawning a new host
+// dynamically. Always returns true for Kubernetes.
+func (m *kubernetesManager) CanSpawn() (bool, error) {
+	return true, nil
+}
+
+// TerminateInstance deletes the host's pod.
+func (m *kubernetesManager) TerminateInstance(h *host.Host, user string) error {
+	if h.Status == evergreen.HostTerminated {
+		err := errors.Errorf("Can not terminate %s - already marked as terminated!", h.Id)
+		grip.Error(err)
+		return err
+	}
+
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return err
+	}
+
+	err = m.client.DeletePod(context.TODO(), settings.Namespace, h.Id)
+	if err != nil && err != errKubernetesPodNotFound {
+		return errors.Wrap(err, "API call to delete pod failed")
+	}
+
+	grip.Info(message.Fields{
+		"message":   "terminated Kubernetes pod",
+		"host":      h.Id,
+		"namespace": settings.Namespace,
+	})
+
+	return h.Terminate(user)
+}
+
+// IsUp returns true if the host's pod is running.
+func (m *kubernetesManager) IsUp(h *host.Host) (bool, error) {
+	status, err := m.GetInstanceStatus(h)
+	if err != nil {
+		return false, err
+	}
+	return status == StatusRunning, nil
+}
+
+// OnUp does nothing.
+func (m *kubernetesManager) OnUp(_ *host.Host) error {
+	return nil
+}
+
+// IsSSHReachable checks that a command can be run in the host's pod. Pods
+// are not reached over SSH, so the key is not used.
+func (m *kubernetesManager) IsSSHReachable(h *host.Host, _ string) (bool, error) {
+	_, err := m.RunCommand(context.TODO(), h, []string{"true"})
+	if err != nil {
+		grip.Debug(message.WrapError(err, message.Fields{
+			"message": "pod is not reachable",
+			"host":    h.Id,
+		}))
+		return false, nil
+	}
+	return true, nil
+}
+
+// RunCommand runs the command in the host's pod.
+func (m *kubernetesManager) RunCommand(ctx context.Context, h *host.Host, cmd []string) (string, error) {
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return "", err
+	}
+
+	return m.client.Exec(ctx, settings.Namespace, h.Id, settings.getContainer(), cmd)
+}
+
+// GetSSHOptions returns the distro's SSH options. Pods are not reached over
+// SSH, but the options are passed through for callers that expect them.
+func (m *kubernetesManager) GetSSHOptions(h *host.Host, keyPath string) ([]string, error) {
+	opts := []string{}
+	if keyPath != "" {
+		opts = append(opts, "-i", keyPath)
+	}
+	for _, opt := range h.Distro.SSHOptions {
+		opts = append(opts, "-o", opt)
+	}
+	return opts, nil
+}
+
+// TimeTilNextPayment returns the amount of time until the next payment is due
+// for the host. For Kubernetes this is not relevant.
+func (m *kubernetesManager) TimeTilNextPayment(_ *host.Host) time.Duration {
+	return time.Duration(0)
+}
@@ -0,0 +1,265 @@
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	kubernetesRequestTimeout = 30 * time.Second
+	defaultKubectl           = "kubectl"
+)
+
+// kubernetesPod is the subset of the Kubernetes Pod object that the
+// manager reads and writes. The spec is kept as a generic document so that
+// distros can use any pod spec field supported by their cluster.
+type kubernetesPod struct {
+	APIVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Metadata   kubernetesPodMetadata  `json:"metadata"`
+	Spec       map[string]interface{} `json:"spec"`
+	Status     kubernetesPodStatus    `json:"status,omitempty"`
+}
+
+type kubernetesPodMetadata struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type kubernetesPodStatus struct {
+	Phase string `json:"phase,omitempty"`
+	PodIP string `json:"podIP,omitempty"`
+}
+
+// errKubernetesPodNotFound is returned by the client when the pod does not
+// exist in the cluster.
+var errKubernetesPodNotFound = errors.New("pod not found")
+
+// The client interface wraps interaction with the Kubernetes API server.
+type kubernetesClient interface {
+	Init(*evergreen.KubernetesConfig) error
+	CreatePod(context.Context, *kubernetesPod) (*kubernetesPod, error)
+	GetPod(ctx context.Context, namespace, name string) (*kubernetesPod, error)
+	DeletePod(ctx context.Context, namespace, name string) error
+	Exec(ctx context.Context, namespace, name, container string, cmd []string) (string, error)
+}
+
+type kubernetesClientImpl struct {
+	config     evergreen.KubernetesConfig
+	httpClient *http.Client
+}
+
+// Init checks the configuration and sets up an HTTP client that trusts the
+// cluster's CA certificate.
+func (c *kubernetesClientImpl) Init(config *evergreen.KubernetesConfig) error {
+	if config.Server == "" {
+		return errors.New("Kubernetes API server must be configured")
+	}
+	c.config = *config
+	if c.config.Kubectl == "" {
+		c.config.Kubectl = defaultKubectl
+	}
+
+	transport := &http.Transport{}
+	if config.CACert != "" {
+		pem, err := ioutil.ReadFile(config.CACert)
+		if err != nil {
+			return errors.Wrapf(err, "problem reading CA certificate '%s'", config.CACert)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.Errorf("no certificates found in '%s'", config.CACert)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	c.httpClient = &http.Client{Transport: transport, Timeout: kubernetesRequestTimeout}
+
+	return nil
+}
+
+func (c *kubernetesClientImpl) podURL(namespace, name string) string {
+	url := fmt.Sprintf("%s/api/v1/namespaces/%s/pods", strings.TrimRight(c.config.Server, "/"), namespace)
+	if name != "" {
+		url += "/" + name
+	}
+	return url
+}
+
+// do sends a request to the API server and decodes the response into out,
+// if out is non-nil.
+func (c *kubernetesClientImpl) do(ctx context.Context, method, url string, body interface{}, out interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "problem marshalling request body")
+		}
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return errors.Wrap(err, "problem building request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(evergreen.ContentTypeHeader, evergreen.ContentTypeValue)
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "problem sending %s request to %s", method, url)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "problem reading response body")
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errKubernetesPodNotFound
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("%s %s returned %d: %s", method, url, resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return errors.Wrap(json.Unmarshal(respBody, out), "problem decoding response")
+}
+
+func (c *kubernetesClientImpl) CreatePod(ctx context.Context, pod *kubernetesPod) (*kubernetesPod, error) {
+	out := &kubernetesPod{}
+	if err := c.do(ctx, http.MethodPost, c.podURL(pod.Metadata.Namespace, ""), pod, out); err != nil {
+		return nil, errors.Wrapf(err, "problem creating pod '%s'", pod.Metadata.Name)
+	}
+	return out, nil
+}
+
+func (c *kubernetesClientImpl) GetPod(ctx context.Context, namespace, name string) (*kubernetesPod, error) {
+	out := &kubernetesPod{}
+	if err := c.do(ctx, http.MethodGet, c.podURL(namespace, name), nil, out); err != nil {
+		if err == errKubernetesPodNotFound {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "problem getting pod '%s'", name)
+	}
+	return out, nil
+}
+
+func (c *kubernetesClientImpl) DeletePod(ctx context.Context, namespace, name string) error {
+	err := c.do(ctx, http.MethodDelete, c.podURL(namespace, name), nil, nil)
+	if err == errKubernetesPodNotFound {
+		return err
+	}
+	return errors.Wrapf(err, "problem deleting pod '%s'", name)
+}
+
+// Exec runs the command in the container with kubectl, since the exec API
+// requires a streaming connection to the API server. The credentials are
+// passed in a temporary kubeconfig file so that the token is not visible in
+// the process list.
+func (c *kubernetesClientImpl) Exec(ctx context.Context, namespace, name, container string, cmd []string) (string, error) {
+	kubeconfig, err := c.writeKubeconfig(namespace)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer func() {
+		grip.Warning(message.WrapError(os.Remove(kubeconfig), message.Fields{
+			"message": "problem removing temporary kubeconfig",
+			"path":    kubeconfig,
+		}))
+	}()
+
+	args := []string{"--kubeconfig", kubeconfig, "exec", name}
+	if container != "" {
+		args = append(args, "--container", container)
+	}
+	args = append(args, "--")
+	args = append(args, cmd...)
+
+	proc, err := subprocess.NewLocalExec(c.config.Kubectl, args, nil, "")
+	if err != nil {
+		return "", errors.Wrap(err, "problem building kubectl command")
+	}
+	output := &bytes.Buffer{}
+	if err = proc.SetOutput(subprocess.OutputOptions{Output: output, SendErrorToOutput: true}); err != nil {
+		return "", errors.Wrap(err, "problem configuring kubectl output")
+	}
+	if err = proc.Run(ctx); err != nil {
+		return output.String(), errors.Wrapf(err, "problem running command in pod '%s': %s", name, output.String())
+	}
+	return output.String(), nil
+}
+
+// kubeconfig returns a kubectl configuration document for the cluster and
+// namespace.
+func (c *kubernetesClientImpl) kubeconfig(namespace string) map[string]interface{} {
+	cluster := map[string]interface{}{"server": c.config.Server}
+	if c.config.CACert != "" {
+		cluster["certificate-authority"] = c.config.CACert
+	}
+	user := map[string]interface{}{}
+	if c.config.Token != "" {
+		user["token"] = c.config.Token
+	}
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Config",
+		"clusters": []map[string]interface{}{
+			{"name": "evergreen", "cluster": cluster},
+		},
+		"users": []map[string]interface{}{
+			{"name": "evergreen", "user": user},
+		},
+		"contexts": []map[string]interface{}{
+			{"name": "evergreen", "context": map[string]interface{}{
+				"cluster":   "evergreen",
+				"user":      "evergreen",
+				"namespace": namespace,
+			}},
+		},
+		"current-context": "evergreen",
+	}
+}
+
+// writeKubeconfig writes the kubectl configuration to a temporary file
+// readable only by the current user and returns its path.
+func (c *kubernetesClientImpl) writeKubeconfig(namespace string) (string, error) {
+	data, err := json.Marshal(c.kubeconfig(namespace))
+	if err != nil {
+		return "", errors.Wrap(err, "problem marshalling kubeconfig")
+	}
+	file, err := ioutil.TempFile("", "evergreen-kubeconfig")
+	if err != nil {
+		return "", errors.Wrap(err, "problem creating kubeconfig file")
+	}
+	if _, err = file.Write(data); err != nil {
+		grip.Warning(file.Close())
+		grip.Warning(os.Remove(file.Name()))
+		return "", errors.Wrap(err, "problem writing kubeconfig file")
+	}
+	if err = file.Close(); err != nil {
+		grip.Warning(os.Remove(file.Name()))
+		return "", errors.Wrap(err, "problem closing kubeconfig file")
+	}
+	return file.Name(), nil
+}
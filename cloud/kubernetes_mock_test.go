@@ -0,0 +1,78 @@
+package cloud
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// kubernetesClientMock is a fake clientset which keeps pods in memory.
+type kubernetesClientMock struct {
+	// API call options
+	failInit   bool
+	failCreate bool
+	failGet    bool
+	failDelete bool
+	failExec   bool
+
+	// Other options
+	phase string
+	podIP string
+
+	// Recorded state
+	pods     map[string]*kubernetesPod
+	commands [][]string
+}
+
+func (c *kubernetesClientMock) Init(_ *evergreen.KubernetesConfig) error {
+	if c.failInit {
+		return errors.New("failed to initialize client")
+	}
+	return nil
+}
+
+func (c *kubernetesClientMock) CreatePod(_ context.Context, pod *kubernetesPod) (*kubernetesPod, error) {
+	if c.failCreate {
+		return nil, errors.New("failed to create pod")
+	}
+	if c.pods == nil {
+		c.pods = map[string]*kubernetesPod{}
+	}
+	c.pods[pod.Metadata.Namespace+"/"+pod.Metadata.Name] = pod
+	return pod, nil
+}
+
+func (c *kubernetesClientMock) GetPod(_ context.Context, namespace, name string) (*kubernetesPod, error) {
+	if c.failGet {
+		return nil, errors.New("failed to get pod")
+	}
+	pod, ok := c.pods[namespace+"/"+name]
+	if !ok {
+		return nil, errKubernetesPodNotFound
+	}
+	pod.Status = kubernetesPodStatus{Phase: c.phase, PodIP: c.podIP}
+	return pod, nil
+}
+
+func (c *kubernetesClientMock) DeletePod(_ context.Context, namespace, name string) error {
+	if c.failDelete {
+		return errors.New("failed to delete pod")
+	}
+	if _, ok := c.pods[namespace+"/"+name]; !ok {
+		return errKubernetesPodNotFound
+	}
+	delete(c.pods, namespace+"/"+name)
+	return nil
+}
+
+func (c *kubernetesClientMock) Exec(_ context.Context, namespace, name, _ string, cmd []string) (string, error) {
+	if c.failExec {
+		return "", errors.New("failed to exec in pod")
+	}
+	if _, ok := c.pods[namespace+"/"+name]; !ok {
+		return "", errKubernetesPodNotFound
+	}
+	c.commands = append(c.commands, cmd)
+	return "", nil
+}
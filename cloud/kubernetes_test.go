@@ -0,0 +1,224 @@
+package cloud
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+type KubernetesSuite struct {
+	client  *kubernetesClientMock
+	manager *kubernetesManager
+	host    *host.Host
+	suite.Suite
+}
+
+func TestKubernetesSuite(t *testing.T) {
+	suite.Run(t, new(KubernetesSuite))
+}
+
+func (s *KubernetesSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *KubernetesSuite) SetupTest() {
+	s.client = &kubernetesClientMock{
+		phase: "Running",
+		podIP: "10.0.0.1",
+	}
+	s.manager = &kubernetesManager{
+		client: s.client,
+	}
+	s.NoError(s.manager.Configure(&evergreen.Settings{}))
+	s.host = &host.Host{
+		Id: "evg-pod-1",
+		Distro: distro.Distro{
+			Id:       "pod",
+			Provider: evergreen.ProviderNameKubernetes,
+			ProviderSettings: &map[string]interface{}{
+				"image": "ubuntu:16.04",
+			},
+		},
+	}
+}
+
+func (s *KubernetesSuite) TestValidateSettings() {
+	s.NoError((&kubernetesSettings{Image: "ubuntu:16.04"}).Validate())
+	s.NoError((&kubernetesSettings{PodSpec: `{"containers": [{"name": "main", "image": "ubuntu:16.04"}]}`}).Validate())
+
+	s.Error((&kubernetesSettings{}).Validate())
+	s.Error((&kubernetesSettings{PodSpec: "{"}).Validate())
+	s.Error((&kubernetesSettings{PodSpec: `{"containers": []}`}).Validate())
+}
+
+func (s *KubernetesSuite) TestPodSpec() {
+	settings := &kubernetesSettings{Image: "ubuntu:16.04"}
+	spec, err := settings.getPodSpec()
+	s.Require().NoError(err)
+	s.Equal("Never", spec["restartPolicy"])
+	containers := spec["containers"].([]interface{})
+	s.Require().Len(containers, 1)
+	s.Equal(defaultKubernetesContainer, containers[0].(map[string]interface{})["name"])
+	s.Equal("ubuntu:16.04", containers[0].(map[string]interface{})["image"])
+
+	settings = &kubernetesSettings{
+		PodSpec:   `{"restartPolicy": "Always", "containers": [{"name": "main", "image": "ubuntu:16.04"}]}`,
+		Container: "main",
+	}
+	spec, err = settings.getPodSpec()
+	s.Require().NoError(err)
+	s.Equal("Always", spec["restartPolicy"])
+	s.Equal("main", settings.getContainer())
+}
+
+func (s *KubernetesSuite) TestConfigureAPICall() {
+	settings := &evergreen.Settings{}
+	s.NoError(s.manager.Configure(settings))
+	s.Equal(defaultKubernetesNamespace, s.manager.namespace)
+
+	settings.Providers.Kubernetes.Namespace = "ci"
+	s.NoError(s.manager.Configure(settings))
+	s.Equal("ci", s.manager.namespace)
+
+	s.client.failInit = true
+	s.Error(s.manager.Configure(settings))
+}
+
+func (s *KubernetesSuite) TestSpawnInvalidSettings() {
+	s.host.Distro.Provider = evergreen.ProviderNameDocker
+	_, err := s.manager.SpawnHost(s.host)
+	s.Error(err)
+
+	s.host.Distro.Provider = evergreen.ProviderNameKubernetes
+	s.host.Distro.ProviderSettings = &map[string]interface{}{}
+	_, err = s.manager.SpawnHost(s.host)
+	s.Error(err)
+}
+
+func (s *KubernetesSuite) TestSpawnHost() {
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+	s.Equal(s.host.Id, h.Id)
+
+	pod, ok := s.client.pods[defaultKubernetesNamespace+"/"+h.Id]
+	s.Require().True(ok)
+	s.Equal("pod", pod.Metadata.Labels[kubernetesDistroLabel])
+	s.Equal(h.Id, pod.Metadata.Labels[kubernetesHostLabel])
+
+	s.client.failCreate = true
+	_, err = s.manager.SpawnHost(s.host)
+	s.Error(err)
+}
+
+func (s *KubernetesSuite) TestGetInstanceStatus() {
+	status, err := s.manager.GetInstanceStatus(s.host)
+	s.NoError(err)
+	s.Equal(StatusTerminated, status)
+
+	_, err = s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	for phase, expected := range map[string]CloudStatus{
+		"Pending":   StatusInitializing,
+		"Running":   StatusRunning,
+		"Succeeded": StatusTerminated,
+		"Failed":    StatusFailed,
+		"Unknown":   StatusUnknown,
+	} {
+		s.client.phase = phase
+		status, err = s.manager.GetInstanceStatus(s.host)
+		s.NoError(err)
+		s.Equal(expected, status)
+	}
+
+	s.client.failGet = true
+	_, err = s.manager.GetInstanceStatus(s.host)
+	s.Error(err)
+	up, err := s.manager.IsUp(s.host)
+	s.Error(err)
+	s.False(up)
+}
+
+func (s *KubernetesSuite) TestGetDNSName() {
+	_, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	dns, err := s.manager.GetDNSName(s.host)
+	s.NoError(err)
+	s.Equal("10.0.0.1", dns)
+}
+
+func (s *KubernetesSuite) TestRunCommand() {
+	reachable, err := s.manager.IsSSHReachable(s.host, "")
+	s.NoError(err)
+	s.False(reachable)
+
+	_, err = s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	reachable, err = s.manager.IsSSHReachable(s.host, "")
+	s.NoError(err)
+	s.True(reachable)
+
+	_, err = s.manager.RunCommand(context.Background(), s.host, []string{"echo", "hi"})
+	s.NoError(err)
+	s.Equal([][]string{{"true"}, {"echo", "hi"}}, s.client.commands)
+
+	s.client.failExec = true
+	reachable, err = s.manager.IsSSHReachable(s.host, "")
+	s.NoError(err)
+	s.False(reachable)
+}
+
+func (s *KubernetesSuite) TestTerminateInstance() {
+	_, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	s.client.failDelete = true
+	s.Error(s.manager.TerminateInstance(s.host, evergreen.User))
+
+	s.host.Status = evergreen.HostTerminated
+	s.client.failDelete = false
+	s.Error(s.manager.TerminateInstance(s.host, evergreen.User))
+}
+
+func (s *KubernetesSuite) TestCloudHostRunCommand() {
+	_, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	cloudHost := &CloudHost{Host: s.host, CloudMgr: s.manager}
+	s.True(cloudHost.UsesCommandRunner())
+	_, err = cloudHost.RunCommand(context.Background(), "cd ~ && ls")
+	s.NoError(err)
+	s.Equal([]string{"sh", "-c", "cd ~ && ls"}, s.client.commands[len(s.client.commands)-1])
+}
+
+func (s *KubernetesSuite) TestKubeconfig() {
+	client := &kubernetesClientImpl{config: evergreen.KubernetesConfig{
+		Server: "https://k8s.example.com",
+		Token:  "t0ken",
+		CACert: "/etc/ca.pem",
+	}}
+	path, err := client.writeKubeconfig("evg")
+	s.Require().NoError(err)
+	defer os.Remove(path)
+
+	info, err := os.Stat(path)
+	s.Require().NoError(err)
+	s.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	data, err := ioutil.ReadFile(path)
+	s.Require().NoError(err)
+	s.Contains(string(data), `"token":"t0ken"`)
+	s.Contains(string(data), `"server":"https://k8s.example.com"`)
+	s.Contains(string(data), `"certificate-authority":"/etc/ca.pem"`)
+	s.Contains(string(data), `"namespace":"evg"`)
+}
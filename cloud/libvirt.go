@@ -0,0 +1,420 @@
+package cloud
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/hostutil"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultLibvirtPool     = "default"
+	defaultLibvirtNetwork  = "default"
+	defaultLibvirtIPSource = "lease"
+)
+
+var libvirtIPSources = []string{"lease", "agent", "arp"}
+
+// libvirtManager implements the CloudManager interface for libvirt. Each host
+// is a KVM virtual machine whose disk is a copy-on-write clone of a base
+// image, placed on whichever configured hypervisor has the most room.
+type libvirtManager struct {
+	client      libvirtClient
+	hypervisors []string
+}
+
+// libvirtSettings specifies the settings used to configure a virtual machine.
+type libvirtSettings struct {
+	// BaseImage is the qcow2 volume in Pool that hosts' disks are cloned
+	// from. It must exist on every hypervisor the distro can use, and must
+	// allow the distro's user to log in with the distro's SSH key.
+	BaseImage string `mapstructure:"base_image" json:"base_image" bson:"base_image"`
+	Pool      string `mapstructure:"pool" json:"pool" bson:"pool"`
+
+	// Network is the libvirt network the host is attached to.
+	Network string `mapstructure:"network" json:"network" bson:"network"`
+
+	NumCPUs  int `mapstructure:"num_cpus" json:"num_cpus" bson:"num_cpus"`
+	MemoryMB int `mapstructure:"memory_mb" json:"memory_mb" bson:"memory_mb"`
+	DiskGB   int `mapstructure:"disk_gb" json:"disk_gb" bson:"disk_gb"`
+
+	// Hypervisors restricts the distro to some of the configured
+	// hypervisors. Defaults to all of them.
+	Hypervisors []string `mapstructure:"hypervisors" json:"hypervisors" bson:"hypervisors"`
+
+	// IPSource is where libvirt finds the host's address: the network's DHCP
+	// leases, the guest agent, or the hypervisor's ARP table. Defaults to
+	// the DHCP leases.
+	IPSource string `mapstructure:"ip_source" json:"ip_source" bson:"ip_source"`
+}
+
+// Validate checks that the settings from the config file are sane.
+func (s *libvirtSettings) Validate() error {
+	if s.BaseImage == "" {
+		return errors.New("base image must not be blank")
+	}
+	if s.NumCPUs <= 0 {
+		return errors.New("number of CPUs must be positive")
+	}
+	if s.MemoryMB <= 0 {
+		return errors.New("memory must be positive")
+	}
+	if s.DiskGB <= 0 {
+		return errors.New("disk size must be positive")
+	}
+	if s.IPSource != "" && !util.StringSliceContains(libvirtIPSources, s.IPSource) {
+		return errors.Errorf("IP source '%s' must be one of lease, agent or arp", s.IPSource)
+	}
+
+	return nil
+}
+
+func (s *libvirtSettings) getPool() string {
+	if s.Pool != "" {
+		return s.Pool
+	}
+	return defaultLibvirtPool
+}
+
+func (s *libvirtSettings) getNetwork() string {
+	if s.Network != "" {
+		return s.Network
+	}
+	return defaultLibvirtNetwork
+}
+
+func (s *libvirtSettings) getIPSource() string {
+	if s.IPSource != "" {
+		return s.IPSource
+	}
+	return defaultLibvirtIPSource
+}
+
+// makeDomain builds the definition of the host's virtual machine.
+func (s *libvirtSettings) makeDomain(h *host.Host) *libvirtDomain {
+	d := &libvirtDomain{Type: "kvm", Name: h.Id, VCPU: s.NumCPUs}
+	d.Memory.Unit = "MiB"
+	d.Memory.Value = s.MemoryMB
+	d.OS.Type.Arch = "x86_64"
+	d.OS.Type.Value = "hvm"
+	d.OS.Boot.Dev = "hd"
+	d.CPU.Mode = "host-passthrough"
+
+	d.Devices.Disk.Type = "volume"
+	d.Devices.Disk.Device = "disk"
+	d.Devices.Disk.Driver.Name = "qemu"
+	d.Devices.Disk.Driver.Type = "qcow2"
+	d.Devices.Disk.Source.Pool = s.getPool()
+	d.Devices.Disk.Source.Volume = libvirtVolumeName(h)
+	d.Devices.Disk.Target.Dev = "vda"
+	d.Devices.Disk.Target.Bus = "virtio"
+
+	d.Devices.Interface.Type = "network"
+	d.Devices.Interface.Source.Network = s.getNetwork()
+	d.Devices.Interface.Model.Type = "virtio"
+
+	d.Devices.Serial.Type = "pty"
+	d.Devices.Console.Type = "pty"
+
+	return d
+}
+
+func libvirtVolumeName(h *host.Host) string { return h.Id + ".qcow2" }
+
+// GetSettings returns an empty libvirtSettings struct.
+func (*libvirtManager) GetSettings() ProviderSettings {
+	return &libvirtSettings{}
+}
+
+// GetInstanceName returns a name to be used for a virtual machine.
+func (*libvirtManager) GetInstanceName(d *distro.Distro) string {
+	return d.GenerateName()
+}
+
+// Configure loads the hypervisors from the admin settings.
+func (m *libvirtManager) Configure(s *evergreen.Settings) error {
+	config := s.Providers.Libvirt
+
+	if m.client == nil {
+		m.client = &libvirtClientImpl{}
+	}
+
+	if err := m.client.Init(&config); err != nil {
+		return errors.Wrap(err, "Failed to initialize client connection")
+	}
+
+	m.hypervisors = []string{}
+	for _, hv := range config.Hypervisors {
+		m.hypervisors = append(m.hypervisors, hv.Name)
+	}
+
+	return nil
+}
+
+// getSettings decodes the provider settings of the host's distro.
+func (m *libvirtManager) getSettings(h *host.Host) (*libvirtSettings, error) {
+	settings := &libvirtSettings{}
+	if err := mapstructure.Decode(h.Distro.ProviderSettings, settings); err != nil {
+		return nil, errors.Wrapf(err, "Error decoding params for distro '%s'", h.Distro.Id)
+	}
+	return settings, nil
+}
+
+// SpawnHost places the host on a hypervisor, clones its disk from the base
+// image and boots it. The hypervisor is recorded in the host's zone.
+func (m *libvirtManager) SpawnHost(h *host.Host) (*host.Host, error) {
+	if h.Distro.Provider != evergreen.ProviderNameLibvirt {
+		return nil, errors.Errorf("Can't spawn instance of %s for distro %s: provider is %s",
+			evergreen.ProviderNameLibvirt, h.Distro.Id, h.Distro.Provider)
+	}
+
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return nil, err
+	}
+	if err = settings.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "Invalid libvirt settings in distro '%s'", h.Distro.Id)
+	}
+
+	ctx := context.TODO()
+	hypervisor, err := m.placeHost(ctx, settings)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to place host '%s'", h.Id)
+	}
+	h.Zone = hypervisor
+
+	if err = m.createDomain(ctx, h, settings); err != nil {
+		err = errors.Wrapf(err, "Failed to create virtual machine for host '%s' on '%s'", h.Id, hypervisor)
+		grip.Error(err)
+		grip.Error(message.WrapError(m.deleteDomain(ctx, h, settings), message.Fields{
+			"message":    "problem cleaning up after failing to create virtual machine",
+			"host":       h.Id,
+			"hypervisor": hypervisor,
+		}))
+		return nil, err
+	}
+
+	grip.Info(message.Fields{
+		"message":    "created libvirt virtual machine",
+		"host":       h.Id,
+		"distro":     h.Distro.Id,
+		"hypervisor": hypervisor,
+	})
+	event.LogHostStarted(h.Id)
+
+	return h, nil
+}
+
+// placeHost picks the hypervisor to run a host on. Hypervisors that cannot
+// be reached are skipped.
+func (m *libvirtManager) placeHost(ctx context.Context, settings *libvirtSettings) (string, error) {
+	candidates := m.hypervisors
+	if len(settings.Hypervisors) > 0 {
+		for _, name := range settings.Hypervisors {
+			if !util.StringSliceContains(m.hypervisors, name) {
+				return "", errors.Errorf("hypervisor '%s' is not configured", name)
+			}
+		}
+		candidates = settings.Hypervisors
+	}
+
+	capacities := map[string]*libvirtCapacity{}
+	for _, name := range candidates {
+		capacity, err := m.client.GetCapacity(ctx, name)
+		if err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message":    "problem getting capacity of hypervisor",
+				"hypervisor": name,
+			}))
+			continue
+		}
+		capacities[name] = capacity
+	}
+
+	return pickLibvirtHypervisor(capacities, settings.NumCPUs, settings.MemoryMB)
+}
+
+// pickLibvirtHypervisor returns the hypervisor with the most free memory of
+// those with room for a virtual machine of the given size. Two hosts placed
+// at the same time may pick the same hypervisor, so hypervisors should have
+// some room to spare.
+func pickLibvirtHypervisor(capacities map[string]*libvirtCapacity, cpus, memoryMB int) (string, error) {
+	names := make([]string, 0, len(capacities))
+	for name := range capacities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best := ""
+	for _, name := range names {
+		capacity := capacities[name]
+		if capacity.freeCPUs() < cpus || capacity.freeMemoryMB() < memoryMB {
+			continue
+		}
+		if best == "" || capacity.freeMemoryMB() > capacities[best].freeMemoryMB() {
+			best = name
+		}
+	}
+	if best == "" {
+		return "", errors.Errorf("no hypervisor has room for %d CPUs and %d MB of memory", cpus, memoryMB)
+	}
+
+	return best, nil
+}
+
+func (m *libvirtManager) createDomain(ctx context.Context, h *host.Host, settings *libvirtSettings) error {
+	if err := m.client.CloneVolume(ctx, h.Zone, settings.getPool(), settings.BaseImage, libvirtVolumeName(h), settings.DiskGB); err != nil {
+		return err
+	}
+	if err := m.client.DefineDomain(ctx, h.Zone, settings.makeDomain(h)); err != nil {
+		return err
+	}
+	return m.client.StartDomain(ctx, h.Zone, h.Id)
+}
+
+// deleteDomain stops and removes the host's virtual machine and deletes its
+// disk. Anything that does not exist is skipped.
+func (m *libvirtManager) deleteDomain(ctx context.Context, h *host.Host, settings *libvirtSettings) error {
+	if err := m.client.DestroyDomain(ctx, h.Zone, h.Id); err != nil && err != errLibvirtNotFound {
+		return errors.Wrap(err, "API call to stop domain failed")
+	}
+	if err := m.client.UndefineDomain(ctx, h.Zone, h.Id); err != nil && err != errLibvirtNotFound {
+		return errors.Wrap(err, "API call to undefine domain failed")
+	}
+	if err := m.client.DeleteVolume(ctx, h.Zone, settings.getPool(), libvirtVolumeName(h)); err != nil && err != errLibvirtNotFound {
+		return errors.Wrap(err, "API call to delete volume failed")
+	}
+	return nil
+}
+
+// GetInstanceStatus returns a universal status code representing the state
+// of the host's virtual machine.
+func (m *libvirtManager) GetInstanceStatus(h *host.Host) (CloudStatus, error) {
+	state, err := m.client.GetDomainState(context.TODO(), h.Zone, h.Id)
+	if err == errLibvirtNotFound {
+		return StatusTerminated, nil
+	}
+	if err != nil {
+		return StatusUnknown, errors.Wrapf(err, "Failed to get domain state for host '%s'", h.Id)
+	}
+
+	return libvirtToEvgStatus(state), nil
+}
+
+// libvirtToEvgStatus converts a domain state, as reported by virsh, to an
+// Evergreen cloud status.
+func libvirtToEvgStatus(state string) CloudStatus {
+	switch state {
+	case "running", "idle", "blocked":
+		return StatusRunning
+	case "paused", "pmsuspended", "in shutdown", "shut off":
+		return StatusStopped
+	case "crashed":
+		return StatusFailed
+	default:
+		return StatusUnknown
+	}
+}
+
+// GetDNSName returns the IP address of the host's virtual machine, which is
+// empty until the machine has booted far enough to get one.
+func (m *libvirtManager) GetDNSName(h *host.Host) (string, error) {
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return "", err
+	}
+
+	ip, err := m.client.GetDomainIP(context.TODO(), h.Zone, h.Id, settings.getIPSource())
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get IP address of host '%s'", h.Id)
+	}
+	return ip, nil
+}
+
+// CanSpawn returns if a given cloud provider supports spawning a new host
+// dynamically. Always returns true for libvirt.
+func (m *libvirtManager) CanSpawn() (bool, error) {
+	return true, nil
+}
+
+// TerminateInstance stops and removes the host's virtual machine and deletes
+// its disk.
+func (m *libvirtManager) TerminateInstance(h *host.Host, user string) error {
+	if h.Status == evergreen.HostTerminated {
+		err := errors.Errorf("Can not terminate %s - already marked as terminated!", h.Id)
+		grip.Error(err)
+		return err
+	}
+
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return err
+	}
+	if err = m.deleteDomain(context.TODO(), h, settings); err != nil {
+		return errors.Wrapf(err, "Failed to delete virtual machine of host '%s'", h.Id)
+	}
+
+	grip.Info(message.Fields{
+		"message":    "terminated libvirt virtual machine",
+		"host":       h.Id,
+		"hypervisor": h.Zone,
+	})
+
+	return h.Terminate(user)
+}
+
+// IsUp returns true if the host's virtual machine is running.
+func (m *libvirtManager) IsUp(h *host.Host) (bool, error) {
+	status, err := m.GetInstanceStatus(h)
+	if err != nil {
+		return false, err
+	}
+	return status == StatusRunning, nil
+}
+
+// OnUp does nothing.
+func (m *libvirtManager) OnUp(_ *host.Host) error {
+	return nil
+}
+
+// IsSSHReachable returns true if the host can successfully accept and run an
+// SSH command.
+func (m *libvirtManager) IsSSHReachable(h *host.Host, keyPath string) (bool, error) {
+	opts, err := m.GetSSHOptions(h, keyPath)
+	if err != nil {
+		return false, err
+	}
+
+	return hostutil.CheckSSHResponse(context.TODO(), h, opts)
+}
+
+// GetSSHOptions generates the command line args to be passed to SSH to allow
+// connection to the machine.
+func (m *libvirtManager) GetSSHOptions(h *host.Host, keyPath string) ([]string, error) {
+	if keyPath == "" {
+		return []string{}, errors.New("No key specified for host")
+	}
+
+	opts := []string{"-i", keyPath}
+	for _, opt := range h.Distro.SSHOptions {
+		opts = append(opts, "-o", opt)
+	}
+
+	return opts, nil
+}
+
+// TimeTilNextPayment returns the amount of time until the next payment is due
+// for the host. For libvirt this is not relevant.
+func (m *libvirtManager) TimeTilNextPayment(_ *host.Host) time.Duration {
+	return time.Duration(0)
+}
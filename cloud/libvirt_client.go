@@ -0,0 +1,325 @@
+package cloud
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/pkg/errors"
+)
+
+const defaultVirsh = "virsh"
+
+// libvirtCapacity is the size of a hypervisor and how much of it is taken by
+// its running virtual machines.
+type libvirtCapacity struct {
+	CPUs              int
+	MemoryMB          int
+	AllocatedCPUs     int
+	AllocatedMemoryMB int
+}
+
+func (c *libvirtCapacity) freeCPUs() int     { return c.CPUs - c.AllocatedCPUs }
+func (c *libvirtCapacity) freeMemoryMB() int { return c.MemoryMB - c.AllocatedMemoryMB }
+
+// libvirtDomain is the subset of the libvirt domain XML format used to
+// define a virtual machine.
+type libvirtDomain struct {
+	XMLName xml.Name `xml:"domain"`
+	Type    string   `xml:"type,attr"`
+	Name    string   `xml:"name"`
+	Memory  struct {
+		Unit  string `xml:"unit,attr"`
+		Value int    `xml:",chardata"`
+	} `xml:"memory"`
+	VCPU int `xml:"vcpu"`
+	OS   struct {
+		Type struct {
+			Arch  string `xml:"arch,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"type"`
+		Boot struct {
+			Dev string `xml:"dev,attr"`
+		} `xml:"boot"`
+	} `xml:"os"`
+	Features struct {
+		ACPI struct{} `xml:"acpi"`
+		APIC struct{} `xml:"apic"`
+	} `xml:"features"`
+	CPU struct {
+		Mode string `xml:"mode,attr"`
+	} `xml:"cpu"`
+	Devices struct {
+		Disk struct {
+			Type   string `xml:"type,attr"`
+			Device string `xml:"device,attr"`
+			Driver struct {
+				Name string `xml:"name,attr"`
+				Type string `xml:"type,attr"`
+			} `xml:"driver"`
+			Source struct {
+				Pool   string `xml:"pool,attr"`
+				Volume string `xml:"volume,attr"`
+			} `xml:"source"`
+			Target struct {
+				Dev string `xml:"dev,attr"`
+				Bus string `xml:"bus,attr"`
+			} `xml:"target"`
+		} `xml:"disk"`
+		Interface struct {
+			Type   string `xml:"type,attr"`
+			Source struct {
+				Network string `xml:"network,attr"`
+			} `xml:"source"`
+			Model struct {
+				Type string `xml:"type,attr"`
+			} `xml:"model"`
+		} `xml:"interface"`
+		Serial struct {
+			Type string `xml:"type,attr"`
+		} `xml:"serial"`
+		Console struct {
+			Type string `xml:"type,attr"`
+		} `xml:"console"`
+	} `xml:"devices"`
+}
+
+// errLibvirtNotFound is returned by the client when the domain or volume
+// does not exist on the hypervisor.
+var errLibvirtNotFound = errors.New("domain or volume not found")
+
+// The libvirtClient interface wraps interaction with the configured
+// hypervisors, which are referred to by name.
+type libvirtClient interface {
+	Init(*evergreen.LibvirtConfig) error
+	GetCapacity(ctx context.Context, hypervisor string) (*libvirtCapacity, error)
+	CloneVolume(ctx context.Context, hypervisor, pool, base, name string, sizeGB int) error
+	DeleteVolume(ctx context.Context, hypervisor, pool, name string) error
+	DefineDomain(ctx context.Context, hypervisor string, domain *libvirtDomain) error
+	StartDomain(ctx context.Context, hypervisor, name string) error
+	GetDomainState(ctx context.Context, hypervisor, name string) (string, error)
+	GetDomainIP(ctx context.Context, hypervisor, name, source string) (string, error)
+	DestroyDomain(ctx context.Context, hypervisor, name string) error
+	UndefineDomain(ctx context.Context, hypervisor, name string) error
+}
+
+type libvirtClientImpl struct {
+	virsh string
+	uris  map[string]string
+}
+
+// Init checks that hypervisors are configured.
+func (c *libvirtClientImpl) Init(config *evergreen.LibvirtConfig) error {
+	if len(config.Hypervisors) == 0 {
+		return errors.New("no libvirt hypervisors are configured")
+	}
+
+	c.virsh = config.Virsh
+	if c.virsh == "" {
+		c.virsh = defaultVirsh
+	}
+	c.uris = map[string]string{}
+	for _, hv := range config.Hypervisors {
+		if hv.Name == "" || hv.URI == "" {
+			return errors.New("libvirt hypervisors must have a name and URI")
+		}
+		c.uris[hv.Name] = hv.URI
+	}
+
+	return nil
+}
+
+// run runs virsh against the hypervisor and returns its output. Errors about
+// missing domains and volumes are returned as errLibvirtNotFound.
+func (c *libvirtClientImpl) run(ctx context.Context, hypervisor string, args ...string) (string, error) {
+	uri, ok := c.uris[hypervisor]
+	if !ok {
+		return "", errors.Errorf("hypervisor '%s' is not configured", hypervisor)
+	}
+
+	proc, err := subprocess.NewLocalExec(c.virsh, append([]string{"--connect", uri}, args...), nil, "")
+	if err != nil {
+		return "", errors.Wrap(err, "problem building virsh command")
+	}
+	output := &bytes.Buffer{}
+	if err = proc.SetOutput(subprocess.OutputOptions{Output: output, SendErrorToOutput: true}); err != nil {
+		return "", errors.Wrap(err, "problem configuring virsh output")
+	}
+	if err = proc.Run(ctx); err != nil {
+		out := output.String()
+		if strings.Contains(out, "failed to get domain") || strings.Contains(out, "Domain not found") ||
+			strings.Contains(out, "failed to get vol") || strings.Contains(out, "Storage volume not found") {
+			return out, errLibvirtNotFound
+		}
+		return out, errors.Wrapf(err, "virsh %s on '%s' failed: %s", args[0], hypervisor, out)
+	}
+	return output.String(), nil
+}
+
+func (c *libvirtClientImpl) GetCapacity(ctx context.Context, hypervisor string) (*libvirtCapacity, error) {
+	nodeinfo, err := c.run(ctx, hypervisor, "nodeinfo")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	stats, err := c.run(ctx, hypervisor, "domstats", "--list-active", "--vcpu", "--balloon")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return parseLibvirtCapacity(nodeinfo, stats)
+}
+
+func (c *libvirtClientImpl) CloneVolume(ctx context.Context, hypervisor, pool, base, name string, sizeGB int) error {
+	_, err := c.run(ctx, hypervisor, "vol-create-as", "--pool", pool, name, fmt.Sprintf("%dG", sizeGB),
+		"--format", "qcow2", "--backing-vol", base, "--backing-vol-format", "qcow2")
+	return errors.Wrapf(err, "problem cloning volume '%s'", base)
+}
+
+func (c *libvirtClientImpl) DeleteVolume(ctx context.Context, hypervisor, pool, name string) error {
+	_, err := c.run(ctx, hypervisor, "vol-delete", "--pool", pool, name)
+	if err == errLibvirtNotFound {
+		return err
+	}
+	return errors.Wrapf(err, "problem deleting volume '%s'", name)
+}
+
+// DefineDomain defines the domain from a temporary XML file, since virsh
+// reads domain definitions from files.
+func (c *libvirtClientImpl) DefineDomain(ctx context.Context, hypervisor string, domain *libvirtDomain) error {
+	data, err := xml.MarshalIndent(domain, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "problem marshalling domain XML")
+	}
+
+	file, err := ioutil.TempFile("", "evergreen-libvirt-")
+	if err != nil {
+		return errors.Wrap(err, "problem creating domain XML file")
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return errors.Wrap(err, "problem writing domain XML file")
+	}
+	if err = file.Close(); err != nil {
+		return errors.Wrap(err, "problem writing domain XML file")
+	}
+
+	_, err = c.run(ctx, hypervisor, "define", file.Name())
+	return errors.Wrapf(err, "problem defining domain '%s'", domain.Name)
+}
+
+func (c *libvirtClientImpl) StartDomain(ctx context.Context, hypervisor, name string) error {
+	_, err := c.run(ctx, hypervisor, "start", name)
+	return errors.Wrapf(err, "problem starting domain '%s'", name)
+}
+
+func (c *libvirtClientImpl) GetDomainState(ctx context.Context, hypervisor, name string) (string, error) {
+	out, err := c.run(ctx, hypervisor, "domstate", name)
+	if err != nil {
+		if err == errLibvirtNotFound {
+			return "", err
+		}
+		return "", errors.Wrapf(err, "problem getting state of domain '%s'", name)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (c *libvirtClientImpl) GetDomainIP(ctx context.Context, hypervisor, name, source string) (string, error) {
+	out, err := c.run(ctx, hypervisor, "domifaddr", name, "--source", source)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem getting addresses of domain '%s'", name)
+	}
+	return parseLibvirtDomainIP(out), nil
+}
+
+// DestroyDomain forcibly stops the domain. Stopping a domain that is not
+// running is not an error.
+func (c *libvirtClientImpl) DestroyDomain(ctx context.Context, hypervisor, name string) error {
+	out, err := c.run(ctx, hypervisor, "destroy", name)
+	if err == errLibvirtNotFound || (err != nil && strings.Contains(out, "domain is not running")) {
+		return errLibvirtNotFound
+	}
+	return errors.Wrapf(err, "problem stopping domain '%s'", name)
+}
+
+func (c *libvirtClientImpl) UndefineDomain(ctx context.Context, hypervisor, name string) error {
+	_, err := c.run(ctx, hypervisor, "undefine", name)
+	if err == errLibvirtNotFound {
+		return err
+	}
+	return errors.Wrapf(err, "problem undefining domain '%s'", name)
+}
+
+// parseLibvirtCapacity reads the hypervisor's size from the output of
+// "virsh nodeinfo" and the resources of its running domains from the output
+// of "virsh domstats --vcpu --balloon", which reports memory in KiB.
+func parseLibvirtCapacity(nodeinfo, domstats string) (*libvirtCapacity, error) {
+	capacity := &libvirtCapacity{}
+
+	scanner := bufio.NewScanner(strings.NewReader(nodeinfo))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields := strings.Fields(parts[1])
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		switch strings.TrimSpace(parts[0]) {
+		case "CPU(s)":
+			capacity.CPUs = value
+		case "Memory size":
+			capacity.MemoryMB = value / 1024
+		}
+	}
+	if capacity.CPUs == 0 || capacity.MemoryMB == 0 {
+		return nil, errors.Errorf("could not read CPUs and memory from node info: %s", nodeinfo)
+	}
+
+	scanner = bufio.NewScanner(strings.NewReader(domstats))
+	for scanner.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		switch parts[0] {
+		case "vcpu.maximum":
+			capacity.AllocatedCPUs += value
+		case "balloon.maximum":
+			capacity.AllocatedMemoryMB += value / 1024
+		}
+	}
+
+	return capacity, nil
+}
+
+// parseLibvirtDomainIP returns the first IPv4 address in the output of
+// "virsh domifaddr", or an empty string if the domain has none yet.
+func parseLibvirtDomainIP(domifaddr string) string {
+	scanner := bufio.NewScanner(strings.NewReader(domifaddr))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if field == "ipv4" && i+1 < len(fields) {
+				return strings.SplitN(fields[i+1], "/", 2)[0]
+			}
+		}
+	}
+	return ""
+}
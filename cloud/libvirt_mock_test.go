@@ -0,0 +1,117 @@
+package cloud
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// libvirtClientMock is a fake client which keeps domains and volumes in
+// memory.
+type libvirtClientMock struct {
+	// API call options
+	failInit     bool
+	failCapacity map[string]bool
+	failDefine   bool
+	failState    bool
+	failIP       bool
+	failDestroy  bool
+
+	// Other options
+	capacities map[string]*libvirtCapacity
+	state      string
+	ip         string
+
+	// Recorded state
+	domains map[string]*libvirtDomain
+	volumes map[string]string
+	started []string
+}
+
+func (c *libvirtClientMock) Init(_ *evergreen.LibvirtConfig) error {
+	if c.failInit {
+		return errors.New("failed to initialize client")
+	}
+	c.domains = map[string]*libvirtDomain{}
+	c.volumes = map[string]string{}
+	return nil
+}
+
+func (c *libvirtClientMock) GetCapacity(_ context.Context, hypervisor string) (*libvirtCapacity, error) {
+	if c.failCapacity[hypervisor] {
+		return nil, errors.New("failed to get capacity")
+	}
+	capacity, ok := c.capacities[hypervisor]
+	if !ok {
+		return nil, errors.Errorf("hypervisor '%s' is not configured", hypervisor)
+	}
+	return capacity, nil
+}
+
+func (c *libvirtClientMock) CloneVolume(_ context.Context, hypervisor, pool, base, name string, _ int) error {
+	c.volumes[hypervisor+"/"+pool+"/"+name] = base
+	return nil
+}
+
+func (c *libvirtClientMock) DeleteVolume(_ context.Context, hypervisor, pool, name string) error {
+	if _, ok := c.volumes[hypervisor+"/"+pool+"/"+name]; !ok {
+		return errLibvirtNotFound
+	}
+	delete(c.volumes, hypervisor+"/"+pool+"/"+name)
+	return nil
+}
+
+func (c *libvirtClientMock) DefineDomain(_ context.Context, hypervisor string, domain *libvirtDomain) error {
+	if c.failDefine {
+		return errors.New("failed to define domain")
+	}
+	c.domains[hypervisor+"/"+domain.Name] = domain
+	return nil
+}
+
+func (c *libvirtClientMock) StartDomain(_ context.Context, hypervisor, name string) error {
+	if _, ok := c.domains[hypervisor+"/"+name]; !ok {
+		return errLibvirtNotFound
+	}
+	c.started = append(c.started, hypervisor+"/"+name)
+	return nil
+}
+
+func (c *libvirtClientMock) GetDomainState(_ context.Context, hypervisor, name string) (string, error) {
+	if c.failState {
+		return "", errors.New("failed to get domain state")
+	}
+	if _, ok := c.domains[hypervisor+"/"+name]; !ok {
+		return "", errLibvirtNotFound
+	}
+	return c.state, nil
+}
+
+func (c *libvirtClientMock) GetDomainIP(_ context.Context, hypervisor, name, _ string) (string, error) {
+	if c.failIP {
+		return "", errors.New("failed to get domain IP")
+	}
+	if _, ok := c.domains[hypervisor+"/"+name]; !ok {
+		return "", errLibvirtNotFound
+	}
+	return c.ip, nil
+}
+
+func (c *libvirtClientMock) DestroyDomain(_ context.Context, hypervisor, name string) error {
+	if c.failDestroy {
+		return errors.New("failed to stop domain")
+	}
+	if _, ok := c.domains[hypervisor+"/"+name]; !ok {
+		return errLibvirtNotFound
+	}
+	return nil
+}
+
+func (c *libvirtClientMock) UndefineDomain(_ context.Context, hypervisor, name string) error {
+	if _, ok := c.domains[hypervisor+"/"+name]; !ok {
+		return errLibvirtNotFound
+	}
+	delete(c.domains, hypervisor+"/"+name)
+	return nil
+}
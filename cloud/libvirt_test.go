@@ -0,0 +1,287 @@
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+type LibvirtSuite struct {
+	client   *libvirtClientMock
+	manager  *libvirtManager
+	settings *evergreen.Settings
+	host     *host.Host
+	suite.Suite
+}
+
+func TestLibvirtSuite(t *testing.T) {
+	suite.Run(t, new(LibvirtSuite))
+}
+
+func (s *LibvirtSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *LibvirtSuite) SetupTest() {
+	s.client = &libvirtClientMock{
+		capacities: map[string]*libvirtCapacity{
+			"rack1-01": {CPUs: 32, MemoryMB: 131072, AllocatedCPUs: 8, AllocatedMemoryMB: 65536},
+			"rack1-02": {CPUs: 32, MemoryMB: 131072, AllocatedCPUs: 8, AllocatedMemoryMB: 16384},
+		},
+		state: "running",
+		ip:    "192.168.122.45",
+	}
+	s.manager = &libvirtManager{
+		client: s.client,
+	}
+	s.settings = &evergreen.Settings{}
+	s.settings.Providers.Libvirt.Hypervisors = []evergreen.LibvirtHypervisor{
+		{Name: "rack1-01", URI: "qemu+ssh://admin@rack1-01/system"},
+		{Name: "rack1-02", URI: "qemu+ssh://admin@rack1-02/system"},
+	}
+	s.NoError(s.manager.Configure(s.settings))
+	s.host = &host.Host{
+		Id: "evg-libvirt-1",
+		Distro: distro.Distro{
+			Id:       "kvm",
+			Provider: evergreen.ProviderNameLibvirt,
+			ProviderSettings: &map[string]interface{}{
+				"base_image": "ubuntu1804.qcow2",
+				"num_cpus":   4,
+				"memory_mb":  8192,
+				"disk_gb":    40,
+			},
+		},
+	}
+}
+
+func (s *LibvirtSuite) TestValidateSettings() {
+	settings := &libvirtSettings{BaseImage: "ubuntu1804.qcow2", NumCPUs: 4, MemoryMB: 8192, DiskGB: 40}
+	s.NoError(settings.Validate())
+
+	settings.IPSource = "agent"
+	s.NoError(settings.Validate())
+	settings.IPSource = "dns"
+	s.Error(settings.Validate())
+	settings.IPSource = ""
+
+	settings.DiskGB = 0
+	s.Error(settings.Validate())
+	settings.DiskGB = 40
+	settings.MemoryMB = 0
+	s.Error(settings.Validate())
+	settings.MemoryMB = 8192
+	settings.NumCPUs = -1
+	s.Error(settings.Validate())
+
+	s.Error((&libvirtSettings{}).Validate())
+}
+
+func (s *LibvirtSuite) TestConfigureAPICall() {
+	s.Equal([]string{"rack1-01", "rack1-02"}, s.manager.hypervisors)
+
+	s.client.failInit = true
+	s.Error(s.manager.Configure(s.settings))
+}
+
+func (s *LibvirtSuite) TestMakeDomain() {
+	settings := &libvirtSettings{BaseImage: "base", NumCPUs: 2, MemoryMB: 4096, Network: "ci"}
+	d := settings.makeDomain(s.host)
+	s.Equal(s.host.Id, d.Name)
+	s.Equal(2, d.VCPU)
+	s.Equal(4096, d.Memory.Value)
+	s.Equal(defaultLibvirtPool, d.Devices.Disk.Source.Pool)
+	s.Equal(libvirtVolumeName(s.host), d.Devices.Disk.Source.Volume)
+	s.Equal("ci", d.Devices.Interface.Source.Network)
+}
+
+func (s *LibvirtSuite) TestPickHypervisor() {
+	capacities := map[string]*libvirtCapacity{
+		"a": {CPUs: 8, MemoryMB: 16384, AllocatedCPUs: 6, AllocatedMemoryMB: 0},
+		"b": {CPUs: 8, MemoryMB: 16384, AllocatedCPUs: 0, AllocatedMemoryMB: 8192},
+		"c": {CPUs: 8, MemoryMB: 16384, AllocatedCPUs: 0, AllocatedMemoryMB: 4096},
+	}
+
+	// a has the most free memory but not enough CPUs
+	name, err := pickLibvirtHypervisor(capacities, 4, 4096)
+	s.NoError(err)
+	s.Equal("c", name)
+
+	name, err = pickLibvirtHypervisor(capacities, 2, 4096)
+	s.NoError(err)
+	s.Equal("a", name)
+
+	_, err = pickLibvirtHypervisor(capacities, 4, 16384)
+	s.Error(err)
+
+	_, err = pickLibvirtHypervisor(map[string]*libvirtCapacity{}, 1, 1)
+	s.Error(err)
+}
+
+func (s *LibvirtSuite) TestParseCapacity() {
+	nodeinfo := `CPU model:           x86_64
+CPU(s):              32
+CPU frequency:       2600 MHz
+Memory size:         131915460 KiB
+`
+	domstats := `Domain: 'one'
+  balloon.current=4194304
+  balloon.maximum=4194304
+  vcpu.current=2
+  vcpu.maximum=2
+
+Domain: 'two'
+  balloon.current=8388608
+  balloon.maximum=8388608
+  vcpu.current=4
+  vcpu.maximum=4
+`
+	capacity, err := parseLibvirtCapacity(nodeinfo, domstats)
+	s.Require().NoError(err)
+	s.Equal(32, capacity.CPUs)
+	s.Equal(128823, capacity.MemoryMB)
+	s.Equal(6, capacity.AllocatedCPUs)
+	s.Equal(12288, capacity.AllocatedMemoryMB)
+
+	_, err = parseLibvirtCapacity("", domstats)
+	s.Error(err)
+}
+
+func (s *LibvirtSuite) TestParseDomainIP() {
+	out := ` Name       MAC address          Protocol     Address
+-------------------------------------------------------------------------------
+ vnet0      52:54:00:6b:29:45    ipv6         fe80::5054:ff:fe6b:2945/64
+ vnet0      52:54:00:6b:29:45    ipv4         192.168.122.45/24
+`
+	s.Equal("192.168.122.45", parseLibvirtDomainIP(out))
+	s.Equal("", parseLibvirtDomainIP(""))
+}
+
+func (s *LibvirtSuite) TestSpawnInvalidSettings() {
+	s.host.Distro.Provider = evergreen.ProviderNameDocker
+	_, err := s.manager.SpawnHost(s.host)
+	s.Error(err)
+
+	s.host.Distro.Provider = evergreen.ProviderNameLibvirt
+	s.host.Distro.ProviderSettings = &map[string]interface{}{}
+	_, err = s.manager.SpawnHost(s.host)
+	s.Error(err)
+}
+
+func (s *LibvirtSuite) TestSpawnHost() {
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+	s.Equal("rack1-02", h.Zone)
+	s.Equal("ubuntu1804.qcow2", s.client.volumes["rack1-02/default/"+libvirtVolumeName(h)])
+	s.Contains(s.client.domains, "rack1-02/"+h.Id)
+	s.Equal([]string{"rack1-02/" + h.Id}, s.client.started)
+}
+
+func (s *LibvirtSuite) TestSpawnHostPlacement() {
+	// hypervisors that cannot be reached are skipped
+	s.client.failCapacity = map[string]bool{"rack1-02": true}
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+	s.Equal("rack1-01", h.Zone)
+
+	s.client.failCapacity = map[string]bool{"rack1-01": true, "rack1-02": true}
+	_, err = s.manager.SpawnHost(s.host)
+	s.Error(err)
+	s.client.failCapacity = nil
+
+	settings := *s.host.Distro.ProviderSettings
+	settings["hypervisors"] = []interface{}{"rack1-01"}
+	h, err = s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+	s.Equal("rack1-01", h.Zone)
+
+	settings["hypervisors"] = []interface{}{"rack9-01"}
+	_, err = s.manager.SpawnHost(s.host)
+	s.Error(err)
+}
+
+func (s *LibvirtSuite) TestSpawnHostCleansUpOnFailure() {
+	s.client.failDefine = true
+	_, err := s.manager.SpawnHost(s.host)
+	s.Error(err)
+	s.Empty(s.client.volumes)
+	s.Empty(s.client.domains)
+}
+
+func (s *LibvirtSuite) TestGetInstanceStatus() {
+	for state, expected := range map[string]CloudStatus{
+		"running":     StatusRunning,
+		"idle":        StatusRunning,
+		"paused":      StatusStopped,
+		"shut off":    StatusStopped,
+		"in shutdown": StatusStopped,
+		"crashed":     StatusFailed,
+		"nostate":     StatusUnknown,
+	} {
+		s.Equal(expected, libvirtToEvgStatus(state), state)
+	}
+
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+	status, err := s.manager.GetInstanceStatus(h)
+	s.NoError(err)
+	s.Equal(StatusRunning, status)
+
+	h.Zone = "rack1-01"
+	status, err = s.manager.GetInstanceStatus(h)
+	s.NoError(err)
+	s.Equal(StatusTerminated, status)
+
+	s.client.failState = true
+	_, err = s.manager.GetInstanceStatus(h)
+	s.Error(err)
+	up, err := s.manager.IsUp(h)
+	s.Error(err)
+	s.False(up)
+}
+
+func (s *LibvirtSuite) TestGetDNSName() {
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	dns, err := s.manager.GetDNSName(h)
+	s.NoError(err)
+	s.Equal("192.168.122.45", dns)
+
+	s.client.failIP = true
+	_, err = s.manager.GetDNSName(h)
+	s.Error(err)
+}
+
+func (s *LibvirtSuite) TestDeleteDomain() {
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+	settings, err := s.manager.getSettings(h)
+	s.Require().NoError(err)
+
+	s.NoError(s.manager.deleteDomain(context.Background(), h, settings))
+	s.Empty(s.client.domains)
+	s.Empty(s.client.volumes)
+
+	// deleting again skips what is already gone
+	s.NoError(s.manager.deleteDomain(context.Background(), h, settings))
+}
+
+func (s *LibvirtSuite) TestTerminateInstance() {
+	h, err := s.manager.SpawnHost(s.host)
+	s.Require().NoError(err)
+
+	s.client.failDestroy = true
+	s.Error(s.manager.TerminateInstance(h, evergreen.User))
+	s.NotEmpty(s.client.domains)
+
+	h.Status = evergreen.HostTerminated
+	s.client.failDestroy = false
+	s.Error(s.manager.TerminateInstance(h, evergreen.User))
+}
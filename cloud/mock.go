@@ -29,6 +29,7 @@ type MockInstance struct {
 	TimeTilNextPayment time.Duration
 	DNSName            string
 	OnUpRan            bool
+	Volumes            []string
 }
 
 type MockProvider interface {
@@ -195,6 +196,74 @@ func (mockMgr *mockManager) TerminateInstance(host *host.Host, user string) erro
 	return errors.WithStack(host.Terminate(user))
 }
 
+// StopInstance stops a running mock instance.
+func (mockMgr *mockManager) StopInstance(host *host.Host, user string) error {
+	l := mockMgr.mutex
+	l.Lock()
+	defer l.Unlock()
+	instance, ok := mockMgr.Instances[host.Id]
+	if !ok {
+		return errors.Errorf("unable to fetch host: %s", host.Id)
+	}
+	if host.Status != evergreen.HostRunning {
+		return errors.Errorf("cannot stop %s; host status is %s", host.Id, host.Status)
+	}
+
+	instance.Status = StatusStopped
+	instance.IsUp = false
+	mockMgr.Instances[host.Id] = instance
+
+	return errors.WithStack(host.SetStopped(user))
+}
+
+// StartInstance starts a stopped mock instance.
+func (mockMgr *mockManager) StartInstance(host *host.Host, user string) error {
+	l := mockMgr.mutex
+	l.Lock()
+	defer l.Unlock()
+	instance, ok := mockMgr.Instances[host.Id]
+	if !ok {
+		return errors.Errorf("unable to fetch host: %s", host.Id)
+	}
+	if host.Status != evergreen.HostStopped {
+		return errors.Errorf("cannot start %s; host status is %s", host.Id, host.Status)
+	}
+
+	instance.Status = StatusRunning
+	instance.IsUp = true
+	mockMgr.Instances[host.Id] = instance
+
+	if instance.DNSName != "" {
+		if err := host.ResetDNSName(instance.DNSName); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return errors.WithStack(host.SetRunning(user))
+}
+
+// CreateVolume gives the volume an ID; mock volumes have no other state.
+func (mockMgr *mockManager) CreateVolume(host *host.Host, volume *host.Volume) (*host.Volume, error) {
+	volume.ID = "volume-" + host.Id
+	return volume, nil
+}
+
+// AttachVolume records the volume on the mock instance.
+func (mockMgr *mockManager) AttachVolume(host *host.Host, volume *host.Volume) error {
+	l := mockMgr.mutex
+	l.Lock()
+	defer l.Unlock()
+	instance, ok := mockMgr.Instances[host.Id]
+	if !ok {
+		return errors.Errorf("unable to fetch host: %s", host.Id)
+	}
+
+	instance.Volumes = append(instance.Volumes, volume.ID)
+	mockMgr.Instances[host.Id] = instance
+
+	return nil
+}
+
 func (mockMgr *mockManager) Configure(settings *evergreen.Settings) error {
 	//no-op. maybe will need to load something from settings in the future.
 	return nil
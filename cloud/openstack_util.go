@@ -34,11 +34,15 @@ func osStatusToEvgStatus(status string) CloudStatus {
 }
 
 func getSpawnOptions(h *host.Host, s *openStackSettings) servers.CreateOpts {
-	return servers.CreateOpts{
+	opts := servers.CreateOpts{
 		Name:           h.Id,
 		ImageName:      s.ImageName,
 		FlavorName:     s.FlavorName,
 		SecurityGroups: []string{s.SecurityGroup},
 		Metadata:       makeTags(h),
 	}
+	if h.BootstrapScript != "" {
+		opts.UserData = []byte(h.BootstrapScript)
+	}
+	return opts
 }
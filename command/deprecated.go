@@ -7,6 +7,21 @@ import (
 	"github.com/evergreen-ci/evergreen/rest/client"
 )
 
+// deprecatedCommandNames are the names of the commands in this file, which
+// do nothing but are kept so that projects that use them still run.
+var deprecatedCommandNames = []string{
+	"expansions.fetch_vars",
+	"git.apply_patch",
+	"shell.cleanup",
+	"shell.track",
+}
+
+// DeprecatedCommandNames returns the names of the commands that are
+// deprecated and do nothing.
+func DeprecatedCommandNames() []string {
+	return append([]string{}, deprecatedCommandNames...)
+}
+
 // gitApplyPatch is deprecated. Its functionality is now a part of GitGetProjectCommand.
 type gitApplyPatch struct{ base }
 
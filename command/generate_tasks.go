@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// generateTasks sends files of project configuration, in YAML or JSON, to the
+// API server, which adds the tasks and variants they define to the task's
+// version.
+type generateTasks struct {
+	// Files are the paths of the generated files, relative to the working
+	// directory. Wildcards are allowed.
+	Files []string `mapstructure:"files" plugin:"expand"`
+
+	// DependOnGenerator makes the generated tasks depend on this task.
+	DependOnGenerator bool `mapstructure:"depend_on_generator"`
+	base
+}
+
+func generateTasksFactory() Command   { return &generateTasks{} }
+func (c *generateTasks) Name() string { return "generate.tasks" }
+
+func (c *generateTasks) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error decoding '%s' params", c.Name())
+	}
+
+	if len(c.Files) == 0 {
+		return errors.Errorf("error validating params: must specify at least one "+
+			"file of generated configuration: '%+v'", params)
+	}
+	return nil
+}
+
+func (c *generateTasks) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	var err error
+
+	if err = util.ExpandValues(c, conf.Expansions); err != nil {
+		err = errors.Wrap(err, "error expanding params")
+		logger.Task().Error(err)
+		return err
+	}
+
+	c.Files, err = util.BuildFileList(conf.WorkDir, c.Files...)
+	if err != nil {
+		err = errors.Wrap(err, "problem building wildcard paths")
+		logger.Task().Error(err)
+		return err
+	}
+
+	if len(c.Files) == 0 {
+		err = errors.New("expanded file specification had no items")
+		logger.Task().Error(err)
+		return err
+	}
+
+	req := &apimodels.GenerateTasksRequest{DependOnGenerator: c.DependOnGenerator}
+	for _, fn := range c.Files {
+		if !filepath.IsAbs(fn) {
+			fn = filepath.Join(conf.WorkDir, fn)
+		}
+		var data []byte
+		data, err = ioutil.ReadFile(fn)
+		if err != nil {
+			err = errors.Wrapf(err, "problem reading file '%s'", fn)
+			logger.Task().Error(err)
+			return err
+		}
+		req.Files = append(req.Files, string(data))
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	if err = comm.GenerateTasks(ctx, td, req); err != nil {
+		return errors.Wrap(err, "problem generating tasks")
+	}
+
+	logger.Task().Infof("'%s' generated tasks from %d files", c.Name(), len(req.Files))
+	return nil
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTasksParseParams(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := generateTasksFactory().(*generateTasks)
+	assert.Error(cmd.ParseParams(map[string]interface{}{}))
+	assert.Error(cmd.ParseParams(map[string]interface{}{"files": 1}))
+
+	cmd = generateTasksFactory().(*generateTasks)
+	assert.NoError(cmd.ParseParams(map[string]interface{}{
+		"files":               []string{"generated.json"},
+		"depend_on_generator": true,
+	}))
+	assert.Equal([]string{"generated.json"}, cmd.Files)
+	assert.True(cmd.DependOnGenerator)
+}
+
+func TestGenerateTasksExecute(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpdir, err := ioutil.TempDir("", "evergreen.command.generate_tasks.test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	generated := `{"tasks": [{"name": "generated"}]}`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "generated.json"), []byte(generated), 0644))
+
+	comm := client.NewMock("http://localhost.com")
+	conf := &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{"file": "generated.json"}),
+		Task:       &task.Task{Id: "generator"},
+		Project:    &model.Project{},
+		WorkDir:    tmpdir,
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret})
+
+	cmd := &generateTasks{Files: []string{"${file}"}, DependOnGenerator: true}
+	assert.NoError(cmd.Execute(ctx, comm, logger, conf))
+	require.Len(t, comm.GeneratedTasks["generator"], 1)
+	assert.Equal([]string{generated}, comm.GeneratedTasks["generator"][0].Files)
+	assert.True(comm.GeneratedTasks["generator"][0].DependOnGenerator)
+
+	cmd = &generateTasks{Files: []string{"missing.json"}}
+	assert.Error(cmd.Execute(ctx, comm, logger, conf))
+}
@@ -1,8 +1,11 @@
 package evergreen
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"time"
@@ -255,6 +258,265 @@ func (c *HostInitConfig) set() error {
 }
 func (c *HostInitConfig) validateAndDefault() error { return nil }
 
+const (
+	defaultHostHealthWindow         = 20
+	defaultHostHealthMinSamples     = 5
+	defaultHostHealthMaxFailureRate = 0.5
+)
+
+// HostHealthConfig holds the thresholds at which static hosts are
+// automatically quarantined. A host's health is judged by its most recent
+// task results and setup attempts.
+type HostHealthConfig struct {
+	// Window is the number of recent tasks and setup attempts considered.
+	Window int `bson:"window" json:"window" yaml:"window"`
+	// MinSamples is the number of tasks or setup attempts that must have
+	// been seen before the corresponding failure rate is acted on.
+	MinSamples           int     `bson:"min_samples" json:"min_samples" yaml:"min_samples"`
+	MaxSystemFailureRate float64 `bson:"max_system_failure_rate" json:"max_system_failure_rate" yaml:"max_system_failure_rate"`
+	MaxSetupFailureRate  float64 `bson:"max_setup_failure_rate" json:"max_setup_failure_rate" yaml:"max_setup_failure_rate"`
+	QuarantineDisabled   bool    `bson:"quarantine_disabled" json:"quarantine_disabled" yaml:"quarantine_disabled"`
+}
+
+func (c *HostHealthConfig) id() string { return "host_health" }
+func (c *HostHealthConfig) get() error {
+	err := legacyDB.FindOneQ(ConfigCollection, legacyDB.Query(byId(c.id())), c)
+	if err != nil && err.Error() == errNotFound {
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.id())
+}
+func (c *HostHealthConfig) set() error {
+	_, err := legacyDB.Upsert(ConfigCollection, byId(c.id()), bson.M{
+		"$set": bson.M{
+			"window":                  c.Window,
+			"min_samples":             c.MinSamples,
+			"max_system_failure_rate": c.MaxSystemFailureRate,
+			"max_setup_failure_rate":  c.MaxSetupFailureRate,
+			"quarantine_disabled":     c.QuarantineDisabled,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.id())
+}
+func (c *HostHealthConfig) validateAndDefault() error {
+	if c.Window < 0 || c.MinSamples < 0 {
+		return errors.New("host health window and minimum samples must not be negative")
+	}
+	if c.MaxSystemFailureRate < 0 || c.MaxSystemFailureRate > 1 ||
+		c.MaxSetupFailureRate < 0 || c.MaxSetupFailureRate > 1 {
+		return errors.New("host health failure rates must be between 0 and 1")
+	}
+	*c = c.WithDefaults()
+	return nil
+}
+
+// WithDefaults returns a copy of the config with defaults in place of any
+// unset thresholds.
+func (c HostHealthConfig) WithDefaults() HostHealthConfig {
+	if c.Window == 0 {
+		c.Window = defaultHostHealthWindow
+	}
+	if c.MinSamples == 0 {
+		c.MinSamples = defaultHostHealthMinSamples
+	}
+	if c.MinSamples > c.Window {
+		c.MinSamples = c.Window
+	}
+	if c.MaxSystemFailureRate == 0 {
+		c.MaxSystemFailureRate = defaultHostHealthMaxFailureRate
+	}
+	if c.MaxSetupFailureRate == 0 {
+		c.MaxSetupFailureRate = defaultHostHealthMaxFailureRate
+	}
+	return c
+}
+
+const (
+	defaultAgentRolloutStageMinutes         = 60
+	defaultAgentRolloutMinTasks             = 20
+	defaultAgentRolloutMaxSystemFailureRate = 0.1
+)
+
+var defaultAgentRolloutStages = []int{10, 50, 100}
+
+// AgentRolloutConfig controls how new agent revisions are rolled out to the
+// hosts of each distro.
+type AgentRolloutConfig struct {
+	// Stages are the increasing percentages of each distro's hosts that run
+	// the new revision at each stage of a rollout. The last must be 100.
+	Stages []int `bson:"stages" json:"stages" yaml:"stages"`
+	// StageMinutes is the minimum time a rollout spends at each stage.
+	StageMinutes int `bson:"stage_minutes" json:"stage_minutes" yaml:"stage_minutes"`
+	// MinTasks is the number of tasks that hosts running the new revision
+	// must finish at a stage before the rollout proceeds or is rolled back.
+	MinTasks             int     `bson:"min_tasks" json:"min_tasks" yaml:"min_tasks"`
+	MaxSystemFailureRate float64 `bson:"max_system_failure_rate" json:"max_system_failure_rate" yaml:"max_system_failure_rate"`
+	// Disabled deploys new revisions to every host at once.
+	Disabled bool `bson:"disabled" json:"disabled" yaml:"disabled"`
+}
+
+func (c *AgentRolloutConfig) id() string { return "agent_rollout" }
+func (c *AgentRolloutConfig) get() error {
+	err := legacyDB.FindOneQ(ConfigCollection, legacyDB.Query(byId(c.id())), c)
+	if err != nil && err.Error() == errNotFound {
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.id())
+}
+func (c *AgentRolloutConfig) set() error {
+	_, err := legacyDB.Upsert(ConfigCollection, byId(c.id()), bson.M{
+		"$set": bson.M{
+			"stages":                  c.Stages,
+			"stage_minutes":           c.StageMinutes,
+			"min_tasks":               c.MinTasks,
+			"max_system_failure_rate": c.MaxSystemFailureRate,
+			"disabled":                c.Disabled,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.id())
+}
+func (c *AgentRolloutConfig) validateAndDefault() error {
+	if c.StageMinutes < 0 || c.MinTasks < 0 {
+		return errors.New("agent rollout stage minutes and minimum tasks must not be negative")
+	}
+	if c.MaxSystemFailureRate < 0 || c.MaxSystemFailureRate > 1 {
+		return errors.New("agent rollout failure rate must be between 0 and 1")
+	}
+	for i, pct := range c.Stages {
+		if pct <= 0 || pct > 100 {
+			return errors.Errorf("agent rollout stage %d must be a percentage between 1 and 100", i)
+		}
+		if i > 0 && pct <= c.Stages[i-1] {
+			return errors.New("agent rollout stages must be increasing")
+		}
+	}
+	if len(c.Stages) > 0 && c.Stages[len(c.Stages)-1] != 100 {
+		return errors.New("the last agent rollout stage must be 100 percent")
+	}
+	*c = c.WithDefaults()
+	return nil
+}
+
+// WithDefaults returns a copy of the config with defaults in place of any
+// unset values.
+func (c AgentRolloutConfig) WithDefaults() AgentRolloutConfig {
+	if len(c.Stages) == 0 {
+		c.Stages = append([]int{}, defaultAgentRolloutStages...)
+	}
+	if c.StageMinutes == 0 {
+		c.StageMinutes = defaultAgentRolloutStageMinutes
+	}
+	if c.MinTasks == 0 {
+		c.MinTasks = defaultAgentRolloutMinTasks
+	}
+	if c.MaxSystemFailureRate == 0 {
+		c.MaxSystemFailureRate = defaultAgentRolloutMaxSystemFailureRate
+	}
+	return c
+}
+
+const defaultHostSSHKeyRotationHours = 24
+
+// HostSSHKeyEncryptionKeyEnv is the environment variable that may hold the
+// host SSH key encryption key in place of the local settings file.
+const HostSSHKeyEncryptionKeyEnv = "EVERGREEN_HOST_SSH_KEY_ENCRYPTION_KEY"
+
+// HostSSHKeysConfig controls the per-host SSH keys that the app server uses
+// in place of the distro's shared key.
+type HostSSHKeysConfig struct {
+	// Enabled generates a key for each host started after it is set.
+	Enabled bool `bson:"enabled" json:"enabled" yaml:"enabled"`
+	// EncryptionKey is the base64-encoded 32 byte AES key that encrypts
+	// the private keys stored on hosts. It is read only from the local
+	// settings file or the HostSSHKeyEncryptionKeyEnv environment variable,
+	// and is never stored in or returned with the admin settings, since the
+	// keys it encrypts are stored in the same database.
+	EncryptionKey string `bson:"-" json:"-" yaml:"encryption_key"`
+	// RotationHours is how old a host's key may get before it is replaced.
+	RotationHours int `bson:"rotation_hours" json:"rotation_hours" yaml:"rotation_hours"`
+	// KeyDirectory is where decrypted keys are written for use by ssh.
+	KeyDirectory string `bson:"key_directory" json:"key_directory" yaml:"key_directory"`
+}
+
+func (c *HostSSHKeysConfig) id() string { return "host_ssh_keys" }
+func (c *HostSSHKeysConfig) get() error {
+	err := legacyDB.FindOneQ(ConfigCollection, legacyDB.Query(byId(c.id())), c)
+	c.EncryptionKey = localHostSSHKeyEncryptionKey()
+	if err != nil && err.Error() == errNotFound {
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.id())
+}
+func (c *HostSSHKeysConfig) set() error {
+	_, err := legacyDB.Upsert(ConfigCollection, byId(c.id()), bson.M{
+		"$set": bson.M{
+			"enabled":        c.Enabled,
+			"rotation_hours": c.RotationHours,
+			"key_directory":  c.KeyDirectory,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.id())
+}
+func (c *HostSSHKeysConfig) validateAndDefault() error {
+	if c.RotationHours < 0 {
+		return errors.New("host SSH key rotation hours must not be negative")
+	}
+	if c.Enabled || c.EncryptionKey != "" {
+		if _, err := c.Key(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	*c = c.WithDefaults()
+	return nil
+}
+
+// WithDefaults returns a copy of the config with defaults in place of any
+// unset values.
+func (c HostSSHKeysConfig) WithDefaults() HostSSHKeysConfig {
+	if c.RotationHours == 0 {
+		c.RotationHours = defaultHostSSHKeyRotationHours
+	}
+	if c.KeyDirectory == "" {
+		c.KeyDirectory = filepath.Join(os.TempDir(), "evergreen-host-keys")
+	}
+	return c
+}
+
+// localHostSSHKeyEncryptionKey returns the host SSH key encryption key from
+// the environment variable or, failing that, from the settings file this
+// process was configured with.
+func localHostSSHKeyEncryptionKey() string {
+	if key := os.Getenv(HostSSHKeyEncryptionKeyEnv); key != "" {
+		return key
+	}
+	env := GetEnvironment()
+	if env == nil {
+		return ""
+	}
+	settings := env.Settings()
+	if settings == nil {
+		return ""
+	}
+	return settings.HostSSHKeys.EncryptionKey
+}
+
+// Key decodes the encryption key, falling back to the environment variable
+// if the config has none.
+func (c *HostSSHKeysConfig) Key() ([]byte, error) {
+	encoded := c.EncryptionKey
+	if encoded == "" {
+		encoded = os.Getenv(HostSSHKeyEncryptionKeyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "host SSH key encryption key is not valid base64")
+	}
+	if len(key) != 32 {
+		return nil, errors.Errorf("host SSH key encryption key must be 32 bytes, not %d", len(key))
+	}
+	return key, nil
+}
+
 // NotifyConfig hold logging and email settings for the notify package.
 type NotifyConfig struct {
 	SMTP *SMTPConfig `bson:"smtp" json:"smtp" yaml:"smtp"`
@@ -349,13 +611,153 @@ func (c *SchedulerConfig) validateAndDefault() error {
 	return nil
 }
 
+const (
+	// DefaultSpawnHostsPerUser is the number of spawn hosts a user may have
+	// running when no quota sets a different limit.
+	DefaultSpawnHostsPerUser = 3
+	// DefaultSpawnHostExpirationHours is the furthest in the future a spawn
+	// host may expire when no quota sets a different limit.
+	DefaultSpawnHostExpirationHours = 24 * 7
+)
+
+// SpawnHostQuota limits the spawn hosts of a single user or, for team quotas,
+// the combined spawn hosts of all users on a team. Zero values are unset.
+type SpawnHostQuota struct {
+	Name               string  `bson:"name,omitempty" json:"name,omitempty" yaml:"name"`
+	MaxHosts           int     `bson:"max_hosts" json:"max_hosts" yaml:"max_hosts"`
+	MaxMonthlyCost     float64 `bson:"max_monthly_cost" json:"max_monthly_cost" yaml:"max_monthly_cost"`
+	MaxExpirationHours int     `bson:"max_expiration_hours" json:"max_expiration_hours" yaml:"max_expiration_hours"`
+}
+
+func (q SpawnHostQuota) validate() error {
+	if q.MaxHosts < 0 || q.MaxMonthlyCost < 0 || q.MaxExpirationHours < 0 {
+		return errors.Errorf("spawn host quota '%s' must not have negative limits", q.Name)
+	}
+	return nil
+}
+
+// SpawnHostConfig holds the spawn host quotas. Users without an override get
+// the default quota, and users on a team are additionally limited by their
+// team's quota, if it has one.
+type SpawnHostConfig struct {
+	Default SpawnHostQuota   `bson:"default" json:"default" yaml:"default"`
+	Teams   []SpawnHostQuota `bson:"teams" json:"teams" yaml:"teams"`
+	Users   []SpawnHostQuota `bson:"users" json:"users" yaml:"users"`
+}
+
+func (c *SpawnHostConfig) id() string { return "spawnhost" }
+func (c *SpawnHostConfig) get() error {
+	err := legacyDB.FindOneQ(ConfigCollection, legacyDB.Query(byId(c.id())), c)
+	if err != nil && err.Error() == errNotFound {
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.id())
+}
+func (c *SpawnHostConfig) set() error {
+	_, err := legacyDB.Upsert(ConfigCollection, byId(c.id()), bson.M{
+		"$set": bson.M{
+			"default": c.Default,
+			"teams":   c.Teams,
+			"users":   c.Users,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.id())
+}
+func (c *SpawnHostConfig) validateAndDefault() error {
+	catcher := grip.NewSimpleCatcher()
+	catcher.Add(c.Default.validate())
+	for _, quotas := range [][]SpawnHostQuota{c.Teams, c.Users} {
+		names := map[string]bool{}
+		for _, q := range quotas {
+			if q.Name == "" {
+				catcher.Add(errors.New("spawn host quota overrides must have a name"))
+			} else if names[q.Name] {
+				catcher.Add(errors.Errorf("duplicate spawn host quota for '%s'", q.Name))
+			}
+			names[q.Name] = true
+			catcher.Add(q.validate())
+		}
+	}
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	if c.Default.MaxHosts == 0 {
+		c.Default.MaxHosts = DefaultSpawnHostsPerUser
+	}
+	if c.Default.MaxExpirationHours == 0 {
+		c.Default.MaxExpirationHours = DefaultSpawnHostExpirationHours
+	}
+	return nil
+}
+
+// UserQuota returns the quota for the given user: the user's override, with
+// any unset limits taken from the default quota.
+func (c *SpawnHostConfig) UserQuota(userID string) SpawnHostQuota {
+	quota, _ := findSpawnHostQuota(c.Users, userID)
+	quota.Name = userID
+
+	if quota.MaxHosts == 0 {
+		quota.MaxHosts = c.Default.MaxHosts
+	}
+	if quota.MaxHosts == 0 {
+		quota.MaxHosts = DefaultSpawnHostsPerUser
+	}
+	if quota.MaxMonthlyCost == 0 {
+		quota.MaxMonthlyCost = c.Default.MaxMonthlyCost
+	}
+	if quota.MaxExpirationHours == 0 {
+		quota.MaxExpirationHours = c.Default.MaxExpirationHours
+	}
+	if quota.MaxExpirationHours == 0 {
+		quota.MaxExpirationHours = DefaultSpawnHostExpirationHours
+	}
+	return quota
+}
+
+// TeamQuota returns the quota for the given team, and false if the team does
+// not have one. Unset limits in a team quota are not enforced.
+func (c *SpawnHostConfig) TeamQuota(team string) (SpawnHostQuota, bool) {
+	if team == "" {
+		return SpawnHostQuota{}, false
+	}
+	return findSpawnHostQuota(c.Teams, team)
+}
+
+// SetUserQuota adds, replaces, or, if the quota has no limits set, removes the
+// override for the user named in the quota.
+func (c *SpawnHostConfig) SetUserQuota(quota SpawnHostQuota) {
+	users := []SpawnHostQuota{}
+	for _, q := range c.Users {
+		if q.Name != quota.Name {
+			users = append(users, q)
+		}
+	}
+	if quota.MaxHosts != 0 || quota.MaxMonthlyCost != 0 || quota.MaxExpirationHours != 0 {
+		users = append(users, quota)
+	}
+	c.Users = users
+}
+
+func findSpawnHostQuota(quotas []SpawnHostQuota, name string) (SpawnHostQuota, bool) {
+	for _, q := range quotas {
+		if q.Name == name {
+			return q, true
+		}
+	}
+	return SpawnHostQuota{}, false
+}
+
 // CloudProviders stores configuration settings for the supported cloud host providers.
 type CloudProviders struct {
-	AWS       AWSConfig       `bson:"aws" json:"aws" yaml:"aws"`
-	Docker    DockerConfig    `bson:"docker" json:"docker" yaml:"docker"`
-	GCE       GCEConfig       `bson:"gce" json:"gce" yaml:"gce"`
-	OpenStack OpenStackConfig `bson:"openstack" json:"openstack" yaml:"openstack"`
-	VSphere   VSphereConfig   `bson:"vsphere" json:"vsphere" yaml:"vsphere"`
+	AWS        AWSConfig        `bson:"aws" json:"aws" yaml:"aws"`
+	Azure      AzureConfig      `bson:"azure" json:"azure" yaml:"azure"`
+	Docker     DockerConfig     `bson:"docker" json:"docker" yaml:"docker"`
+	GCE        GCEConfig        `bson:"gce" json:"gce" yaml:"gce"`
+	Kubernetes KubernetesConfig `bson:"kubernetes" json:"kubernetes" yaml:"kubernetes"`
+	Libvirt    LibvirtConfig    `bson:"libvirt" json:"libvirt" yaml:"libvirt"`
+	OpenStack  OpenStackConfig  `bson:"openstack" json:"openstack" yaml:"openstack"`
+	VSphere    VSphereConfig    `bson:"vsphere" json:"vsphere" yaml:"vsphere"`
 }
 
 func (c *CloudProviders) id() string { return "providers" }
@@ -369,11 +771,14 @@ func (c *CloudProviders) get() error {
 func (c *CloudProviders) set() error {
 	_, err := legacyDB.Upsert(ConfigCollection, byId(c.id()), bson.M{
 		"$set": bson.M{
-			"aws":       c.AWS,
-			"docker":    c.Docker,
-			"gce":       c.GCE,
-			"openstack": c.OpenStack,
-			"vsphere":   c.VSphere,
+			"aws":        c.AWS,
+			"azure":      c.Azure,
+			"docker":     c.Docker,
+			"gce":        c.GCE,
+			"kubernetes": c.Kubernetes,
+			"libvirt":    c.Libvirt,
+			"openstack":  c.OpenStack,
+			"vsphere":    c.VSphere,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.id())
@@ -386,6 +791,16 @@ type AWSConfig struct {
 	Id     string `bson:"aws_id" json:"aws_id" yaml:"aws_id"`
 }
 
+// AzureConfig stores auth info for Microsoft Azure. Evergreen authenticates
+// as an Azure AD application (service principal) that has been granted access
+// to the subscription's resource groups.
+type AzureConfig struct {
+	SubscriptionID string `bson:"subscription_id" json:"subscription_id" yaml:"subscription_id"`
+	TenantID       string `bson:"tenant_id" json:"tenant_id" yaml:"tenant_id"`
+	ClientID       string `bson:"client_id" json:"client_id" yaml:"client_id"`
+	ClientSecret   string `bson:"client_secret" json:"client_secret" yaml:"client_secret"`
+}
+
 // DockerConfig stores auth info for Docker.
 type DockerConfig struct {
 	APIVersion string `bson:"api_version" json:"api_version" yaml:"api_version"`
@@ -416,6 +831,37 @@ type GCEConfig struct {
 	TokenURI     string `bson:"token_uri" json:"token_uri" yaml:"token_uri"`
 }
 
+// KubernetesConfig stores auth info for a Kubernetes cluster. Pods are managed
+// through the cluster's API server; commands are run in them with kubectl.
+type KubernetesConfig struct {
+	// Server is the base URL of the API server e.g. https://k8s.example.com:6443
+	Server string `bson:"server" json:"server" yaml:"server"`
+	// Token is a bearer token for a service account allowed to manage pods.
+	Token string `bson:"token" json:"token" yaml:"token"`
+	// CACert is the path to the CA certificate of the API server.
+	CACert string `bson:"ca_cert" json:"ca_cert" yaml:"ca_cert"`
+	// Namespace is used for distros that do not specify one.
+	Namespace string `bson:"namespace" json:"namespace" yaml:"namespace"`
+	// Kubectl is the path to the kubectl binary. Defaults to kubectl on the PATH.
+	Kubectl string `bson:"kubectl" json:"kubectl" yaml:"kubectl"`
+}
+
+// LibvirtConfig lists the hypervisors that libvirt virtual machines are
+// placed on. Each is managed with virsh through its libvirt connection URI,
+// so the app server must be able to reach it e.g. over SSH.
+type LibvirtConfig struct {
+	// Virsh is the path to the virsh binary. Defaults to virsh on the PATH.
+	Virsh       string              `bson:"virsh" json:"virsh" yaml:"virsh"`
+	Hypervisors []LibvirtHypervisor `bson:"hypervisors" json:"hypervisors" yaml:"hypervisors"`
+}
+
+// LibvirtHypervisor is a machine that runs libvirt virtual machines.
+type LibvirtHypervisor struct {
+	Name string `bson:"name" json:"name" yaml:"name"`
+	// URI is the libvirt connection URI e.g. qemu+ssh://admin@rack1-01/system
+	URI string `bson:"uri" json:"uri" yaml:"uri"`
+}
+
 // VSphereConfig stores auth info for VMware vSphere. The config fields refer
 // to your vCenter server, a centralized management tool for the vSphere suite.
 type VSphereConfig struct {
@@ -758,6 +1204,7 @@ func IsValidBannerTheme(input string) (bool, BannerTheme) {
 // Settings contains all configuration settings for running Evergreen.
 type Settings struct {
 	Id                 string                    `bson:"_id" json:"id"`
+	AgentRollout       AgentRolloutConfig        `yaml:"agent_rollout" bson:"agent_rollout" json:"agent_rollout" id:"agent_rollout"`
 	Alerts             AlertsConfig              `yaml:"alerts" bson:"alerts" json:"alerts" id:"alerts"`
 	Amboy              AmboyConfig               `yaml:"amboy" bson:"amboy" json:"amboy" id:"amboy"`
 	Api                APIConfig                 `yaml:"api" bson:"api" json:"api" id:"api"`
@@ -771,7 +1218,9 @@ type Settings struct {
 	Database           DBSettings                `yaml:"database"`
 	Expansions         map[string]string         `yaml:"expansions" bson:"expansions" json:"expansions"`
 	GithubPRCreatorOrg string                    `yaml:"github_pr_creator_org" bson:"github_pr_creator_org" json:"github_pr_creator_org"`
+	HostHealth         HostHealthConfig          `yaml:"host_health" bson:"host_health" json:"host_health" id:"host_health"`
 	HostInit           HostInitConfig            `yaml:"hostinit" bson:"hostinit" json:"hostinit" id:"hostinit"`
+	HostSSHKeys        HostSSHKeysConfig         `yaml:"host_ssh_keys" bson:"host_ssh_keys" json:"host_ssh_keys" id:"host_ssh_keys"`
 	IsNonProd          bool                      `yaml:"isnonprod" bson:"isnonprod" json:"isnonprod"`
 	Jira               JiraConfig                `yaml:"jira" bson:"jira" json:"jira" id:"jira"`
 	Keys               map[string]string         `yaml:"keys" bson:"keys" json:"keys"`
@@ -786,6 +1235,7 @@ type Settings struct {
 	Scheduler          SchedulerConfig           `yaml:"scheduler" bson:"scheduler" json:"scheduler" id:"scheduler"`
 	ServiceFlags       ServiceFlags              `bson:"service_flags" json:"service_flags" id:"service_flags"`
 	Slack              SlackConfig               `yaml:"slack" bson:"slack" json:"slack" id:"slack"`
+	SpawnHost          SpawnHostConfig           `yaml:"spawnhost" bson:"spawnhost" json:"spawnhost" id:"spawnhost"`
 	Splunk             send.SplunkConnectionInfo `yaml:"splunk" bson:"splunk" json:"splunk"`
 	SuperUsers         []string                  `yaml:"superusers" bson:"superusers" json:"superusers"`
 	Ui                 UIConfig                  `yaml:"ui" bson:"ui" json:"ui" id:"ui"`
@@ -3,6 +3,7 @@ package evergreen
 import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -33,7 +34,10 @@ var (
 	apiKey                = bsonutil.MustHaveTag(Settings{}, "Api")
 	alertsConfigKey       = bsonutil.MustHaveTag(Settings{}, "Alerts")
 	uiKey                 = bsonutil.MustHaveTag(Settings{}, "Ui")
+	agentRolloutKey       = bsonutil.MustHaveTag(Settings{}, "AgentRollout")
+	hostHealthKey         = bsonutil.MustHaveTag(Settings{}, "HostHealth")
 	hostInitConfigKey     = bsonutil.MustHaveTag(Settings{}, "HostInit")
+	hostSSHKeysKey        = bsonutil.MustHaveTag(Settings{}, "HostSSHKeys")
 	notifyKey             = bsonutil.MustHaveTag(Settings{}, "Notify")
 	schedulerConfigKey    = bsonutil.MustHaveTag(Settings{}, "Scheduler")
 	amboyKey              = bsonutil.MustHaveTag(Settings{}, "Amboy")
@@ -45,6 +49,7 @@ var (
 	pprofPortKey          = bsonutil.MustHaveTag(Settings{}, "PprofPort")
 	githubPRCreatorOrgKey = bsonutil.MustHaveTag(Settings{}, "GithubPRCreatorOrg")
 	newRelicKey           = bsonutil.MustHaveTag(Settings{}, "NewRelic")
+	spawnHostKey          = bsonutil.MustHaveTag(Settings{}, "SpawnHost")
 
 	// degraded mode flags
 	taskDispatchKey                 = bsonutil.MustHaveTag(ServiceFlags{}, "TaskDispatchDisabled")
@@ -97,3 +102,22 @@ func SetBannerTheme(theme BannerTheme) error {
 func SetServiceFlags(flags ServiceFlags) error {
 	return flags.set()
 }
+
+// SetSpawnHostUserQuota sets the spawn host quota override for the user named
+// in the quota. A quota with no limits set removes the user's override.
+func SetSpawnHostUserQuota(quota SpawnHostQuota) error {
+	if quota.Name == "" {
+		return errors.New("spawn host quota must name a user")
+	}
+	if err := quota.validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	conf := &SpawnHostConfig{}
+	if err := conf.get(); err != nil {
+		return errors.WithStack(err)
+	}
+	conf.SetUserQuota(quota)
+
+	return conf.set()
+}
@@ -25,12 +25,15 @@ type configSectionRegistry struct {
 func resetRegistry() error {
 	// add any new config sections to the variable below to register them
 	configSections := []configSection{
+		&AgentRolloutConfig{},
 		&AlertsConfig{},
 		&AmboyConfig{},
 		&APIConfig{},
 		&AuthConfig{},
 		&CloudProviders{},
+		&HostHealthConfig{},
 		&HostInitConfig{},
+		&HostSSHKeysConfig{},
 		&JiraConfig{},
 		&LoggerConfig{},
 		&NewRelicConfig{},
@@ -39,6 +42,7 @@ func resetRegistry() error {
 		&SchedulerConfig{},
 		&ServiceFlags{},
 		&SlackConfig{},
+		&SpawnHostConfig{},
 		&UIConfig{},
 	}
 
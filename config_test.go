@@ -1,6 +1,8 @@
 package evergreen
 
 import (
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/mongodb/grip/send"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
 )
 
 const (
@@ -263,6 +266,72 @@ func (s *AdminSuite) TestAuthConfig() {
 	s.Equal(config, settings.AuthConfig)
 }
 
+func (s *AdminSuite) TestAgentRolloutConfig() {
+	config := AgentRolloutConfig{
+		Stages:               []int{5, 25, 100},
+		StageMinutes:         30,
+		MinTasks:             10,
+		MaxSystemFailureRate: 0.2,
+	}
+
+	err := config.set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.AgentRollout)
+}
+
+func (s *AdminSuite) TestHostSSHKeysConfig() {
+	config := HostSSHKeysConfig{
+		Enabled:       true,
+		EncryptionKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+		RotationHours: 12,
+		KeyDirectory:  "/srv/evergreen/host-keys",
+	}
+
+	err := config.set()
+	s.NoError(err)
+
+	// the encryption key must never be stored with the admin settings
+	doc := bson.M{}
+	s.NoError(db.FindOneQ(ConfigCollection, db.Query(byId(config.id())), &doc))
+	s.NotContains(doc, "encryption_key")
+
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Empty(settings.HostSSHKeys.EncryptionKey)
+	config.EncryptionKey = ""
+	s.Equal(config, settings.HostSSHKeys)
+
+	s.NoError(os.Setenv(HostSSHKeyEncryptionKeyEnv, "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="))
+	defer os.Unsetenv(HostSSHKeyEncryptionKeyEnv)
+	settings, err = GetConfig()
+	s.NoError(err)
+	s.Equal("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=", settings.HostSSHKeys.EncryptionKey)
+
+	out, err := json.Marshal(settings.HostSSHKeys)
+	s.NoError(err)
+	s.NotContains(string(out), "MDEyMzQ1")
+}
+
+func (s *AdminSuite) TestHostHealthConfig() {
+	config := HostHealthConfig{
+		Window:               10,
+		MinSamples:           3,
+		MaxSystemFailureRate: 0.3,
+		MaxSetupFailureRate:  0.6,
+	}
+
+	err := config.set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.HostHealth)
+}
+
 func (s *AdminSuite) TestHostinitConfig() {
 	config := HostInitConfig{
 		SSHTimeoutSeconds: 10,
@@ -333,6 +402,12 @@ func (s *AdminSuite) TestProvidersConfig() {
 			Secret: "aws_secret",
 			Id:     "aws",
 		},
+		Azure: AzureConfig{
+			SubscriptionID: "subscription",
+			TenantID:       "tenant",
+			ClientID:       "azure_client",
+			ClientSecret:   "azure_secret",
+		},
 		Docker: DockerConfig{
 			APIVersion: "docker_version",
 		},
@@ -342,6 +417,17 @@ func (s *AdminSuite) TestProvidersConfig() {
 			PrivateKeyID: "gce_key_id",
 			TokenURI:     "gce_token",
 		},
+		Kubernetes: KubernetesConfig{
+			Server:    "https://k8s.example.com",
+			Token:     "k8s_token",
+			Namespace: "ci",
+		},
+		Libvirt: LibvirtConfig{
+			Virsh: "/usr/bin/virsh",
+			Hypervisors: []LibvirtHypervisor{
+				{Name: "rack1-01", URI: "qemu+ssh://admin@rack1-01/system"},
+			},
+		},
 		OpenStack: OpenStackConfig{
 			IdentityEndpoint: "endpoint",
 			Username:         "username",
@@ -414,6 +500,28 @@ func (s *AdminSuite) TestSlackConfig() {
 	s.Equal(config, settings.Slack)
 }
 
+func (s *AdminSuite) TestSpawnHostConfig() {
+	config := SpawnHostConfig{
+		Default: SpawnHostQuota{MaxHosts: 3, MaxExpirationHours: 168},
+		Teams:   []SpawnHostQuota{{Name: "team", MaxMonthlyCost: 500}},
+		Users:   []SpawnHostQuota{{Name: "user", MaxHosts: 5}},
+	}
+
+	err := config.set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.SpawnHost)
+
+	s.NoError(SetSpawnHostUserQuota(SpawnHostQuota{Name: "user"}))
+	s.NoError(SetSpawnHostUserQuota(SpawnHostQuota{Name: "other", MaxHosts: 1}))
+	settings, err = GetConfig()
+	s.NoError(err)
+	s.Equal([]SpawnHostQuota{{Name: "other", MaxHosts: 1}}, settings.SpawnHost.Users)
+	s.Error(SetSpawnHostUserQuota(SpawnHostQuota{Name: "other", MaxHosts: -1}))
+}
+
 func (s *AdminSuite) TestUiConfig() {
 	config := UIConfig{
 		Url:            "url",
@@ -461,3 +569,102 @@ func (s *AdminSuite) TestConfigDefaults() {
 	s.Equal("info", config.LoggerConfig.DefaultLevel)
 	s.Equal(defaultAmboyPoolSize, config.Amboy.PoolSizeLocal)
 }
+
+func TestSpawnHostQuotas(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := SpawnHostConfig{
+		Teams: []SpawnHostQuota{{Name: "team", MaxHosts: 10}},
+		Users: []SpawnHostQuota{{Name: "user", MaxMonthlyCost: 100}},
+	}
+	assert.NoError(conf.validateAndDefault())
+	assert.Equal(DefaultSpawnHostsPerUser, conf.Default.MaxHosts)
+
+	conf.Default.MaxMonthlyCost = 50
+	assert.Equal(SpawnHostQuota{
+		Name:               "user",
+		MaxHosts:           DefaultSpawnHostsPerUser,
+		MaxMonthlyCost:     100,
+		MaxExpirationHours: DefaultSpawnHostExpirationHours,
+	}, conf.UserQuota("user"))
+	assert.Equal(50.0, conf.UserQuota("other").MaxMonthlyCost)
+
+	quota, ok := conf.TeamQuota("team")
+	assert.True(ok)
+	assert.Equal(10, quota.MaxHosts)
+	_, ok = conf.TeamQuota("")
+	assert.False(ok)
+
+	conf.SetUserQuota(SpawnHostQuota{Name: "user", MaxHosts: 1})
+	assert.Equal(1, conf.UserQuota("user").MaxHosts)
+	assert.Equal(50.0, conf.UserQuota("user").MaxMonthlyCost)
+	conf.SetUserQuota(SpawnHostQuota{Name: "user"})
+	assert.Empty(conf.Users)
+
+	conf.Users = []SpawnHostQuota{{Name: "user"}, {Name: "user"}}
+	assert.Error(conf.validateAndDefault())
+	conf.Users = []SpawnHostQuota{{Name: "user", MaxHosts: -1}}
+	assert.Error(conf.validateAndDefault())
+}
+
+func TestHostHealthConfigDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := HostHealthConfig{MaxSetupFailureRate: 0.25}
+	assert.NoError(conf.validateAndDefault())
+	assert.Equal(defaultHostHealthWindow, conf.Window)
+	assert.Equal(defaultHostHealthMinSamples, conf.MinSamples)
+	assert.Equal(defaultHostHealthMaxFailureRate, conf.MaxSystemFailureRate)
+	assert.Equal(0.25, conf.MaxSetupFailureRate)
+
+	assert.Equal(2, HostHealthConfig{Window: 2}.WithDefaults().MinSamples)
+
+	conf = HostHealthConfig{MaxSystemFailureRate: 1.5}
+	assert.Error(conf.validateAndDefault())
+	conf = HostHealthConfig{Window: -1}
+	assert.Error(conf.validateAndDefault())
+}
+
+func TestHostSSHKeysConfigDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := HostSSHKeysConfig{}
+	assert.NoError(conf.validateAndDefault())
+	assert.Equal(defaultHostSSHKeyRotationHours, conf.RotationHours)
+	assert.NotEmpty(conf.KeyDirectory)
+
+	conf = HostSSHKeysConfig{Enabled: true}
+	assert.Error(conf.validateAndDefault())
+	conf = HostSSHKeysConfig{Enabled: true, EncryptionKey: "c2hvcnQ="}
+	assert.Error(conf.validateAndDefault())
+	conf = HostSSHKeysConfig{Enabled: true, EncryptionKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}
+	assert.NoError(conf.validateAndDefault())
+	key, err := conf.Key()
+	assert.NoError(err)
+	assert.Len(key, 32)
+	conf = HostSSHKeysConfig{RotationHours: -1}
+	assert.Error(conf.validateAndDefault())
+
+	assert.NoError(os.Setenv(HostSSHKeyEncryptionKeyEnv, "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="))
+	defer os.Unsetenv(HostSSHKeyEncryptionKeyEnv)
+	conf = HostSSHKeysConfig{Enabled: true}
+	assert.NoError(conf.validateAndDefault())
+}
+
+func TestAgentRolloutConfigDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := AgentRolloutConfig{MinTasks: 5}
+	assert.NoError(conf.validateAndDefault())
+	assert.Equal(defaultAgentRolloutStages, conf.Stages)
+	assert.Equal(defaultAgentRolloutStageMinutes, conf.StageMinutes)
+	assert.Equal(5, conf.MinTasks)
+	assert.Equal(defaultAgentRolloutMaxSystemFailureRate, conf.MaxSystemFailureRate)
+
+	conf = AgentRolloutConfig{Stages: []int{50, 20, 100}}
+	assert.Error(conf.validateAndDefault())
+	conf = AgentRolloutConfig{Stages: []int{10, 50}}
+	assert.Error(conf.validateAndDefault())
+	conf = AgentRolloutConfig{MaxSystemFailureRate: 2}
+	assert.Error(conf.validateAndDefault())
+}
@@ -25,6 +25,7 @@ const (
 	HostUnreachable     = "unreachable"
 	HostQuarantined     = "quarantined"
 	HostDecommissioned  = "decommissioned"
+	HostStopped         = "stopped"
 
 	HostStatusSuccess = "success"
 	HostStatusFailed  = "failed"
@@ -90,6 +91,7 @@ const (
 	DefaultTaskActivator   = ""
 	StepbackTaskActivator  = "stepback"
 	APIServerTaskActivator = "apiserver"
+	AutoRetryTaskActivator = "auto-retry"
 
 	RestRoutePrefix = "rest"
 	APIRoutePrefix  = "api"
@@ -129,8 +131,11 @@ const (
 	ProviderNameEc2Auto     = "ec2-auto"
 	ProviderNameEc2OnDemand = "ec2-ondemand"
 	ProviderNameEc2Spot     = "ec2-spot"
+	ProviderNameAzure       = "azure"
 	ProviderNameDocker      = "docker"
 	ProviderNameGce         = "gce"
+	ProviderNameKubernetes  = "kubernetes"
+	ProviderNameLibvirt     = "libvirt"
 	ProviderNameStatic      = "static"
 	ProviderNameOpenstack   = "openstack"
 	ProviderNameVsphere     = "vsphere"
@@ -174,6 +179,7 @@ var (
 		HostStarting,
 		HostInitializing,
 		HostProvisionFailed,
+		HostStopped,
 	}
 
 	// constant arrays for db update logic
@@ -0,0 +1,328 @@
+package hostinit
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/hostutil"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// taskExpansionsFileName is the file in the spawn host user's home
+	// directory that exports the expansions of the task the host was
+	// spawned for.
+	taskExpansionsFileName = "task_expansions.sh"
+
+	// taskReplayScriptName is the file in the spawn host user's home
+	// directory that replays the task's commands, and taskReplayLogName is
+	// where its output goes.
+	taskReplayScriptName = "replay_task.sh"
+	taskReplayLogName    = "replay_task.log"
+)
+
+var shellVariableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// setupSpawnHostTask writes the expansions of the task the spawn host was
+// created for to a file sourced by the user's shell and, if requested,
+// starts replaying the task's commands up to the one that failed. The task's
+// source and artifacts must already have been fetched onto the host.
+func (init *HostInit) setupSpawnHostTask(ctx context.Context, h *host.Host, sshOptions []string) error {
+	t, err := task.FindOne(task.ById(h.ProvisionOptions.TaskId))
+	if err != nil {
+		return errors.Wrapf(err, "error finding task %s", h.ProvisionOptions.TaskId)
+	}
+	if t == nil {
+		return errors.Errorf("task %s not found", h.ProvisionOptions.TaskId)
+	}
+
+	conf, err := makeSpawnHostTaskConfig(t, h)
+	if err != nil {
+		return errors.Wrapf(err, "error loading config for task %s", t.Id)
+	}
+
+	if err = init.copyFile(ctx, h, taskExpansionsFileName, makeExpansionsFile(conf.Expansions)); err != nil {
+		return errors.Wrap(err, "error writing task expansions to host")
+	}
+	sourceCmd := fmt.Sprintf(`grep -qF '%[1]s' ~/.bashrc || echo 'source ~/%[1]s' >> ~/.bashrc`, taskExpansionsFileName)
+	if logs, err := hostutil.RunSSHCommand(ctx, sourceCmd, sshOptions, *h); err != nil {
+		return errors.Wrapf(err, "error sourcing task expansions: %s", logs)
+	}
+
+	if !h.ProvisionOptions.ReplayUntilFailure {
+		return nil
+	}
+
+	script, err := makeReplayScript(conf, t.Details)
+	if err != nil {
+		return errors.Wrapf(err, "error making replay script for task %s", t.Id)
+	}
+	if err = init.copyFile(ctx, h, taskReplayScriptName, script); err != nil {
+		return errors.Wrap(err, "error writing replay script to host")
+	}
+
+	// the task may take a long time to replay, so the user can follow its
+	// progress in the log rather than holding up provisioning
+	replayCmd := fmt.Sprintf("nohup bash ~/%s > ~/%s 2>&1 &", taskReplayScriptName, taskReplayLogName)
+	if logs, err := hostutil.RunSSHCommand(ctx, replayCmd, sshOptions, *h); err != nil {
+		return errors.Wrapf(err, "error starting task replay: %s", logs)
+	}
+
+	grip.Info(message.Fields{
+		"message": "started replaying task on spawn host",
+		"task":    t.Id,
+		"host":    h.Id,
+		"runner":  RunnerName,
+	})
+
+	return nil
+}
+
+// makeSpawnHostTaskConfig builds the task's config as the agent would have,
+// but for the spawn host's distro. Private project variables are left out
+// since the spawn host belongs to a user.
+func makeSpawnHostTaskConfig(t *task.Task, h *host.Host) (*model.TaskConfig, error) {
+	v, err := version.FindOne(version.ById(t.Version))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding version %s", t.Version)
+	}
+	if v == nil {
+		return nil, errors.Errorf("version %s not found", t.Version)
+	}
+
+	project := &model.Project{}
+	if err = model.LoadProjectInto([]byte(v.Config), t.Project, project); err != nil {
+		return nil, errors.Wrapf(err, "error loading project for version %s", v.Id)
+	}
+
+	projectRef, err := model.FindOneProjectRef(t.Project)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding project ref %s", t.Project)
+	}
+
+	var patchDoc *patch.Patch
+	if evergreen.IsPatchRequester(v.Requester) {
+		patchDoc, err = patch.FindOne(patch.ByVersion(v.Id))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error finding patch for version %s", v.Id)
+		}
+	}
+
+	conf, err := model.NewTaskConfig(&h.Distro, v, project, t, projectRef, patchDoc)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	vars, err := model.FindOneProjectVars(t.Project)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding variables for project %s", t.Project)
+	}
+	if vars != nil {
+		for k, val := range vars.Vars {
+			if !vars.PrivateVars[k] {
+				conf.Expansions.Put(k, val)
+			}
+		}
+	}
+
+	return conf, nil
+}
+
+// makeExpansionsFile returns a shell script that exports the expansions as
+// environment variables. Expansions whose names aren't valid variable names
+// are left out.
+func makeExpansionsFile(expansions *util.Expansions) string {
+	keys := []string{}
+	for k := range *expansions {
+		if shellVariableNameRegex.MatchString(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := []string{}
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("export %s=%s", k, hostutil.ShellQuote(expansions.Get(k))))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// makeReplayScript returns a bash script that runs the task's pre and task
+// commands until it reaches the failing command at the step recorded in the
+// task's end details, which is not run. Only shell.exec and subprocess.exec commands are run;
+// git.get_project links the source fetched onto the host into its directory,
+// and all other commands are skipped.
+func makeReplayScript(conf *model.TaskConfig, details apimodels.TaskEndDetail) (string, error) {
+	if details.CommandStep == 0 {
+		return "", errors.Errorf("task %s has no failing command", conf.Task.Id)
+	}
+	failedCommand := fmt.Sprintf("at step %d.%d", details.CommandStep, details.FunctionStep)
+	if details.Description != "" {
+		failedCommand = details.Description + " " + failedCommand
+	}
+
+	projectTask := conf.Project.FindProjectTask(conf.Task.DisplayName)
+	if projectTask == nil {
+		return "", errors.Errorf("task %s not found in project", conf.Task.DisplayName)
+	}
+
+	lines := []string{
+		"#!/bin/bash",
+		fmt.Sprintf("# replays task %s up to, but not including, the command %s", conf.Task.Id, failedCommand),
+		"set -o errexit",
+		fmt.Sprintf("source ~/%s", taskExpansionsFileName),
+		fmt.Sprintf("cd %s", hostutil.ShellQuote(conf.WorkDir)),
+	}
+
+	if conf.Project.Pre != nil {
+		// pre commands don't fail the task, so they don't stop the replay
+		pre, _, err := makeReplayCommands(conf, conf.Project.Pre.List(), 0, 0, true)
+		if err != nil {
+			return "", errors.Wrap(err, "error replaying pre commands")
+		}
+		lines = append(lines, pre...)
+	}
+
+	cmds, found, err := makeReplayCommands(conf, projectTask.Commands, details.CommandStep, details.FunctionStep, false)
+	if err != nil {
+		return "", errors.Wrap(err, "error replaying task commands")
+	}
+	if !found {
+		return "", errors.Errorf("failing command %s not found in task %s", failedCommand, conf.Task.DisplayName)
+	}
+	lines = append(lines, cmds...)
+	lines = append(lines, fmt.Sprintf("echo %s", hostutil.ShellQuote("Stopped before failing command "+failedCommand)))
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// replayCommandParams holds the parameters of the commands that can be
+// replayed.
+type replayCommandParams struct {
+	Script          string            `mapstructure:"script" plugin:"expand"`
+	Shell           string            `mapstructure:"shell" plugin:"expand"`
+	Binary          string            `mapstructure:"binary" plugin:"expand"`
+	Args            []string          `mapstructure:"args" plugin:"expand"`
+	Command         string            `mapstructure:"command" plugin:"expand"`
+	Env             map[string]string `mapstructure:"env" plugin:"expand"`
+	WorkingDir      string            `mapstructure:"working_dir" plugin:"expand"`
+	Directory       string            `mapstructure:"directory" plugin:"expand"`
+	ContinueOnError bool              `mapstructure:"continue_on_err"`
+	Background      bool              `mapstructure:"background"`
+}
+
+// makeReplayCommands returns the script lines that replay the commands,
+// stopping at the command at the given 1-based step, and step within its
+// function, as numbered by the agent, if any. It also returns whether that
+// command was found.
+func makeReplayCommands(conf *model.TaskConfig, commands []model.PluginCommandConf, stopStep, stopFunctionStep int, continueOnError bool) ([]string, bool, error) {
+	lines := []string{}
+	for i, commandInfo := range commands {
+		if !commandInfo.RunOnVariant(conf.BuildVariant.Name) {
+			continue
+		}
+
+		expansions := util.NewExpansions(*conf.Expansions)
+		rendered := []model.PluginCommandConf{commandInfo}
+		if commandInfo.Function != "" {
+			fn, ok := conf.Project.Functions[commandInfo.Function]
+			if !ok || fn == nil {
+				return nil, false, errors.Errorf("function '%s' not found in project functions", commandInfo.Function)
+			}
+			expansions.Update(commandInfo.Vars)
+			rendered = []model.PluginCommandConf{}
+			for _, c := range fn.List() {
+				if c.DisplayName == "" {
+					c.DisplayName = fmt.Sprintf(`'%v' in "%v"`, c.Command, commandInfo.Function)
+				}
+				rendered = append(rendered, c)
+			}
+		}
+
+		for idx, c := range rendered {
+			if stopStep == i+1 && stopFunctionStep == idx+1 {
+				return lines, true, nil
+			}
+
+			cmdLines, err := makeReplayCommand(conf, expansions, c, continueOnError)
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "error replaying command %s", c.GetDisplayName())
+			}
+			lines = append(lines, cmdLines...)
+		}
+	}
+
+	return lines, false, nil
+}
+
+func makeReplayCommand(conf *model.TaskConfig, expansions *util.Expansions, c model.PluginCommandConf, continueOnError bool) ([]string, error) {
+	params := replayCommandParams{}
+	if err := mapstructure.Decode(c.Params, &params); err != nil {
+		return nil, errors.Wrap(err, "error decoding command parameters")
+	}
+	if err := util.ExpandValues(&params, expansions); err != nil {
+		return nil, errors.Wrap(err, "error applying expansions")
+	}
+
+	lines := []string{fmt.Sprintf("echo %s", hostutil.ShellQuote("Running command "+c.GetDisplayName()))}
+	dir := filepath.ToSlash(filepath.Join(conf.WorkDir, params.WorkingDir))
+
+	var cmd string
+	switch c.Command {
+	case "shell.exec":
+		shell := params.Shell
+		if shell == "" {
+			shell = "sh"
+		}
+		cmd = fmt.Sprintf("(cd %s && %s -c %s)", hostutil.ShellQuote(dir), shell, hostutil.ShellQuote(params.Script))
+	case "subprocess.exec":
+		env := []string{}
+		for k, v := range params.Env {
+			env = append(env, hostutil.ShellQuote(k+"="+v))
+		}
+		sort.Strings(env)
+		run := append([]string{"env"}, env...)
+		if params.Command != "" {
+			run = append(run, params.Command)
+		} else {
+			run = append(run, hostutil.ShellQuote(params.Binary))
+			for _, arg := range params.Args {
+				run = append(run, hostutil.ShellQuote(arg))
+			}
+		}
+		cmd = fmt.Sprintf("(cd %s && %s)", hostutil.ShellQuote(dir), strings.Join(run, " "))
+	case "git.get_project":
+		if params.Directory == "" {
+			return nil, errors.New("git.get_project has no directory")
+		}
+		target := filepath.ToSlash(filepath.Join(conf.WorkDir, params.Directory))
+		cmd = fmt.Sprintf(`ln -sfn "$(ls -d %s/source-* | head -n 1)" %s`, hostutil.ShellQuote(conf.WorkDir), hostutil.ShellQuote(target))
+	default:
+		return append(lines, fmt.Sprintf("echo %s", hostutil.ShellQuote("Skipping command "+c.Command+", which can't be replayed"))), nil
+	}
+
+	switch {
+	case params.Background:
+		cmd += " &"
+	case params.ContinueOnError || continueOnError:
+		cmd += " || true"
+	}
+
+	return append(lines, cmd), nil
+}
@@ -0,0 +1,118 @@
+package hostinit
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeExpansionsFile(t *testing.T) {
+	assert := assert.New(t)
+
+	expansions := util.NewExpansions(map[string]string{
+		"task_id":   "t1",
+		"quoted":    "it's",
+		"not-a-var": "skipped",
+	})
+
+	assert.Equal("export quoted='it'\"'\"'s'\nexport task_id='t1'\n", makeExpansionsFile(expansions))
+}
+
+func TestMakeReplayScript(t *testing.T) {
+	assert := assert.New(t)
+
+	project := &model.Project{
+		Pre: &model.YAMLCommandSet{SingleCommand: &model.PluginCommandConf{
+			Command: "shell.exec",
+			Params:  map[string]interface{}{"script": "echo pre"},
+		}},
+		Functions: map[string]*model.YAMLCommandSet{
+			"run tests": &model.YAMLCommandSet{MultiCommand: []model.PluginCommandConf{
+				{
+					Command: "subprocess.exec",
+					Params: map[string]interface{}{
+						"binary": "make",
+						"args":   []interface{}{"${target}"},
+					},
+				},
+				{
+					Command: "shell.exec",
+					Params:  map[string]interface{}{"script": "exit 1"},
+				},
+			}},
+		},
+		Tasks: []model.ProjectTask{
+			{
+				Name: "test",
+				Commands: []model.PluginCommandConf{
+					{
+						Command: "git.get_project",
+						Params:  map[string]interface{}{"directory": "src"},
+					},
+					{
+						Command:  "shell.exec",
+						Variants: []string{"other"},
+						Params:   map[string]interface{}{"script": "echo other variant"},
+					},
+					{
+						Command:     "shell.exec",
+						DisplayName: "compile",
+						Params: map[string]interface{}{
+							"script":          "echo ${project}",
+							"working_dir":     "src",
+							"continue_on_err": true,
+						},
+					},
+					{Command: "s3.put"},
+					{
+						Function: "run tests",
+						Vars:     map[string]string{"target": "check"},
+					},
+					{
+						Command: "shell.exec",
+						Params:  map[string]interface{}{"script": "echo after"},
+					},
+				},
+			},
+		},
+	}
+	conf := &model.TaskConfig{
+		Project:      project,
+		Task:         &task.Task{Id: "t1", DisplayName: "test"},
+		BuildVariant: &model.BuildVariant{Name: "bv"},
+		Expansions:   util.NewExpansions(map[string]string{"project": "evg", "workdir": "/data"}),
+		WorkDir:      "/data",
+	}
+
+	// the failing command is the second in the function at step 5
+	script, err := makeReplayScript(conf, apimodels.TaskEndDetail{
+		Description:  `'shell.exec' in "run tests"`,
+		CommandStep:  5,
+		FunctionStep: 2,
+	})
+	assert.NoError(err)
+	assert.Contains(script, "(cd '/data' && sh -c 'echo pre') || true")
+	assert.Contains(script, `ln -sfn "$(ls -d '/data'/source-* | head -n 1)" '/data/src'`)
+	assert.NotContains(script, "other variant")
+	assert.Contains(script, "(cd '/data/src' && sh -c 'echo evg') || true")
+	assert.Contains(script, "Skipping command s3.put")
+	assert.Contains(script, "(cd '/data' && env 'make' 'check')")
+	assert.NotContains(script, "exit 1")
+	assert.NotContains(script, "echo after")
+
+	// top level commands without display names are found by their step
+	script, err = makeReplayScript(conf, apimodels.TaskEndDetail{CommandStep: 6, FunctionStep: 1})
+	assert.NoError(err)
+	assert.Contains(script, "exit 1")
+	assert.NotContains(script, "echo after")
+
+	_, err = makeReplayScript(conf, apimodels.TaskEndDetail{})
+	assert.Error(err)
+
+	_, err = makeReplayScript(conf, apimodels.TaskEndDetail{CommandStep: 7, FunctionStep: 1})
+	assert.Error(err)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/notify"
 	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/evergreen-ci/evergreen/taskrunner"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -101,6 +102,21 @@ func (init *HostInit) startHosts(ctx context.Context) error {
 			continue
 		}
 
+		if err = init.generateSSHKey(&h, cloudManager); err != nil {
+			catcher.Add(errors.Wrapf(err, "error generating SSH key for host %s", h.Id))
+			continue
+		}
+
+		if h.Distro.BootstrapsWithUserData() {
+			// the host reports back to the API with its secret once
+			// its bootstrap script has provisioned it
+			h.Secret = util.RandomString()
+			if err = init.setBootstrapScript(&h); err != nil {
+				catcher.Add(errors.Wrapf(err, "error rendering bootstrap script for host %s", h.Id))
+				continue
+			}
+		}
+
 		err = h.Remove()
 		if err != nil {
 			grip.Notice(message.WrapError(err, message.Fields{
@@ -167,6 +183,95 @@ func (init *HostInit) startHosts(ctx context.Context) error {
 	return catcher.Resolve()
 }
 
+// generateSSHKey gives the host its own SSH key if per-host keys are enabled.
+// Hosts that bootstrap from user data authorize the key themselves, while
+// others have it installed with the distro's key when they are set up.
+func (init *HostInit) generateSSHKey(h *host.Host, cloudManager cloud.CloudManager) error {
+	conf := init.Settings.HostSSHKeys
+	if !conf.Enabled || hostutil.IsWindows(&h.Distro) {
+		return nil
+	}
+	if _, ok := cloudManager.(cloud.CommandRunner); ok {
+		// commands are run through the provider rather than over SSH
+		return nil
+	}
+
+	encryptionKey, err := conf.Key()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	h.SSHKey, err = host.NewHostSSHKey(encryptionKey)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	h.SSHKey.Installed = h.Distro.BootstrapsWithUserData()
+	return nil
+}
+
+// setBootstrapScript renders the host's bootstrap script, which downloads the
+// agent revision that the distro's agent rollout has the host run.
+func (init *HostInit) setBootstrapScript(h *host.Host) error {
+	gateway := taskrunner.NewTaskRunner(init.Settings).HostGateway
+	current, err := gateway.GetAgentRevision()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	h.AgentRevision, err = gateway.TargetAgentRevision(h, init.Settings.AgentRollout)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	h.BootstrapScript, err = hostutil.BootstrapScript(init.Settings, h, current)
+	return errors.WithStack(err)
+}
+
+// installSSHKey authorizes the host's own SSH key on it, connecting with the
+// distro's key, then revokes the distro's key connecting with the host's key,
+// so that later commands use the host's key and the distro's key no longer
+// works on the host.
+func (init *HostInit) installSSHKey(ctx context.Context, h *host.Host) error {
+	cloudHost, err := cloud.GetCloudHost(h, init.Settings)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get cloud host for %s", h.Id)
+	}
+	sshOptions, err := cloudHost.GetSSHOptions()
+	if err != nil {
+		return errors.Wrapf(err, "error getting ssh options for host %s", h.Id)
+	}
+
+	output, err := hostutil.RunSSHCommand(ctx, hostutil.AuthorizeSSHKeyCommand(h.SSHKey.PublicKey), sshOptions, *h)
+	if err != nil {
+		return errors.Wrapf(err, "error authorizing SSH key on host %s: %s", h.Id, output)
+	}
+
+	distroKey, err := hostutil.DistroPublicKey(init.Settings, &h.Distro)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if distroKey != "" {
+		installed := *h
+		key := *h.SSHKey
+		key.Installed = true
+		installed.SSHKey = &key
+		installedHost, err := cloud.GetCloudHost(&installed, init.Settings)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get cloud host for %s", h.Id)
+		}
+		if !installedHost.UsesHostSSHKey() {
+			return errors.Errorf("host %s cannot be connected to with its own SSH key", h.Id)
+		}
+		sshOptions, err = installedHost.GetSSHOptions()
+		if err != nil {
+			return errors.Wrapf(err, "error getting ssh options for host %s", h.Id)
+		}
+		output, err = hostutil.RunSSHCommand(ctx, hostutil.RevokeSSHKeyCommand(distroKey), sshOptions, installed)
+		if err != nil {
+			return errors.Wrapf(err, "error revoking distro SSH key on host %s: %s", h.Id, output)
+		}
+	}
+
+	return errors.Wrapf(h.SetSSHKeyInstalled(), "error recording SSH key installation for host %s", h.Id)
+}
+
 // setupReadyHosts runs the distro setup script of all hosts that are up and reachable.
 func (init *HostInit) setupReadyHosts(ctx context.Context) error {
 	// find all hosts in the uninitialized state
@@ -220,6 +325,21 @@ func (init *HostInit) setupReadyHosts(ctx context.Context) error {
 						"runner":  RunnerName,
 					})
 
+					// hosts that bootstrap from user data provision
+					// themselves and report back to the API
+					if h.Distro.BootstrapsWithUserData() {
+						if err := init.recordUserDataHostRunning(&h); err != nil {
+							grip.Error(message.WrapError(err, message.Fields{
+								"GUID":    init.GUID,
+								"message": "problem checking whether user data host is running",
+								"hostid":  h.Id,
+								"distro":  h.Distro.Id,
+								"runner":  RunnerName,
+							}))
+						}
+						continue
+					}
+
 					// check whether or not the host is ready for its setup script to be run
 					// if the host isn't ready (for instance, it might not be up yet), skip it
 					if ready, err := init.IsHostReady(&h); !ready {
@@ -383,6 +503,30 @@ func (init *HostInit) IsHostReady(host *host.Host) (bool, error) {
 	return reachable, err
 }
 
+// recordUserDataHostRunning moves a host that bootstraps from user data into
+// the provisioning status once its cloud provider reports it running, as
+// setupHost does for other hosts, so that the time it takes to provision
+// itself is measured.
+func (init *HostInit) recordUserDataHostRunning(h *host.Host) error {
+	cloudMgr, err := cloud.GetCloudManager(h.Distro.Provider, init.Settings)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get cloud manager for provider %s", h.Distro.Provider)
+	}
+	hostStatus, err := cloudMgr.GetInstanceStatus(h)
+	if err != nil {
+		return errors.Wrapf(err, "error checking instance status of host %s", h.Id)
+	}
+	if hostStatus != cloud.StatusRunning {
+		return nil
+	}
+
+	// the host may have reported back already
+	if err = h.SetInitializing(); err != nil && err != mgo.ErrNotFound {
+		return errors.Wrapf(err, "error marking host %s as provisioning", h.Id)
+	}
+	return nil
+}
+
 // setupHost runs the specified setup script for an individual host. Returns
 // the output from running the script remotely, as well as any error that
 // occurs. If the script exits with a non-zero exit code, the error will be non-nil.
@@ -411,6 +555,12 @@ func (init *HostInit) setupHost(ctx context.Context, targetHost *host.Host) (str
 		return "", err
 	}
 
+	if targetHost.SSHKey != nil && !targetHost.SSHKey.Installed {
+		if err = init.installSSHKey(ctx, targetHost); err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+
 	// get expansions mapping using settings
 	if targetHost.Distro.Setup == "" {
 		exp := util.NewExpansions(init.Settings.Expansions)
@@ -439,10 +589,29 @@ func (init *HostInit) setupHost(ctx context.Context, targetHost *host.Host) (str
 	return "", nil
 }
 
-// copyScript writes a given script as file "name" to the target host. This works
-// by creating a local copy of the script on the runner's machine, scping it over
-// then removing the local copy.
+// copyScript writes a given script as file "name" to the target host, after
+// replacing the admin settings' expansions in it.
 func (init *HostInit) copyScript(ctx context.Context, target *host.Host, name, script string) error {
+	expanded, err := init.expandScript(script)
+	if err != nil {
+		return errors.Wrapf(err, "error expanding script for host %s", target.Id)
+	}
+
+	return init.copyFile(ctx, target, name, expanded)
+}
+
+// copyFile writes the given contents as file "name" to the target host. This
+// works by creating a local copy of the file on the runner's machine, scping it
+// over then removing the local copy.
+func (init *HostInit) copyFile(ctx context.Context, target *host.Host, name, contents string) error {
+	cloudHost, err := cloud.GetCloudHost(target, init.Settings)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get cloud host for %s", target.Id)
+	}
+	if runner, ok := cloudHost.CloudMgr.(cloud.CommandRunner); ok {
+		return init.writeFile(ctx, runner, target, name, contents)
+	}
+
 	// parse the hostname into the user, host and port
 	hostInfo, err := util.ParseSSHInfo(target.Host)
 	if err != nil {
@@ -475,18 +644,10 @@ func (init *HostInit) copyScript(ctx context.Context, target *host.Host, name, s
 		grip.Error(message.WrapError(os.Remove(file.Name()), errCtx))
 	}()
 
-	expanded, err := init.expandScript(script)
-	if err != nil {
-		return errors.Wrapf(err, "error expanding script for host %s", target.Id)
-	}
-	if _, err = io.WriteString(file, expanded); err != nil {
+	if _, err = io.WriteString(file, contents); err != nil {
 		return errors.Wrap(err, "error writing local script")
 	}
 
-	cloudHost, err := cloud.GetCloudHost(target, init.Settings)
-	if err != nil {
-		return errors.Wrapf(err, "failed to get cloud host for %s", target.Id)
-	}
 	sshOptions, err := cloudHost.GetSSHOptions()
 	if err != nil {
 		return errors.Wrapf(err, "error getting ssh options for host %v", target.Id)
@@ -536,6 +697,32 @@ func (init *HostInit) copyScript(ctx context.Context, target *host.Host, name, s
 	return nil
 }
 
+// writeFile writes the given contents as file "name" to the target host by
+// running a command through the host's cloud provider, for hosts that are
+// not reachable over SSH.
+func (init *HostInit) writeFile(ctx context.Context, runner cloud.CommandRunner, target *host.Host, name, contents string) error {
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, SCPTimeout)
+	defer cancel()
+
+	// the script is passed as an argument so that it does not need to be
+	// quoted for the shell
+	cmd := []string{"sh", "-c", `printf '%s' "$1" > "$HOME/$2" && chmod 700 "$HOME/$2"`, "sh", contents, name}
+	if output, err := runner.RunCommand(ctx, target, cmd); err != nil {
+		grip.Notice(message.WrapError(err, message.Fields{
+			"message": "problem writing script to host",
+			"GUID":    init.GUID,
+			"runner":  RunnerName,
+			"distro":  target.Distro.Id,
+			"host":    target.Id,
+			"output":  output,
+		}))
+
+		return errors.Wrapf(err, "error writing script %s to host %s", name, target.Id)
+	}
+	return nil
+}
+
 // Build the setup script that will need to be run on the specified host.
 func (init *HostInit) expandScript(s string) (string, error) {
 	// replace expansions in the script
@@ -636,7 +823,7 @@ func (init *HostInit) ProvisionHost(ctx context.Context, h *host.Host) error {
 
 		grip.Infof("Running setup script for spawn host %s", h.Id)
 		// run the setup script with the agent
-		if logs, err := hostutil.RunSSHCommand(ctx, hostutil.SetupCommand(h), sshOptions, *h); err != nil {
+		if logs, err := cloudHost.RunCommand(ctx, hostutil.SetupCommand(h)); err != nil {
 			grip.Error(message.WrapError(h.SetUnprovisioned(), message.Fields{
 				"operation": "setting host unprovisioned",
 				"runner":    RunnerName,
@@ -646,6 +833,18 @@ func (init *HostInit) ProvisionHost(ctx context.Context, h *host.Host) error {
 			return errors.Wrapf(err, "error running setup script on remote host: %s", logs)
 		}
 
+		if h.ProvisionOptions.HomeVolumeSize > 0 {
+			grip.Infof("Attaching home volume to spawn host %s", h.Id)
+			if err = init.attachHomeVolume(ctx, cloudHost, sshOptions); err != nil {
+				grip.Error(message.WrapError(h.SetUnprovisioned(), message.Fields{
+					"operation": "setting host unprovisioned",
+					"runner":    RunnerName,
+					"host":      h.Id,
+				}))
+				return errors.Wrapf(err, "error attaching home volume to host %s", h.Id)
+			}
+		}
+
 		if h.ProvisionOptions.OwnerId != "" && len(h.ProvisionOptions.TaskId) > 0 {
 			grip.Info(message.Fields{
 				"message": "fetching data for task on host",
@@ -661,6 +860,14 @@ func (init *HostInit) ProvisionHost(ctx context.Context, h *host.Host) error {
 					"host":    h.Id,
 					"runner":  RunnerName,
 				}))
+
+			grip.Error(message.WrapError(init.setupSpawnHostTask(ctx, h, sshOptions),
+				message.Fields{
+					"message": "failed to set up task on host",
+					"task":    h.ProvisionOptions.TaskId,
+					"host":    h.Id,
+					"runner":  RunnerName,
+				}))
 		}
 	}
 
@@ -739,14 +946,17 @@ func (init *HostInit) LoadClient(ctx context.Context, target *host.Host) (*LoadC
 	// 1. mkdir the destination directory on the host,
 	//    and modify ~/.profile so the target binary will be on the $PATH
 	targetDir := "cli_bin"
-	hostSSHInfo, err := util.ParseSSHInfo(target.Host)
+	cloudHost, err := cloud.GetCloudHost(target, init.Settings)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error parsing ssh info %s", target.Host)
+		return nil, errors.Wrapf(err, "Failed to get cloud host for %s", target.Id)
+	}
+	if cloudHost.UsesCommandRunner() {
+		return init.loadClientThroughProvider(ctx, cloudHost, owner, targetDir)
 	}
 
-	cloudHost, err := cloud.GetCloudHost(target, init.Settings)
+	hostSSHInfo, err := util.ParseSSHInfo(target.Host)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Failed to get cloud host for %s", target.Id)
+		return nil, errors.Wrapf(err, "error parsing ssh info %s", target.Host)
 	}
 	sshOptions, err := cloudHost.GetSSHOptions()
 	if err != nil {
@@ -757,7 +967,7 @@ func (init *HostInit) LoadClient(ctx context.Context, target *host.Host) (*LoadC
 	mkdirOutput := &util.CappedWriter{&bytes.Buffer{}, 1024 * 1024}
 	opts := subprocess.OutputOptions{Output: mkdirOutput, SendErrorToOutput: true}
 	makeShellCmd := subprocess.NewRemoteCommand(
-		fmt.Sprintf("mkdir -m 777 -p ~/%s && (echo 'PATH=$PATH:~/%s' >> ~/.profile || true; echo 'PATH=$PATH:~/%s' >> ~/.bash_profile || true)", targetDir, targetDir, targetDir),
+		cliDirCommand(targetDir),
 		hostSSHInfo.Hostname,
 		target.User,
 		nil,   // env
@@ -819,13 +1029,7 @@ func (init *HostInit) LoadClient(ctx context.Context, target *host.Host) (*LoadC
 	}
 
 	// 4. Write a settings file for the user that owns the host, and scp it to the directory
-	outputStruct := model.CLISettings{
-		User:          owner.Id,
-		APIKey:        owner.APIKey,
-		APIServerHost: init.Settings.ApiUrl + "/api",
-		UIServerHost:  init.Settings.Ui.Url,
-	}
-	outputJSON, err := json.Marshal(outputStruct)
+	outputJSON, err := json.Marshal(init.cliSettings(owner))
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -873,16 +1077,66 @@ func (init *HostInit) LoadClient(ctx context.Context, target *host.Host) (*LoadC
 	}, nil
 }
 
-func (init *HostInit) fetchRemoteTaskData(ctx context.Context, taskId, cliPath, confPath string, target *host.Host) error {
-	hostSSHInfo, err := util.ParseSSHInfo(target.Host)
+// loadClientThroughProvider places the command line client and the owner's
+// settings on a host whose commands are run through its provider.
+func (init *HostInit) loadClientThroughProvider(ctx context.Context, cloudHost *cloud.CloudHost, owner *user.DBUser, targetDir string) (*LoadClientResult, error) {
+	target := cloudHost.Host
+	if output, err := cloudHost.RunCommand(ctx, cliDirCommand(targetDir)); err != nil {
+		return nil, errors.Wrapf(err, "error running setup command for cli, %v", output)
+	}
+	if output, err := cloudHost.RunCommand(ctx, hostutil.CurlCommand(init.Settings.Ui.Url, target)); err != nil {
+		return nil, errors.Wrapf(err, "error running curl command for cli, %v", output)
+	}
+
+	outputJSON, err := json.Marshal(init.cliSettings(owner))
 	if err != nil {
-		return errors.Wrapf(err, "error parsing ssh info %s", target.Host)
+		return nil, errors.WithStack(err)
+	}
+	if err = init.copyFile(ctx, target, fmt.Sprintf("%s/.evergreen.yml", targetDir), string(outputJSON)); err != nil {
+		return nil, errors.Wrap(err, "error writing evergreen.yml")
+	}
+
+	return &LoadClientResult{
+		BinaryPath: filepath.Join("~", "evergreen"),
+		ConfigPath: fmt.Sprintf("%s/.evergreen.yml", targetDir),
+	}, nil
+}
+
+// cliDirCommand returns a command that creates the directory for the command
+// line client. It makes a best effort to add the directory to $PATH upon login.
+func cliDirCommand(targetDir string) string {
+	return fmt.Sprintf("mkdir -m 777 -p ~/%s && (echo 'PATH=$PATH:~/%s' >> ~/.profile || true; echo 'PATH=$PATH:~/%s' >> ~/.bash_profile || true)", targetDir, targetDir, targetDir)
+}
+
+// cliSettings returns the command line client settings for the host's owner.
+func (init *HostInit) cliSettings(owner *user.DBUser) model.CLISettings {
+	return model.CLISettings{
+		User:          owner.Id,
+		APIKey:        owner.APIKey,
+		APIServerHost: init.Settings.ApiUrl + "/api",
+		UIServerHost:  init.Settings.Ui.Url,
 	}
+}
 
+func (init *HostInit) fetchRemoteTaskData(ctx context.Context, taskId, cliPath, confPath string, target *host.Host) error {
 	cloudHost, err := cloud.GetCloudHost(target, init.Settings)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to get cloud host for %v", target.Id)
 	}
+	fetchCmd := fmt.Sprintf("%s -c %s fetch -t %s --source --artifacts --dir='%s'", cliPath, confPath, taskId, target.Distro.WorkDir)
+
+	if runner, ok := cloudHost.CloudMgr.(cloud.CommandRunner); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 15*time.Minute)
+		defer cancel()
+		output, err := runner.RunCommand(ctx, target, []string{"sh", "-c", fetchCmd})
+		return errors.Wrapf(err, "error fetching task data onto host %s: %s", target.Id, output)
+	}
+
+	hostSSHInfo, err := util.ParseSSHInfo(target.Host)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing ssh info %s", target.Host)
+	}
 	sshOptions, err := cloudHost.GetSSHOptions()
 	if err != nil {
 		return errors.Wrapf(err, "Error getting ssh options for host %v", target.Id)
@@ -890,7 +1144,6 @@ func (init *HostInit) fetchRemoteTaskData(ctx context.Context, taskId, cliPath,
 	sshOptions = append(sshOptions, "-o", "UserKnownHostsFile=/dev/null")
 
 	cmdOutput := &util.CappedWriter{&bytes.Buffer{}, 1024 * 1024}
-	fetchCmd := fmt.Sprintf("%s -c %s fetch -t %s --source --artifacts --dir='%s'", cliPath, confPath, taskId, target.Distro.WorkDir)
 	makeShellCmd := subprocess.NewRemoteCommand(
 		fetchCmd,
 		hostSSHInfo.Hostname,
@@ -15,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/smartystreets/goconvey/convey/reporting"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -109,6 +110,39 @@ func TestSetupReadyHosts(t *testing.T) {
 
 }
 
+func TestRecordUserDataHostRunning(t *testing.T) {
+	assert := assert.New(t)
+	assert.NoError(db.ClearCollections(host.Collection))
+	mockCloud := cloud.GetMockProvider()
+	mockCloud.Reset()
+
+	hostInit := &HostInit{Settings: testutil.TestConfig(), GUID: util.RandomString()}
+	h := &host.Host{
+		Id:     "h1",
+		Status: evergreen.HostStarting,
+		Distro: distro.Distro{
+			Provider:        evergreen.ProviderNameMock,
+			BootstrapMethod: distro.BootstrapMethodUserData,
+		},
+	}
+	assert.NoError(h.Insert())
+
+	mockCloud.Set(h.Id, cloud.MockInstance{Status: cloud.StatusInitializing})
+	assert.NoError(hostInit.recordUserDataHostRunning(h))
+	assert.Equal(evergreen.HostStarting, h.Status)
+
+	// the host provisions itself once its provider reports it running
+	mockCloud.Set(h.Id, cloud.MockInstance{Status: cloud.StatusRunning})
+	assert.NoError(hostInit.recordUserDataHostRunning(h))
+	dbHost, err := host.FindOne(host.ById(h.Id))
+	assert.NoError(err)
+	assert.Equal(evergreen.HostInitializing, dbHost.Status)
+
+	// hosts that have moved on are left alone
+	h.Status = evergreen.HostStarting
+	assert.NoError(hostInit.recordUserDataHostRunning(h))
+}
+
 func TestHostIsReady(t *testing.T) {
 	testutil.ConfigureIntegrationTest(t, testutil.TestConfig(), "TestHostIsReady")
 
@@ -254,3 +288,35 @@ func spawnMockHost() (*host.Host, error) {
 
 	return newHost, nil
 }
+
+func TestGenerateSSHKey(t *testing.T) {
+	assert := assert.New(t)
+
+	settings := &evergreen.Settings{}
+	init := &HostInit{Settings: settings}
+	mgr, err := cloud.GetCloudManager(evergreen.ProviderNameMock, settings)
+	assert.NoError(err)
+	h := &host.Host{Id: "h1", Distro: distro.Distro{Arch: "linux_amd64"}}
+
+	// per-host keys are disabled by default
+	assert.NoError(init.generateSSHKey(h, mgr))
+	assert.Nil(h.SSHKey)
+
+	settings.HostSSHKeys = evergreen.HostSSHKeysConfig{Enabled: true, EncryptionKey: "bm90IGEga2V5"}
+	assert.Error(init.generateSSHKey(h, mgr))
+
+	settings.HostSSHKeys.EncryptionKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	assert.NoError(init.generateSSHKey(h, mgr))
+	assert.NotNil(h.SSHKey)
+	assert.False(h.SSHKey.Installed)
+
+	// hosts that bootstrap from user data authorize their key on boot
+	h.Distro.BootstrapMethod = distro.BootstrapMethodUserData
+	assert.NoError(init.generateSSHKey(h, mgr))
+	assert.True(h.SSHKey.Installed)
+
+	h.SSHKey = nil
+	h.Distro.Arch = "windows_amd64"
+	assert.NoError(init.generateSSHKey(h, mgr))
+	assert.Nil(h.SSHKey)
+}
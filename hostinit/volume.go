@@ -0,0 +1,136 @@
+package hostinit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/hostutil"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// homeVolumeMountScript returns a script that waits for the volume's block
+// device to appear and mounts it at ~/volume. It is not mounted over the home
+// directory itself, which holds files the host needs, such as the CLI and
+// authorized keys.
+//
+// On Nitro instances the volume's device is found by its serial, which is
+// the volume id, since the NVMe device names also cover any instance store
+// disks; on other instances it has the name it was attached as. A filesystem
+// is created only on a volume that was just created, so a disk is never
+// formatted because probing it failed. The mount is added to /etc/fstab by
+// the filesystem's UUID, with nofail so that the host still boots without
+// it, so that it is remounted when a stopped host is started again.
+func homeVolumeMountScript(volumeID string, isNew bool) string {
+	format := ""
+	if isNew {
+		format = "sudo mkfs -t ext4 $dev\n"
+	}
+	nvmeDev := "/dev/disk/by-id/nvme-Amazon_Elastic_Block_Store_" + strings.Replace(volumeID, "-", "", 1)
+
+	return fmt.Sprintf(`set -o errexit
+dev=""
+for i in $(seq 1 30); do
+	for candidate in %s /dev/xvdh /dev/sdh; do
+		if [ -b $candidate ]; then
+			dev=$(readlink -f $candidate)
+			break 2
+		fi
+	done
+	sleep 2
+done
+if [ -z "$dev" ]; then
+	echo "home volume device never appeared" >&2
+	exit 1
+fi
+%suuid=$(sudo blkid -s UUID -o value $dev || true)
+if [ -z "$uuid" ]; then
+	echo "home volume $dev has no filesystem" >&2
+	exit 1
+fi
+mkdir -p ~/volume
+sudo sed -i "\\| $HOME/volume |d" /etc/fstab
+echo "UUID=$uuid $HOME/volume ext4 defaults,nofail 0 2" | sudo tee -a /etc/fstab > /dev/null
+sudo mount ~/volume
+sudo chown $(id -u):$(id -g) ~/volume
+`, nvmeDev, format)
+}
+
+// attachHomeVolume attaches the spawn host owner's persistent home volume to
+// the host, creating the volume first if the owner does not have one yet. A
+// volume may only be attached to one host at a time.
+func (init *HostInit) attachHomeVolume(ctx context.Context, cloudHost *cloud.CloudHost, sshOptions []string) error {
+	h := cloudHost.Host
+	mgr, ok := cloudHost.CloudMgr.(cloud.VolumeManager)
+	if !ok {
+		return errors.Errorf("provider '%s' does not support home volumes", h.Provider)
+	}
+
+	volume, err := host.FindOneVolume(host.VolumeByUser(h.StartedBy, h.Provider))
+	if err != nil {
+		return errors.Wrapf(err, "error finding home volume for user %s", h.StartedBy)
+	}
+
+	isNew := volume == nil
+	if isNew {
+		volume, err = mgr.CreateVolume(h, &host.Volume{
+			CreatedBy:    h.StartedBy,
+			Provider:     h.Provider,
+			Size:         h.ProvisionOptions.HomeVolumeSize,
+			CreationTime: time.Now(),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error creating home volume for user %s", h.StartedBy)
+		}
+		if err = volume.Insert(); err != nil {
+			return errors.Wrapf(err, "error inserting volume %s", volume.ID)
+		}
+		grip.Info(message.Fields{
+			"message": "created home volume",
+			"volume":  volume.ID,
+			"user":    h.StartedBy,
+			"host":    h.Id,
+			"runner":  RunnerName,
+		})
+	} else if volume.Host != "" {
+		var previous *host.Host
+		previous, err = host.FindOne(host.ById(volume.Host))
+		if err != nil {
+			return errors.Wrapf(err, "error finding host %s", volume.Host)
+		}
+		if previous != nil && previous.Status != evergreen.HostTerminated {
+			return errors.Errorf("volume %s is still attached to host %s", volume.ID, previous.Id)
+		}
+	}
+
+	if err = mgr.AttachVolume(h, volume); err != nil {
+		return errors.Wrapf(err, "error attaching volume %s to host %s", volume.ID, h.Id)
+	}
+	if err = volume.SetHost(h.Id); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if h.Distro.IsWindows() {
+		return nil
+	}
+
+	logs, err := hostutil.RunSSHCommand(ctx, homeVolumeMountScript(volume.ID, isNew), sshOptions, *h)
+	if err != nil {
+		return errors.Wrapf(err, "error mounting home volume: %s", logs)
+	}
+
+	grip.Info(message.Fields{
+		"message": fmt.Sprintf("attached home volume %s", volume.ID),
+		"user":    h.StartedBy,
+		"host":    h.Id,
+		"runner":  RunnerName,
+	})
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package hostinit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHomeVolumeMountScript(t *testing.T) {
+	assert := assert.New(t)
+
+	script := homeVolumeMountScript("vol-0123abc", false)
+	assert.Contains(script, "/dev/disk/by-id/nvme-Amazon_Elastic_Block_Store_vol0123abc /dev/xvdh /dev/sdh")
+	assert.NotContains(script, "/dev/nvme1n1")
+	assert.NotContains(script, "mkfs")
+	assert.Contains(script, "UUID=$uuid $HOME/volume ext4 defaults,nofail 0 2")
+
+	// only a volume that was just created is formatted
+	assert.Contains(homeVolumeMountScript("vol-0123abc", true), "sudo mkfs -t ext4 $dev\n")
+}
@@ -0,0 +1,201 @@
+package hostutil
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// agentLogFile is the prefix of the agent's log files in the working directory.
+const agentLogFile = "agent"
+
+// AgentCommand returns the command for starting the agent on a host.
+func AgentCommand(settings *evergreen.Settings, h *host.Host) string {
+	parts := []string{
+		filepath.Join("~", binaryName(&h.Distro)),
+		"agent",
+		"--api_server=" + ShellQuote(settings.ApiUrl),
+		"--host_id=" + ShellQuote(h.Id),
+		"--host_secret=" + ShellQuote(h.Secret),
+		"--log_prefix=" + ShellQuote(filepath.Join(h.Distro.WorkDir, agentLogFile)),
+		"--working_directory=" + ShellQuote(h.Distro.WorkDir),
+		"--cleanup",
+	}
+	// spawning an ec2-auto host records whether it chose a spot instance in
+	// its distro's provider
+	if h.Distro.Provider == evergreen.ProviderNameEc2Spot {
+		parts = append(parts, "--spot")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// AgentEnv returns the environment variables the agent needs to send its
+// logs to the configured services.
+func AgentEnv(settings *evergreen.Settings) map[string]string {
+	env := map[string]string{}
+	if sumoEndpoint, ok := settings.Credentials["sumologic"]; ok {
+		env["GRIP_SUMO_ENDPOINT"] = sumoEndpoint
+	}
+
+	if settings.Splunk.Populated() {
+		env["GRIP_SPLUNK_SERVER_URL"] = settings.Splunk.ServerURL
+		env["GRIP_SPLUNK_CLIENT_TOKEN"] = settings.Splunk.Token
+
+		if settings.Splunk.Channel != "" {
+			env["GRIP_SPLUNK_CHANNEL"] = settings.Splunk.Channel
+		}
+	}
+
+	return env
+}
+
+// AgentEnvAssignments returns the agent's environment as shell variable
+// assignments, sorted by name, for prefixing the agent command.
+func AgentEnvAssignments(settings *evergreen.Settings) string {
+	agentEnv := AgentEnv(settings)
+	envKeys := make([]string, 0, len(agentEnv))
+	for k := range agentEnv {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	parts := make([]string, 0, len(envKeys))
+	for _, k := range envKeys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, ShellQuote(agentEnv[k])))
+	}
+	return strings.Join(parts, " ")
+}
+
+// BackgroundAgentCommand returns a command that starts the agent with its
+// environment and returns without waiting for it, for hosts that are not
+// reached over SSH.
+func BackgroundAgentCommand(settings *evergreen.Settings, h *host.Host) string {
+	cmd := "nohup " + AgentCommand(settings, h)
+	if env := AgentEnvAssignments(settings); env != "" {
+		cmd = env + " " + cmd
+	}
+	return cmd + " > /dev/null 2>&1 < /dev/null &"
+}
+
+// ShellQuote quotes the string as a single word for a POSIX shell.
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'"'"'`, -1) + "'"
+}
+
+// instanceLifecycleURL is the EC2 instance metadata URL that reports whether
+// the instance is spot or on-demand.
+const instanceLifecycleURL = "http://169.254.169.254/latest/meta-data/instance-life-cycle"
+
+var bootstrapTemplate = template.Must(template.New("bootstrap").Parse(`#!/bin/bash
+su - {{.User}} <<'EVERGREEN_BOOTSTRAP'
+report() {
+  curl -s -X POST -H '{{.HostHeader}}: {{.HostID}}' -H '{{.SecretHeader}}: {{.Secret}}' --data-binary @bootstrap.log '{{.ReadyURL}}/'"$1"
+}
+cd ~
+{{if .AuthorizeSSHKey}}{{.AuthorizeSSHKey}}
+{{end}}{{if .Setup}}cat > {{.SetupScriptName}} <<'EVERGREEN_SETUP'
+{{.Setup}}
+EVERGREEN_SETUP
+{{end}}{{if .Teardown}}cat > {{.TeardownScriptName}} <<'EVERGREEN_TEARDOWN'
+{{.Teardown}}
+EVERGREEN_TEARDOWN
+{{end}}if ! ({{.CurlCommand}} && {{.SetupCommand}}) > bootstrap.log 2>&1; then
+  report {{.Failed}}
+  exit 1
+fi
+report {{.Success}} || exit 1
+{{if .DetectSpot}}SPOT_FLAG=""
+if [ "$(curl -s -m 10 {{.LifecycleURL}})" = "spot" ]; then
+  SPOT_FLAG="--spot"
+fi
+{{end}}{{.Env}}nohup {{.AgentCommand}}{{if .DetectSpot}} $SPOT_FLAG{{end}} > /dev/null 2>&1 &
+EVERGREEN_BOOTSTRAP
+`))
+
+// BootstrapScript returns a script that provisions the host when its provider
+// runs it as root on boot. The script writes the distro's setup and teardown
+// scripts, downloads the CLI, runs the setup script, reports the result to the
+// API with the host's secret, and starts the agent, so that the app server
+// never needs to connect to the host over SSH. If the host has its own SSH key,
+// the script authorizes it in place of the distro's key before anything else.
+// The script downloads the host's agent revision, where current is the latest
+// built revision. Since the script is rendered before an ec2-auto host chooses
+// between spot and on-demand, such hosts ask the instance metadata whether
+// they are spot before starting the agent.
+func BootstrapScript(settings *evergreen.Settings, h *host.Host, current string) (string, error) {
+	if h.Secret == "" {
+		return "", errors.Errorf("host %s has no secret to report back with", h.Id)
+	}
+	if IsWindows(&h.Distro) {
+		return "", errors.Errorf("cannot bootstrap windows host %s from user data", h.Id)
+	}
+
+	exp := util.NewExpansions(settings.Expansions)
+	setup, err := exp.ExpandString(h.Distro.Setup)
+	if err != nil {
+		return "", errors.Wrap(err, "error expanding setup script")
+	}
+	teardown, err := exp.ExpandString(h.Distro.Teardown)
+	if err != nil {
+		return "", errors.Wrap(err, "error expanding teardown script")
+	}
+
+	env := AgentEnvAssignments(settings)
+	if env != "" {
+		env += " "
+	}
+
+	// the host's own key replaces the distro's key
+	authorizeSSHKey := ""
+	if h.SSHKey != nil {
+		authorizeSSHKey = AuthorizeSSHKeyCommand(h.SSHKey.PublicKey)
+		distroKey, err := DistroPublicKey(settings, &h.Distro)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		if distroKey != "" {
+			authorizeSSHKey += " && " + RevokeSSHKeyCommand(distroKey)
+		}
+	}
+
+	detectSpot := ""
+	if h.Distro.Provider == evergreen.ProviderNameEc2Auto {
+		detectSpot = "true"
+	}
+
+	buf := &bytes.Buffer{}
+	err = bootstrapTemplate.Execute(buf, map[string]string{
+		"User":               h.Distro.User,
+		"HostHeader":         evergreen.HostHeader,
+		"SecretHeader":       evergreen.HostSecretHeader,
+		"HostID":             h.Id,
+		"Secret":             h.Secret,
+		"ReadyURL":           fmt.Sprintf("%s/api/2/host/%s/ready", settings.ApiUrl, h.Id),
+		"Success":            evergreen.HostStatusSuccess,
+		"Failed":             evergreen.HostStatusFailed,
+		"SetupScriptName":    evergreen.SetupScriptName,
+		"Setup":              setup,
+		"TeardownScriptName": evergreen.TeardownScriptName,
+		"Teardown":           teardown,
+		"CurlCommand":        CurlAgentCommand(settings.Ui.Url, h.AgentRevision, current, h),
+		"SetupCommand":       SetupCommand(h),
+		"Env":                env,
+		"AgentCommand":       AgentCommand(settings, h),
+		"AuthorizeSSHKey":    authorizeSSHKey,
+		"DetectSpot":         detectSpot,
+		"LifecycleURL":       instanceLifecycleURL,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "error rendering bootstrap script")
+	}
+
+	return buf.String(), nil
+}
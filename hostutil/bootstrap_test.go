@@ -0,0 +1,143 @@
+package hostutil
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapScript(t *testing.T) {
+	assert := assert.New(t)
+
+	settings := &evergreen.Settings{
+		ApiUrl:      "https://api.example.com",
+		Ui:          evergreen.UIConfig{Url: "https://ui.example.com"},
+		Expansions:  map[string]string{"greeting": "hello"},
+		Credentials: map[string]string{"sumologic": "https://sumo.example.com/it's"},
+	}
+	h := &host.Host{
+		Id:     "h1",
+		Secret: "s3cret",
+		Distro: distro.Distro{
+			Arch:     "linux_amd64",
+			User:     "admin",
+			WorkDir:  "/data/mci",
+			Setup:    "echo ${greeting}",
+			Provider: evergreen.ProviderNameEc2Spot,
+		},
+	}
+
+	script, err := BootstrapScript(settings, h, "abc")
+	assert.NoError(err)
+	assert.Contains(script, "su - admin <<'EVERGREEN_BOOTSTRAP'")
+	assert.Contains(script, "cat > setup.sh <<'EVERGREEN_SETUP'\necho hello\nEVERGREEN_SETUP")
+	assert.NotContains(script, "teardown.sh")
+	assert.Contains(script, CurlCommand(settings.Ui.Url, h))
+
+	// hosts held back by an agent rollout download the earlier build
+	h.AgentRevision = "old"
+	script, err = BootstrapScript(settings, h, "abc")
+	assert.NoError(err)
+	assert.Contains(script, "curl -fLO 'https://ui.example.com/clients/old/linux_amd64/evergreen'")
+	h.AgentRevision = "abc"
+	script, err = BootstrapScript(settings, h, "abc")
+	assert.NoError(err)
+	assert.Contains(script, SetupCommand(h))
+	assert.Contains(script, "-H 'Host-Id: h1' -H 'Host-Secret: s3cret'")
+	assert.Contains(script, "'https://api.example.com/api/2/host/h1/ready/'\"$1\"")
+	assert.Contains(script, "report failed")
+	assert.Contains(script, "report success")
+	assert.Contains(script, `GRIP_SUMO_ENDPOINT='https://sumo.example.com/it'"'"'s' nohup `+AgentCommand(settings, h))
+	assert.Contains(AgentCommand(settings, h), "--spot")
+	assert.NotContains(script, instanceLifecycleURL)
+	assert.NotContains(script, "authorized_keys")
+
+	// ec2-auto hosts choose spot after the script is rendered, so the
+	// script asks the instance whether it is spot
+	h.Distro.Provider = evergreen.ProviderNameEc2Auto
+	assert.NotContains(AgentCommand(settings, h), "--spot")
+	script, err = BootstrapScript(settings, h, "abc")
+	assert.NoError(err)
+	assert.Contains(script, "curl -s -m 10 "+instanceLifecycleURL)
+	assert.Contains(script, "nohup "+AgentCommand(settings, h)+" $SPOT_FLAG > /dev/null")
+	h.Distro.Provider = evergreen.ProviderNameEc2OnDemand
+	assert.NotContains(AgentCommand(settings, h), "--spot")
+	h.Distro.Provider = evergreen.ProviderNameEc2Spot
+
+	h.SSHKey = &host.HostSSHKey{PublicKey: "ssh-rsa AAAAB3Nza"}
+	script, err = BootstrapScript(settings, h, "abc")
+	assert.NoError(err)
+	assert.Contains(script, "cd ~\n"+AuthorizeSSHKeyCommand("ssh-rsa AAAAB3Nza")+"\n")
+
+	// the distro's key is revoked once the host's key is authorized
+	dir, err := ioutil.TempDir("", "bootstrap-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	private, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "distro_key"), pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(private),
+	}), 0600))
+	settings.Keys = map[string]string{"distro_key": filepath.Join(dir, "distro_key")}
+	h.Distro.SSHKey = "distro_key"
+	distroKey, err := DistroPublicKey(settings, &h.Distro)
+	require.NoError(t, err)
+	assert.True(strings.HasPrefix(distroKey, "ssh-rsa "))
+	script, err = BootstrapScript(settings, h, "abc")
+	assert.NoError(err)
+	assert.Contains(script, AuthorizeSSHKeyCommand("ssh-rsa AAAAB3Nza")+" && "+RevokeSSHKeyCommand(distroKey)+"\n")
+
+	h.Distro.SSHKey = "missing"
+	_, err = BootstrapScript(settings, h, "abc")
+	assert.Error(err)
+	h.Distro.SSHKey = ""
+	h.SSHKey = nil
+
+	// hosts without secrets have no way to report back
+	h.Secret = ""
+	_, err = BootstrapScript(settings, h, "abc")
+	assert.Error(err)
+
+	h.Secret = util.RandomString()
+	h.Distro.Arch = "windows_amd64"
+	_, err = BootstrapScript(settings, h, "abc")
+	assert.Error(err)
+}
+
+func TestBackgroundAgentCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	settings := &evergreen.Settings{ApiUrl: "https://api.example.com"}
+	h := &host.Host{Id: "pod1", Secret: "s3cret", Distro: distro.Distro{Arch: "linux_amd64"}}
+	assert.Equal("nohup "+AgentCommand(settings, h)+" > /dev/null 2>&1 < /dev/null &", BackgroundAgentCommand(settings, h))
+
+	settings.Credentials = map[string]string{"sumologic": "https://sumo.example.com/it's"}
+	assert.Equal(`GRIP_SUMO_ENDPOINT='https://sumo.example.com/it'"'"'s' nohup `+AgentCommand(settings, h)+" > /dev/null 2>&1 < /dev/null &",
+		BackgroundAgentCommand(settings, h))
+
+	assert.Equal(`''`, ShellQuote(""))
+	assert.Equal(`'a b'`, ShellQuote("a b"))
+}
+
+func TestRevokeSSHKeyCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("grep -vF -e 'AAAAB3Nza' ~/.ssh/authorized_keys > ~/.ssh/authorized_keys.tmp; "+
+		"chmod 600 ~/.ssh/authorized_keys.tmp && mv ~/.ssh/authorized_keys.tmp ~/.ssh/authorized_keys",
+		RevokeSSHKeyCommand("ssh-rsa AAAAB3Nza comment"))
+	assert.Contains(RevokeSSHKeyCommand("ssh-rsa AAAAB3Nza", "ssh-ed25519 AAAAC3Nza"), "grep -vF -e 'AAAAB3Nza' -e 'AAAAC3Nza' ")
+}
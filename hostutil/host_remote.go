@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/subprocess"
@@ -15,6 +17,7 @@ import (
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
 )
 
 const (
@@ -49,6 +52,32 @@ func CurlCommand(url string, host *host.Host) string {
 		binaryName(&host.Distro))
 }
 
+// CurlRevisionCommand returns a command for curling the agent binary of an
+// earlier revision to a host. The task runner keeps a copy of each build it
+// has deployed in a subdirectory of the clients directory named for the
+// revision.
+func CurlRevisionCommand(url, revision string, host *host.Host) string {
+	return fmt.Sprintf("cd ~ && curl -fLO '%s/clients/%s' && chmod +x %s",
+		url,
+		RevisionExecutableSubPath(revision, &host.Distro),
+		binaryName(&host.Distro))
+}
+
+// CurlAgentCommand returns a command for curling the agent binary of the
+// given revision to a host, where current is the latest built revision.
+func CurlAgentCommand(url, revision, current string, host *host.Host) string {
+	if revision == "" || revision == current {
+		return CurlCommand(url, host)
+	}
+	return CurlRevisionCommand(url, revision, host)
+}
+
+// RevisionExecutableSubPath returns the path of the agent binary of the
+// given revision, relative to the clients directory.
+func RevisionExecutableSubPath(revision string, d *distro.Distro) string {
+	return filepath.Join(revision, executableSubPath(d))
+}
+
 // SetupCommand returns a command for running the setup script on a host
 func SetupCommand(host *host.Host) string {
 	cmd := fmt.Sprintf("%s host setup",
@@ -62,6 +91,58 @@ func SetupCommand(host *host.Host) string {
 	return cmd
 }
 
+// AuthorizeSSHKeyCommand returns a command that adds the public key to the
+// user's authorized keys if it is not already there.
+func AuthorizeSSHKeyCommand(publicKey string) string {
+	return fmt.Sprintf("mkdir -p ~/.ssh && chmod 700 ~/.ssh && "+
+		"(grep -qxF '%[1]s' ~/.ssh/authorized_keys 2>/dev/null || echo '%[1]s' >> ~/.ssh/authorized_keys) && "+
+		"chmod 600 ~/.ssh/authorized_keys", publicKey)
+}
+
+// RevokeSSHKeyCommand returns a command that removes the public keys from the
+// user's authorized keys. Keys are matched on their encoded key data, so that
+// entries with options or a different comment are removed as well.
+func RevokeSSHKeyCommand(publicKeys ...string) string {
+	patterns := make([]string, 0, len(publicKeys))
+	for _, key := range publicKeys {
+		patterns = append(patterns, "-e "+ShellQuote(sshKeyData(key)))
+	}
+	return fmt.Sprintf("grep -vF %s ~/.ssh/authorized_keys > ~/.ssh/authorized_keys.tmp; "+
+		"chmod 600 ~/.ssh/authorized_keys.tmp && mv ~/.ssh/authorized_keys.tmp ~/.ssh/authorized_keys",
+		strings.Join(patterns, " "))
+}
+
+// sshKeyData returns the encoded key data of a public key in authorized_keys
+// format, which follows the key type.
+func sshKeyData(publicKey string) string {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return publicKey
+	}
+	return fields[1]
+}
+
+// DistroPublicKey returns the public key, in authorized_keys format, of the
+// distro's SSH key. It returns an empty key if the distro has none.
+func DistroPublicKey(settings *evergreen.Settings, d *distro.Distro) (string, error) {
+	if d.SSHKey == "" {
+		return "", nil
+	}
+	path, ok := settings.Keys[d.SSHKey]
+	if !ok {
+		return "", errors.Errorf("SSH key '%s' of distro %s is not configured", d.SSHKey, d.Id)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem reading SSH key of distro %s", d.Id)
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem parsing SSH key of distro %s", d.Id)
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))), nil
+}
+
 // TearDownCommand returns a command for running a teardown script on a host.
 func TearDownCommand(host *host.Host) string {
 	cmd := fmt.Sprintf("%s host teardown",
@@ -11,6 +11,7 @@ var (
 	IdKey               = bsonutil.MustHaveTag(Distro{}, "Id")
 	ArchKey             = bsonutil.MustHaveTag(Distro{}, "Arch")
 	PoolSizeKey         = bsonutil.MustHaveTag(Distro{}, "PoolSize")
+	WarmPoolSizeKey     = bsonutil.MustHaveTag(Distro{}, "WarmPoolSize")
 	ProviderKey         = bsonutil.MustHaveTag(Distro{}, "Provider")
 	ProviderSettingsKey = bsonutil.MustHaveTag(Distro{}, "ProviderSettings")
 	SetupAsSudoKey      = bsonutil.MustHaveTag(Distro{}, "SetupAsSudo")
@@ -20,7 +21,8 @@ var (
 	SSHOptionsKey       = bsonutil.MustHaveTag(Distro{}, "SSHOptions")
 	WorkDirKey          = bsonutil.MustHaveTag(Distro{}, "WorkDir")
 
-	UserDataKey = bsonutil.MustHaveTag(Distro{}, "UserData")
+	UserDataKey        = bsonutil.MustHaveTag(Distro{}, "UserData")
+	BootstrapMethodKey = bsonutil.MustHaveTag(Distro{}, "BootstrapMethod")
 
 	SpawnAllowedKey = bsonutil.MustHaveTag(Distro{}, "SpawnAllowed")
 	ExpansionsKey   = bsonutil.MustHaveTag(Distro{}, "Expansions")
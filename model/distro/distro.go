@@ -16,11 +16,20 @@ const (
 	UserDataFormatYAML           = "yaml"
 )
 
+// Methods of provisioning hosts. Hosts are provisioned over SSH from the app
+// server by default; with user data, the host provisions itself on boot and
+// calls back to the API when it is ready.
+const (
+	BootstrapMethodSSH      = "ssh"
+	BootstrapMethodUserData = "user-data"
+)
+
 type Distro struct {
 	Id               string                  `bson:"_id" json:"_id,omitempty" mapstructure:"_id,omitempty"`
 	Arch             string                  `bson:"arch" json:"arch,omitempty" mapstructure:"arch,omitempty"`
 	WorkDir          string                  `bson:"work_dir" json:"work_dir,omitempty" mapstructure:"work_dir,omitempty"`
 	PoolSize         int                     `bson:"pool_size,omitempty" json:"pool_size,omitempty" mapstructure:"pool_size,omitempty" yaml:"poolsize"`
+	WarmPoolSize     int                     `bson:"warm_pool_size,omitempty" json:"warm_pool_size,omitempty" mapstructure:"warm_pool_size,omitempty" yaml:"warmpoolsize"`
 	Provider         string                  `bson:"provider" json:"provider,omitempty" mapstructure:"provider,omitempty"`
 	ProviderSettings *map[string]interface{} `bson:"settings" json:"settings,omitempty" mapstructure:"settings,omitempty"`
 
@@ -32,6 +41,8 @@ type Distro struct {
 	SSHOptions  []string `bson:"ssh_options,omitempty" json:"ssh_options,omitempty" mapstructure:"ssh_options,omitempty"`
 	UserData    UserData `bson:"user_data,omitempty" json:"user_data,omitempty" mapstructure:"user_data,omitempty"`
 
+	BootstrapMethod string `bson:"bootstrap_method,omitempty" json:"bootstrap_method,omitempty" mapstructure:"bootstrap_method,omitempty"`
+
 	SpawnAllowed bool        `bson:"spawn_allowed" json:"spawn_allowed,omitempty" mapstructure:"spawn_allowed,omitempty"`
 	Expansions   []Expansion `bson:"expansions,omitempty" json:"expansions,omitempty" mapstructure:"expansions,omitempty"`
 }
@@ -58,6 +69,12 @@ func (d *Distro) GenerateName() string {
 	return fmt.Sprintf("evg-%s-%s-%d", d.Id, time.Now().Format(evergreen.NameTimeFormat), rand.Int())
 }
 
+// BootstrapsWithUserData returns whether hosts in the distro provision
+// themselves from user data rather than being provisioned over SSH.
+func (d *Distro) BootstrapsWithUserData() bool {
+	return d.BootstrapMethod == BootstrapMethodUserData
+}
+
 func (d *Distro) IsWindows() bool {
 	// XXX: if this is-windows check is updated, make sure to also update
 	// public/static/js/spawned_hosts.js as well
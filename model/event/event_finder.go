@@ -67,6 +67,61 @@ func HostEventsInOrder(id string) db.Q {
 	return HostEventsForId(id).Sort([]string{TimestampKey})
 }
 
+// MostRecentHostEventsOfTypes returns a query for the n most recent events
+// of the given types for a host that happened after the given time.
+func MostRecentHostEventsOfTypes(id string, since time.Time, n int, types ...string) db.Q {
+	return db.Query(bson.M{
+		DataKey + "." + ResourceTypeKey: ResourceTypeHost,
+		ResourceIdKey:                   id,
+		TypeKey:                         bson.M{"$in": types},
+		TimestampKey:                    bson.M{"$gt": since},
+	}).Sort([]string{"-" + TimestampKey}).Limit(n)
+}
+
+// HostEventsByTime returns a query for up to limit events of a host,
+// restricted to the given types if there are any. Events at or before ts are
+// returned newest first, or, if sortAsc is set, events after ts oldest first.
+func HostEventsByTime(id string, types []string, ts time.Time, limit int, sortAsc bool) db.Q {
+	filter := bson.M{
+		DataKey + "." + ResourceTypeKey: ResourceTypeHost,
+		ResourceIdKey:                   id,
+	}
+	if len(types) > 0 {
+		filter[TypeKey] = bson.M{"$in": types}
+	}
+
+	sortSpec := TimestampKey
+	if !sortAsc {
+		sortSpec = "-" + sortSpec
+		filter[TimestampKey] = bson.M{"$lte": ts}
+	} else {
+		filter[TimestampKey] = bson.M{"$gt": ts}
+	}
+	return db.Query(filter).Sort([]string{sortSpec}).Limit(limit)
+}
+
+// HostEventsOfTypesSince returns a query for the events of the given types
+// for any of the given hosts that happened after the given time.
+func HostEventsOfTypesSince(ids []string, since time.Time, types ...string) db.Q {
+	return db.Query(bson.M{
+		DataKey + "." + ResourceTypeKey: ResourceTypeHost,
+		ResourceIdKey:                   bson.M{"$in": ids},
+		TypeKey:                         bson.M{"$in": types},
+		TimestampKey:                    bson.M{"$gt": since},
+	})
+}
+
+// MostRecentHostStatusChangeFrom returns a query for the most recent event in
+// which the host's status changed from the given status.
+func MostRecentHostStatusChangeFrom(id, oldStatus string) db.Q {
+	return db.Query(bson.M{
+		DataKey + "." + ResourceTypeKey:      ResourceTypeHost,
+		ResourceIdKey:                        id,
+		TypeKey:                              EventHostStatusChanged,
+		DataKey + "." + hostDataOldStatusKey: oldStatus,
+	}).Sort([]string{"-" + TimestampKey}).Limit(1)
+}
+
 // Task Events
 func TaskEventsForId(id string) db.Q {
 	return db.Query(bson.D{
@@ -32,6 +32,7 @@ const (
 	EventTaskFinished             = "HOST_TASK_FINISHED"
 	EventHostTeardown             = "HOST_TEARDOWN"
 	EventHostTerminatedExternally = "HOST_TERMINATED_EXTERNALLY"
+	EventHostQuarantined          = "HOST_QUARANTINED"
 )
 
 // implements EventData
@@ -57,6 +58,7 @@ type HostEventData struct {
 var (
 	hostDataResourceTypeKey = bsonutil.MustHaveTag(HostEventData{}, "ResourceType")
 	hostDataStatusKey       = bsonutil.MustHaveTag(HostEventData{}, "TaskStatus")
+	hostDataOldStatusKey    = bsonutil.MustHaveTag(HostEventData{}, "OldStatus")
 )
 
 func (self HostEventData) IsValid() bool {
@@ -145,6 +147,12 @@ func LogHostTeardown(hostId, teardownLogs string, success bool, duration time.Du
 		HostEventData{Logs: teardownLogs, Successful: success, Duration: duration})
 }
 
+// LogHostQuarantined records that a host was quarantined automatically
+// because of its recent failures.
+func LogHostQuarantined(hostId, reason string) {
+	LogHostEvent(hostId, EventHostQuarantined, HostEventData{Logs: reason, User: evergreen.User})
+}
+
 func LogMonitorOperation(hostId string, op string) {
 	LogHostEvent(hostId, EventHostMonitorFlag, HostEventData{MonitorOp: op})
 }
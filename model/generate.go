@@ -0,0 +1,361 @@
+package model
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/yaml.v2"
+)
+
+// Tasks may add to their version's project at runtime with the
+// generate.tasks command, which sends files of project configuration that
+// are merged into the version's config by these rules:
+//  - functions, tasks and task groups are added to the project; generating
+//    one that is already defined is an error.
+//  - build variants that are not defined are added. For variants that are,
+//    the generated tasks and display tasks are added to the variant and its
+//    other settings are ignored.
+//  - matrices and project settings may not be generated.
+// The builds and tasks of the generated variants are then created and
+// activated. A task's files are merged only once, so a generator that is
+// restarted does not generate its tasks again.
+
+// GeneratedProject is a version's project with the configuration generated
+// by a task merged in.
+type GeneratedProject struct {
+	Project *Project
+	// Config is the version's new config. It is empty if the task's
+	// configuration had already been merged into the version.
+	Config string
+	// Tasks are the tasks and display tasks to create for the generated
+	// variants.
+	Tasks TaskVariantPairs
+}
+
+// MergeGeneratedProject merges the configuration files generated by the task
+// into its version's project. If dependOnGenerator is set, the generated
+// tasks depend on the generator.
+func MergeGeneratedProject(v *version.Version, generator *task.Task, files []string, dependOnGenerator bool) (*GeneratedProject, error) {
+	pp, errs := createIntermediateProject([]byte(v.Config))
+	if len(errs) > 0 {
+		return nil, errors.Wrapf(projectErrors(errs), "error parsing config of version %s", v.Id)
+	}
+
+	generated := make([]*parserProject, 0, len(files))
+	for i, file := range files {
+		gp, errs := createIntermediateProject([]byte(file))
+		if len(errs) > 0 {
+			return nil, errors.Wrapf(projectErrors(errs), "error parsing generated file %d", i)
+		}
+		if errs = checkGeneratedProject(gp); len(errs) > 0 {
+			return nil, errors.Wrapf(projectErrors(errs), "generated file %d is invalid", i)
+		}
+		generated = append(generated, gp)
+	}
+
+	merged := util.StringSliceContains(v.GeneratedBy, generator.Id)
+	if !merged {
+		for i, gp := range generated {
+			if dependOnGenerator {
+				addGeneratorDependency(gp, generator)
+			}
+			if errs = mergeGeneratedProject(pp, gp); len(errs) > 0 {
+				return nil, errors.Wrapf(projectErrors(errs), "error merging generated file %d", i)
+			}
+		}
+	}
+
+	project, errs := translateProject(pp)
+	if len(errs) > 0 {
+		return nil, errors.Wrap(projectErrors(errs), "error evaluating generated project")
+	}
+	project.Identifier = v.Identifier
+
+	g := &GeneratedProject{Project: project}
+	tse := NewParserTaskSelectorEvaluator(pp.Tasks)
+	tgse := newTaskGroupSelectorEvaluator(pp.TaskGroups)
+	vse := NewVariantSelectorEvaluator(pp.BuildVariants, NewAxisSelectorEvaluator(pp.Axes))
+	for _, gp := range generated {
+		for _, bv := range gp.BuildVariants {
+			units, errs := evaluateBVTasks(tse, tgse, vse, bv.Tasks)
+			if len(errs) > 0 {
+				return nil, errors.Wrapf(projectErrors(errs), "error evaluating tasks of generated variant '%s'", bv.Name)
+			}
+			for _, unit := range units {
+				g.Tasks.ExecTasks = append(g.Tasks.ExecTasks, TVPair{Variant: bv.Name, TaskName: unit.Name})
+			}
+			for _, dt := range bv.DisplayTasks {
+				g.Tasks.DisplayTasks = append(g.Tasks.DisplayTasks, TVPair{Variant: bv.Name, TaskName: dt.Name})
+			}
+		}
+	}
+
+	if !merged {
+		config, err := yaml.Marshal(project)
+		if err != nil {
+			return nil, errors.Wrap(err, "error marshaling generated project")
+		}
+		g.Config = string(config)
+	}
+
+	return g, nil
+}
+
+// checkGeneratedProject returns errors for the parts of a generated file
+// that may only be set in the project's configuration file.
+func checkGeneratedProject(gp *parserProject) []error {
+	settings := setProjectSettings(gp)
+	for name, set := range map[string]bool{
+		"include": len(gp.Include) > 0,
+		"ignore":  len(gp.Ignore) > 0,
+		"modules": len(gp.Modules) > 0,
+		"axes":    len(gp.Axes) > 0,
+		"pre":     gp.Pre != nil,
+		"post":    gp.Post != nil,
+		"timeout": gp.Timeout != nil,
+	} {
+		if set {
+			settings = append(settings, name)
+		}
+	}
+	sort.Strings(settings)
+
+	var errs []error
+	if len(settings) > 0 {
+		errs = append(errs, errors.Errorf("generated configuration sets %s, which may only be set in the main configuration file",
+			strings.Join(settings, ", ")))
+	}
+	for _, bv := range gp.BuildVariants {
+		if bv.matrix != nil {
+			errs = append(errs, errors.Errorf("generated configuration defines matrix '%s', but may not define matrices", bv.matrix.Id))
+		}
+	}
+	return errs
+}
+
+// addGeneratorDependency makes the generated tasks depend on the generator.
+func addGeneratorDependency(gp *parserProject, generator *task.Task) {
+	for i := range gp.Tasks {
+		gp.Tasks[i].DependsOn = append(gp.Tasks[i].DependsOn, parserDependency{
+			taskSelector: taskSelector{
+				Name:    generator.DisplayName,
+				Variant: &variantSelector{stringSelector: generator.BuildVariant},
+			},
+		})
+	}
+}
+
+// mergeGeneratedProject adds the generated definitions to the project,
+// following the rules described at the top of this file.
+func mergeGeneratedProject(pp, gp *parserProject) []error {
+	var errs []error
+	duplicate := func(kind, name string) {
+		errs = append(errs, errors.Errorf("generated %s '%s' is already defined", kind, name))
+	}
+
+	if pp.Functions == nil && len(gp.Functions) > 0 {
+		pp.Functions = map[string]*YAMLCommandSet{}
+	}
+	functionNames := make([]string, 0, len(gp.Functions))
+	for name := range gp.Functions {
+		functionNames = append(functionNames, name)
+	}
+	sort.Strings(functionNames)
+	for _, name := range functionNames {
+		if _, ok := pp.Functions[name]; ok {
+			duplicate("function", name)
+			continue
+		}
+		pp.Functions[name] = gp.Functions[name]
+	}
+
+	taskNames := map[string]bool{}
+	for _, t := range pp.Tasks {
+		taskNames[t.Name] = true
+	}
+	for _, t := range gp.Tasks {
+		if taskNames[t.Name] {
+			duplicate("task", t.Name)
+			continue
+		}
+		taskNames[t.Name] = true
+		pp.Tasks = append(pp.Tasks, t)
+	}
+
+	groupNames := map[string]bool{}
+	for _, tg := range pp.TaskGroups {
+		groupNames[tg.Name] = true
+	}
+	for _, tg := range gp.TaskGroups {
+		if groupNames[tg.Name] {
+			duplicate("task group", tg.Name)
+			continue
+		}
+		groupNames[tg.Name] = true
+		pp.TaskGroups = append(pp.TaskGroups, tg)
+	}
+
+	for _, bv := range gp.BuildVariants {
+		var existing *parserBV
+		for i := range pp.BuildVariants {
+			if pp.BuildVariants[i].Name == bv.Name {
+				existing = &pp.BuildVariants[i]
+				break
+			}
+		}
+		if existing == nil {
+			pp.BuildVariants = append(pp.BuildVariants, bv)
+			continue
+		}
+		existing.Tasks = append(existing.Tasks, bv.Tasks...)
+		existing.DisplayTasks = append(existing.DisplayTasks, bv.DisplayTasks...)
+	}
+
+	return errs
+}
+
+// AddGeneratedBuildsAndTasks creates the generated tasks that do not exist
+// yet, adding them to the version's existing builds or creating builds for
+// them. The tasks are activated.
+func AddGeneratedBuildsAndTasks(v *version.Version, project *Project, pairs TaskVariantPairs) error {
+	builds, err := build.Find(build.ByVersion(v.Id))
+	if err != nil {
+		return errors.Wrapf(err, "error finding builds of version %s", v.Id)
+	}
+	buildsByVariant := map[string]*build.Build{}
+	for i := range builds {
+		buildsByVariant[builds[i].BuildVariant] = &builds[i]
+	}
+
+	taskIds := NewTaskIdTable(project, v)
+	newBuildIds := []string{}
+	newBuildStatuses := []version.BuildStatus{}
+	variantsProcessed := map[string]bool{}
+	for _, pair := range pairs.ExecTasks {
+		if variantsProcessed[pair.Variant] {
+			continue
+		}
+		variantsProcessed[pair.Variant] = true
+		taskNames := pairs.ExecTasks.TaskNames(pair.Variant)
+		displayNames := pairs.DisplayTasks.TaskNames(pair.Variant)
+
+		b, ok := buildsByVariant[pair.Variant]
+		if !ok {
+			buildId, err := CreateBuildFromVersion(project, v, taskIds, pair.Variant, true, taskNames, displayNames)
+			if err != nil {
+				return errors.Wrapf(err, "error creating build for generated variant '%s'", pair.Variant)
+			}
+			newBuildIds = append(newBuildIds, buildId)
+			newBuildStatuses = append(newBuildStatuses, version.BuildStatus{
+				BuildVariant: pair.Variant,
+				BuildId:      buildId,
+				Activated:    true,
+			})
+			continue
+		}
+
+		tasksToAdd, displayTasksToAdd, err := missingTasks(project, b, taskNames, displayNames)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if len(tasksToAdd) == 0 {
+			continue
+		}
+		// the tasks take the build's activation
+		if !b.Activated {
+			b.Activated = true
+			if err = activateGeneratedBuild(v, b); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if _, err = AddTasksToBuild(b, project, v, tasksToAdd, displayTasksToAdd); err != nil {
+			return errors.Wrapf(err, "error adding generated tasks to build %s", b.Id)
+		}
+		grip.Infof("Added %d generated tasks to build %s", len(tasksToAdd), b.Id)
+	}
+
+	if len(newBuildIds) == 0 {
+		return nil
+	}
+	return errors.Wrapf(version.UpdateOne(
+		bson.M{version.IdKey: v.Id},
+		bson.M{
+			"$push": bson.M{
+				version.BuildIdsKey:      bson.M{"$each": newBuildIds},
+				version.BuildVariantsKey: bson.M{"$each": newBuildStatuses},
+			},
+		},
+	), "error adding generated builds to version %s", v.Id)
+}
+
+// activateGeneratedBuild activates an existing build that generated tasks are
+// added to, along with its variant's status in the version, without
+// activating the tasks already in it.
+func activateGeneratedBuild(v *version.Version, b *build.Build) error {
+	if err := build.UpdateActivation(b.Id, true, evergreen.APIServerTaskActivator); err != nil {
+		return errors.Wrapf(err, "error activating build %s", b.Id)
+	}
+	err := version.UpdateOne(
+		bson.M{
+			version.IdKey: v.Id,
+			bsonutil.GetDottedKeyName(version.BuildVariantsKey, version.BuildStatusVariantKey): b.BuildVariant,
+		},
+		bson.M{"$set": bson.M{
+			bsonutil.GetDottedKeyName(version.BuildVariantsKey, "$", version.BuildStatusActivatedKey): true,
+		}},
+	)
+	if err != nil && err != mgo.ErrNotFound {
+		return errors.Wrapf(err, "error activating variant %s in version %s", b.BuildVariant, v.Id)
+	}
+	for i := range v.BuildVariants {
+		if v.BuildVariants[i].BuildVariant == b.BuildVariant {
+			v.BuildVariants[i].Activated = true
+		}
+	}
+	return nil
+}
+
+// missingTasks returns the tasks and display tasks that do not exist yet in
+// the build. A task group is missing if none of its tasks exist.
+func missingTasks(project *Project, b *build.Build, taskNames, displayNames []string) ([]string, []string, error) {
+	tasksInBuild, err := task.Find(task.ByBuildId(b.Id).WithFields(task.DisplayNameKey))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error finding tasks of build %s", b.Id)
+	}
+	existing := map[string]bool{}
+	for _, t := range tasksInBuild {
+		existing[t.DisplayName] = true
+	}
+
+	tasksToAdd := []string{}
+	for _, name := range taskNames {
+		names := []string{name}
+		if tg := project.FindTaskGroup(name); tg != nil {
+			names = tg.Tasks
+		}
+		exists := false
+		for _, n := range names {
+			exists = exists || existing[n]
+		}
+		if !exists {
+			tasksToAdd = append(tasksToAdd, name)
+		}
+	}
+	displayTasksToAdd := []string{}
+	for _, name := range displayNames {
+		if !existing[name] {
+			displayTasksToAdd = append(displayTasksToAdd, name)
+		}
+	}
+	return tasksToAdd, displayTasksToAdd, nil
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const generatorProject = `
+functions:
+  run:
+    command: shell.exec
+tasks:
+- name: generator
+  commands:
+  - command: generate.tasks
+    params:
+      files: ["generated.json"]
+- name: compile
+buildvariants:
+- name: linux
+  run_on: ["linux"]
+  tasks:
+  - name: generator
+  - name: compile
+`
+
+func TestMergeGeneratedProject(t *testing.T) {
+	assert := assert.New(t)
+	project := &Project{}
+	require.NoError(t, LoadProjectInto([]byte(generatorProject), "generate", project))
+	config, err := yaml.Marshal(project)
+	require.NoError(t, err)
+	v := &version.Version{Id: "v", Identifier: "generate", Config: string(config)}
+	generator := &task.Task{Id: "generator_id", DisplayName: "generator", BuildVariant: "linux"}
+
+	files := []string{
+		`{"tasks": [{"name": "lint", "commands": [{"func": "run"}]}],
+		  "buildvariants": [{"name": "linux", "tasks": ["lint"]}]}`,
+		`
+tasks:
+- name: test
+buildvariants:
+- name: windows
+  run_on: ["windows"]
+  display_tasks:
+  - name: all
+    execution_tasks: ["test"]
+  tasks:
+  - name: test
+`,
+	}
+	g, err := MergeGeneratedProject(v, generator, files, true)
+	require.NoError(t, err)
+	assert.NotEmpty(g.Config)
+	assert.Equal("generate", g.Project.Identifier)
+	assert.Equal([]string{"linux/lint", "windows/test"}, pairNames(g.Tasks.ExecTasks))
+	assert.Equal([]string{"windows/all"}, pairNames(g.Tasks.DisplayTasks))
+
+	linux := g.Project.FindBuildVariant("linux")
+	require.NotNil(t, linux)
+	assert.Len(linux.Tasks, 3)
+	require.NotNil(t, g.Project.FindBuildVariant("windows"))
+	test := g.Project.FindProjectTask("test")
+	require.NotNil(t, test)
+	require.Len(t, test.DependsOn, 1)
+	assert.Equal("generator", test.DependsOn[0].Name)
+	assert.Equal("linux", test.DependsOn[0].Variant)
+
+	// the generated config parses as a project
+	merged := &Project{}
+	require.NoError(t, LoadProjectInto([]byte(g.Config), "generate", merged))
+	assert.NotNil(merged.FindProjectTask("lint"))
+
+	// a restarted generator does not merge its files again
+	v.Config = g.Config
+	v.GeneratedBy = []string{generator.Id}
+	g, err = MergeGeneratedProject(v, generator, files, true)
+	require.NoError(t, err)
+	assert.Empty(g.Config)
+	assert.Equal([]string{"linux/lint", "windows/test"}, pairNames(g.Tasks.ExecTasks))
+	assert.Len(g.Project.FindBuildVariant("linux").Tasks, 3)
+}
+
+func TestMergeGeneratedProjectErrors(t *testing.T) {
+	project := &Project{}
+	require.NoError(t, LoadProjectInto([]byte(generatorProject), "generate", project))
+	config, err := yaml.Marshal(project)
+	require.NoError(t, err)
+	v := &version.Version{Id: "v", Identifier: "generate", Config: string(config)}
+	generator := &task.Task{Id: "generator_id", DisplayName: "generator", BuildVariant: "linux"}
+
+	for name, file := range map[string]string{
+		"DuplicateTask":     `{"tasks": [{"name": "compile"}]}`,
+		"DuplicateFunction": `{"functions": {"run": {"command": "shell.exec"}}}`,
+		"ProjectSetting":    `{"stepback": true}`,
+		"Pre":               `{"pre": [{"command": "shell.exec"}]}`,
+		"Matrix":            `{"buildvariants": [{"matrix_name": "m", "matrix_spec": {"os": "*"}}]}`,
+		"Invalid":           `{"tasks": "`,
+		"UndefinedTask":     `{"buildvariants": [{"name": "linux", "tasks": ["nonexistent"]}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := MergeGeneratedProject(v, generator, []string{file}, false)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestActivateGeneratedBuild(t *testing.T) {
+	assert := assert.New(t)
+	require.NoError(t, db.ClearCollections(build.Collection, version.Collection))
+
+	b := &build.Build{Id: "b1", Version: "v1", BuildVariant: "linux"}
+	require.NoError(t, b.Insert())
+	v := &version.Version{
+		Id: "v1",
+		BuildVariants: []version.BuildStatus{
+			{BuildVariant: "linux", BuildId: "b1"},
+			{BuildVariant: "windows", BuildId: "b2"},
+		},
+	}
+	require.NoError(t, v.Insert())
+
+	require.NoError(t, activateGeneratedBuild(v, b))
+	assert.True(v.BuildVariants[0].Activated)
+	assert.False(v.BuildVariants[1].Activated)
+
+	dbBuild, err := build.FindOne(build.ById("b1"))
+	require.NoError(t, err)
+	assert.True(dbBuild.Activated)
+	dbVersion, err := version.FindOne(version.ById("v1"))
+	require.NoError(t, err)
+	assert.True(dbVersion.BuildVariants[0].Activated)
+	assert.False(dbVersion.BuildVariants[1].Activated)
+}
@@ -0,0 +1,350 @@
+package host
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// AgentRolloutsCollection is the name of the MongoDB collection that
+	// stores agent rollouts.
+	AgentRolloutsCollection = "agent_rollouts"
+
+	AgentRolloutActive     = "active"
+	AgentRolloutPaused     = "paused"
+	AgentRolloutCompleted  = "completed"
+	AgentRolloutRolledBack = "rolled-back"
+	AgentRolloutSuperseded = "superseded"
+)
+
+// AgentRollout tracks the deployment of one agent revision to the hosts of a
+// distro. At each stage a larger share of the distro's hosts is given the new
+// revision while the rest keep the previous one.
+type AgentRollout struct {
+	ID               string    `bson:"_id" json:"id"`
+	Distro           string    `bson:"distro" json:"distro"`
+	Revision         string    `bson:"revision" json:"revision"`
+	PreviousRevision string    `bson:"previous_revision" json:"previous_revision"`
+	Stage            int       `bson:"stage" json:"stage"`
+	StageStartTime   time.Time `bson:"stage_start_time" json:"stage_start_time"`
+	Status           string    `bson:"status" json:"status"`
+	Reason           string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	CreateTime       time.Time `bson:"create_time" json:"create_time"`
+}
+
+var (
+	AgentRolloutIDKey               = bsonutil.MustHaveTag(AgentRollout{}, "ID")
+	AgentRolloutDistroKey           = bsonutil.MustHaveTag(AgentRollout{}, "Distro")
+	AgentRolloutRevisionKey         = bsonutil.MustHaveTag(AgentRollout{}, "Revision")
+	AgentRolloutPreviousRevisionKey = bsonutil.MustHaveTag(AgentRollout{}, "PreviousRevision")
+	AgentRolloutStageKey            = bsonutil.MustHaveTag(AgentRollout{}, "Stage")
+	AgentRolloutStageStartTimeKey   = bsonutil.MustHaveTag(AgentRollout{}, "StageStartTime")
+	AgentRolloutStatusKey           = bsonutil.MustHaveTag(AgentRollout{}, "Status")
+	AgentRolloutReasonKey           = bsonutil.MustHaveTag(AgentRollout{}, "Reason")
+	AgentRolloutCreateTimeKey       = bsonutil.MustHaveTag(AgentRollout{}, "CreateTime")
+)
+
+func agentRolloutID(distroID, revision string) string {
+	return fmt.Sprintf("%s_%s", distroID, revision)
+}
+
+// AgentRolloutByDistroRevision returns a query for the rollout of the given
+// revision to the given distro.
+func AgentRolloutByDistroRevision(distroID, revision string) db.Q {
+	return db.Query(bson.M{AgentRolloutIDKey: agentRolloutID(distroID, revision)})
+}
+
+// AgentRolloutsByStatus returns a query for all rollouts in any of the given
+// statuses, newest first.
+func AgentRolloutsByStatus(statuses ...string) db.Q {
+	return db.Query(bson.M{
+		AgentRolloutStatusKey: bson.M{"$in": statuses},
+	}).Sort([]string{"-" + AgentRolloutCreateTimeKey})
+}
+
+// LatestAgentRollout returns a query for the most recent rollout to the
+// distro.
+func LatestAgentRollout(distroID string) db.Q {
+	return db.Query(bson.M{
+		AgentRolloutDistroKey: distroID,
+	}).Sort([]string{"-" + AgentRolloutCreateTimeKey}).Limit(1)
+}
+
+// LastCompletedAgentRollout returns a query for the most recent rollout to
+// the distro that finished.
+func LastCompletedAgentRollout(distroID string) db.Q {
+	return db.Query(bson.M{
+		AgentRolloutDistroKey: distroID,
+		AgentRolloutStatusKey: AgentRolloutCompleted,
+	}).Sort([]string{"-" + AgentRolloutCreateTimeKey}).Limit(1)
+}
+
+// FindOneAgentRollout gets one rollout for the given query.
+func FindOneAgentRollout(query db.Q) (*AgentRollout, error) {
+	r := &AgentRollout{}
+	err := db.FindOneQ(AgentRolloutsCollection, query, r)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return r, err
+}
+
+// FindAgentRollouts gets all rollouts for the given query.
+func FindAgentRollouts(query db.Q) ([]AgentRollout, error) {
+	rollouts := []AgentRollout{}
+	err := db.FindAllQ(AgentRolloutsCollection, query, &rollouts)
+	return rollouts, err
+}
+
+// GetOrCreateAgentRollout returns the rollout of the revision to the distro,
+// starting one if none exists. The revision being replaced is taken from the
+// distro's last completed rollout or, failing that, from one of its hosts. If
+// no hosts run an older revision, there is nothing to stage and the rollout
+// starts completed.
+func GetOrCreateAgentRollout(distroID, revision string) (*AgentRollout, error) {
+	r, err := FindOneAgentRollout(AgentRolloutByDistroRevision(distroID, revision))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding agent rollout for distro %s", distroID)
+	}
+	if r != nil {
+		return r, nil
+	}
+
+	previous := ""
+	last, err := FindOneAgentRollout(LastCompletedAgentRollout(distroID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding last agent rollout for distro %s", distroID)
+	}
+	if last != nil && last.Revision != revision {
+		previous = last.Revision
+	} else {
+		query := ByDistroIdDoc(distroID)
+		query[AgentRevisionKey] = bson.M{"$nin": []string{"", revision}}
+		h, err := FindOne(db.Query(query))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error finding hosts of distro %s", distroID)
+		}
+		if h != nil {
+			previous = h.AgentRevision
+		}
+	}
+
+	now := time.Now()
+	r = &AgentRollout{
+		ID:               agentRolloutID(distroID, revision),
+		Distro:           distroID,
+		Revision:         revision,
+		PreviousRevision: previous,
+		StageStartTime:   now,
+		Status:           AgentRolloutActive,
+		CreateTime:       now,
+	}
+	if previous == "" {
+		r.Status = AgentRolloutCompleted
+	}
+
+	err = db.Insert(AgentRolloutsCollection, r)
+	if mgo.IsDup(err) {
+		// another request started the rollout first
+		return FindOneAgentRollout(AgentRolloutByDistroRevision(distroID, revision))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error starting agent rollout for distro %s", distroID)
+	}
+
+	// rollouts of older revisions that had not finished are replaced by
+	// this one
+	_, err = db.UpdateAll(AgentRolloutsCollection,
+		bson.M{
+			AgentRolloutDistroKey: distroID,
+			AgentRolloutIDKey:     bson.M{"$ne": r.ID},
+			AgentRolloutStatusKey: bson.M{"$in": []string{AgentRolloutActive, AgentRolloutPaused}},
+		},
+		bson.M{"$set": bson.M{AgentRolloutStatusKey: AgentRolloutSuperseded}})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error superseding agent rollouts for distro %s", distroID)
+	}
+	return r, nil
+}
+
+// TargetAgentRevision returns the agent revision the host should run while
+// current is the latest built revision.
+func TargetAgentRevision(h *Host, current string, conf evergreen.AgentRolloutConfig) (string, error) {
+	if conf.Disabled {
+		return current, nil
+	}
+
+	r, err := GetOrCreateAgentRollout(h.Distro.Id, current)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return r.TargetRevision(h.Id, conf.WithDefaults()), nil
+}
+
+// TargetRevision returns the revision the host should run at the rollout's
+// current stage.
+func (r *AgentRollout) TargetRevision(hostID string, conf evergreen.AgentRolloutConfig) string {
+	switch r.Status {
+	case AgentRolloutCompleted:
+		return r.Revision
+	case AgentRolloutRolledBack:
+		return r.PreviousRevision
+	}
+
+	if hostBucket(hostID) < r.StagePercent(conf) {
+		return r.Revision
+	}
+	return r.PreviousRevision
+}
+
+// StagePercent returns the percentage of the distro's hosts that run the new
+// revision at the rollout's current stage.
+func (r *AgentRollout) StagePercent(conf evergreen.AgentRolloutConfig) int {
+	if r.Stage >= len(conf.Stages) {
+		return 100
+	}
+	return conf.Stages[r.Stage]
+}
+
+// StageHosts returns the number of the distro's hosts that are given the new
+// revision at the rollout's current stage.
+func (r *AgentRollout) StageHosts(conf evergreen.AgentRolloutConfig) (int, error) {
+	hosts, err := Find(db.Query(ByDistroIdDoc(r.Distro)).WithFields(IdKey))
+	if err != nil {
+		return 0, errors.Wrapf(err, "error finding hosts of distro %s", r.Distro)
+	}
+
+	percent := r.StagePercent(conf)
+	count := 0
+	for _, h := range hosts {
+		if hostBucket(h.Id) < percent {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// hostBucket places each host in one of 100 stable buckets so that a host
+// stays on the new revision as the rollout widens.
+func hostBucket(hostID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(hostID))
+	return int(h.Sum32() % 100)
+}
+
+// Health summarizes the tasks finished at the current stage by the distro's
+// hosts running the new revision.
+func (r *AgentRollout) Health() (*Health, error) {
+	query := ByDistroIdDoc(r.Distro)
+	query[AgentRevisionKey] = r.Revision
+	hosts, err := Find(db.Query(query).WithFields(IdKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding hosts of distro %s", r.Distro)
+	}
+	if len(hosts) == 0 {
+		return &Health{}, nil
+	}
+
+	ids := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		ids = append(ids, h.Id)
+	}
+	tasks, err := event.Find(event.AllLogCollection,
+		event.HostEventsOfTypesSince(ids, r.StageStartTime, event.EventTaskFinished))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding task events for distro %s", r.Distro)
+	}
+
+	return healthFromEvents(tasks, nil), nil
+}
+
+// Advance moves the rollout to its next stage, or completes it if it is at
+// the last of the given number of stages.
+func (r *AgentRollout) Advance(numStages int) error {
+	if r.Stage+1 >= numStages {
+		return r.setStatus(AgentRolloutCompleted, "")
+	}
+
+	now := time.Now()
+	err := db.Update(AgentRolloutsCollection,
+		bson.M{AgentRolloutIDKey: r.ID},
+		bson.M{"$set": bson.M{
+			AgentRolloutStageKey:          r.Stage + 1,
+			AgentRolloutStageStartTimeKey: now,
+		}})
+	if err != nil {
+		return errors.Wrapf(err, "error advancing agent rollout %s", r.ID)
+	}
+	r.Stage++
+	r.StageStartTime = now
+	return nil
+}
+
+// RollBack returns every host in the distro to the previous revision.
+func (r *AgentRollout) RollBack(reason string) error {
+	return r.setStatus(AgentRolloutRolledBack, reason)
+}
+
+// Pause holds the rollout at its current stage.
+func (r *AgentRollout) Pause() error {
+	if r.Status != AgentRolloutActive {
+		return errors.Errorf("agent rollout %s is %s, not active", r.ID, r.Status)
+	}
+	return r.setStatus(AgentRolloutPaused, "")
+}
+
+// Resume continues a paused rollout. A rolled back rollout starts over from
+// its first stage.
+func (r *AgentRollout) Resume() error {
+	switch r.Status {
+	case AgentRolloutPaused:
+		return r.setStatus(AgentRolloutActive, "")
+	case AgentRolloutRolledBack:
+		now := time.Now()
+		err := db.Update(AgentRolloutsCollection,
+			bson.M{AgentRolloutIDKey: r.ID},
+			bson.M{
+				"$set": bson.M{
+					AgentRolloutStatusKey:         AgentRolloutActive,
+					AgentRolloutStageKey:          0,
+					AgentRolloutStageStartTimeKey: now,
+				},
+				"$unset": bson.M{AgentRolloutReasonKey: 1},
+			})
+		if err != nil {
+			return errors.Wrapf(err, "error resuming agent rollout %s", r.ID)
+		}
+		r.Status = AgentRolloutActive
+		r.Stage = 0
+		r.StageStartTime = now
+		r.Reason = ""
+		return nil
+	default:
+		return errors.Errorf("agent rollout %s is %s and cannot be resumed", r.ID, r.Status)
+	}
+}
+
+func (r *AgentRollout) setStatus(status, reason string) error {
+	update := bson.M{"$set": bson.M{AgentRolloutStatusKey: status}}
+	if reason == "" {
+		update["$unset"] = bson.M{AgentRolloutReasonKey: 1}
+	} else {
+		update["$set"].(bson.M)[AgentRolloutReasonKey] = reason
+	}
+
+	if err := db.Update(AgentRolloutsCollection, bson.M{AgentRolloutIDKey: r.ID}, update); err != nil {
+		return errors.Wrapf(err, "error setting agent rollout %s to %s", r.ID, status)
+	}
+	r.Status = status
+	r.Reason = reason
+	return nil
+}
@@ -0,0 +1,100 @@
+package host
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentRolloutTargetRevision(t *testing.T) {
+	assert := assert.New(t)
+	conf := evergreen.AgentRolloutConfig{}.WithDefaults()
+
+	r := &AgentRollout{
+		Revision:         "new",
+		PreviousRevision: "old",
+		Status:           AgentRolloutActive,
+	}
+
+	// each stage moves roughly its share of hosts to the new revision, and
+	// hosts on the new revision stay there as the rollout widens
+	onNew := map[string]bool{}
+	for stage, pct := range conf.Stages {
+		r.Stage = stage
+		count := 0
+		for i := 0; i < 1000; i++ {
+			id := fmt.Sprintf("host-%d", i)
+			if r.TargetRevision(id, conf) == "new" {
+				count++
+				onNew[id] = true
+			} else {
+				assert.False(onNew[id], "host %s left the new revision", id)
+			}
+		}
+		assert.InDelta(pct*10, count, 50)
+	}
+	assert.Len(onNew, 1000)
+
+	r.Status = AgentRolloutRolledBack
+	assert.Equal("old", r.TargetRevision("host-1", conf))
+
+	r.Status = AgentRolloutCompleted
+	assert.Equal("new", r.TargetRevision("host-1", conf))
+
+	r.Status = AgentRolloutActive
+	r.Stage = len(conf.Stages) + 1
+	assert.Equal(100, r.StagePercent(conf))
+}
+
+func TestTargetAgentRevisionSkipsRollout(t *testing.T) {
+	assert := assert.New(t)
+
+	h := &Host{Id: "h"}
+	rev, err := TargetAgentRevision(h, "new", evergreen.AgentRolloutConfig{Disabled: true})
+	assert.NoError(err)
+	assert.Equal("new", rev)
+}
+
+func TestGetOrCreateAgentRolloutSupersedesUnfinishedRollouts(t *testing.T) {
+	assert := assert.New(t)
+	require.NoError(t, db.ClearCollections(AgentRolloutsCollection, Collection))
+
+	h := &Host{
+		Id:            "h",
+		Distro:        distro.Distro{Id: "d"},
+		StartedBy:     evergreen.User,
+		Status:        evergreen.HostRunning,
+		AgentRevision: "a",
+	}
+	require.NoError(t, h.Insert())
+
+	first, err := GetOrCreateAgentRollout("d", "b")
+	require.NoError(t, err)
+	assert.Equal(AgentRolloutActive, first.Status)
+	assert.Equal("a", first.PreviousRevision)
+
+	second, err := GetOrCreateAgentRollout("d", "c")
+	require.NoError(t, err)
+	assert.Equal(AgentRolloutActive, second.Status)
+
+	first, err = FindOneAgentRollout(AgentRolloutByDistroRevision("d", "b"))
+	require.NoError(t, err)
+	assert.Equal(AgentRolloutSuperseded, first.Status)
+
+	active, err := FindAgentRollouts(AgentRolloutsByStatus(AgentRolloutActive))
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(second.ID, active[0].ID)
+
+	count, err := second.StageHosts(evergreen.AgentRolloutConfig{Stages: []int{100}})
+	assert.NoError(err)
+	assert.Equal(1, count)
+	count, err = second.StageHosts(evergreen.AgentRolloutConfig{Stages: []int{0}})
+	assert.NoError(err)
+	assert.Equal(0, count)
+}
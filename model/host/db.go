@@ -52,6 +52,11 @@ var (
 	ProjectKey               = bsonutil.MustHaveTag(Host{}, "Project")
 	ProvisionOptionsKey      = bsonutil.MustHaveTag(Host{}, "ProvisionOptions")
 	StartTimeKey             = bsonutil.MustHaveTag(Host{}, "StartTime")
+	WarmPoolKey              = bsonutil.MustHaveTag(Host{}, "WarmPool")
+	WarmPoolCostKey          = bsonutil.MustHaveTag(Host{}, "WarmPoolCost")
+	QuarantineReasonKey      = bsonutil.MustHaveTag(Host{}, "QuarantineReason")
+	SSHKeyKey                = bsonutil.MustHaveTag(Host{}, "SSHKey")
+	PendingSSHKeyKey         = bsonutil.MustHaveTag(Host{}, "PendingSSHKey")
 )
 
 // === Queries ===
@@ -69,6 +74,20 @@ func ByUserWithRunningStatus(user string) db.Q {
 		})
 }
 
+// ByUsersActiveSince produces a query that returns all hosts started by any
+// of the given users that are either not terminated or were terminated after
+// the given time.
+func ByUsersActiveSince(users []string, since time.Time) db.Q {
+	return db.Query(
+		bson.M{
+			StartedByKey: bson.M{"$in": users},
+			"$or": []bson.M{
+				{StatusKey: bson.M{"$ne": evergreen.HostTerminated}},
+				{TerminationTimeKey: bson.M{"$gt": since}},
+			},
+		})
+}
+
 // IsRunning is a query that returns all hosts that are running
 // (i.e. status != terminated).
 var IsRunning = db.Query(bson.M{StatusKey: bson.M{"$ne": evergreen.HostTerminated}})
@@ -114,6 +133,19 @@ func ByAvailableForDistro(d string) db.Q {
 	}).Sort([]string{"-" + LTCTimeKey})
 }
 
+// ByIdleWarmPoolForDistro returns the running hosts in the distro's warm pool
+// that have no task and whose agents have asked for one since the given time.
+func ByIdleWarmPoolForDistro(d string, since time.Time) db.Q {
+	distroIdKey := fmt.Sprintf("%v.%v", DistroKey, distro.IdKey)
+	return db.Query(bson.M{
+		distroIdKey:              d,
+		WarmPoolKey:              true,
+		RunningTaskKey:           bson.M{"$exists": false},
+		StatusKey:                evergreen.HostRunning,
+		LastCommunicationTimeKey: bson.M{"$gte": since},
+	})
+}
+
 // IsFree is a query that returns all running
 // Evergreen hosts without an assigned task.
 var IsFree = db.Query(
@@ -255,6 +287,13 @@ var AllStatic = db.Query(
 		ProviderKey: evergreen.HostTypeStatic,
 	})
 
+// RunningStatic is a query that returns all running static hosts.
+var RunningStatic = db.Query(
+	bson.M{
+		ProviderKey: evergreen.HostTypeStatic,
+		StatusKey:   evergreen.HostRunning,
+	})
+
 // IsIdle is a query that returns all running Evergreen hosts with no task.
 var IsIdle = db.Query(
 	bson.M{
@@ -444,6 +483,41 @@ func GetHostsByFromIdWithStatus(id, status, user string, limit, sortDir int) ([]
 	return hosts, nil
 }
 
+// WarmPoolCostByDistroPipeline returns a pipeline that sums the warm pool
+// cost of the distro's hosts which were started in the given time range.
+func WarmPoolCostByDistroPipeline(distroId string, starttime time.Time, duration time.Duration) []bson.M {
+	return []bson.M{
+		{
+			"$match": bson.M{
+				bsonutil.GetDottedKeyName(DistroKey, distro.IdKey): distroId,
+				StartTimeKey:    bson.M{"$gte": starttime, "$lte": starttime.Add(duration)},
+				WarmPoolCostKey: bson.M{"$gt": 0},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id":                "$" + bsonutil.GetDottedKeyName(DistroKey, distro.IdKey),
+				"sum_warm_pool_cost": bson.M{"$sum": "$" + WarmPoolCostKey},
+			},
+		},
+	}
+}
+
+// WarmPoolCostForDistro returns the total cost of the time the distro's hosts
+// started in the given time range spent idle in its warm pool.
+func WarmPoolCostForDistro(distroId string, starttime time.Time, duration time.Duration) (float64, error) {
+	res := []struct {
+		Cost float64 `bson:"sum_warm_pool_cost"`
+	}{}
+	if err := db.Aggregate(Collection, WarmPoolCostByDistroPipeline(distroId, starttime, duration), &res); err != nil {
+		return 0, errors.Wrapf(err, "error aggregating warm pool cost for distro %s", distroId)
+	}
+	if len(res) == 0 {
+		return 0, nil
+	}
+	return res[0].Cost, nil
+}
+
 // statsByDistroPipeline returns a pipeline that will group all up hosts by distro
 // and return the count of hosts as well as how many are running tasks
 func statsByDistroPipeline() []bson.M {
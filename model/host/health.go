@@ -0,0 +1,154 @@
+package host
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Health summarizes a host's most recent task results and setup attempts.
+// Only results since the host was last returned from quarantine are counted.
+type Health struct {
+	Tasks          int `json:"tasks"`
+	SystemFailures int `json:"system_failures"`
+	SetupAttempts  int `json:"setup_attempts"`
+	SetupFailures  int `json:"setup_failures"`
+}
+
+var (
+	systemFailedTaskStatuses = []string{
+		evergreen.TaskSystemFailed,
+		evergreen.TaskSystemUnresponse,
+		evergreen.TaskSystemTimedOut,
+	}
+	setupSucceededEvents = []string{
+		event.EventHostAgentDeployed,
+	}
+	setupFailedEvents = []string{
+		event.EventHostAgentDeployFailed,
+		event.EventHostProvisionFailed,
+		event.EventHostProvisionError,
+	}
+)
+
+// GetHealth computes the health of the host from its window most recent task
+// results and setup attempts.
+func GetHealth(hostID string, window int) (*Health, error) {
+	since := time.Time{}
+	cleared, err := event.Find(event.AllLogCollection,
+		event.MostRecentHostStatusChangeFrom(hostID, evergreen.HostQuarantined))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding quarantine history of host %s", hostID)
+	}
+	if len(cleared) > 0 {
+		since = cleared[0].Timestamp
+	}
+
+	tasks, err := event.Find(event.AllLogCollection,
+		event.MostRecentHostEventsOfTypes(hostID, since, window, event.EventTaskFinished))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding task events for host %s", hostID)
+	}
+
+	setupEvents := append(append([]string{}, setupSucceededEvents...), setupFailedEvents...)
+	setups, err := event.Find(event.AllLogCollection,
+		event.MostRecentHostEventsOfTypes(hostID, since, window, setupEvents...))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding setup events for host %s", hostID)
+	}
+
+	return healthFromEvents(tasks, setups), nil
+}
+
+// healthFromEvents counts the task results and setup attempts in the given
+// task finished and setup events.
+func healthFromEvents(tasks, setups []event.Event) *Health {
+	health := &Health{}
+
+	for _, e := range tasks {
+		data, ok := e.Data.Data.(*event.HostEventData)
+		if !ok {
+			continue
+		}
+		health.Tasks++
+		if util.StringSliceContains(systemFailedTaskStatuses, data.TaskStatus) {
+			health.SystemFailures++
+		}
+	}
+
+	for _, e := range setups {
+		health.SetupAttempts++
+		if util.StringSliceContains(setupFailedEvents, e.EventType) {
+			health.SetupFailures++
+		}
+	}
+
+	return health
+}
+
+// SystemFailureRate is the fraction of recent tasks that system failed.
+func (h *Health) SystemFailureRate() float64 {
+	if h.Tasks == 0 {
+		return 0
+	}
+	return float64(h.SystemFailures) / float64(h.Tasks)
+}
+
+// SetupFailureRate is the fraction of recent setup attempts that failed.
+func (h *Health) SetupFailureRate() float64 {
+	if h.SetupAttempts == 0 {
+		return 0
+	}
+	return float64(h.SetupFailures) / float64(h.SetupAttempts)
+}
+
+// QuarantineReason returns why a host with this health should be quarantined
+// under the given thresholds, or an empty string if it should not be.
+func (h *Health) QuarantineReason(conf evergreen.HostHealthConfig) string {
+	conf = conf.WithDefaults()
+
+	if h.Tasks >= conf.MinSamples && h.SystemFailureRate() > conf.MaxSystemFailureRate {
+		return fmt.Sprintf("%d of the last %d tasks system failed", h.SystemFailures, h.Tasks)
+	}
+	if h.SetupAttempts >= conf.MinSamples && h.SetupFailureRate() > conf.MaxSetupFailureRate {
+		return fmt.Sprintf("%d of the last %d setup attempts failed", h.SetupFailures, h.SetupAttempts)
+	}
+	return ""
+}
+
+// Quarantine stops tasks from being dispatched to the host, recording the
+// reason why.
+func (h *Host) Quarantine(reason string) error {
+	if err := h.SetQuarantined(evergreen.User); err != nil {
+		return errors.WithStack(err)
+	}
+	event.LogHostQuarantined(h.Id, reason)
+
+	h.QuarantineReason = reason
+	return UpdateOne(
+		bson.M{IdKey: h.Id},
+		bson.M{"$set": bson.M{QuarantineReasonKey: reason}},
+	)
+}
+
+// Unquarantine returns a quarantined host to service. The host's health is
+// judged only by results from after this point.
+func (h *Host) Unquarantine(user string) error {
+	if h.Status != evergreen.HostQuarantined {
+		return errors.Errorf("host %s is not quarantined", h.Id)
+	}
+	if err := h.SetStatus(evergreen.HostRunning, user); err != nil {
+		return errors.WithStack(err)
+	}
+
+	h.QuarantineReason = ""
+	return UpdateOne(
+		bson.M{IdKey: h.Id},
+		bson.M{"$unset": bson.M{QuarantineReasonKey: 1}},
+	)
+}
@@ -0,0 +1,69 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func taskFinishedEvent(status string) event.Event {
+	return event.Event{
+		EventType: event.EventTaskFinished,
+		Data:      event.DataWrapper{Data: &event.HostEventData{TaskStatus: status}},
+	}
+}
+
+func TestHealthFromEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	tasks := []event.Event{
+		taskFinishedEvent(evergreen.TaskSucceeded),
+		taskFinishedEvent(evergreen.TaskFailed),
+		taskFinishedEvent(evergreen.TaskSystemFailed),
+		taskFinishedEvent(evergreen.TaskSystemUnresponse),
+		taskFinishedEvent(evergreen.TaskSystemTimedOut),
+	}
+	setups := []event.Event{
+		{EventType: event.EventHostAgentDeployed},
+		{EventType: event.EventHostAgentDeployFailed},
+		{EventType: event.EventHostProvisionFailed},
+		{EventType: event.EventHostAgentDeployed},
+	}
+
+	health := healthFromEvents(tasks, setups)
+	assert.Equal(5, health.Tasks)
+	assert.Equal(3, health.SystemFailures)
+	assert.Equal(4, health.SetupAttempts)
+	assert.Equal(2, health.SetupFailures)
+	assert.Equal(0.6, health.SystemFailureRate())
+	assert.Equal(0.5, health.SetupFailureRate())
+
+	empty := healthFromEvents(nil, nil)
+	assert.Zero(empty.SystemFailureRate())
+	assert.Zero(empty.SetupFailureRate())
+}
+
+func TestHealthQuarantineReason(t *testing.T) {
+	assert := assert.New(t)
+	conf := evergreen.HostHealthConfig{
+		MinSamples:           4,
+		MaxSystemFailureRate: 0.5,
+		MaxSetupFailureRate:  0.25,
+	}
+
+	// too few samples to judge, however bad the rate
+	health := &Health{Tasks: 3, SystemFailures: 3, SetupAttempts: 3, SetupFailures: 3}
+	assert.Empty(health.QuarantineReason(conf))
+
+	// a rate at the threshold is tolerated
+	health = &Health{Tasks: 4, SystemFailures: 2, SetupAttempts: 4, SetupFailures: 1}
+	assert.Empty(health.QuarantineReason(conf))
+
+	health = &Health{Tasks: 4, SystemFailures: 3}
+	assert.Equal("3 of the last 4 tasks system failed", health.QuarantineReason(conf))
+
+	health = &Health{Tasks: 4, SetupAttempts: 4, SetupFailures: 2}
+	assert.Equal("2 of the last 4 setup attempts failed", health.QuarantineReason(conf))
+}
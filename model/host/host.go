@@ -74,6 +74,26 @@ type Host struct {
 
 	// if set, the time at which the host first became unreachable
 	UnreachableSince time.Time `bson:"unreachable_since,omitempty" json:"unreachable_since"`
+
+	// true if the host was started to fill its distro's warm pool and has
+	// not yet been dispatched a task
+	WarmPool bool `bson:"warm_pool,omitempty" json:"warm_pool,omitempty"`
+	// the cost of the time the host spent idle in the warm pool, which is
+	// attributed to the distro rather than to any task
+	WarmPoolCost float64 `bson:"warm_pool_cost,omitempty" json:"warm_pool_cost,omitempty"`
+
+	// BootstrapScript is passed to the provider as user data for hosts that
+	// provision themselves on boot. It is not stored.
+	BootstrapScript string `bson:"-" json:"-"`
+
+	// the SSH key authorized only on this host, if one was generated
+	SSHKey *HostSSHKey `bson:"ssh_key,omitempty" json:"ssh_key,omitempty"`
+	// the key replacing SSHKey while it is being rotated, saved before it is
+	// authorized on the host so that it is never lost
+	PendingSSHKey *HostSSHKey `bson:"pending_ssh_key,omitempty" json:"pending_ssh_key,omitempty"`
+
+	// if the host was quarantined automatically, the reason why
+	QuarantineReason string `bson:"quarantine_reason,omitempty" json:"quarantine_reason,omitempty"`
 }
 
 // ProvisionOptions is struct containing options about how a new host should be set up.
@@ -88,6 +108,14 @@ type ProvisionOptions struct {
 
 	// Owner is the user associated with the host used to populate any necessary metadata.
 	OwnerId string `bson:"owner_id" json:"owner_id"`
+
+	// HomeVolumeSize if non-zero attaches the owner's home volume to the host,
+	// creating a volume of this size in GB if the owner does not have one.
+	HomeVolumeSize int `bson:"home_volume_size,omitempty" json:"home_volume_size,omitempty"`
+
+	// ReplayUntilFailure if set runs the commands of the task given by TaskId
+	// on the host, stopping before the command that failed.
+	ReplayUntilFailure bool `bson:"replay_until_failure,omitempty" json:"replay_until_failure,omitempty"`
 }
 
 type StatsByDistro struct {
@@ -150,7 +178,7 @@ func (h *Host) SetStatus(status, user string) error {
 // SetInitializing marks the host as initializing. Only allow this
 // if the host is uninitialized.
 func (h *Host) SetInitializing() error {
-	return UpdateOne(
+	err := UpdateOne(
 		bson.M{
 			IdKey:     h.Id,
 			StatusKey: evergreen.HostStarting,
@@ -161,6 +189,13 @@ func (h *Host) SetInitializing() error {
 			},
 		},
 	)
+	if err != nil {
+		return err
+	}
+
+	event.LogHostStatusChanged(h.Id, evergreen.HostStarting, evergreen.HostInitializing, evergreen.User)
+	h.Status = evergreen.HostInitializing
+	return nil
 }
 
 func (h *Host) SetStarting() error {
@@ -211,6 +246,10 @@ func (h *Host) SetUnprovisioned() error {
 	)
 }
 
+func (h *Host) SetStopped(user string) error {
+	return h.SetStatus(evergreen.HostStopped, user)
+}
+
 func (h *Host) SetQuarantined(user string) error {
 	return h.SetStatus(evergreen.HostQuarantined, user)
 }
@@ -297,6 +336,21 @@ func (h *Host) SetDNSName(dnsName string) error {
 	return err
 }
 
+// ResetDNSName updates the DNS name of a host whose name has changed, such
+// as when a stopped host is started again.
+func (h *Host) ResetDNSName(dnsName string) error {
+	err := UpdateOne(
+		bson.M{IdKey: h.Id},
+		bson.M{"$set": bson.M{DNSKey: dnsName}},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error updating DNS name for host %s", h.Id)
+	}
+	h.Host = dnsName
+	event.LogHostDNSNameSet(h.Id, dnsName)
+	return nil
+}
+
 func (h *Host) MarkAsProvisioned() error {
 	event.LogHostProvisioned(h.Id)
 	h.Status = evergreen.HostRunning
@@ -422,6 +476,25 @@ func (h *Host) SetNeedsNewAgent(needsAgent bool) error {
 	return nil
 }
 
+// LeaveWarmPool records that the host has been dispatched a task or been
+// terminated, along with the cost of the time it spent idle in its distro's
+// warm pool.
+func (h *Host) LeaveWarmPool(cost float64) error {
+	err := UpdateOne(
+		bson.M{IdKey: h.Id},
+		bson.M{
+			"$unset": bson.M{WarmPoolKey: 1},
+			"$inc":   bson.M{WarmPoolCostKey: cost},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error removing host %s from warm pool", h.Id)
+	}
+	h.WarmPool = false
+	h.WarmPoolCost += cost
+	return nil
+}
+
 // SetExpirationTime updates the expiration time of a spawn host
 func (h *Host) SetExpirationTime(expirationTime time.Time) error {
 	// update the in-memory host, then the database
@@ -555,6 +628,8 @@ func (h *Host) Upsert() (*mgo.ChangeInfo, error) {
 			"$setOnInsert": bson.M{
 				StatusKey:     h.Status,
 				CreateTimeKey: h.CreationTime,
+				SecretKey:     h.Secret,
+				SSHKeyKey:     h.SSHKey,
 			},
 		},
 	)
@@ -0,0 +1,167 @@
+package host
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const hostSSHKeyBits = 2048
+
+// HostSSHKey is an SSH keypair that is authorized only on a single host. The
+// private key is stored encrypted with the key from the admin settings.
+type HostSSHKey struct {
+	PublicKey           string    `bson:"public_key" json:"public_key"`
+	EncryptedPrivateKey []byte    `bson:"encrypted_private_key" json:"-"`
+	CreateTime          time.Time `bson:"create_time" json:"create_time"`
+	// Installed is set once the public key is authorized on the host, after
+	// which remote commands use the key instead of the distro's.
+	Installed bool `bson:"installed" json:"installed"`
+}
+
+var (
+	HostSSHKeyPublicKeyKey  = bsonutil.MustHaveTag(HostSSHKey{}, "PublicKey")
+	HostSSHKeyCreateTimeKey = bsonutil.MustHaveTag(HostSSHKey{}, "CreateTime")
+	HostSSHKeyInstalledKey  = bsonutil.MustHaveTag(HostSSHKey{}, "Installed")
+)
+
+// NewHostSSHKey generates a keypair and encrypts its private key with the
+// given AES key.
+func NewHostSSHKey(encryptionKey []byte) (*HostSSHKey, error) {
+	private, err := rsa.GenerateKey(rand.Reader, hostSSHKeyBits)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem generating key")
+	}
+	public, err := ssh.NewPublicKey(&private.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem encoding public key")
+	}
+
+	encrypted, err := encryptHostSSHKey(encryptionKey, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(private),
+	}))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &HostSSHKey{
+		PublicKey:           strings.TrimSpace(string(ssh.MarshalAuthorizedKey(public))),
+		EncryptedPrivateKey: encrypted,
+		CreateTime:          time.Now(),
+	}, nil
+}
+
+// PrivateKey decrypts the PEM-encoded private key.
+func (k *HostSSHKey) PrivateKey(encryptionKey []byte) ([]byte, error) {
+	gcm, err := newHostSSHKeyCipher(encryptionKey)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(k.EncryptedPrivateKey) < gcm.NonceSize() {
+		return nil, errors.New("encrypted private key is too short")
+	}
+
+	nonce, data := k.EncryptedPrivateKey[:gcm.NonceSize()], k.EncryptedPrivateKey[gcm.NonceSize():]
+	key, err := gcm.Open(nil, nonce, data, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem decrypting private key")
+	}
+	return key, nil
+}
+
+// encryptHostSSHKey seals the data with AES-GCM, prefixed by its nonce.
+func encryptHostSSHKey(encryptionKey, data []byte) ([]byte, error) {
+	gcm, err := newHostSSHKeyCipher(encryptionKey)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "problem generating nonce")
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+func newHostSSHKeyCipher(encryptionKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid encryption key")
+	}
+	gcm, err := cipher.NewGCM(block)
+	return gcm, errors.Wrap(err, "problem creating cipher")
+}
+
+// SetSSHKey replaces the host's SSH key and clears any pending key both
+// locally and in the database.
+func (h *Host) SetSSHKey(key *HostSSHKey) error {
+	err := UpdateOne(
+		bson.M{IdKey: h.Id},
+		bson.M{
+			"$set":   bson.M{SSHKeyKey: key},
+			"$unset": bson.M{PendingSSHKeyKey: 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	h.SSHKey = key
+	h.PendingSSHKey = nil
+	return nil
+}
+
+// SetPendingSSHKey saves the key that is about to replace the host's SSH key
+// both locally and in the database.
+func (h *Host) SetPendingSSHKey(key *HostSSHKey) error {
+	err := UpdateOne(
+		bson.M{IdKey: h.Id},
+		bson.M{"$set": bson.M{PendingSSHKeyKey: key}},
+	)
+	if err != nil {
+		return err
+	}
+	h.PendingSSHKey = key
+	return nil
+}
+
+// SetSSHKeyInstalled records that the host's SSH key has been authorized on
+// the host.
+func (h *Host) SetSSHKeyInstalled() error {
+	if h.SSHKey == nil {
+		return errors.Errorf("host %s has no SSH key", h.Id)
+	}
+	err := UpdateOne(
+		bson.M{
+			IdKey: h.Id,
+			bsonutil.GetDottedKeyName(SSHKeyKey, HostSSHKeyPublicKeyKey): h.SSHKey.PublicKey,
+		},
+		bson.M{"$set": bson.M{bsonutil.GetDottedKeyName(SSHKeyKey, HostSSHKeyInstalledKey): true}},
+	)
+	if err != nil {
+		return err
+	}
+	h.SSHKey.Installed = true
+	return nil
+}
+
+// ByRunningWithSSHKeyOlderThan produces a query that returns running hosts
+// whose installed SSH key was created before the cutoff.
+func ByRunningWithSSHKeyOlderThan(cutoff time.Time) db.Q {
+	return db.Query(bson.M{
+		StatusKey: evergreen.HostRunning,
+		bsonutil.GetDottedKeyName(SSHKeyKey, HostSSHKeyInstalledKey):  true,
+		bsonutil.GetDottedKeyName(SSHKeyKey, HostSSHKeyCreateTimeKey): bson.M{"$lt": cutoff},
+	}).Sort([]string{bsonutil.GetDottedKeyName(SSHKeyKey, HostSSHKeyCreateTimeKey)})
+}
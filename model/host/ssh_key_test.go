@@ -0,0 +1,38 @@
+package host
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestHostSSHKey(t *testing.T) {
+	assert := assert.New(t)
+	encryptionKey := []byte("0123456789abcdef0123456789abcdef")
+
+	key, err := NewHostSSHKey(encryptionKey)
+	require.NoError(t, err)
+	assert.True(strings.HasPrefix(key.PublicKey, "ssh-rsa "))
+	assert.False(key.Installed)
+	assert.NotContains(string(key.EncryptedPrivateKey), "PRIVATE KEY")
+
+	private, err := key.PrivateKey(encryptionKey)
+	require.NoError(t, err)
+	signer, err := ssh.ParsePrivateKey(private)
+	require.NoError(t, err)
+	assert.Equal(key.PublicKey, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))))
+
+	_, err = key.PrivateKey([]byte("fedcba9876543210fedcba9876543210"))
+	assert.Error(err)
+	_, err = key.PrivateKey([]byte("short"))
+	assert.Error(err)
+	_, err = (&HostSSHKey{EncryptedPrivateKey: []byte("x")}).PrivateKey(encryptionKey)
+	assert.Error(err)
+
+	other, err := NewHostSSHKey(encryptionKey)
+	require.NoError(t, err)
+	assert.NotEqual(key.PublicKey, other.PublicKey)
+}
@@ -0,0 +1,157 @@
+package host
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// LifecycleTimings summarizes how long a distro's hosts took to come up.
+// RequestToRunning measures from when a host was requested until its cloud
+// provider reported it running, and RunningToProvisioned from then until the
+// host was provisioned.
+type LifecycleTimings struct {
+	Distro               string        `json:"distro"`
+	Since                time.Time     `json:"since"`
+	Hosts                int           `json:"hosts"`
+	RequestToRunning     DurationStats `json:"request_to_running"`
+	RunningToProvisioned DurationStats `json:"running_to_provisioned"`
+}
+
+// DurationStats summarizes a set of durations.
+type DurationStats struct {
+	Count  int           `json:"count"`
+	Mean   time.Duration `json:"mean"`
+	Median time.Duration `json:"median"`
+	P90    time.Duration `json:"p90"`
+	Max    time.Duration `json:"max"`
+}
+
+var lifecycleEvents = []string{
+	event.EventHostCreated,
+	event.EventHostStatusChanged,
+	event.EventHostProvisioned,
+}
+
+// GetLifecycleTimings computes the lifecycle timings of the distro's hosts
+// that were created after the given time.
+func GetLifecycleTimings(distroID string, since time.Time) (*LifecycleTimings, error) {
+	hosts, err := Find(db.Query(bson.M{
+		fmt.Sprintf("%v.%v", DistroKey, distro.IdKey): distroID,
+		CreateTimeKey: bson.M{"$gte": since},
+	}).WithFields(IdKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding hosts of distro %s", distroID)
+	}
+
+	timings := &LifecycleTimings{
+		Distro: distroID,
+		Since:  since,
+		Hosts:  len(hosts),
+	}
+	if len(hosts) == 0 {
+		return timings, nil
+	}
+
+	ids := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		ids = append(ids, h.Id)
+	}
+	events, err := event.Find(event.AllLogCollection,
+		event.HostEventsOfTypesSince(ids, since, lifecycleEvents...).Sort([]string{event.TimestampKey}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding lifecycle events for distro %s", distroID)
+	}
+
+	requestToRunning, runningToProvisioned := lifecycleDurations(events)
+	timings.RequestToRunning = newDurationStats(requestToRunning)
+	timings.RunningToProvisioned = newDurationStats(runningToProvisioned)
+	return timings, nil
+}
+
+// lifecycleDurations measures each host's lifecycle from its events, which
+// must be in chronological order. A host's cloud provider reporting it
+// running is recorded as its move into the provisioning status. Hosts that
+// have not reached a stage are left out of that stage's durations.
+func lifecycleDurations(events []event.Event) ([]time.Duration, []time.Duration) {
+	type hostTimes struct {
+		created, running, provisioned time.Time
+	}
+	byHost := map[string]*hostTimes{}
+	order := []string{}
+
+	for _, e := range events {
+		times, ok := byHost[e.ResourceId]
+		if !ok {
+			times = &hostTimes{}
+			byHost[e.ResourceId] = times
+			order = append(order, e.ResourceId)
+		}
+
+		switch e.EventType {
+		case event.EventHostCreated:
+			if times.created.IsZero() {
+				times.created = e.Timestamp
+			}
+		case event.EventHostStatusChanged:
+			data, ok := e.Data.Data.(*event.HostEventData)
+			if ok && data.NewStatus == evergreen.HostInitializing && times.running.IsZero() {
+				times.running = e.Timestamp
+			}
+		case event.EventHostProvisioned:
+			if times.provisioned.IsZero() {
+				times.provisioned = e.Timestamp
+			}
+		}
+	}
+
+	requestToRunning := []time.Duration{}
+	runningToProvisioned := []time.Duration{}
+	for _, id := range order {
+		times := byHost[id]
+		if times.running.IsZero() {
+			continue
+		}
+		if !times.created.IsZero() {
+			requestToRunning = append(requestToRunning, times.running.Sub(times.created))
+		}
+		if !times.provisioned.IsZero() {
+			runningToProvisioned = append(runningToProvisioned, times.provisioned.Sub(times.running))
+		}
+	}
+
+	return requestToRunning, runningToProvisioned
+}
+
+func newDurationStats(durations []time.Duration) DurationStats {
+	stats := DurationStats{Count: len(durations)}
+	if len(durations) == 0 {
+		return stats
+	}
+
+	sorted := append([]time.Duration{}, durations...)
+	sort.Sort(durationSlice(sorted))
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	stats.Mean = total / time.Duration(len(sorted))
+	stats.Median = sorted[len(sorted)/2]
+	stats.P90 = sorted[len(sorted)*9/10]
+	stats.Max = sorted[len(sorted)-1]
+	return stats
+}
+
+type durationSlice []time.Duration
+
+func (s durationSlice) Len() int           { return len(s) }
+func (s durationSlice) Less(i, j int) bool { return s[i] < s[j] }
+func (s durationSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,59 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func lifecycleEvent(hostID, eventType string, at time.Time, newStatus string) event.Event {
+	return event.Event{
+		ResourceId: hostID,
+		EventType:  eventType,
+		Timestamp:  at,
+		Data:       event.DataWrapper{Data: &event.HostEventData{NewStatus: newStatus}},
+	}
+}
+
+func TestLifecycleDurations(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Now()
+
+	events := []event.Event{
+		lifecycleEvent("h1", event.EventHostCreated, start, ""),
+		lifecycleEvent("h2", event.EventHostCreated, start, ""),
+		lifecycleEvent("h3", event.EventHostCreated, start, ""),
+		lifecycleEvent("h1", event.EventHostStatusChanged, start.Add(2*time.Minute), evergreen.HostInitializing),
+		lifecycleEvent("h2", event.EventHostStatusChanged, start.Add(4*time.Minute), evergreen.HostInitializing),
+		lifecycleEvent("h1", event.EventHostProvisioned, start.Add(5*time.Minute), ""),
+		// a later status change does not move the time the host started running
+		lifecycleEvent("h1", event.EventHostStatusChanged, start.Add(6*time.Minute), evergreen.HostInitializing),
+		lifecycleEvent("h3", event.EventHostStatusChanged, start.Add(6*time.Minute), evergreen.HostTerminated),
+	}
+
+	requestToRunning, runningToProvisioned := lifecycleDurations(events)
+	assert.Equal([]time.Duration{2 * time.Minute, 4 * time.Minute}, requestToRunning)
+	assert.Equal([]time.Duration{3 * time.Minute}, runningToProvisioned)
+}
+
+func TestNewDurationStats(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(DurationStats{}, newDurationStats(nil))
+
+	durations := []time.Duration{}
+	for i := 10; i > 0; i-- {
+		durations = append(durations, time.Duration(i)*time.Second)
+	}
+	stats := newDurationStats(durations)
+	assert.Equal(10, stats.Count)
+	assert.Equal(5500*time.Millisecond, stats.Mean)
+	assert.Equal(6*time.Second, stats.Median)
+	assert.Equal(10*time.Second, stats.P90)
+	assert.Equal(10*time.Second, stats.Max)
+	// the input is left unsorted
+	assert.Equal(10*time.Second, durations[0])
+}
@@ -0,0 +1,72 @@
+package host
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// VolumesCollection is the name of the MongoDB collection that stores
+	// volumes.
+	VolumesCollection = "volumes"
+)
+
+// Volume is a persistent disk that belongs to a user. It is attached to the
+// user's spawn hosts in turn, so its contents outlive any one host.
+type Volume struct {
+	ID               string    `bson:"_id" json:"id"`
+	CreatedBy        string    `bson:"created_by" json:"created_by"`
+	Provider         string    `bson:"provider" json:"provider"`
+	Size             int       `bson:"size" json:"size"`
+	AvailabilityZone string    `bson:"availability_zone,omitempty" json:"availability_zone,omitempty"`
+	Host             string    `bson:"host,omitempty" json:"host,omitempty"`
+	CreationTime     time.Time `bson:"creation_time" json:"creation_time"`
+}
+
+var (
+	VolumeIDKey        = bsonutil.MustHaveTag(Volume{}, "ID")
+	VolumeCreatedByKey = bsonutil.MustHaveTag(Volume{}, "CreatedBy")
+	VolumeProviderKey  = bsonutil.MustHaveTag(Volume{}, "Provider")
+	VolumeHostKey      = bsonutil.MustHaveTag(Volume{}, "Host")
+)
+
+// VolumeByUser returns a query for the volume a user created with the given
+// provider.
+func VolumeByUser(user, provider string) db.Q {
+	return db.Query(bson.M{
+		VolumeCreatedByKey: user,
+		VolumeProviderKey:  provider,
+	})
+}
+
+// FindOneVolume gets one volume for the given query.
+func FindOneVolume(query db.Q) (*Volume, error) {
+	v := &Volume{}
+	err := db.FindOneQ(VolumesCollection, query, v)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return v, err
+}
+
+// Insert writes the volume to the db.
+func (v *Volume) Insert() error {
+	return db.Insert(VolumesCollection, v)
+}
+
+// SetHost records the host the volume is attached to.
+func (v *Volume) SetHost(hostID string) error {
+	err := db.Update(VolumesCollection,
+		bson.M{VolumeIDKey: v.ID},
+		bson.M{"$set": bson.M{VolumeHostKey: hostID}})
+	if err != nil {
+		return errors.Wrapf(err, "error setting host for volume %s", v.ID)
+	}
+	v.Host = hostID
+	return nil
+}
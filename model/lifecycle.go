@@ -15,6 +15,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -640,7 +641,28 @@ func createTasksForBuild(project *Project, buildVariant *BuildVariant, b *build.
 
 				newDeps := []task.Dependency{}
 
-				if dep.Variant == AllVariants {
+				if dep.IsCrossProject() {
+					unresolved := task.UnresolvedDependency{
+						Project:  dep.Project,
+						Variant:  dep.Variant,
+						Name:     dep.Name,
+						Status:   status,
+						Revision: dep.Revision,
+					}
+					depTask, err := unresolved.FindTask()
+					if err != nil {
+						return nil, errors.Wrapf(err, "problem resolving dependency of task '%s'", newTask.DisplayName)
+					}
+					if depTask == nil {
+						// the task is blocked until the scheduler finds a
+						// task that satisfies the dependency, rather than
+						// failing the version
+						newTask.UnresolvedDependencies = append(newTask.UnresolvedDependencies, unresolved)
+						recordUnresolvedDependency(v, newTask.DisplayName, dep)
+					} else {
+						newDeps = []task.Dependency{{TaskId: depTask.Id, Status: status}}
+					}
+				} else if dep.Variant == AllVariants {
 					// for * case, we need to add all variants of the task
 					var ids []string
 					if dep.Name != AllDependencies {
@@ -684,6 +706,38 @@ func createTasksForBuild(project *Project, buildVariant *BuildVariant, b *build.
 	return tasks, nil
 }
 
+// recordUnresolvedDependency adds a warning about the unresolved dependency
+// to the version, both in memory for versions that are still being created
+// and in the database for existing ones.
+func recordUnresolvedDependency(v *version.Version, taskName string, dep TaskUnitDependency) {
+	warning := fmt.Sprintf("task '%s' is blocked: project '%s' has no task '%s' on variant '%s'",
+		taskName, dep.Project, dep.Name, dep.Variant)
+	if dep.Revision != "" {
+		warning += fmt.Sprintf(" at revision %s", dep.Revision)
+	} else if dep.Status != "" && dep.Status != evergreen.TaskSucceeded {
+		warning += fmt.Sprintf(" with status '%s'", dep.Status)
+	} else {
+		warning += " that has succeeded"
+	}
+	if util.StringSliceContains(v.Warnings, warning) {
+		return
+	}
+	v.Warnings = append(v.Warnings, warning)
+
+	err := version.UpdateOne(
+		bson.M{version.IdKey: v.Id},
+		bson.M{"$addToSet": bson.M{version.WarningsKey: warning}},
+	)
+	if err != nil && err != mgo.ErrNotFound {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "problem recording unresolved dependency on version",
+			"version": v.Id,
+			"task":    taskName,
+			"project": dep.Project,
+		}))
+	}
+}
+
 // setNumDeps sets NumDependents for each task in tasks.
 // NumDependents is the number of tasks depending on the task. Only tasks created at the same time
 // and in the same variant are included.
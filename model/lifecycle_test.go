@@ -12,6 +12,7 @@ import (
 	"github.com/evergreen-ci/evergreen/testutil"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
 )
 
 func init() {
@@ -1121,6 +1122,121 @@ func TestCreateTaskGroup(t *testing.T) {
 	}
 }
 
+func TestCreateTasksWithCrossProjectDependencies(t *testing.T) {
+	assert := assert.New(t) //nolint
+	testutil.HandleTestingErr(db.ClearCollections(build.Collection, task.Collection), t, "Error clearing collection")
+	for _, dep := range []task.Task{
+		{Id: "package_1", Revision: "rev1", RevisionOrderNumber: 1, Status: evergreen.TaskSucceeded},
+		{Id: "package_2", Revision: "rev2", RevisionOrderNumber: 2, Status: evergreen.TaskFailed},
+		{Id: "package_3", Revision: "rev3", RevisionOrderNumber: 3, Status: evergreen.TaskStarted},
+	} {
+		dep.Project = "server"
+		dep.BuildVariant = "linux"
+		dep.DisplayName = "package"
+		dep.Requester = evergreen.RepotrackerVersionRequester
+		assert.NoError(dep.Insert())
+	}
+	projYml := `
+  tasks:
+  - name: passing
+    depends_on:
+    - {name: package, variant: linux, project: server}
+  - name: finished
+    depends_on:
+    - {name: package, variant: linux, project: server, status: "*"}
+  - name: pinned
+    depends_on:
+    - {name: package, variant: linux, project: server, revision: rev3}
+  buildvariants:
+  - name: "bv"
+    tasks:
+    - name: passing
+    - name: finished
+    - name: pinned
+  `
+	proj, errs := projectFromYAML([]byte(projYml))
+	assert.Empty(errs)
+	proj.Identifier = "client"
+	v := &version.Version{
+		Id:        "versionId",
+		Revision:  "foobar",
+		Requester: evergreen.RepotrackerVersionRequester,
+	}
+
+	buildId, err := CreateBuildFromVersion(proj, v, NewTaskIdTable(proj, v), "bv", true, nil, nil)
+	assert.NoError(err)
+	dbTasks, err := task.Find(task.ByBuildId(buildId))
+	assert.NoError(err)
+	assert.Len(dbTasks, 3)
+	expected := map[string]string{
+		"passing":  "package_1",
+		"finished": "package_2",
+		"pinned":   "package_3",
+	}
+	for _, dbTask := range dbTasks {
+		if assert.Len(dbTask.DependsOn, 1) {
+			assert.Equal(expected[dbTask.DisplayName], dbTask.DependsOn[0].TaskId, dbTask.DisplayName)
+		}
+	}
+
+	// a dependency on a task that does not exist blocks the task
+	proj.Tasks[0].DependsOn[0].Revision = "rev4"
+	buildId, err = CreateBuildFromVersion(proj, v, NewTaskIdTable(proj, v), "bv", true, []string{"passing"}, nil)
+	assert.NoError(err)
+	dbTasks, err = task.Find(task.ByBuildId(buildId))
+	assert.NoError(err)
+	if assert.Len(dbTasks, 1) && assert.Len(dbTasks[0].UnresolvedDependencies, 1) {
+		blocked := dbTasks[0]
+		assert.Empty(blocked.DependsOn)
+		assert.Equal(task.UnresolvedDependency{
+			Project:  "server",
+			Variant:  "linux",
+			Name:     "package",
+			Status:   evergreen.TaskSucceeded,
+			Revision: "rev4",
+		}, blocked.UnresolvedDependencies[0])
+		met, err := blocked.DependenciesMet(map[string]task.Task{})
+		assert.NoError(err)
+		assert.False(met)
+
+		// once the other project builds the revision, the dependency
+		// resolves to its task
+		assert.NoError(blocked.ResolveDependencies())
+		assert.Len(blocked.UnresolvedDependencies, 1)
+		assert.NoError((&task.Task{
+			Id:           "package_4",
+			Revision:     "rev4",
+			Project:      "server",
+			BuildVariant: "linux",
+			DisplayName:  "package",
+			Requester:    evergreen.RepotrackerVersionRequester,
+			Status:       evergreen.TaskUndispatched,
+		}).Insert())
+		assert.NoError(blocked.ResolveDependencies())
+		assert.Empty(blocked.UnresolvedDependencies)
+		assert.Equal([]task.Dependency{{TaskId: "package_4", Status: evergreen.TaskSucceeded}}, blocked.DependsOn)
+		dbTask, err := task.FindOne(task.ById(blocked.Id))
+		assert.NoError(err)
+		assert.Empty(dbTask.UnresolvedDependencies)
+		assert.Equal(blocked.DependsOn, dbTask.DependsOn)
+	}
+	assert.Len(v.Warnings, 1)
+
+	// so does a dependency that no task's status meets
+	proj.Tasks[0].DependsOn[0].Revision = ""
+	proj.Tasks[0].DependsOn[0].Status = evergreen.TaskFailed
+	assert.NoError(db.Update(task.Collection, bson.M{"_id": "package_2"}, bson.M{"$set": bson.M{"status": evergreen.TaskStarted}}))
+	buildId, err = CreateBuildFromVersion(proj, v, NewTaskIdTable(proj, v), "bv", true, []string{"passing"}, nil)
+	assert.NoError(err)
+	dbTasks, err = task.Find(task.ByBuildId(buildId))
+	assert.NoError(err)
+	if assert.Len(dbTasks, 1) && assert.Len(dbTasks[0].UnresolvedDependencies, 1) {
+		assert.Empty(dbTasks[0].DependsOn)
+		assert.Equal(evergreen.TaskFailed, dbTasks[0].UnresolvedDependencies[0].Status)
+	}
+	assert.Len(v.Warnings, 2)
+}
+
 func TestDeletingBuild(t *testing.T) {
 
 	Convey("With a build", t, func() {
@@ -63,6 +63,9 @@ type cliIntent struct {
 
 	// alias defines the variants and tasks to run this patch on.
 	Alias string `bson:"alias"`
+
+	// Parameters overrides the defaults of the project's parameters.
+	Parameters []Parameter `bson:"parameters,omitempty"`
 }
 
 // BSON fields for the patches
@@ -83,6 +86,7 @@ var (
 	cliProcessedAtKey   = bsonutil.MustHaveTag(cliIntent{}, "ProcessedAt")
 	cliIntentTypeKey    = bsonutil.MustHaveTag(cliIntent{}, "IntentType")
 	cliAliasKey         = bsonutil.MustHaveTag(cliIntent{}, "Alias")
+	cliParametersKey    = bsonutil.MustHaveTag(cliIntent{}, "Parameters")
 )
 
 func (c *cliIntent) Insert() error {
@@ -146,6 +150,7 @@ func (c *cliIntent) NewPatch() *Patch {
 		BuildVariants: c.BuildVariants,
 		Alias:         c.Alias,
 		Tasks:         c.Tasks,
+		Parameters:    c.Parameters,
 		Patches: []ModulePatch{
 			{
 				ModuleName: c.Module,
@@ -158,7 +163,7 @@ func (c *cliIntent) NewPatch() *Patch {
 	}
 }
 
-func NewCliIntent(user, project, baseHash, module, patchContent, description string, finalize bool, variants, tasks []string, alias string) (Intent, error) {
+func NewCliIntent(user, project, baseHash, module, patchContent, description string, finalize bool, variants, tasks []string, alias string, parameters []Parameter) (Intent, error) {
 	if user == "" {
 		return nil, errors.New("no user provided")
 	}
@@ -192,6 +197,7 @@ func NewCliIntent(user, project, baseHash, module, patchContent, description str
 		Finalize:      finalize,
 		Module:        module,
 		Alias:         alias,
+		Parameters:    parameters,
 	}, nil
 }
 
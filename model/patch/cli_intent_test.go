@@ -47,7 +47,7 @@ func (s *CliIntentSuite) SetupTest() {
 }
 
 func (s *CliIntentSuite) TestNewCliIntent() {
-	intent, err := NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
+	intent, err := NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias, nil)
 	s.NotNil(intent)
 	s.NoError(err)
 	s.Implements((*Intent)(nil), intent)
@@ -72,7 +72,7 @@ func (s *CliIntentSuite) TestNewCliIntent() {
 	s.Equal(cIntent.DocumentID.Hex(), intent.ID())
 	s.Equal(s.alias, cIntent.Alias)
 
-	intent, err = NewCliIntent(s.user, s.projectID, s.hash, "", s.patchContent, "", false, []string{}, []string{}, "")
+	intent, err = NewCliIntent(s.user, s.projectID, s.hash, "", s.patchContent, "", false, []string{}, []string{}, "", nil)
 	s.NotNil(intent)
 	s.NoError(err)
 
@@ -84,35 +84,35 @@ func (s *CliIntentSuite) TestNewCliIntent() {
 	s.Empty(cIntent.Module)
 	s.Empty(cIntent.Alias)
 
-	intent, err = NewCliIntent(s.user, s.projectID, s.hash, s.module, "", s.description, true, s.variants, s.tasks, s.alias)
+	intent, err = NewCliIntent(s.user, s.projectID, s.hash, s.module, "", s.description, true, s.variants, s.tasks, s.alias, nil)
 	s.NotNil(intent)
 	s.NoError(err)
 }
 
 func (s *CliIntentSuite) TestNewCliIntentRejectsInvalidIntents() {
-	intent, err := NewCliIntent("", s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
+	intent, err := NewCliIntent("", s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias, nil)
 	s.Nil(intent)
 	s.Error(err)
 
-	intent, err = NewCliIntent(s.user, "", s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
+	intent, err = NewCliIntent(s.user, "", s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias, nil)
 	s.Nil(intent)
 	s.Error(err)
 
-	intent, err = NewCliIntent(s.user, s.projectID, "", s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
+	intent, err = NewCliIntent(s.user, s.projectID, "", s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias, nil)
 	s.Nil(intent)
 	s.Error(err)
 
-	intent, err = NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, []string{}, s.tasks, "")
+	intent, err = NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, []string{}, s.tasks, "", nil)
 	s.Nil(intent)
 	s.Error(err)
 
-	intent, err = NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, []string{}, "")
+	intent, err = NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, []string{}, "", nil)
 	s.Nil(intent)
 	s.Error(err)
 }
 
 func (s *CliIntentSuite) TestInsert() {
-	intent, err := NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
+	intent, err := NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias, nil)
 	s.NoError(err)
 	s.NotNil(intent)
 
@@ -126,7 +126,7 @@ func (s *CliIntentSuite) TestInsert() {
 }
 
 func (s *CliIntentSuite) TestSetProcessed() {
-	intent, err := NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
+	intent, err := NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias, nil)
 	s.NoError(err)
 	s.NotNil(intent)
 	s.NoError(intent.Insert())
@@ -152,7 +152,8 @@ func findCliIntents(processed bool) ([]*cliIntent, error) {
 }
 
 func (s *CliIntentSuite) TestNewPatch() {
-	intent, err := NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
+	parameters := []Parameter{{Key: "suite", Value: "core"}}
+	intent, err := NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias, parameters)
 	s.NoError(err)
 	s.NotNil(intent)
 
@@ -178,5 +179,6 @@ func (s *CliIntentSuite) TestNewPatch() {
 	s.False(patchDoc.Activated)
 	s.Empty(patchDoc.PatchedConfig)
 	s.Equal(s.alias, patchDoc.Alias)
+	s.Equal(parameters, patchDoc.Parameters)
 	s.Zero(patchDoc.GithubPatchData)
 }
@@ -46,6 +46,14 @@ type Patch struct {
 	PatchedConfig   string         `bson:"patched_config"`
 	Alias           string         `bson:"alias"`
 	GithubPatchData GithubPatch    `bson:"github_patch_data,omitempty"`
+	// Parameters overrides the defaults of the project's parameters.
+	Parameters []Parameter `bson:"parameters,omitempty"`
+}
+
+// Parameter is the value of a project parameter.
+type Parameter struct {
+	Key   string `bson:"key" json:"key"`
+	Value string `bson:"value" json:"value"`
 }
 
 // GithubPatch stores patch data for patches create from GitHub pull requests
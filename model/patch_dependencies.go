@@ -3,7 +3,10 @@ package model
 import "github.com/mongodb/grip"
 
 type dependencyIncluder struct {
-	Project  *Project
+	Project *Project
+	// mainline includes dependencies for a mainline commit rather than a
+	// patch, so unpatchable and patch_optional tasks are included.
+	mainline bool
 	included map[TVPair]bool
 }
 
@@ -40,7 +43,7 @@ func (di *dependencyIncluder) handle(pair TVPair) bool {
 
 	// we must load the BuildVariantTaskUnit for the task/variant pair,
 	// since it contains the full scope of dependency information
-	bvt := di.Project.FindTaskForVariant(pair.TaskName, pair.Variant)
+	bvt := di.findUnitForVariant(pair)
 	if bvt == nil {
 		grip.Errorf("task %s does not exist in project %s", pair.TaskName,
 			di.Project.Identifier)
@@ -48,7 +51,7 @@ func (di *dependencyIncluder) handle(pair TVPair) bool {
 		return false // task not found in project--skip it.
 	}
 
-	if patchable := bvt.Patchable; patchable != nil && !*patchable {
+	if patchable := bvt.Patchable; !di.mainline && patchable != nil && !*patchable {
 		di.included[pair] = false
 		return false // task cannot be patched, so skip it
 	}
@@ -69,6 +72,30 @@ func (di *dependencyIncluder) handle(pair TVPair) bool {
 	return true
 }
 
+// findUnitForVariant returns the variant's entry for the pair's task or task
+// group. A task group's entry takes its dependency information from the group.
+func (di *dependencyIncluder) findUnitForVariant(pair TVPair) *BuildVariantTaskUnit {
+	tg := di.Project.FindTaskGroup(pair.TaskName)
+	if tg == nil {
+		return di.Project.FindTaskForVariant(pair.TaskName, pair.Variant)
+	}
+	bv := di.Project.FindBuildVariant(pair.Variant)
+	if bv == nil {
+		return nil
+	}
+	for _, bvt := range bv.Tasks {
+		if bvt.Name == pair.TaskName && bvt.IsGroup {
+			if bvt.Patchable == nil {
+				bvt.Patchable = tg.Patchable
+			}
+			bvt.DependsOn = tg.DependsOn
+			bvt.Requires = tg.Requires
+			return &bvt
+		}
+	}
+	return nil
+}
+
 // expandRequirements finds all tasks required by the current task/variant pair.
 func (di *dependencyIncluder) expandRequirements(pair TVPair, reqs []TaskUnitRequirement) []TVPair {
 	deps := []TVPair{}
@@ -98,7 +125,11 @@ func (di *dependencyIncluder) expandDependencies(pair TVPair, depends []TaskUnit
 	deps := []TVPair{}
 	for _, d := range depends {
 		// don't automatically add dependencies if they are marked patch_optional
-		if d.PatchOptional {
+		if d.PatchOptional && !di.mainline {
+			continue
+		}
+		// tasks in other projects are not part of the patch
+		if d.IsCrossProject() {
 			continue
 		}
 		switch {
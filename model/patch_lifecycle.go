@@ -17,7 +17,6 @@ import (
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/subprocess"
 	"github.com/evergreen-ci/evergreen/thirdparty"
-	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/level"
 	"github.com/mongodb/grip/message"
@@ -199,8 +198,8 @@ func IncludePatchDependencies(project *Project, tvpairs []TVPair) []TVPair {
 
 // MakePatchedConfig takes in the path to a remote configuration a stringified version
 // of the current project and returns an unmarshalled version of the project
-// with the patch applied
-func MakePatchedConfig(p *patch.Patch, remoteConfigPath, projectConfig string) (
+// with the patch applied. Files included by the project are read with fetch.
+func MakePatchedConfig(p *patch.Patch, remoteConfigPath, projectConfig string, fetch ProjectFileFetcher) (
 	*Project, error) {
 	for _, patchPart := range p.Patches {
 		// we only need to patch the main project and not any other modules
@@ -208,107 +207,96 @@ func MakePatchedConfig(p *patch.Patch, remoteConfigPath, projectConfig string) (
 			continue
 		}
 
-		var patchFilePath string
-		var err error
-		if patchPart.PatchSet.Patch == "" {
-			reader, err := db.GetGridFile(patch.GridFSPrefix, patchPart.PatchSet.PatchFileId)
-			if err != nil {
-				return nil, errors.Wrap(err, "Can't fetch patch file from gridfs")
-			}
-			defer reader.Close()
-			bytes, err := ioutil.ReadAll(reader)
-			if err != nil {
-				return nil, errors.Wrap(err, "Can't read patch file contents from gridfs")
-			}
-
-			patchFilePath, err = util.WriteToTempFile(string(bytes))
-			if err != nil {
-				return nil, errors.Wrap(err, "could not write temporary patch file")
-			}
-
-		} else {
-			patchFilePath, err = util.WriteToTempFile(patchPart.PatchSet.Patch)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not write temporary patch file")
-			}
-		}
-
-		defer os.Remove(patchFilePath)
-		// write project configuration
-		configFilePath, err := util.WriteToTempFile(projectConfig)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not write config file")
-		}
-		defer os.Remove(configFilePath)
-
-		// clean the working directory
-		workingDirectory := filepath.Dir(patchFilePath)
-		localConfigPath := filepath.Join(
-			workingDirectory,
-			remoteConfigPath,
-		)
-		parentDir := strings.Split(
-			remoteConfigPath,
-			string(os.PathSeparator),
-		)[0]
-		err = os.RemoveAll(filepath.Join(workingDirectory, parentDir))
+		data, err := patchFile(patchPart, remoteConfigPath, projectConfig)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
-		if err = os.MkdirAll(filepath.Dir(localConfigPath), 0755); err != nil {
+		project := &Project{}
+		if err = LoadProjectWithIncludes(data, p.Project, fetch, project); err != nil {
 			return nil, errors.WithStack(err)
 		}
-		// rename the temporary config file name to the remote config
-		// file path if we are patching an existing remote config
-		if len(projectConfig) > 0 {
-			if err = os.Rename(configFilePath, localConfigPath); err != nil {
-				return nil, errors.Wrapf(err, "could not rename file '%v' to '%v'",
-					configFilePath, localConfigPath)
-			}
-			defer os.Remove(localConfigPath)
-		}
-
-		// selectively apply the patch to the config file
-		patchCommandStrings := []string{
-			fmt.Sprintf("set -o xtrace"),
-			fmt.Sprintf("set -o errexit"),
-			fmt.Sprintf("git apply --whitespace=fix --include=%v < '%v'",
-				remoteConfigPath, patchFilePath),
-		}
-
-		stderr := send.MakeWriterSender(grip.GetSender(), level.Error)
-		defer stderr.Close()
-		stdout := send.MakeWriterSender(grip.GetSender(), level.Info)
-		defer stdout.Close()
-		output := subprocess.OutputOptions{Output: stdout, Error: stderr}
-
-		patchCmd := subprocess.NewLocalCommand(
-			strings.Join(patchCommandStrings, "\n"),
-			workingDirectory,
-			"bash",
-			nil,
-			true)
-
-		if err = patchCmd.SetOutput(output); err != nil {
-			return nil, errors.Wrap(err, "problem configuring command output")
-		}
+		return project, nil
+	}
+	return nil, errors.New("no patch on project")
+}
 
-		ctx := context.TODO()
-		if err = patchCmd.Run(ctx); err != nil {
-			return nil, errors.Errorf("could not run patch command: %v", err)
+// patchFile applies the changes in the patch part to the file at remotePath,
+// whose contents before the patch are given, and returns the patched contents.
+func patchFile(patchPart patch.ModulePatch, remotePath, contents string) ([]byte, error) {
+	patchContents := patchPart.PatchSet.Patch
+	if patchContents == "" {
+		reader, err := db.GetGridFile(patch.GridFSPrefix, patchPart.PatchSet.PatchFileId)
+		if err != nil {
+			return nil, errors.Wrap(err, "Can't fetch patch file from gridfs")
 		}
-		// read in the patched config file
-		data, err := ioutil.ReadFile(localConfigPath)
+		defer reader.Close()
+		bytes, err := ioutil.ReadAll(reader)
 		if err != nil {
-			return nil, errors.Wrap(err, "could not read patched config file")
+			return nil, errors.Wrap(err, "Can't read patch file contents from gridfs")
 		}
-		project := &Project{}
-		if err = LoadProjectInto(data, p.Project, project); err != nil {
-			return nil, errors.WithStack(err)
+		patchContents = string(bytes)
+	}
+
+	// apply the patch in a working directory of its own
+	workingDirectory, err := ioutil.TempDir("", "patch-file")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create working directory")
+	}
+	defer os.RemoveAll(workingDirectory)
+
+	patchFilePath := filepath.Join(workingDirectory, "patch.diff")
+	if err = ioutil.WriteFile(patchFilePath, []byte(patchContents), 0600); err != nil {
+		return nil, errors.Wrap(err, "could not write temporary patch file")
+	}
+
+	// write the file's current contents if we are patching an existing
+	// remote file
+	localPath := filepath.Join(workingDirectory, "src", remotePath)
+	if err = os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(contents) > 0 {
+		if err = ioutil.WriteFile(localPath, []byte(contents), 0600); err != nil {
+			return nil, errors.Wrap(err, "could not write file")
 		}
-		return project, nil
 	}
-	return nil, errors.New("no patch on project")
+
+	// selectively apply the patch to the file
+	patchCommandStrings := []string{
+		fmt.Sprintf("set -o xtrace"),
+		fmt.Sprintf("set -o errexit"),
+		fmt.Sprintf("git apply --whitespace=fix --include=%v < '%v'",
+			remotePath, patchFilePath),
+	}
+
+	stderr := send.MakeWriterSender(grip.GetSender(), level.Error)
+	defer stderr.Close()
+	stdout := send.MakeWriterSender(grip.GetSender(), level.Info)
+	defer stdout.Close()
+	output := subprocess.OutputOptions{Output: stdout, Error: stderr}
+
+	patchCmd := subprocess.NewLocalCommand(
+		strings.Join(patchCommandStrings, "\n"),
+		filepath.Join(workingDirectory, "src"),
+		"bash",
+		nil,
+		true)
+
+	if err = patchCmd.SetOutput(output); err != nil {
+		return nil, errors.Wrap(err, "problem configuring command output")
+	}
+
+	ctx := context.TODO()
+	if err = patchCmd.Run(ctx); err != nil {
+		return nil, errors.Errorf("could not run patch command: %v", err)
+	}
+
+	// read in the patched file
+	data, err := ioutil.ReadFile(localPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read patched file")
+	}
+	return data, nil
 }
 
 // Finalizes a patch:
@@ -354,6 +342,11 @@ func FinalizePatch(p *patch.Patch, requester string, githubOauthToken string) (*
 		RevisionOrderNumber: p.PatchNumber,
 	}
 
+	patchVersion.Parameters, err = project.ResolveParameters(p.Parameters)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid patch parameters")
+	}
+
 	tasks := TaskVariantPairs{}
 	if len(p.VariantsTasks) > 0 {
 		tasks = VariantTasksToTVPairs(p.VariantsTasks)
@@ -373,6 +366,14 @@ func FinalizePatch(p *patch.Patch, requester string, githubOauthToken string) (*
 		}).TVPairsToVariantTasks()
 	}
 
+	// only run the tasks that watch the files the patch changes
+	if project.HasPathFilters() {
+		tasks = project.filterPatchTasks(p, tasks)
+		if err = p.SetVariantsTasks(tasks.TVPairsToVariantTasks()); err != nil {
+			return nil, errors.Wrap(err, "error saving tasks affected by the patch")
+		}
+	}
+
 	taskIds := NewPatchTaskIdTable(project, patchVersion, tasks)
 	variantsProcessed := map[string]bool{}
 	for _, vt := range p.VariantsTasks {
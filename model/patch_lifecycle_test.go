@@ -47,7 +47,7 @@ func TestMakePatchedConfig(t *testing.T) {
 			}
 			projectBytes, err := ioutil.ReadFile(filepath.Join(cwd, "testdata", "project.config"))
 			So(err, ShouldBeNil)
-			project, err := MakePatchedConfig(p, remoteConfigPath, string(projectBytes))
+			project, err := MakePatchedConfig(p, remoteConfigPath, string(projectBytes), nil)
 			So(err, ShouldBeNil)
 			So(project, ShouldNotBeNil)
 			So(len(project.Tasks), ShouldEqual, 2)
@@ -66,7 +66,7 @@ func TestMakePatchedConfig(t *testing.T) {
 				}},
 			}
 
-			project, err := MakePatchedConfig(p, remoteConfigPath, "")
+			project, err := MakePatchedConfig(p, remoteConfigPath, "", nil)
 			So(err, ShouldBeNil)
 			So(project, ShouldNotBeNil)
 			So(len(project.Tasks), ShouldEqual, 1)
@@ -313,6 +313,33 @@ func TestIncludePatchDependencies(t *testing.T) {
 
 }
 
+func TestPatchFileLeavesTempDirAlone(t *testing.T) {
+	assert := assert.New(t)
+
+	// a directory in the temp dir named like the patched file's directory
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	sentinel := filepath.Join(dir, "keep")
+	assert.NoError(ioutil.WriteFile(sentinel, []byte("keep"), 0600))
+
+	remotePath := filepath.Join(filepath.Base(dir), "evergreen.yml")
+	diff := fmt.Sprintf(`diff --git a/%[1]s b/%[1]s
+index 0000000..1111111 100644
+--- a/%[1]s
++++ b/%[1]s
+@@ -1 +1,2 @@
+ a: 1
++b: 2
+`, remotePath)
+	data, err := patchFile(patch.ModulePatch{PatchSet: patch.PatchSet{Patch: diff}}, remotePath, "a: 1\n")
+	assert.NoError(err)
+	assert.Equal("a: 1\nb: 2\n", string(data))
+
+	_, err = os.Stat(sentinel)
+	assert.NoError(err)
+}
+
 func TestVariantTasksToTVPairs(t *testing.T) {
 	assert := assert.New(t)
 
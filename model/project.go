@@ -24,6 +24,19 @@ const (
 	SystemCommandType = "system"
 )
 
+// The kinds of task failure that an auto_retry policy can retry.
+const (
+	AutoRetryOnSystemFailed = "system-failed"
+	AutoRetryOnTimeout      = "timeout"
+	AutoRetryOnTestFailure  = "test-failure"
+)
+
+var AutoRetryFailureTypes = []string{
+	AutoRetryOnSystemFailed,
+	AutoRetryOnTimeout,
+	AutoRetryOnTestFailure,
+}
+
 const (
 	// DefaultCommandType is a system configuration option that is used to
 	// differentiate between setup related commands and actual testing commands.
@@ -53,6 +66,8 @@ type Project struct {
 	TaskGroups      []TaskGroup                `yaml:"task_groups,omitempty" bson:"task_groups"`
 	Tasks           []ProjectTask              `yaml:"tasks,omitempty" bson:"tasks"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
+	Parameters      []ProjectParameter         `yaml:"parameters,omitempty" bson:"parameters,omitempty"`
+	LintIgnore      []string                   `yaml:"lint_ignore,omitempty" bson:"lint_ignore,omitempty"`
 
 	// Flag that indicates a project as requiring user authentication
 	Private bool `yaml:"private,omitempty" bson:"private"`
@@ -78,6 +93,20 @@ type BuildVariantTaskUnit struct {
 	// currently unsupported (TODO EVG-578)
 	ExecTimeoutSecs int   `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
 	Stepback        *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
+
+	AutoRetry *AutoRetry `yaml:"auto_retry,omitempty" bson:"auto_retry,omitempty"`
+}
+
+// AutoRetry is a policy for running a task again, as a new execution, when
+// it fails.
+type AutoRetry struct {
+	// MaxAttempts is the most times the task runs, including the first.
+	MaxAttempts int `yaml:"max_attempts,omitempty" bson:"max_attempts"`
+	// On are the kinds of failure to retry; every kind if empty.
+	On []string `yaml:"on,omitempty" bson:"on,omitempty"`
+	// OnlyRequesters limits retries to tasks created by these requesters;
+	// every requester if empty.
+	OnlyRequesters []string `yaml:"only_requesters,omitempty" bson:"only_requesters,omitempty"`
 }
 
 type DisplayTask struct {
@@ -108,6 +137,9 @@ func (bvt *BuildVariantTaskUnit) Populate(pt ProjectTask) {
 	if bvt.Stepback == nil {
 		bvt.Stepback = pt.Stepback
 	}
+	if bvt.AutoRetry == nil {
+		bvt.AutoRetry = pt.AutoRetry
+	}
 }
 
 // UnmarshalYAML allows tasks to be referenced as single selector strings.
@@ -141,6 +173,11 @@ type BuildVariant struct {
 	Tags        []string          `yaml:"tags,omitempty" bson:"tags"`
 	Push        bool              `yaml:"push,omitempty" bson:"push"`
 
+	// Paths and IgnorePaths are gitignore-style patterns of the files the
+	// variant watches; see AffectedTVPairs.
+	Paths       []string `yaml:"paths,omitempty" bson:"paths,omitempty"`
+	IgnorePaths []string `yaml:"ignore_paths,omitempty" bson:"ignore_paths,omitempty"`
+
 	// Use a *int for 2 possible states
 	// nil - not overriding the project setting
 	// non-nil - overriding the project setting with this BatchTime
@@ -152,6 +189,9 @@ type BuildVariant struct {
 	//   3. false = overriding the project setting with false
 	Stepback *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
 
+	// the auto_retry policy of tasks that do not set their own
+	AutoRetry *AutoRetry `yaml:"auto_retry,omitempty" bson:"auto_retry,omitempty"`
+
 	// the default distros.  will be used to run a task if no distro field is
 	// provided for the task
 	RunOn []string `yaml:"run_on,omitempty" bson:"run_on"`
@@ -246,6 +286,20 @@ type TaskUnitDependency struct {
 	Variant       string `yaml:"variant,omitempty" bson:"variant,omitempty"`
 	Status        string `yaml:"status,omitempty" bson:"status,omitempty"`
 	PatchOptional bool   `yaml:"patch_optional,omitempty" bson:"patch_optional,omitempty"`
+
+	// Project is set for dependencies on a task in another project, which
+	// is resolved to the project's task at Revision, or to its most recent
+	// mainline task with the required status if Revision is not set. If
+	// there is no such task, the dependent task is blocked and the version
+	// records a warning.
+	Project  string `yaml:"project,omitempty" bson:"project,omitempty"`
+	Revision string `yaml:"revision,omitempty" bson:"revision,omitempty"`
+}
+
+// IsCrossProject returns true if the dependency is on a task in another
+// project.
+func (td TaskUnitDependency) IsCrossProject() bool {
+	return td.Project != ""
 }
 
 // TaskUnitRequirement represents tasks/groups that must exist along with
@@ -310,12 +364,19 @@ type ProjectTask struct {
 	Commands        []PluginCommandConf   `yaml:"commands,omitempty" bson:"commands"`
 	Tags            []string              `yaml:"tags,omitempty" bson:"tags"`
 
+	// Paths and IgnorePaths are gitignore-style patterns of the files the
+	// task watches; see AffectedTVPairs.
+	Paths       []string `yaml:"paths,omitempty" bson:"paths,omitempty"`
+	IgnorePaths []string `yaml:"ignore_paths,omitempty" bson:"ignore_paths,omitempty"`
+
 	// Use a *bool so that there are 3 possible states:
 	//   1. nil   = not overriding the project setting (default)
 	//   2. true  = overriding the project setting with true
 	//   3. false = overriding the project setting with false
 	Patchable *bool `yaml:"patchable,omitempty" bson:"patchable,omitempty"`
 	Stepback  *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
+
+	AutoRetry *AutoRetry `yaml:"auto_retry,omitempty" bson:"auto_retry,omitempty"`
 }
 
 // TaskIdTable is a map of [variant, task display name]->[task id].
@@ -561,6 +622,11 @@ func populateExpansions(d *distro.Distro, v *version.Version, bv *BuildVariant,
 		expansions.Put(e.Key, e.Value)
 	}
 	expansions.Update(bv.Expansions)
+
+	// parameters come last so that a patch's overrides always apply
+	for _, param := range v.Parameters {
+		expansions.Put(param.Key, param.Value)
+	}
 	return expansions
 }
 
@@ -746,6 +812,25 @@ func (p *Project) FindTaskForVariant(task, variant string) *BuildVariantTaskUnit
 	return nil
 }
 
+// FindAutoRetry returns the auto_retry policy of the task on the variant,
+// which is the first one set on the variant's entry for the task, the task
+// itself, or the variant. It returns nil if none is set.
+func (p *Project) FindAutoRetry(task, variant string) *AutoRetry {
+	bv := p.FindBuildVariant(variant)
+	if bv == nil {
+		return nil
+	}
+	for _, bvt := range bv.Tasks {
+		if bvt.Name == task && bvt.AutoRetry != nil {
+			return bvt.AutoRetry
+		}
+	}
+	if pt := p.FindProjectTask(task); pt != nil && pt.AutoRetry != nil {
+		return pt.AutoRetry
+	}
+	return bv.AutoRetry
+}
+
 func (p *Project) FindBuildVariant(build string) *BuildVariant {
 	for _, b := range p.BuildVariants {
 		if b.Name == build {
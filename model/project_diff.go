@@ -0,0 +1,403 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"gopkg.in/yaml.v2"
+)
+
+// The kinds of project definitions that a diff reports changes to.
+const (
+	ProjectDiffBlock     = "block"
+	ProjectDiffFunction  = "function"
+	ProjectDiffTask      = "task"
+	ProjectDiffTaskGroup = "task_group"
+	ProjectDiffVariant   = "buildvariant"
+)
+
+// How a definition changed between two projects.
+const (
+	ProjectDiffAdded   = "added"
+	ProjectDiffRemoved = "removed"
+	ProjectDiffChanged = "changed"
+)
+
+// ProjectChange is a definition that differs between two versions of a
+// project. Details describe how a changed definition differs.
+type ProjectChange struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	Change  string   `json:"change"`
+	Details []string `json:"details,omitempty"`
+}
+
+func (pc ProjectChange) String() string {
+	out := fmt.Sprintf("%s %s '%s'", pc.Change, pc.Kind, pc.Name)
+	for _, detail := range pc.Details {
+		out += "\n\t" + detail
+	}
+	return out
+}
+
+// DiffProjects returns the definitions that were added, removed or changed
+// from the before project to the after project. Projects are compared after
+// their tags and matrices are evaluated, so a change is reported for each
+// variant or task that runs differently, rather than for the YAML that
+// changed.
+func DiffProjects(before, after *Project) []ProjectChange {
+	changes := []ProjectChange{}
+
+	for _, block := range []struct {
+		name          string
+		before, after *YAMLCommandSet
+	}{
+		{name: "pre", before: before.Pre, after: after.Pre},
+		{name: "post", before: before.Post, after: after.Post},
+		{name: "timeout", before: before.Timeout, after: after.Timeout},
+	} {
+		changes = append(changes, diffDefinitions(ProjectDiffBlock, block.name,
+			block.before != nil, block.after != nil,
+			func() []string { return diffFields(diffField{"commands", block.before, block.after}) })...)
+	}
+
+	functionNames := []string{}
+	for name := range before.Functions {
+		functionNames = append(functionNames, name)
+	}
+	for name := range after.Functions {
+		if _, ok := before.Functions[name]; !ok {
+			functionNames = append(functionNames, name)
+		}
+	}
+	sort.Strings(functionNames)
+	for _, name := range functionNames {
+		b, a := before.Functions[name], after.Functions[name]
+		changes = append(changes, diffDefinitions(ProjectDiffFunction, name, b != nil, a != nil,
+			func() []string { return diffFields(diffField{"commands", b, a}) })...)
+	}
+
+	beforeTasks, afterTasks := map[string]*ProjectTask{}, map[string]*ProjectTask{}
+	var taskNames []string
+	for i := range before.Tasks {
+		beforeTasks[before.Tasks[i].Name] = &before.Tasks[i]
+		taskNames = append(taskNames, before.Tasks[i].Name)
+	}
+	for i := range after.Tasks {
+		afterTasks[after.Tasks[i].Name] = &after.Tasks[i]
+		if beforeTasks[after.Tasks[i].Name] == nil {
+			taskNames = append(taskNames, after.Tasks[i].Name)
+		}
+	}
+	sort.Strings(taskNames)
+	for _, name := range taskNames {
+		b, a := beforeTasks[name], afterTasks[name]
+		changes = append(changes, diffDefinitions(ProjectDiffTask, name, b != nil, a != nil,
+			func() []string { return diffTasks(b, a) })...)
+	}
+
+	beforeGroups, afterGroups := map[string]*TaskGroup{}, map[string]*TaskGroup{}
+	var groupNames []string
+	for i := range before.TaskGroups {
+		beforeGroups[before.TaskGroups[i].Name] = &before.TaskGroups[i]
+		groupNames = append(groupNames, before.TaskGroups[i].Name)
+	}
+	for i := range after.TaskGroups {
+		afterGroups[after.TaskGroups[i].Name] = &after.TaskGroups[i]
+		if beforeGroups[after.TaskGroups[i].Name] == nil {
+			groupNames = append(groupNames, after.TaskGroups[i].Name)
+		}
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		b, a := beforeGroups[name], afterGroups[name]
+		changes = append(changes, diffDefinitions(ProjectDiffTaskGroup, name, b != nil, a != nil,
+			func() []string { return diffTaskGroups(b, a) })...)
+	}
+
+	beforeVariants, afterVariants := map[string]*BuildVariant{}, map[string]*BuildVariant{}
+	var variantNames []string
+	for i := range before.BuildVariants {
+		beforeVariants[before.BuildVariants[i].Name] = &before.BuildVariants[i]
+		variantNames = append(variantNames, before.BuildVariants[i].Name)
+	}
+	for i := range after.BuildVariants {
+		afterVariants[after.BuildVariants[i].Name] = &after.BuildVariants[i]
+		if beforeVariants[after.BuildVariants[i].Name] == nil {
+			variantNames = append(variantNames, after.BuildVariants[i].Name)
+		}
+	}
+	sort.Strings(variantNames)
+	for _, name := range variantNames {
+		b, a := beforeVariants[name], afterVariants[name]
+		changes = append(changes, diffDefinitions(ProjectDiffVariant, name, b != nil, a != nil,
+			func() []string { return diffVariants(b, a) })...)
+	}
+
+	return changes
+}
+
+// diffDefinitions returns the change to a definition, if there is one. The
+// details of a definition in both projects are only computed if it is.
+func diffDefinitions(kind, name string, inBefore, inAfter bool, details func() []string) []ProjectChange {
+	change := ProjectChange{Kind: kind, Name: name}
+	switch {
+	case inBefore && !inAfter:
+		change.Change = ProjectDiffRemoved
+	case !inBefore && inAfter:
+		change.Change = ProjectDiffAdded
+	case inBefore && inAfter:
+		change.Details = details()
+		if len(change.Details) == 0 {
+			return nil
+		}
+		change.Change = ProjectDiffChanged
+	default:
+		return nil
+	}
+	return []ProjectChange{change}
+}
+
+func diffTasks(before, after *ProjectTask) []string {
+	return diffFields(
+		diffField{"depends_on", dependencyNames(before.DependsOn), dependencyNames(after.DependsOn)},
+		diffField{"requires", requirementNames(before.Requires), requirementNames(after.Requires)},
+		diffField{"commands", before.Commands, after.Commands},
+		diffField{"priority", before.Priority, after.Priority},
+		diffField{"exec_timeout_secs", before.ExecTimeoutSecs, after.ExecTimeoutSecs},
+		diffField{"patchable", before.Patchable, after.Patchable},
+		diffField{"stepback", before.Stepback, after.Stepback},
+		diffField{"auto_retry", before.AutoRetry, after.AutoRetry},
+		diffField{"tags", before.Tags, after.Tags},
+		diffField{"paths", before.Paths, after.Paths},
+		diffField{"ignore_paths", before.IgnorePaths, after.IgnorePaths},
+	)
+}
+
+func diffTaskGroups(before, after *TaskGroup) []string {
+	return diffFields(
+		diffField{"tasks", before.Tasks, after.Tasks},
+		diffField{"max_hosts", before.MaxHosts, after.MaxHosts},
+		diffField{"setup_group", before.SetupGroup, after.SetupGroup},
+		diffField{"teardown_group", before.TeardownGroup, after.TeardownGroup},
+		diffField{"setup_task", before.SetupTask, after.SetupTask},
+		diffField{"teardown_task", before.TeardownTask, after.TeardownTask},
+		diffField{"timeout", before.Timeout, after.Timeout},
+		diffField{"depends_on", dependencyNames(before.DependsOn), dependencyNames(after.DependsOn)},
+		diffField{"priority", before.Priority, after.Priority},
+		diffField{"patchable", before.Patchable, after.Patchable},
+		diffField{"tags", before.Tags, after.Tags},
+	)
+}
+
+func diffVariants(before, after *BuildVariant) []string {
+	details := diffFields(
+		diffField{"display_name", before.DisplayName, after.DisplayName},
+		diffField{"run_on", before.RunOn, after.RunOn},
+		diffField{"modules", before.Modules, after.Modules},
+		diffField{"disabled", before.Disabled, after.Disabled},
+		diffField{"batchtime", before.BatchTime, after.BatchTime},
+		diffField{"stepback", before.Stepback, after.Stepback},
+		diffField{"auto_retry", before.AutoRetry, after.AutoRetry},
+		diffField{"push", before.Push, after.Push},
+		diffField{"tags", before.Tags, after.Tags},
+		diffField{"paths", before.Paths, after.Paths},
+		diffField{"ignore_paths", before.IgnorePaths, after.IgnorePaths},
+	)
+
+	expansionNames := []string{}
+	for name := range before.Expansions {
+		expansionNames = append(expansionNames, name)
+	}
+	for name := range after.Expansions {
+		if _, ok := before.Expansions[name]; !ok {
+			expansionNames = append(expansionNames, name)
+		}
+	}
+	sort.Strings(expansionNames)
+	for _, name := range expansionNames {
+		b, inBefore := before.Expansions[name]
+		a, inAfter := after.Expansions[name]
+		switch {
+		case !inAfter:
+			details = append(details, fmt.Sprintf("expansion '%s' removed", name))
+		case !inBefore:
+			details = append(details, fmt.Sprintf("expansion '%s' added: %s", name, a))
+		case a != b:
+			details = append(details, fmt.Sprintf("expansion '%s' changed from %s to %s", name, b, a))
+		}
+	}
+
+	beforeTasks, afterTasks := map[string]*BuildVariantTaskUnit{}, map[string]*BuildVariantTaskUnit{}
+	var taskNames []string
+	for i := range before.Tasks {
+		beforeTasks[before.Tasks[i].Name] = &before.Tasks[i]
+		taskNames = append(taskNames, before.Tasks[i].Name)
+	}
+	for i := range after.Tasks {
+		afterTasks[after.Tasks[i].Name] = &after.Tasks[i]
+		if beforeTasks[after.Tasks[i].Name] == nil {
+			taskNames = append(taskNames, after.Tasks[i].Name)
+		}
+	}
+	sort.Strings(taskNames)
+	for _, name := range taskNames {
+		b, a := beforeTasks[name], afterTasks[name]
+		switch {
+		case a == nil:
+			details = append(details, fmt.Sprintf("task '%s' removed", name))
+		case b == nil:
+			details = append(details, fmt.Sprintf("task '%s' added", name))
+		default:
+			for _, detail := range diffFields(
+				diffField{"depends_on", dependencyNames(b.DependsOn), dependencyNames(a.DependsOn)},
+				diffField{"requires", requirementNames(b.Requires), requirementNames(a.Requires)},
+				diffField{"priority", b.Priority, a.Priority},
+				diffField{"patchable", b.Patchable, a.Patchable},
+				diffField{"distros", b.Distros, a.Distros},
+				diffField{"exec_timeout_secs", b.ExecTimeoutSecs, a.ExecTimeoutSecs},
+				diffField{"stepback", b.Stepback, a.Stepback},
+				diffField{"auto_retry", b.AutoRetry, a.AutoRetry},
+			) {
+				details = append(details, fmt.Sprintf("task '%s' %s", name, detail))
+			}
+		}
+	}
+
+	beforeDisplay, afterDisplay := map[string][]string{}, map[string][]string{}
+	var displayNames []string
+	for _, dt := range before.DisplayTasks {
+		beforeDisplay[dt.Name] = dt.ExecutionTasks
+		displayNames = append(displayNames, dt.Name)
+	}
+	for _, dt := range after.DisplayTasks {
+		afterDisplay[dt.Name] = dt.ExecutionTasks
+		if _, ok := beforeDisplay[dt.Name]; !ok {
+			displayNames = append(displayNames, dt.Name)
+		}
+	}
+	sort.Strings(displayNames)
+	for _, name := range displayNames {
+		b, inBefore := beforeDisplay[name]
+		a, inAfter := afterDisplay[name]
+		switch {
+		case !inAfter:
+			details = append(details, fmt.Sprintf("display task '%s' removed", name))
+		case !inBefore:
+			details = append(details, fmt.Sprintf("display task '%s' added", name))
+		default:
+			for _, detail := range diffFields(diffField{"execution_tasks", b, a}) {
+				details = append(details, fmt.Sprintf("display task '%s' %s", name, detail))
+			}
+		}
+	}
+
+	return details
+}
+
+// dependencyNames returns the dependencies as strings of the form
+// variant/task (status), which can be compared and read.
+func dependencyNames(deps []TaskUnitDependency) []string {
+	names := []string{}
+	for _, dep := range deps {
+		name := dep.Name
+		if dep.Variant != "" {
+			name = dep.Variant + "/" + name
+		}
+		if dep.IsCrossProject() {
+			name = dep.Project + ":" + name
+			if dep.Revision != "" {
+				name += "@" + dep.Revision
+			}
+		}
+		if dep.Status != "" {
+			name += fmt.Sprintf(" (%s)", dep.Status)
+		}
+		if dep.PatchOptional {
+			name += " (patch optional)"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func requirementNames(reqs []TaskUnitRequirement) []string {
+	names := []string{}
+	for _, req := range reqs {
+		name := req.Name
+		if req.Variant != "" {
+			name = req.Variant + "/" + name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// diffField is a field of a definition in two projects.
+type diffField struct {
+	name          string
+	before, after interface{}
+}
+
+// diffFields describes the fields that differ. Lists of strings are
+// described by the items added and removed, values that are short by their
+// old and new values, and other values only by their name.
+func diffFields(fields ...diffField) []string {
+	details := []string{}
+	for _, f := range fields {
+		before, after := diffValue(f.before), diffValue(f.after)
+		if before == after {
+			continue
+		}
+
+		beforeList, isList := f.before.([]string)
+		afterList, _ := f.after.([]string)
+		if isList {
+			var added, removed []string
+			for _, item := range afterList {
+				if !util.StringSliceContains(beforeList, item) {
+					added = append(added, item)
+				}
+			}
+			for _, item := range beforeList {
+				if !util.StringSliceContains(afterList, item) {
+					removed = append(removed, item)
+				}
+			}
+			if len(added) > 0 {
+				details = append(details, fmt.Sprintf("%s added %s", f.name, strings.Join(added, ", ")))
+			}
+			if len(removed) > 0 {
+				details = append(details, fmt.Sprintf("%s removed %s", f.name, strings.Join(removed, ", ")))
+			}
+			if len(added) == 0 && len(removed) == 0 {
+				details = append(details, fmt.Sprintf("%s reordered", f.name))
+			}
+			continue
+		}
+
+		if !strings.Contains(before, "\n") && !strings.Contains(after, "\n") {
+			details = append(details, fmt.Sprintf("%s changed from %s to %s", f.name, before, after))
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s changed", f.name))
+	}
+	return details
+}
+
+// diffValue returns the value as YAML, which is compared to find changes.
+// Empty values and nil pointers are all "none".
+func diffValue(value interface{}) string {
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	str := strings.TrimSpace(string(out))
+	switch str {
+	case "", "null", "[]", "{}", `""`:
+		return "none"
+	}
+	return str
+}
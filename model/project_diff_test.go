@@ -0,0 +1,137 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffBaseProject = `
+functions:
+  setup:
+    command: shell.exec
+    params:
+      script: ./setup.sh
+tasks:
+- name: compile
+  commands:
+  - func: setup
+- name: test
+  depends_on:
+  - name: compile
+  commands:
+  - func: setup
+- name: lint
+axes:
+- id: os
+  values:
+  - id: linux
+    run_on: linux-small
+  - id: windows
+    run_on: windows-small
+buildvariants:
+- matrix_name: tests
+  matrix_spec:
+    os: "*"
+  tasks:
+  - name: compile
+  - name: test
+- name: docs
+  expansions:
+    format: html
+  tasks:
+  - name: lint
+`
+
+const diffPatchedProject = `
+functions:
+  setup:
+    command: shell.exec
+    params:
+      script: ./setup.sh --verbose
+tasks:
+- name: compile
+  commands:
+  - func: setup
+- name: test
+  depends_on:
+  - name: compile
+  - name: package
+  commands:
+  - func: setup
+- name: package
+axes:
+- id: os
+  values:
+  - id: linux
+    run_on: linux-large
+  - id: mac
+    run_on: mac-small
+buildvariants:
+- matrix_name: tests
+  matrix_spec:
+    os: "*"
+  tasks:
+  - name: compile
+  - name: test
+- name: docs
+  expansions:
+    format: pdf
+    paper: a4
+  tasks:
+  - name: package
+`
+
+func TestDiffProjects(t *testing.T) {
+	assert := assert.New(t)
+	before, after := &Project{}, &Project{}
+	require.NoError(t, LoadProjectInto([]byte(diffBaseProject), "diff", before))
+	require.NoError(t, LoadProjectInto([]byte(diffPatchedProject), "diff", after))
+
+	changes := DiffProjects(before, after)
+	byName := map[string]ProjectChange{}
+	for _, change := range changes {
+		byName[change.Kind+" "+change.Name] = change
+	}
+
+	assert.Equal(ProjectDiffChanged, byName["function setup"].Change)
+	assert.Equal([]string{"commands changed"}, byName["function setup"].Details)
+
+	assert.Equal(ProjectDiffRemoved, byName["task lint"].Change)
+	assert.Equal(ProjectDiffAdded, byName["task package"].Change)
+	assert.Equal([]string{"depends_on added package"}, byName["task test"].Details)
+	assert.NotContains(byName, "task compile")
+
+	// matrix variants are compared after they are expanded
+	var linux, mac, windows string
+	for _, bv := range after.BuildVariants {
+		if util.StringSliceContains(bv.RunOn, "linux-large") {
+			linux = bv.Name
+		}
+		if util.StringSliceContains(bv.RunOn, "mac-small") {
+			mac = bv.Name
+		}
+	}
+	for _, bv := range before.BuildVariants {
+		if util.StringSliceContains(bv.RunOn, "windows-small") {
+			windows = bv.Name
+		}
+	}
+	require.NotEmpty(t, linux)
+	assert.Equal(ProjectDiffChanged, byName["buildvariant "+linux].Change)
+	assert.Equal([]string{"run_on added linux-large", "run_on removed linux-small"},
+		byName["buildvariant "+linux].Details)
+	assert.Equal(ProjectDiffAdded, byName["buildvariant "+mac].Change)
+	assert.Equal(ProjectDiffRemoved, byName["buildvariant "+windows].Change)
+
+	assert.Equal([]string{
+		"expansion 'format' changed from html to pdf",
+		"expansion 'paper' added: a4",
+		"task 'lint' removed",
+		"task 'package' added",
+	}, byName["buildvariant docs"].Details)
+
+	assert.Empty(DiffProjects(after, after))
+}
@@ -0,0 +1,398 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/pkg/errors"
+)
+
+// Project configurations may be split across files with an include list in
+// the main configuration file:
+//
+//   include:
+//     - filename: evergreen/tasks.yml
+//     - filename: etc/evergreen_variants.yml
+//       module: enterprise
+//
+// Paths are relative to the root of the project's repository or, if a module
+// is given, of the module's repository. Included files are merged into the
+// main file before it is translated, by these rules:
+//  - functions, tasks, task groups, build variants, matrix axes and modules
+//    are added to those of the main file; defining one with the same name as
+//    one already defined is an error.
+//  - ignore lists are appended to the main file's.
+//  - pre, post and timeout may be defined in at most one file.
+//  - other project settings may only be set in the main file.
+//  - included files may not include other files.
+
+// parserInclude is a file whose definitions are merged into the project.
+type parserInclude struct {
+	FileName string `yaml:"filename"`
+	Module   string `yaml:"module"`
+}
+
+// UnmarshalYAML allows an include to be given as just a file name.
+func (pi *parserInclude) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var fileName string
+	if err := unmarshal(&fileName); err == nil {
+		*pi = parserInclude{FileName: fileName}
+		return nil
+	}
+	type copyType parserInclude
+	var include copyType
+	if err := unmarshal(&include); err != nil {
+		return errors.WithStack(err)
+	}
+	*pi = parserInclude(include)
+	return nil
+}
+
+func (pi parserInclude) String() string {
+	if pi.Module != "" {
+		return fmt.Sprintf("%s:%s", pi.Module, pi.FileName)
+	}
+	return pi.FileName
+}
+
+// ProjectFileFetcher returns the contents of a file at the revision of the
+// project being loaded. The module is nil for files in the project's own
+// repository.
+type ProjectFileFetcher func(module *Module, path string) ([]byte, error)
+
+// LoadProjectWithIncludes loads the raw data from the config file into project
+// as LoadProjectInto does, first merging in any files that it includes, which
+// are read with fetch.
+func LoadProjectWithIncludes(data []byte, identifier string, fetch ProjectFileFetcher, project *Project) error {
+	return loadProject(data, identifier, fetch, project)
+}
+
+// mergeIncludes reads the files included by the project and merges their
+// definitions into it. An error reading a file is returned on its own, since
+// the project cannot be loaded without it.
+func mergeIncludes(pp *parserProject, fetch ProjectFileFetcher) []error {
+	if len(pp.Include) == 0 {
+		return nil
+	}
+	if fetch == nil {
+		return []error{errors.New("project includes other files, which cannot be read here")}
+	}
+
+	var errs []error
+	for _, include := range pp.Include {
+		if include.FileName == "" {
+			errs = append(errs, errors.New("include is missing a file name"))
+			continue
+		}
+		var module *Module
+		if include.Module != "" {
+			for i := range pp.Modules {
+				if pp.Modules[i].Name == include.Module {
+					module = &pp.Modules[i]
+					break
+				}
+			}
+			if module == nil {
+				errs = append(errs, errors.Errorf("include '%s' refers to module '%s', which is not defined", include, include.Module))
+				continue
+			}
+		}
+
+		data, err := fetch(module, include.FileName)
+		if err != nil {
+			return []error{errors.Wrapf(err, "error reading included file '%s'", include)}
+		}
+		included, parseErrs := createIntermediateProject(data)
+		if len(parseErrs) > 0 {
+			for _, err := range parseErrs {
+				errs = append(errs, errors.Wrapf(err, "error parsing included file '%s'", include))
+			}
+			continue
+		}
+		errs = append(errs, mergeParserProject(pp, included, include.String())...)
+	}
+	pp.Include = nil
+
+	return errs
+}
+
+// mergeParserProject adds the definitions in the included project to the
+// project, following the rules described at the top of this file.
+func mergeParserProject(pp, included *parserProject, file string) []error {
+	var errs []error
+	if len(included.Include) > 0 {
+		errs = append(errs, errors.Errorf("included file '%s' cannot include other files", file))
+	}
+	if settings := setProjectSettings(included); len(settings) > 0 {
+		errs = append(errs, errors.Errorf("included file '%s' sets %s, which may only be set in the main configuration file",
+			file, strings.Join(settings, ", ")))
+	}
+
+	duplicate := func(kind, name string) {
+		errs = append(errs, errors.Errorf("%s '%s' in included file '%s' is already defined", kind, name, file))
+	}
+
+	if pp.Functions == nil && len(included.Functions) > 0 {
+		pp.Functions = map[string]*YAMLCommandSet{}
+	}
+	functionNames := make([]string, 0, len(included.Functions))
+	for name := range included.Functions {
+		functionNames = append(functionNames, name)
+	}
+	sort.Strings(functionNames)
+	for _, name := range functionNames {
+		if _, ok := pp.Functions[name]; ok {
+			duplicate("function", name)
+			continue
+		}
+		pp.Functions[name] = included.Functions[name]
+	}
+
+	taskNames := map[string]bool{}
+	for _, t := range pp.Tasks {
+		taskNames[t.Name] = true
+	}
+	for _, t := range included.Tasks {
+		if taskNames[t.Name] {
+			duplicate("task", t.Name)
+			continue
+		}
+		taskNames[t.Name] = true
+		pp.Tasks = append(pp.Tasks, t)
+	}
+
+	groupNames := map[string]bool{}
+	for _, tg := range pp.TaskGroups {
+		groupNames[tg.Name] = true
+	}
+	for _, tg := range included.TaskGroups {
+		if groupNames[tg.Name] {
+			duplicate("task group", tg.Name)
+			continue
+		}
+		groupNames[tg.Name] = true
+		pp.TaskGroups = append(pp.TaskGroups, tg)
+	}
+
+	variantNames := map[string]bool{}
+	for _, bv := range pp.BuildVariants {
+		variantNames[parserBVName(bv)] = true
+	}
+	for _, bv := range included.BuildVariants {
+		if variantNames[parserBVName(bv)] {
+			duplicate("buildvariant", parserBVName(bv))
+			continue
+		}
+		variantNames[parserBVName(bv)] = true
+		pp.BuildVariants = append(pp.BuildVariants, bv)
+	}
+
+	axisIds := map[string]bool{}
+	for _, axis := range pp.Axes {
+		axisIds[axis.Id] = true
+	}
+	for _, axis := range included.Axes {
+		if axisIds[axis.Id] {
+			duplicate("axis", axis.Id)
+			continue
+		}
+		axisIds[axis.Id] = true
+		pp.Axes = append(pp.Axes, axis)
+	}
+
+	moduleNames := map[string]bool{}
+	for _, module := range pp.Modules {
+		moduleNames[module.Name] = true
+	}
+	for _, module := range included.Modules {
+		if moduleNames[module.Name] {
+			duplicate("module", module.Name)
+			continue
+		}
+		moduleNames[module.Name] = true
+		pp.Modules = append(pp.Modules, module)
+	}
+
+	pp.Ignore = append(pp.Ignore, included.Ignore...)
+
+	for _, block := range []struct {
+		name     string
+		project  **YAMLCommandSet
+		included *YAMLCommandSet
+	}{
+		{name: "pre", project: &pp.Pre, included: included.Pre},
+		{name: "post", project: &pp.Post, included: included.Post},
+		{name: "timeout", project: &pp.Timeout, included: included.Timeout},
+	} {
+		if block.included == nil {
+			continue
+		}
+		if *block.project != nil {
+			duplicate("block", block.name)
+			continue
+		}
+		*block.project = block.included
+	}
+
+	return errs
+}
+
+// parserBVName returns the name that identifies a variant or matrix.
+func parserBVName(bv parserBV) string {
+	if bv.matrix != nil {
+		return bv.matrix.Id
+	}
+	return bv.Name
+}
+
+// setProjectSettings returns the project-wide settings that are set in the
+// project, which may not be set by included files.
+func setProjectSettings(pp *parserProject) []string {
+	var settings []string
+	for name, set := range map[string]bool{
+		"enabled":               pp.Enabled,
+		"stepback":              pp.Stepback,
+		"batchtime":             pp.BatchTime != 0,
+		"owner":                 pp.Owner != "",
+		"repo":                  pp.Repo != "",
+		"remote_path":           pp.RemotePath != "",
+		"repokind":              pp.RepoKind != "",
+		"branch":                pp.Branch != "",
+		"identifier":            pp.Identifier != "",
+		"display_name":          pp.DisplayName != "",
+		"command_type":          pp.CommandType != "",
+		"callback_timeout_secs": pp.CallbackTimeout != 0,
+		"exec_timeout_secs":     pp.ExecTimeoutSecs != 0,
+		"parameters":            len(pp.Parameters) > 0,
+		"lint_ignore":           len(pp.LintIgnore) > 0,
+	} {
+		if set {
+			settings = append(settings, name)
+		}
+	}
+	sort.Strings(settings)
+	return settings
+}
+
+// GithubProjectFileFetcher returns a fetcher that reads files in the
+// project's repository at the given revision, and files in its modules at
+// their configured ref or, if they have none, at the last commit on their
+// branch as of the revision's commit, so that the files read for a revision
+// do not change as the modules' branches move on.
+func GithubProjectFileFetcher(oauthToken string, ref *ProjectRef, revision string) ProjectFileFetcher {
+	moduleRevisions := map[string]string{}
+	return func(module *Module, path string) ([]byte, error) {
+		if module == nil {
+			return getGithubFile(oauthToken, ref.Owner, ref.Repo, path, revision)
+		}
+		moduleRevision, ok := moduleRevisions[module.Name]
+		if !ok {
+			var err error
+			moduleRevision, err = moduleRevisionAt(oauthToken, ref, revision, module)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			moduleRevisions[module.Name] = moduleRevision
+		}
+		owner, repo := module.GetRepoOwnerAndName()
+		return getGithubFile(oauthToken, owner, repo, path, moduleRevision)
+	}
+}
+
+// moduleRevisionAt returns the module's ref, or the last commit on its branch
+// as of the project's commit at the revision.
+func moduleRevisionAt(oauthToken string, ref *ProjectRef, revision string, module *Module) (string, error) {
+	if module.Ref != "" {
+		return module.Ref, nil
+	}
+	commit, err := thirdparty.GetCommitEvent(oauthToken, ref.Owner, ref.Repo, revision)
+	if err != nil {
+		return "", errors.Wrapf(err, "error getting commit %s", revision)
+	}
+	owner, repo := module.GetRepoOwnerAndName()
+	commits, _, err := thirdparty.GetGithubCommits(oauthToken,
+		moduleCommitsURL(owner, repo, module.Branch, commit.Commit.Committer.Date))
+	if err != nil {
+		return "", errors.Wrapf(err, "error getting commits of module '%s'", module.Name)
+	}
+	if len(commits) == 0 {
+		return "", errors.Errorf("module '%s' has no commits on branch '%s' as of revision %s",
+			module.Name, module.Branch, revision)
+	}
+	return commits[0].SHA, nil
+}
+
+// moduleCommitsURL returns the URL of the last commit on the branch as of
+// the given time.
+func moduleCommitsURL(owner, repo, branch string, until time.Time) string {
+	return fmt.Sprintf("%s/repos/%s/%s/commits?sha=%s&until=%s&per_page=1",
+		thirdparty.GithubAPIBase, owner, repo, url.QueryEscape(branch),
+		url.QueryEscape(until.UTC().Format(time.RFC3339)))
+}
+
+// PatchProjectFileFetcher returns a fetcher that reads files at the patch's
+// base revisions, applying the patch's changes to any file that it changes.
+func PatchProjectFileFetcher(p *patch.Patch, oauthToken string, ref *ProjectRef) ProjectFileFetcher {
+	base := GithubProjectFileFetcher(oauthToken, ref, p.Githash)
+	return func(module *Module, path string) ([]byte, error) {
+		moduleName := ""
+		if module != nil {
+			moduleName = module.Name
+		}
+		var patchPart *patch.ModulePatch
+		for i := range p.Patches {
+			if p.Patches[i].ModuleName == moduleName {
+				patchPart = &p.Patches[i]
+				break
+			}
+		}
+		if patchPart == nil {
+			return base(module, path)
+		}
+
+		var data []byte
+		var err error
+		if module != nil && patchPart.Githash != "" {
+			owner, repo := module.GetRepoOwnerAndName()
+			data, err = getGithubFile(oauthToken, owner, repo, path, patchPart.Githash)
+		} else {
+			data, err = base(module, path)
+		}
+
+		changed := false
+		for _, summary := range patchPart.PatchSet.Summary {
+			if summary.Name == path {
+				changed = true
+				break
+			}
+		}
+		if err != nil {
+			// the patch may add the file
+			if !(changed && thirdparty.IsFileNotFound(err)) {
+				return nil, errors.WithStack(err)
+			}
+		}
+		if !changed {
+			return data, nil
+		}
+		return patchFile(*patchPart, path, string(data))
+	}
+}
+
+func getGithubFile(oauthToken, owner, repo, path, revision string) ([]byte, error) {
+	githubFile, err := thirdparty.GetGithubFile(oauthToken, thirdparty.GetGithubFileURL(owner, repo, path, revision))
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(githubFile.Content)
+	if err != nil {
+		return nil, thirdparty.FileDecodeError{Message: err.Error()}
+	}
+	return data, nil
+}
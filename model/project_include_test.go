@@ -0,0 +1,167 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mapProjectFileFetcher(files map[string]string) ProjectFileFetcher {
+	return func(module *Module, path string) ([]byte, error) {
+		if module != nil {
+			path = module.Name + ":" + path
+		}
+		data, ok := files[path]
+		if !ok {
+			return nil, errors.Errorf("no file '%s'", path)
+		}
+		return []byte(data), nil
+	}
+}
+
+func TestLoadProjectWithIncludes(t *testing.T) {
+	assert := assert.New(t)
+
+	main := `
+stepback: true
+include:
+  - evergreen/tasks.yml
+  - filename: etc/variants.yml
+    module: enterprise
+modules:
+  - name: enterprise
+    repo: git@github.com:evergreen-ci/enterprise.git
+    branch: master
+functions:
+  compile:
+    command: shell.exec
+tasks:
+  - name: compile
+    commands:
+      - func: compile
+buildvariants:
+  - name: ubuntu
+    run_on: ubuntu1604-test
+    tasks:
+      - name: compile
+      - name: test
+`
+	files := map[string]string{
+		"evergreen/tasks.yml": `
+functions:
+  run tests:
+    command: shell.exec
+pre:
+  - command: shell.exec
+tasks:
+  - name: test
+    depends_on:
+      - name: compile
+    commands:
+      - func: run tests
+task_groups:
+  - name: test_group
+    tasks:
+      - test
+`,
+		"enterprise:etc/variants.yml": `
+buildvariants:
+  - name: enterprise-ubuntu
+    run_on: ubuntu1604-test
+    modules: [enterprise]
+    tasks:
+      - name: "*"
+`,
+	}
+
+	p := &Project{}
+	require.NoError(t, LoadProjectWithIncludes([]byte(main), "mci", mapProjectFileFetcher(files), p))
+	assert.True(p.Stepback)
+	assert.Len(p.Functions, 2)
+	assert.NotNil(p.Pre)
+	require.Len(t, p.Tasks, 2)
+	assert.Equal("compile", p.Tasks[0].Name)
+	assert.Equal("test", p.Tasks[1].Name)
+	require.Len(t, p.TaskGroups, 1)
+	require.Len(t, p.BuildVariants, 2)
+	assert.Equal("enterprise-ubuntu", p.BuildVariants[1].Name)
+	// the selector matches tasks and task groups from every file
+	assert.Len(p.BuildVariants[1].Tasks, 3)
+
+	// includes cannot be loaded without a way to read the files
+	assert.Error(LoadProjectInto([]byte(main), "mci", &Project{}))
+
+	// an error reading a file is returned on its own
+	err := LoadProjectWithIncludes([]byte(main), "mci", mapProjectFileFetcher(map[string]string{}), &Project{})
+	require.Error(t, err)
+	assert.Contains(err.Error(), "error reading included file 'evergreen/tasks.yml'")
+}
+
+func TestLoadProjectWithIncludesErrors(t *testing.T) {
+	for name, test := range map[string]struct {
+		main     string
+		included string
+		expected string
+	}{
+		"DuplicateTask": {
+			main:     "include: [a.yml]\ntasks:\n  - name: compile\n",
+			included: "tasks:\n  - name: compile\n",
+			expected: "task 'compile' in included file 'a.yml' is already defined",
+		},
+		"DuplicateFunction": {
+			main:     "include: [a.yml]\nfunctions:\n  f:\n    command: shell.exec\n",
+			included: "functions:\n  f:\n    command: shell.exec\n",
+			expected: "function 'f' in included file 'a.yml' is already defined",
+		},
+		"DuplicateVariant": {
+			main:     "include: [a.yml]\nbuildvariants:\n  - name: ubuntu\n",
+			included: "buildvariants:\n  - name: ubuntu\n",
+			expected: "buildvariant 'ubuntu' in included file 'a.yml' is already defined",
+		},
+		"DuplicateTaskGroup": {
+			main:     "include: [a.yml]\ntask_groups:\n  - name: tg\n",
+			included: "task_groups:\n  - name: tg\n",
+			expected: "task group 'tg' in included file 'a.yml' is already defined",
+		},
+		"DuplicatePre": {
+			main:     "include: [a.yml]\npre:\n  - command: shell.exec\n",
+			included: "pre:\n  - command: shell.exec\n",
+			expected: "block 'pre' in included file 'a.yml' is already defined",
+		},
+		"ProjectSettings": {
+			main:     "include: [a.yml]\n",
+			included: "stepback: true\nexec_timeout_secs: 10\n",
+			expected: "included file 'a.yml' sets exec_timeout_secs, stepback",
+		},
+		"NestedInclude": {
+			main:     "include: [a.yml]\n",
+			included: "include: [b.yml]\n",
+			expected: "included file 'a.yml' cannot include other files",
+		},
+		"UndefinedModule": {
+			main:     "include:\n  - filename: a.yml\n    module: enterprise\n",
+			expected: "refers to module 'enterprise', which is not defined",
+		},
+		"InvalidYAML": {
+			main:     "include: [a.yml]\n",
+			included: "tasks: {",
+			expected: "error parsing included file 'a.yml'",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fetch := mapProjectFileFetcher(map[string]string{"a.yml": test.included})
+			err := LoadProjectWithIncludes([]byte(test.main), "mci", fetch, &Project{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expected)
+		})
+	}
+}
+
+func TestModuleCommitsURL(t *testing.T) {
+	until := time.Date(2018, 3, 4, 5, 6, 7, 0, time.FixedZone("EST", -5*60*60))
+	assert.Equal(t, "https://api.github.com/repos/mongodb/enterprise/commits?sha=release%2F1.0&until=2018-03-04T10%3A06%3A07Z&per_page=1",
+		moduleCommitsURL("mongodb", "enterprise", "release/1.0", until))
+}
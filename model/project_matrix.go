@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/pkg/errors"
@@ -19,7 +21,8 @@ import (
 //  and moved into a separate "matrices" slice.
 //   2. A tag selector evaluator is constructed for evaluating axis selectors
 //   3. The matrix and axis definitions are passed to buildMatrixVariants, which
-//  creates all combinations of matrix cells and removes excluded ones.
+//  creates all combinations of matrix cells, removes excluded ones, and adds the
+//  cells of the include spec.
 //   4. During the generation of a single cell, we merge all axis values for the cell
 //  together to create a fully filled-in variant. Matrix rules concerning non-task settings
 //  are evaluated as well. Rules `add_tasks` and `remove_tasks` are stored in the variant
@@ -33,11 +36,13 @@ type matrix struct {
 	Id          string            `yaml:"matrix_name"`
 	Spec        matrixDefinition  `yaml:"matrix_spec"`
 	Exclude     matrixDefinitions `yaml:"exclude_spec"`
+	Include     matrixIncludes    `yaml:"include_spec"`
 	DisplayName string            `yaml:"display_name"`
 	Tags        parserStringSlice `yaml:"tags"`
 	Modules     parserStringSlice `yaml:"modules"`
 	BatchTime   *int              `yaml:"batchtime"`
 	Stepback    *bool             `yaml:"stepback"`
+	AutoRetry   *AutoRetry        `yaml:"auto_retry"`
 	RunOn       parserStringSlice `yaml:"run_on"`
 	Tasks       parserBVTaskUnits     `yaml:"tasks"`
 	Rules       []matrixRule      `yaml:"rules"`
@@ -213,6 +218,17 @@ func (mds matrixDefinitions) contain(v matrixValue) bool {
 	return false
 }
 
+// indexContaining returns the index of the first definition that contains
+// the given value, or -1 if none do.
+func (mds matrixDefinitions) indexContaining(v matrixValue) int {
+	for i, m := range mds {
+		if m.contains(v) {
+			return i
+		}
+	}
+	return -1
+}
+
 // evaluatedCopies is like evaluatedCopy, but for multiple definitions.
 func (mds matrixDefinitions) evaluatedCopies(ase *axisSelectorEvaluator) (matrixDefinitions, []error) {
 	var out matrixDefinitions
@@ -225,6 +241,35 @@ func (mds matrixDefinitions) evaluatedCopies(ase *axisSelectorEvaluator) (matrix
 	return out, errs
 }
 
+// matrixInclude adds the cells of a definition to a matrix whether or not
+// they are in the product of its spec, for sparse cells that would otherwise
+// need hand-written variants. The definition's axes are written inline, next
+// to the optional tasks that its cells run instead of the matrix's tasks.
+type matrixInclude struct {
+	Spec  matrixDefinition  `yaml:",inline"`
+	Tasks parserBVTaskUnits `yaml:"tasks"`
+}
+
+// matrixIncludes is a helper type for parsing either a single include or a
+// slice of them from YAML.
+type matrixIncludes []matrixInclude
+
+// UnmarshalYAML allows the YAML parser to read both a single include or
+// an array of them into a slice.
+func (mis *matrixIncludes) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single matrixInclude
+	if err := unmarshal(&single); err == nil {
+		*mis = matrixIncludes{single}
+		return nil
+	}
+	var slice []matrixInclude
+	if err := unmarshal(&slice); err != nil {
+		return err
+	}
+	*mis = slice
+	return nil
+}
+
 // evaluateAxisTags returns an evaluated list of axis value ids with tag selectors evaluated.
 func evaluateAxisTags(ase *axisSelectorEvaluator, axis string, selectors []string) ([]string, []error) {
 	var errs []error
@@ -246,14 +291,82 @@ func evaluateAxisTags(ase *axisSelectorEvaluator, axis string, selectors []strin
 	return out, errs
 }
 
+// MatrixCellExplanation describes how a cell of a matrix became a variant,
+// or why it did not.
+type MatrixCellExplanation struct {
+	Matrix string
+	// Cell is the cell's axis values, in JSON.
+	Cell string
+	// Variant is the name of the variant built from the cell, if it was not
+	// excluded.
+	Variant string
+	// Source is the spec entry that produced the cell, either "matrix_spec"
+	// or "include_spec[i]".
+	Source     string
+	ExcludedBy string
+	// Rules describes the matrix rules that modified the variant.
+	Rules []string
+}
+
+// String returns the explanation as an indented block of text.
+func (e MatrixCellExplanation) String() string {
+	buf := bytes.Buffer{}
+	if e.ExcludedBy != "" {
+		fmt.Fprintf(&buf, "%s (not built)\n", e.Cell)
+		fmt.Fprintf(&buf, "    from %s, excluded by %s\n", e.Source, e.ExcludedBy)
+		return buf.String()
+	}
+	fmt.Fprintf(&buf, "%s %s\n", e.Variant, e.Cell)
+	fmt.Fprintf(&buf, "    from %s\n", e.Source)
+	for _, rule := range e.Rules {
+		fmt.Fprintf(&buf, "    modified by %s\n", rule)
+	}
+	return buf.String()
+}
+
+// matrixCellExplanations sorts explanations by cell, since the order in
+// which a matrix's cells are generated is not stable.
+type matrixCellExplanations []MatrixCellExplanation
+
+func (e matrixCellExplanations) Len() int           { return len(e) }
+func (e matrixCellExplanations) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e matrixCellExplanations) Less(i, j int) bool { return e[i].Cell < e[j].Cell }
+
+// ExplainProjectMatrices returns how each cell of each matrix in the project
+// YAML became a variant, or why it did not, grouped by matrix.
+func ExplainProjectMatrices(yml []byte, fetch ProjectFileFetcher) ([]MatrixCellExplanation, error) {
+	pp, errs := createIntermediateProject(yml)
+	if len(errs) > 0 {
+		return nil, projectErrors(errs)
+	}
+	if errs = mergeIncludes(pp, fetch); len(errs) > 0 {
+		return nil, projectErrors(errs)
+	}
+	_, matrices := sieveMatrixVariants(pp.BuildVariants)
+	_, explanations, errs := explainMatrixVariants(pp.Axes, NewAxisSelectorEvaluator(pp.Axes), matrices)
+	if len(errs) > 0 {
+		return nil, projectErrors(errs)
+	}
+	return explanations, nil
+}
+
 // buildMatrixVariants takes in a list of axis definitions, an axisSelectorEvaluator, and a slice of
 // matrix definitions. It returns a slice of parserBuildVariants constructed according to
 // our matrix specification.
 func buildMatrixVariants(axes []matrixAxis, ase *axisSelectorEvaluator, matrices []matrix) (
 	[]parserBV, []error) {
+	variants, _, errs := explainMatrixVariants(axes, ase, matrices)
+	return variants, errs
+}
+
+// explainMatrixVariants is buildMatrixVariants, but it also explains where
+// each cell of the matrices came from and what happened to it.
+func explainMatrixVariants(axes []matrixAxis, ase *axisSelectorEvaluator, matrices []matrix) (
+	[]parserBV, []MatrixCellExplanation, []error) {
 	var errs []error
 	// for each matrix, build out its declarations
 	matrixVariants := []parserBV{}
+	explanations := []MatrixCellExplanation{}
 	for i, m := range matrices {
 		// for each axis value, iterate through possible inputs
 		evaluatedSpec, evalErrs := m.Spec.evaluatedCopy(ase)
@@ -268,25 +381,71 @@ func buildMatrixVariants(axes []matrixAxis, ase *axisSelectorEvaluator, matrices
 		}
 		unpruned := evaluatedSpec.allCells()
 		pruned := []parserBV{}
+		matrixExplanations := []MatrixCellExplanation{}
+		inMatrix := map[string]bool{}
 		for _, cell := range unpruned {
+			explanation := MatrixCellExplanation{Matrix: m.Id, Cell: cell.String(), Source: "matrix_spec"}
 			// create the variant if it isn't excluded
-			if !evaluatedExcludes.contain(cell) {
-				v, err := buildMatrixVariant(axes, cell, &matrices[i], ase)
-				if err != nil {
-					errs = append(errs, errors.Wrapf(err, "%v: error building matrix cell %v",
-						m.Id, cell))
-					continue
-				}
-				pruned = append(pruned, *v)
+			if excludedBy := evaluatedExcludes.indexContaining(cell); excludedBy >= 0 {
+				explanation.ExcludedBy = fmt.Sprintf("exclude_spec[%d]", excludedBy)
+				matrixExplanations = append(matrixExplanations, explanation)
+				continue
 			}
+			v, err := buildMatrixVariant(axes, cell, &matrices[i], ase)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "%v: error building matrix cell %v",
+					m.Id, cell))
+				continue
+			}
+			pruned = append(pruned, *v)
+			inMatrix[cell.String()] = true
+			explanation.Variant = v.Name
+			explanation.Rules = v.matrixRulesApplied
+			matrixExplanations = append(matrixExplanations, explanation)
 		}
 		// safety check to make sure the exclude field is actually working
 		if len(m.Exclude) > 0 && len(unpruned) == len(pruned) {
 			errs = append(errs, errors.Errorf("%v: exclude field did not exclude anything", m.Id))
 		}
+		// included cells are added as is, since they are explicit
+		for j, include := range m.Include {
+			evaluatedInclude, evalErrs := include.Spec.evaluatedCopy(ase)
+			if len(evalErrs) > 0 {
+				errs = append(errs, evalErrs...)
+				continue
+			}
+			includeMatrix := matrices[i]
+			if len(include.Tasks) > 0 {
+				includeMatrix.Tasks = include.Tasks
+			}
+			for _, cell := range evaluatedInclude.allCells() {
+				if inMatrix[cell.String()] {
+					errs = append(errs, errors.Errorf("%v: include_spec[%d] cell %v is already in the matrix",
+						m.Id, j, cell))
+					continue
+				}
+				v, err := buildMatrixVariant(axes, cell, &includeMatrix, ase)
+				if err != nil {
+					errs = append(errs, errors.Wrapf(err, "%v: error building include_spec[%d] cell %v",
+						m.Id, j, cell))
+					continue
+				}
+				pruned = append(pruned, *v)
+				inMatrix[cell.String()] = true
+				matrixExplanations = append(matrixExplanations, MatrixCellExplanation{
+					Matrix:  m.Id,
+					Cell:    cell.String(),
+					Source:  fmt.Sprintf("include_spec[%d]", j),
+					Variant: v.Name,
+					Rules:   v.matrixRulesApplied,
+				})
+			}
+		}
 		matrixVariants = append(matrixVariants, pruned...)
+		sort.Stable(matrixCellExplanations(matrixExplanations))
+		explanations = append(explanations, matrixExplanations...)
 	}
-	return matrixVariants, errs
+	return matrixVariants, explanations, errs
 }
 
 // buildMatrixVariant does the heavy lifting of building a matrix variant based on axis information.
@@ -298,6 +457,7 @@ func buildMatrixVariant(axes []matrixAxis, mv matrixValue, m *matrix, ase *axisS
 		matrixVal:  mv,
 		matrixId:   m.Id,
 		Stepback:   m.Stepback,
+		AutoRetry:  m.AutoRetry,
 		BatchTime:  m.BatchTime,
 		Modules:    m.Modules,
 		RunOn:      m.RunOn,
@@ -377,6 +537,8 @@ func buildMatrixVariant(axes []matrixAxis, mv matrixValue, m *matrix, ase *axisS
 			return nil, errors.Errorf("evaluating rules for matrix %v: %v", m.Id, errs)
 		}
 		if matchers.contain(mv) {
+			v.matrixRulesApplied = append(v.matrixRulesApplied,
+				fmt.Sprintf("rules[%d]: %s", i, r.Then.describe()))
 			if r.Then.Set != nil {
 				if err := v.mergeAxisValue(*r.Then.Set); err != nil {
 					return nil, errors.Wrapf(err, "evaluating %s rule %d", m.Id, i)
@@ -406,6 +568,47 @@ type ruleAction struct {
 	AddTasks    parserBVTaskUnits     `yaml:"add_tasks"`
 }
 
+// describe summarizes what the action changes about a variant.
+func (ra ruleAction) describe() string {
+	var changes []string
+	if av := ra.Set; av != nil {
+		var fields []string
+		if len(av.Variables) > 0 {
+			fields = append(fields, "variables")
+		}
+		if len(av.RunOn) > 0 {
+			fields = append(fields, "run_on")
+		}
+		if len(av.Tags) > 0 {
+			fields = append(fields, "tags")
+		}
+		if len(av.Modules) > 0 {
+			fields = append(fields, "modules")
+		}
+		if av.BatchTime != nil {
+			fields = append(fields, "batchtime")
+		}
+		if av.Stepback != nil {
+			fields = append(fields, "stepback")
+		}
+		changes = append(changes, "set "+strings.Join(fields, ", "))
+	}
+	if len(ra.RemoveTasks) > 0 {
+		changes = append(changes, "remove_tasks "+strings.Join(ra.RemoveTasks, ", "))
+	}
+	if len(ra.AddTasks) > 0 {
+		var names []string
+		for _, t := range ra.AddTasks {
+			names = append(names, t.Name)
+		}
+		changes = append(changes, "add_tasks "+strings.Join(names, ", "))
+	}
+	if len(changes) == 0 {
+		return "no changes"
+	}
+	return strings.Join(changes, "; ")
+}
+
 // mergeAxisValue overwrites a parserBV's fields based on settings
 // in the axis value. Matrix expansions are evaluated as this process occurs.
 // Returns any errors evaluating expansions.
@@ -507,6 +710,14 @@ func expandParserBVTask(pbvt parserBVTaskUnit, exp util.Expansions) (parserBVTas
 		if err != nil {
 			return parserBVTaskUnit{}, errors.Wrapf(err, "expanding depends_on[%d/%d]", i, len(pbvt.DependsOn))
 		}
+		newDep.Project, err = exp.ExpandString(d.Project)
+		if err != nil {
+			return parserBVTaskUnit{}, errors.Wrapf(err, "expanding depends_on[%d/%d].project", i, len(pbvt.DependsOn))
+		}
+		newDep.Revision, err = exp.ExpandString(d.Revision)
+		if err != nil {
+			return parserBVTaskUnit{}, errors.Wrapf(err, "expanding depends_on[%d/%d].revision", i, len(pbvt.DependsOn))
+		}
 		newDeps = append(newDeps, newDep)
 	}
 	newTask.DependsOn = newDeps
@@ -2,10 +2,13 @@ package model
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/evergreen-ci/evergreen/util"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMatrixIntermediateParsing(t *testing.T) {
@@ -695,3 +698,105 @@ func TestRulesEvaluation(t *testing.T) {
 		})
 	})
 }
+
+const includeSpecProject = `
+axes:
+- id: os
+  values:
+  - id: linux
+  - id: windows
+  - id: solaris
+- id: compiler
+  values:
+  - id: gcc
+  - id: clang
+tasks:
+- name: compile
+- name: test
+- name: exotic
+buildvariants:
+- matrix_name: m
+  matrix_spec: {os: [linux, windows], compiler: "*"}
+  exclude_spec: {os: windows, compiler: clang}
+  include_spec:
+  - os: solaris
+    compiler: gcc
+    tasks: [exotic]
+  - {os: windows, compiler: clang}
+  display_name: ${os} ${compiler}
+  tasks: [compile, test]
+  rules:
+  - if: {os: solaris, compiler: "*"}
+    then:
+      set:
+        run_on: solaris-distro
+  - if: {os: "*", compiler: clang}
+    then:
+      remove_tasks: test
+`
+
+func TestMatrixIncludeSpec(t *testing.T) {
+	assert := assert.New(t)
+	p, errs := projectFromYAML([]byte(includeSpecProject))
+	require.Len(t, errs, 0)
+	assert.Len(p.BuildVariants, 5)
+
+	solaris := p.FindBuildVariant("m__os~solaris_compiler~gcc")
+	require.NotNil(t, solaris)
+	assert.Equal("solaris gcc", solaris.DisplayName)
+	assert.Equal([]string{"solaris-distro"}, solaris.RunOn)
+	require.Len(t, solaris.Tasks, 1)
+	assert.Equal("exotic", solaris.Tasks[0].Name)
+
+	// an include can add back a cell that the exclude spec removed
+	windowsClang := p.FindBuildVariant("m__os~windows_compiler~clang")
+	require.NotNil(t, windowsClang)
+	require.Len(t, windowsClang.Tasks, 1)
+	assert.Equal("compile", windowsClang.Tasks[0].Name)
+
+	duplicate := strings.Replace(includeSpecProject, "  - {os: windows, compiler: clang}\n",
+		"  - {os: windows, compiler: clang}\n  - {os: linux, compiler: gcc}\n", 1)
+	_, errs = projectFromYAML([]byte(duplicate))
+	require.Len(t, errs, 1)
+	assert.Contains(errs[0].Error(), "include_spec[2] cell")
+	assert.Contains(errs[0].Error(), "is already in the matrix")
+}
+
+func TestExplainProjectMatrices(t *testing.T) {
+	assert := assert.New(t)
+	explanations, err := ExplainProjectMatrices([]byte(includeSpecProject), nil)
+	require.NoError(t, err)
+	require.Len(t, explanations, 6)
+
+	byCell := map[string]MatrixCellExplanation{}
+	for _, e := range explanations {
+		assert.Equal("m", e.Matrix)
+		byCell[e.Cell] = e
+	}
+	linux := byCell[`{"compiler":"gcc","os":"linux"}`]
+	assert.Equal("m__os~linux_compiler~gcc", linux.Variant)
+	assert.Equal("matrix_spec", linux.Source)
+	assert.Empty(linux.Rules)
+
+	excluded := byCell[`{"compiler":"clang","os":"windows"}`]
+	assert.Equal("include_spec[1]", excluded.Source)
+	assert.Equal([]string{"rules[1]: remove_tasks test"}, excluded.Rules)
+	assert.Equal("m__os~windows_compiler~clang", excluded.Variant)
+
+	solaris := byCell[`{"compiler":"gcc","os":"solaris"}`]
+	assert.Equal("include_spec[0]", solaris.Source)
+	assert.Equal([]string{"rules[0]: set run_on"}, solaris.Rules)
+	assert.Contains(solaris.String(), "modified by rules[0]: set run_on")
+
+	// the spec's windows/clang cell is excluded before the include adds it back
+	var excludedCells int
+	for _, e := range explanations {
+		if e.ExcludedBy != "" {
+			excludedCells++
+			assert.Equal("exclude_spec[0]", e.ExcludedBy)
+			assert.Equal("matrix_spec", e.Source)
+			assert.Contains(e.String(), "excluded by exclude_spec[0]")
+		}
+	}
+	assert.Equal(1, excludedCells)
+}
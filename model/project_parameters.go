@@ -0,0 +1,116 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/pkg/errors"
+)
+
+// Parameter types.
+const (
+	ParameterTypeString = "string"
+	ParameterTypeInt    = "int"
+	ParameterTypeFloat  = "float"
+	ParameterTypeBool   = "bool"
+)
+
+// ValidParameterTypes are the types that a parameter may be declared with.
+var ValidParameterTypes = []string{
+	ParameterTypeString,
+	ParameterTypeInt,
+	ParameterTypeFloat,
+	ParameterTypeBool,
+}
+
+var parameterNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ProjectParameter is a value declared in the project's parameters section,
+// which is available to tasks as an expansion. Patches may override its
+// default.
+type ProjectParameter struct {
+	Name        string `yaml:"name,omitempty" bson:"name"`
+	Type        string `yaml:"type,omitempty" bson:"type"`
+	Default     string `yaml:"default,omitempty" bson:"default"`
+	Description string `yaml:"description,omitempty" bson:"description"`
+}
+
+// GetType returns the parameter's type, which is a string if none is
+// declared.
+func (pp *ProjectParameter) GetType() string {
+	if pp.Type == "" {
+		return ParameterTypeString
+	}
+	return pp.Type
+}
+
+// ValidateValue returns an error if the value cannot be parsed as the
+// parameter's type.
+func (pp *ProjectParameter) ValidateValue(value string) error {
+	var err error
+	switch pp.GetType() {
+	case ParameterTypeString:
+	case ParameterTypeInt:
+		_, err = strconv.Atoi(value)
+	case ParameterTypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case ParameterTypeBool:
+		_, err = strconv.ParseBool(value)
+	default:
+		return errors.Errorf("parameter '%s' has invalid type '%s'", pp.Name, pp.Type)
+	}
+	if err != nil {
+		return errors.Errorf("value '%s' for parameter '%s' is not a valid %s", value, pp.Name, pp.GetType())
+	}
+	return nil
+}
+
+// ValidParameterName returns true if the name can be used as an expansion.
+func ValidParameterName(name string) bool {
+	return parameterNameRegex.MatchString(name)
+}
+
+// FindParameter returns the parameter with the given name, or nil if the
+// project does not declare it.
+func (p *Project) FindParameter(name string) *ProjectParameter {
+	for i := range p.Parameters {
+		if p.Parameters[i].Name == name {
+			return &p.Parameters[i]
+		}
+	}
+	return nil
+}
+
+// ResolveParameters returns the value of each of the project's parameters,
+// sorted by name, taking the value from the overrides if one is given and
+// the default otherwise. Overrides must be declared by the project and valid
+// for the parameter's type.
+func (p *Project) ResolveParameters(overrides []patch.Parameter) ([]patch.Parameter, error) {
+	values := map[string]string{}
+	for _, param := range p.Parameters {
+		values[param.Name] = param.Default
+	}
+	for _, override := range overrides {
+		param := p.FindParameter(override.Key)
+		if param == nil {
+			return nil, errors.Errorf("project does not declare parameter '%s'", override.Key)
+		}
+		if err := param.ValidateValue(override.Value); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		values[override.Key] = override.Value
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	resolved := make([]patch.Parameter, 0, len(names))
+	for _, name := range names {
+		resolved = append(resolved, patch.Parameter{Key: name, Value: values[name]})
+	}
+	return resolved, nil
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveParameters(t *testing.T) {
+	assert := assert.New(t)
+	yml := `
+parameters:
+- name: suite
+  description: the test suite to run
+  default: core
+- name: jobs
+  type: int
+  default: 4
+- name: verbose
+  type: bool
+  default: false
+`
+	project := &Project{}
+	require.NoError(t, LoadProjectInto([]byte(yml), "params", project))
+	require.Len(t, project.Parameters, 3)
+	assert.Equal("4", project.FindParameter("jobs").Default)
+	assert.Equal(ParameterTypeString, project.FindParameter("suite").GetType())
+	assert.Nil(project.FindParameter("nope"))
+
+	resolved, err := project.ResolveParameters(nil)
+	assert.NoError(err)
+	assert.Equal([]patch.Parameter{
+		{Key: "jobs", Value: "4"},
+		{Key: "suite", Value: "core"},
+		{Key: "verbose", Value: "false"},
+	}, resolved)
+
+	resolved, err = project.ResolveParameters([]patch.Parameter{{Key: "jobs", Value: "16"}, {Key: "verbose", Value: "true"}})
+	assert.NoError(err)
+	assert.Equal([]patch.Parameter{
+		{Key: "jobs", Value: "16"},
+		{Key: "suite", Value: "core"},
+		{Key: "verbose", Value: "true"},
+	}, resolved)
+
+	_, err = project.ResolveParameters([]patch.Parameter{{Key: "jobs", Value: "many"}})
+	assert.Error(err)
+	_, err = project.ResolveParameters([]patch.Parameter{{Key: "undeclared", Value: "1"}})
+	assert.Error(err)
+}
+
+func TestValidateParameterValue(t *testing.T) {
+	assert := assert.New(t)
+	for paramType, values := range map[string]map[string]bool{
+		ParameterTypeString: {"": true, "anything": true},
+		ParameterTypeInt:    {"42": true, "-1": true, "4.2": false, "": false},
+		ParameterTypeFloat:  {"4.2": true, "1e3": true, "x": false},
+		ParameterTypeBool:   {"true": true, "0": true, "yes": false},
+	} {
+		param := ProjectParameter{Name: "p", Type: paramType}
+		for value, valid := range values {
+			if valid {
+				assert.NoError(param.ValidateValue(value), "%s %s", paramType, value)
+			} else {
+				assert.Error(param.ValidateValue(value), "%s %s", paramType, value)
+			}
+		}
+	}
+	assert.Error((&ProjectParameter{Name: "p", Type: "enum"}).ValidateValue("a"))
+
+	assert.True(ValidParameterName("test_suite2"))
+	assert.False(ValidParameterName("2suite"))
+	assert.False(ValidParameterName("test.suite"))
+}
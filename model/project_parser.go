@@ -59,6 +59,9 @@ type parserProject struct {
 	TaskGroups      []parserTaskGroup          `yaml:"task_groups"`
 	Tasks           []parserTask               `yaml:"tasks"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs"`
+	Include         []parserInclude            `yaml:"include"`
+	Parameters      []ProjectParameter         `yaml:"parameters"`
+	LintIgnore      parserStringSlice          `yaml:"lint_ignore"`
 
 	// Matrix code
 	Axes []matrixAxis `yaml:"axes"`
@@ -95,8 +98,11 @@ type parserTask struct {
 	Requires        taskSelectors       `yaml:"requires"`
 	Commands        []PluginCommandConf `yaml:"commands"`
 	Tags            parserStringSlice   `yaml:"tags"`
+	Paths           parserStringSlice   `yaml:"paths"`
+	IgnorePaths     parserStringSlice   `yaml:"ignore_paths"`
 	Patchable       *bool               `yaml:"patchable"`
 	Stepback        *bool               `yaml:"stepback"`
+	AutoRetry       *AutoRetry          `yaml:"auto_retry"`
 }
 
 type displayTask struct {
@@ -113,6 +119,8 @@ type parserDependency struct {
 	taskSelector
 	Status        string `yaml:"status"`
 	PatchOptional bool   `yaml:"patch_optional"`
+	Project       string `yaml:"project"`
+	Revision      string `yaml:"revision"`
 }
 
 // parserDependencies is a type defined for unmarshalling both a single
@@ -145,11 +153,15 @@ func (pd *parserDependency) UnmarshalYAML(unmarshal func(interface{}) error) err
 	otherFields := struct {
 		Status        string `yaml:"status"`
 		PatchOptional bool   `yaml:"patch_optional"`
+		Project       string `yaml:"project"`
+		Revision      string `yaml:"revision"`
 	}{}
 	// ignore any errors here; if we're using a single-string selector, this is expected to fail
 	grip.Debug(unmarshal(&otherFields))
 	pd.Status = otherFields.Status
 	pd.PatchOptional = otherFields.PatchOptional
+	pd.Project = otherFields.Project
+	pd.Revision = otherFields.Revision
 	return nil
 }
 
@@ -246,8 +258,11 @@ type parserBV struct {
 	Modules      parserStringSlice `yaml:"modules"`
 	Disabled     bool              `yaml:"disabled"`
 	Push         bool              `yaml:"push"`
+	Paths        parserStringSlice `yaml:"paths"`
+	IgnorePaths  parserStringSlice `yaml:"ignore_paths"`
 	BatchTime    *int              `yaml:"batchtime"`
 	Stepback     *bool             `yaml:"stepback"`
+	AutoRetry    *AutoRetry        `yaml:"auto_retry"`
 	RunOn        parserStringSlice `yaml:"run_on"`
 	Tasks        parserBVTaskUnits `yaml:"tasks"`
 	DisplayTasks []displayTask     `yaml:"display_tasks"`
@@ -257,7 +272,8 @@ type parserBV struct {
 	matrixVal matrixValue
 	matrix    *matrix
 
-	matrixRules []ruleAction
+	matrixRules        []ruleAction
+	matrixRulesApplied []string
 }
 
 // helper methods for variant tag evaluations
@@ -301,6 +317,7 @@ type parserBVTaskUnit struct {
 	Requires        taskSelectors      `yaml:"requires"`
 	ExecTimeoutSecs int                `yaml:"exec_timeout_secs"`
 	Stepback        *bool              `yaml:"stepback"`
+	AutoRetry       *AutoRetry         `yaml:"auto_retry"`
 	Distros         parserStringSlice  `yaml:"distros"`
 	RunOn           parserStringSlice  `yaml:"run_on"` // Alias for "Distros" TODO: deprecate Distros
 }
@@ -380,33 +397,51 @@ func (pss *parserStringSlice) UnmarshalYAML(unmarshal func(interface{}) error) e
 // LoadProjectInto loads the raw data from the config file into project
 // and sets the project's identifier field to identifier. Tags are evaluateed.
 func LoadProjectInto(data []byte, identifier string, project *Project) error {
-	p, errs := projectFromYAML(data) // ignore warnings, for now (TODO)
+	return loadProject(data, identifier, nil, project)
+}
+
+func loadProject(data []byte, identifier string, fetch ProjectFileFetcher, project *Project) error {
+	p, errs := projectFromYAMLWithIncludes(data, fetch) // ignore warnings, for now (TODO)
 	if len(errs) > 0 {
-		// create a human-readable error list
-		buf := bytes.Buffer{}
-		for _, e := range errs {
-			if len(errs) > 1 {
-				buf.WriteString("\n\t") //only newline if we have multiple errs
-			}
-			buf.WriteString(e.Error())
-		}
-		if len(errs) > 1 {
-			return errors.Errorf("project errors: %v", buf.String())
-		}
-		return errors.Errorf("project error: %v", buf.String())
+		return projectErrors(errs)
 	}
 	*project = *p
 	project.Identifier = identifier
 	return nil
 }
 
+// projectErrors combines errors from loading a project into a human-readable
+// error list.
+func projectErrors(errs []error) error {
+	buf := bytes.Buffer{}
+	for _, e := range errs {
+		if len(errs) > 1 {
+			buf.WriteString("\n\t") //only newline if we have multiple errs
+		}
+		buf.WriteString(e.Error())
+	}
+	if len(errs) > 1 {
+		return errors.Errorf("project errors: %v", buf.String())
+	}
+	return errors.Errorf("project error: %v", buf.String())
+}
+
 // projectFromYAML reads and evaluates project YAML, returning a project and warnings and
 // errors encountered during parsing or evaluation.
 func projectFromYAML(yml []byte) (*Project, []error) {
+	return projectFromYAMLWithIncludes(yml, nil)
+}
+
+// projectFromYAMLWithIncludes is projectFromYAML for project YAML that may
+// include other files, which are read with fetch.
+func projectFromYAMLWithIncludes(yml []byte, fetch ProjectFileFetcher) (*Project, []error) {
 	pp, errs := createIntermediateProject(yml)
 	if len(errs) > 0 {
 		return nil, errs
 	}
+	if errs = mergeIncludes(pp, fetch); len(errs) > 0 {
+		return nil, errs
+	}
 	p, errs := translateProject(pp)
 	return p, errs
 }
@@ -449,6 +484,8 @@ func translateProject(pp *parserProject) (*Project, []error) {
 		Modules:         pp.Modules,
 		Functions:       pp.Functions,
 		ExecTimeoutSecs: pp.ExecTimeoutSecs,
+		Parameters:      pp.Parameters,
+		LintIgnore:      pp.LintIgnore,
 	}
 	tse := NewParserTaskSelectorEvaluator(pp.Tasks)
 	tgse := newTaskGroupSelectorEvaluator(pp.TaskGroups)
@@ -493,8 +530,11 @@ func evaluateTaskUnits(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluator, v
 			ExecTimeoutSecs: pt.ExecTimeoutSecs,
 			Commands:        pt.Commands,
 			Tags:            pt.Tags,
+			Paths:           pt.Paths,
+			IgnorePaths:     pt.IgnorePaths,
 			Patchable:       pt.Patchable,
 			Stepback:        pt.Stepback,
+			AutoRetry:       pt.AutoRetry,
 		}
 		t.DependsOn, errs = evaluateDependsOn(tse.tagEval, tgse, vse, pt.DependsOn)
 		evalErrs = append(evalErrs, errs...)
@@ -553,8 +593,11 @@ func evaluateBuildVariants(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluato
 			Modules:     pbv.Modules,
 			Disabled:    pbv.Disabled,
 			Push:        pbv.Push,
+			Paths:       pbv.Paths,
+			IgnorePaths: pbv.IgnorePaths,
 			BatchTime:   pbv.BatchTime,
 			Stepback:    pbv.Stepback,
+			AutoRetry:   pbv.AutoRetry,
 			RunOn:       pbv.RunOn,
 			Tags:        pbv.Tags,
 		}
@@ -697,6 +740,7 @@ func evaluateBVTasks(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluator, vse
 				Priority:        pt.Priority,
 				ExecTimeoutSecs: pt.ExecTimeoutSecs,
 				Stepback:        pt.Stepback,
+				AutoRetry:       pt.AutoRetry,
 				Distros:         pt.Distros,
 			}
 			t.DependsOn, errs = evaluateDependsOn(tse.tagEval, tgse, vse, pt.DependsOn)
@@ -730,6 +774,18 @@ func evaluateDependsOn(tse *tagSelectorEvaluator, tgse *tagSelectorEvaluator, vs
 	newDeps := []TaskUnitDependency{}
 	newDepsByNameAndVariant := map[TVPair]TaskUnitDependency{}
 	for _, d := range deps {
+		if d.Project != "" {
+			// selectors cannot be evaluated against another project's
+			// tasks, so cross-project dependencies name a single task
+			newDep, err := evaluateCrossProjectDependency(d)
+			if err != nil {
+				evalErrs = append(evalErrs, err)
+				continue
+			}
+			newDeps = append(newDeps, newDep)
+			continue
+		}
+
 		var names []string
 
 		if d.Name == AllDependencies {
@@ -790,6 +846,27 @@ func evaluateDependsOn(tse *tagSelectorEvaluator, tgse *tagSelectorEvaluator, vs
 	return newDeps, evalErrs
 }
 
+// evaluateCrossProjectDependency checks that a dependency on another
+// project's task names a single task and variant.
+func evaluateCrossProjectDependency(d parserDependency) (TaskUnitDependency, error) {
+	variant := ""
+	if d.Variant != nil {
+		variant = d.Variant.stringSelector
+	}
+	if d.Name == "" || d.Name == AllDependencies || variant == "" || variant == AllVariants {
+		return TaskUnitDependency{}, errors.Errorf(
+			"dependency on project '%s' must name a single task and variant", d.Project)
+	}
+	return TaskUnitDependency{
+		Name:          d.Name,
+		Variant:       variant,
+		Status:        d.Status,
+		PatchOptional: d.PatchOptional,
+		Project:       d.Project,
+		Revision:      d.Revision,
+	}, nil
+}
+
 // evaluateRequires expands any selectors in a requirement definition.
 func evaluateRequires(tse *tagSelectorEvaluator, tgse *tagSelectorEvaluator, vse *variantSelectorEvaluator,
 	reqs []taskSelector) ([]TaskUnitRequirement, []error) {
@@ -963,3 +963,78 @@ buildvariants:
 		assert.Equal(0, v%2)
 	}
 }
+
+func TestCrossProjectDependencies(t *testing.T) {
+	assert := assert.New(t)
+	yml := `
+tasks:
+- name: integration
+  depends_on:
+  - name: compile
+  - name: package
+    variant: linux
+    project: server
+    status: "*"
+  - name: package
+    variant: windows
+    project: server
+    revision: abc123
+- name: compile
+buildvariants:
+- name: linux
+  tasks:
+  - name: compile
+  - name: integration
+`
+	p := &Project{}
+	assert.NoError(LoadProjectInto([]byte(yml), "client", p))
+	deps := p.Tasks[0].DependsOn
+	assert.Len(deps, 3)
+	assert.False(deps[0].IsCrossProject())
+	assert.Equal(TaskUnitDependency{Name: "package", Variant: "linux", Project: "server", Status: "*"}, deps[1])
+	assert.Equal(TaskUnitDependency{Name: "package", Variant: "windows", Project: "server", Revision: "abc123"}, deps[2])
+
+	for _, dep := range []string{
+		"{name: package, project: server}",
+		"{name: '*', variant: linux, project: server}",
+		"{name: package, variant: '*', project: server}",
+	} {
+		yml := "tasks:\n- name: integration\n  depends_on:\n  - " + dep + "\n"
+		assert.Error(LoadProjectInto([]byte(yml), "client", &Project{}), dep)
+	}
+}
+
+func TestAutoRetry(t *testing.T) {
+	assert := assert.New(t)
+	yml := `
+tasks:
+- name: compile
+  auto_retry:
+    max_attempts: 2
+    on: [system-failed]
+- name: test
+buildvariants:
+- name: linux
+  auto_retry:
+    max_attempts: 3
+    only_requesters: [patch_request]
+  tasks:
+  - name: compile
+  - name: test
+    auto_retry:
+      max_attempts: 4
+      on: [timeout, test-failure]
+`
+	p := &Project{}
+	assert.NoError(LoadProjectInto([]byte(yml), "", p))
+	assert.Equal(&AutoRetry{MaxAttempts: 2, On: []string{AutoRetryOnSystemFailed}}, p.Tasks[0].AutoRetry)
+	assert.Nil(p.Tasks[1].AutoRetry)
+	assert.Equal(&AutoRetry{MaxAttempts: 3, OnlyRequesters: []string{"patch_request"}},
+		p.BuildVariants[0].AutoRetry)
+	assert.Equal(&AutoRetry{MaxAttempts: 4, On: []string{AutoRetryOnTimeout, AutoRetryOnTestFailure}},
+		p.BuildVariants[0].Tasks[1].AutoRetry)
+
+	assert.Equal(p.Tasks[0].AutoRetry, p.FindAutoRetry("compile", "linux"))
+	assert.Equal(p.BuildVariants[0].Tasks[1].AutoRetry, p.FindAutoRetry("test", "linux"))
+	assert.Nil(p.FindAutoRetry("test", "windows"))
+}
@@ -0,0 +1,190 @@
+package model
+
+import (
+	"path"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	ignore "github.com/sabhiram/go-git-ignore"
+)
+
+// Build variants and tasks may list the files they watch with paths and
+// ignore_paths, which take gitignore-style patterns:
+//
+//   buildvariants:
+//   - name: java
+//     paths: ["java/", "build.gradle"]
+//     ignore_paths: ["*.md"]
+//
+// A changed file is watched if it matches paths, or paths is empty, and does
+// not match ignore_paths. A task runs on a variant for a commit or patch only
+// if some changed file is watched by both the task and the variant, along
+// with the tasks it depends on.
+
+// pathFilter matches the files that a variant or task watches.
+type pathFilter struct {
+	paths       *ignore.GitIgnore
+	ignorePaths *ignore.GitIgnore
+}
+
+func newPathFilter(paths, ignorePaths []string) *pathFilter {
+	f := &pathFilter{}
+	// CompileIgnoreLines always returns a nil error.
+	if len(paths) > 0 {
+		f.paths, _ = ignore.CompileIgnoreLines(paths...)
+	}
+	if len(ignorePaths) > 0 {
+		f.ignorePaths, _ = ignore.CompileIgnoreLines(ignorePaths...)
+	}
+	return f
+}
+
+func (f *pathFilter) watches(file string) bool {
+	if f.paths != nil && !f.paths.MatchesPath(file) {
+		return false
+	}
+	return f.ignorePaths == nil || !f.ignorePaths.MatchesPath(file)
+}
+
+// HasPathFilters returns true if any variant or task in the project sets
+// paths or ignore_paths.
+func (p *Project) HasPathFilters() bool {
+	for _, bv := range p.BuildVariants {
+		if len(bv.Paths) > 0 || len(bv.IgnorePaths) > 0 {
+			return true
+		}
+	}
+	for _, t := range p.Tasks {
+		if len(t.Paths) > 0 || len(t.IgnorePaths) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// AffectedTVPairs returns the tasks on enabled variants that watch any of the
+// changed files, along with the tasks they depend on, and the display tasks
+// that contain them. If no files are given, every task is affected.
+func (p *Project) AffectedTVPairs(files []string) TaskVariantPairs {
+	taskFilters := map[string]*pathFilter{}
+	for _, t := range p.Tasks {
+		taskFilters[t.Name] = newPathFilter(t.Paths, t.IgnorePaths)
+	}
+	watchedBy := func(variant *pathFilter, task string) bool {
+		if len(files) == 0 {
+			return true
+		}
+		filter, ok := taskFilters[task]
+		if !ok {
+			return false
+		}
+		for _, f := range files {
+			if variant.watches(f) && filter.watches(f) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var pairs, groupPairs []TVPair
+	for _, bv := range p.BuildVariants {
+		if bv.Disabled {
+			continue
+		}
+		variantFilter := newPathFilter(bv.Paths, bv.IgnorePaths)
+		for _, t := range bv.Tasks {
+			if !t.IsGroup {
+				if watchedBy(variantFilter, t.Name) {
+					pairs = append(pairs, TVPair{Variant: bv.Name, TaskName: t.Name})
+				}
+				continue
+			}
+			tg := p.FindTaskGroup(t.Name)
+			if tg == nil {
+				continue
+			}
+			for _, name := range tg.Tasks {
+				if watchedBy(variantFilter, name) {
+					groupPairs = append(groupPairs, TVPair{Variant: bv.Name, TaskName: t.Name})
+					break
+				}
+			}
+		}
+	}
+
+	di := &dependencyIncluder{Project: p, mainline: true}
+	affected := TaskVariantPairs{
+		ExecTasks: di.Include(append(pairs, groupPairs...)),
+	}
+	affected.DisplayTasks = p.displayTasksContaining(affected.ExecTasks)
+	return affected
+}
+
+// displayTasksContaining returns the display tasks with an execution task in
+// the set.
+func (p *Project) displayTasksContaining(execTasks TVPairSet) TVPairSet {
+	inSet := map[TVPair]bool{}
+	for _, pair := range execTasks {
+		inSet[pair] = true
+	}
+	displayTasks := TVPairSet{}
+	for _, bv := range p.BuildVariants {
+		for _, dt := range bv.DisplayTasks {
+			for _, et := range dt.ExecutionTasks {
+				if inSet[TVPair{Variant: bv.Name, TaskName: et}] {
+					displayTasks = append(displayTasks, TVPair{Variant: bv.Name, TaskName: dt.Name})
+					break
+				}
+			}
+		}
+	}
+	return displayTasks
+}
+
+// PatchChangedFiles returns the files changed by the patch, relative to the
+// root of the project's working directory, where modules are checked out at
+// their prefix.
+func (p *Project) PatchChangedFiles(patchDoc *patch.Patch) []string {
+	files := []string{}
+	for _, patchPart := range patchDoc.Patches {
+		dir := ""
+		if patchPart.ModuleName != "" {
+			module, err := p.GetModuleByName(patchPart.ModuleName)
+			if err != nil {
+				continue
+			}
+			dir = path.Join(module.Prefix, module.Name)
+		}
+		for _, summary := range patchPart.PatchSet.Summary {
+			files = append(files, path.Join(dir, summary.Name))
+		}
+	}
+	return files
+}
+
+// filterPatchTasks removes the tasks from the patch's pairs that are not
+// affected by the files it changes.
+func (p *Project) filterPatchTasks(patchDoc *patch.Patch, tasks TaskVariantPairs) TaskVariantPairs {
+	files := p.PatchChangedFiles(patchDoc)
+	affectedPairs := p.AffectedTVPairs(files)
+	affected := map[TVPair]bool{}
+	for _, pair := range affectedPairs.ExecTasks {
+		affected[pair] = true
+	}
+
+	filtered := TaskVariantPairs{}
+	for _, pair := range tasks.ExecTasks {
+		if affected[pair] {
+			filtered.ExecTasks = append(filtered.ExecTasks, pair)
+		}
+	}
+	inPatch := map[TVPair]bool{}
+	for _, pair := range tasks.DisplayTasks {
+		inPatch[pair] = true
+	}
+	for _, pair := range p.displayTasksContaining(filtered.ExecTasks) {
+		if inPatch[pair] {
+			filtered.DisplayTasks = append(filtered.DisplayTasks, pair)
+		}
+	}
+	return filtered
+}
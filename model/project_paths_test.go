@@ -0,0 +1,114 @@
+package model
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pathFiltersProject = `
+tasks:
+- name: compile
+  paths: ["src/"]
+- name: java_test
+  paths: ["java/"]
+  depends_on:
+  - name: compile
+- name: docs
+  paths: ["docs/", "*.md"]
+- name: lint
+  ignore_paths: ["*.md"]
+task_groups:
+- name: java_group
+  tasks: ["java_test"]
+  depends_on:
+  - name: compile
+buildvariants:
+- name: linux
+  display_tasks:
+  - name: all_java
+    execution_tasks: ["java_test"]
+  tasks:
+  - name: compile
+  - name: java_test
+  - name: docs
+  - name: lint
+- name: windows
+  ignore_paths: ["docs/"]
+  tasks:
+  - name: compile
+  - name: docs
+  - name: java_group
+- name: old
+  disabled: true
+  tasks:
+  - name: lint
+`
+
+func pairNames(pairs TVPairSet) []string {
+	names := []string{}
+	for _, pair := range pairs {
+		names = append(names, pair.String())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestAffectedTVPairs(t *testing.T) {
+	assert := assert.New(t)
+	project := &Project{}
+	require.NoError(t, LoadProjectInto([]byte(pathFiltersProject), "paths", project))
+	assert.True(project.HasPathFilters())
+
+	// tasks and variants without filters watch every file
+	affected := project.AffectedTVPairs([]string{"docs/index.rst", "README.md"})
+	assert.Equal([]string{"linux/docs", "linux/lint", "windows/docs"},
+		pairNames(affected.ExecTasks))
+	assert.Empty(affected.DisplayTasks)
+
+	// dependencies are included even if they are not affected, including
+	// those of task groups
+	affected = project.AffectedTVPairs([]string{"java/Main.java"})
+	assert.Equal([]string{"linux/compile", "linux/java_test", "linux/lint", "windows/compile", "windows/java_group"},
+		pairNames(affected.ExecTasks))
+	assert.Equal([]string{"linux/all_java"}, pairNames(affected.DisplayTasks))
+
+	// a file must be watched by both the task and the variant
+	affected = project.AffectedTVPairs([]string{"docs/index.rst", "java/Main.java"})
+	assert.NotContains(pairNames(affected.ExecTasks), "windows/docs")
+	assert.Contains(pairNames(affected.ExecTasks), "linux/docs")
+
+	affected = project.AffectedTVPairs(nil)
+	assert.Len(affected.ExecTasks, 7)
+
+	assert.False((&Project{}).HasPathFilters())
+}
+
+func TestFilterPatchTasks(t *testing.T) {
+	assert := assert.New(t)
+	project := &Project{}
+	require.NoError(t, LoadProjectInto([]byte(pathFiltersProject), "paths", project))
+	project.Modules = []Module{{Name: "enterprise", Prefix: "src/mongo/db/modules"}}
+
+	p := &patch.Patch{
+		Patches: []patch.ModulePatch{
+			{PatchSet: patch.PatchSet{Summary: []patch.Summary{{Name: "README.md"}}}},
+			{ModuleName: "enterprise", PatchSet: patch.PatchSet{Summary: []patch.Summary{{Name: "README.md"}}}},
+		},
+	}
+	assert.Equal([]string{"README.md", "src/mongo/db/modules/enterprise/README.md"}, project.PatchChangedFiles(p))
+
+	tasks := TaskVariantPairs{
+		ExecTasks: TVPairSet{
+			{Variant: "linux", TaskName: "docs"},
+			{Variant: "linux", TaskName: "lint"},
+			{Variant: "windows", TaskName: "docs"},
+		},
+	}
+	filtered := project.filterPatchTasks(p, tasks)
+	assert.Equal([]string{"linux/docs", "windows/docs"}, pairNames(filtered.ExecTasks))
+	assert.Empty(filtered.DisplayTasks)
+}
@@ -581,4 +581,11 @@ func TestPopulateExpansions(t *testing.T) {
 	assert.Equal("octocat", expansions.Get("github_author"))
 	assert.Equal("42", expansions.Get("github_pr_number"))
 	assert.Equal("wut?", expansions.Get("github_org"))
+
+	// parameters override variant expansions
+	v.Parameters = []patch.Parameter{{Key: "cake", Value: "truth"}, {Key: "suite", Value: "core"}}
+	expansions = populateExpansions(d, v, bv, taskDoc, patchDoc)
+	assert.Len(map[string]string(*expansions), 22)
+	assert.Equal("truth", expansions.Get("cake"))
+	assert.Equal("core", expansions.Get("suite"))
 }
@@ -39,11 +39,13 @@ var (
 	DistroIdKey            = bsonutil.MustHaveTag(Task{}, "DistroId")
 	BuildVariantKey        = bsonutil.MustHaveTag(Task{}, "BuildVariant")
 	DependsOnKey           = bsonutil.MustHaveTag(Task{}, "DependsOn")
+	UnresolvedDepsKey      = bsonutil.MustHaveTag(Task{}, "UnresolvedDependencies")
 	NumDepsKey             = bsonutil.MustHaveTag(Task{}, "NumDependents")
 	DisplayNameKey         = bsonutil.MustHaveTag(Task{}, "DisplayName")
 	HostIdKey              = bsonutil.MustHaveTag(Task{}, "HostId")
 	ExecutionKey           = bsonutil.MustHaveTag(Task{}, "Execution")
 	RestartsKey            = bsonutil.MustHaveTag(Task{}, "Restarts")
+	AutoRetriesKey         = bsonutil.MustHaveTag(Task{}, "AutoRetries")
 	OldTaskIdKey           = bsonutil.MustHaveTag(Task{}, "OldTaskId")
 	ArchivedKey            = bsonutil.MustHaveTag(Task{}, "Archived")
 	RevisionOrderNumberKey = bsonutil.MustHaveTag(Task{}, "RevisionOrderNumber")
@@ -190,6 +192,22 @@ func ByCommit(revision, buildVariant, displayName, project, requester string) db
 	})
 }
 
+// ByLatestMainlineTask creates a query on Evergreen as the requester for a
+// project's tasks with the given buildVariant and displayName, most recent
+// revision first. If statuses are given, only tasks with those statuses match.
+func ByLatestMainlineTask(buildVariant, displayName, project string, statuses []string) db.Q {
+	q := bson.M{
+		RequesterKey:    evergreen.RepotrackerVersionRequester,
+		BuildVariantKey: buildVariant,
+		DisplayNameKey:  displayName,
+		ProjectKey:      project,
+	}
+	if len(statuses) > 0 {
+		q[StatusKey] = bson.M{"$in": statuses}
+	}
+	return db.Query(q).Sort([]string{"-" + RevisionOrderNumberKey})
+}
+
 // ByStatusAndActivation creates a query that returns tasks of a certain status and activation state.
 func ByStatusAndActivation(status string, active bool) db.Q {
 	return db.Query(bson.M{
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip/message"
 )
@@ -26,7 +27,7 @@ func (t *Task) ResultStatus() string {
 		status = evergreen.TaskSucceeded
 	} else if t.Status == evergreen.TaskFailed {
 		status = evergreen.TaskFailed
-		if t.Details.Type == "system" {
+		if t.Details.Type == "system" || t.Details.Type == apimodels.SpotInterruptionType {
 			status = evergreen.TaskSystemFailed
 			if t.Details.TimedOut {
 				if t.Details.Description == "heartbeat" {
@@ -59,6 +60,8 @@ type ResultCounts struct {
 	SystemUnresponsive int `json:"system-unresponsive"`
 	SystemTimedOut     int `json:"system-timed-out"`
 	TestTimedOut       int `json:"test-timed-out"`
+	SpotInterrupted    int `json:"spot-interrupted"`
+	PassedOnRetry      int `json:"passed-on-retry"`
 
 	loggable      bool
 	cachedMessage string
@@ -92,6 +95,13 @@ func GetResultCounts(tasks []Task) *ResultCounts {
 		case evergreen.TaskTestTimedOut:
 			out.TestTimedOut++
 		}
+		if t.Details.Type == apimodels.SpotInterruptionType {
+			out.SpotInterrupted++
+		}
+		// successes that needed an automatic retry point to flaky tasks
+		if t.Status == evergreen.TaskSucceeded && t.AutoRetries > 0 {
+			out.PassedOnRetry++
+		}
 	}
 
 	if out.Total > 0 {
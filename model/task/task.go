@@ -11,9 +11,11 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -65,6 +67,9 @@ type Task struct {
 	BuildVariant  string       `bson:"build_variant" json:"build_variant"`
 	DependsOn     []Dependency `bson:"depends_on" json:"depends_on"`
 	NumDependents int          `bson:"num_dependents,omitempty" json:"num_dependents,omitempty"`
+	// dependencies on tasks in other projects that did not exist yet when
+	// the task was created; the task is not scheduled until they resolve
+	UnresolvedDependencies []UnresolvedDependency `bson:"unresolved_depends_on,omitempty" json:"unresolved_depends_on,omitempty"`
 
 	// Human-readable name
 	DisplayName string `bson:"display_name" json:"display_name"`
@@ -75,8 +80,11 @@ type Task struct {
 	// The host the task was run on
 	HostId string `bson:"host_id" json:"host_id"`
 
-	// the number of times this task has been restarted
+	// the number of times this task has been restarted, and the number of
+	// times it has been retried by an auto_retry policy since it was
+	// created or last restarted
 	Restarts            int    `bson:"restarts" json:"restarts,omitempty"`
+	AutoRetries         int    `bson:"auto_retries,omitempty" json:"auto_retries,omitempty"`
 	Execution           int    `bson:"execution" json:"execution"`
 	OldTaskId           string `bson:"old_task_id,omitempty" json:"old_task_id,omitempty"`
 	Archived            bool   `bson:"archived,omitempty" json:"archived,omitempty"`
@@ -119,6 +127,84 @@ type Dependency struct {
 	Status string `bson:"status" json:"status"`
 }
 
+// UnresolvedDependency is a dependency on a task in another project that is
+// resolved to a Dependency once the task exists: the project's task at
+// Revision if it is set, and otherwise its most recent mainline task with
+// the required Status.
+type UnresolvedDependency struct {
+	Project  string `bson:"project" json:"project"`
+	Variant  string `bson:"variant" json:"variant"`
+	Name     string `bson:"name" json:"name"`
+	Status   string `bson:"status" json:"status"`
+	Revision string `bson:"revision,omitempty" json:"revision,omitempty"`
+}
+
+// FindTask returns the other project's task that the dependency refers to,
+// or nil if there is no such task yet.
+func (d UnresolvedDependency) FindTask() (*Task, error) {
+	var depTask *Task
+	var err error
+	if d.Revision != "" {
+		depTask, err = FindOneNoMerge(ByCommit(d.Revision, d.Variant, d.Name,
+			d.Project, evergreen.RepotrackerVersionRequester))
+	} else {
+		statuses := []string{evergreen.TaskSucceeded}
+		switch d.Status {
+		case evergreen.TaskFailed:
+			statuses = []string{evergreen.TaskFailed}
+		case AllStatuses:
+			statuses = []string{evergreen.TaskSucceeded, evergreen.TaskFailed}
+		}
+		depTask, err = FindOneNoMerge(ByLatestMainlineTask(d.Variant, d.Name, d.Project, statuses))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding task '%s' on variant '%s' in project '%s'",
+			d.Name, d.Variant, d.Project)
+	}
+	return depTask, nil
+}
+
+// ResolveDependencies replaces each of the task's unresolved dependencies
+// whose task now exists with a dependency on that task, both locally and in
+// the database.
+func (t *Task) ResolveDependencies() error {
+	remaining := make([]UnresolvedDependency, 0, len(t.UnresolvedDependencies))
+	catcher := grip.NewBasicCatcher()
+	for _, dep := range t.UnresolvedDependencies {
+		depTask, err := dep.FindTask()
+		if err != nil {
+			catcher.Add(err)
+			remaining = append(remaining, dep)
+			continue
+		}
+		if depTask == nil {
+			remaining = append(remaining, dep)
+			continue
+		}
+
+		resolved := Dependency{TaskId: depTask.Id, Status: dep.Status}
+		err = UpdateOne(
+			bson.M{
+				IdKey:             t.Id,
+				UnresolvedDepsKey: dep,
+			},
+			bson.M{
+				"$push": bson.M{DependsOnKey: resolved},
+				"$pull": bson.M{UnresolvedDepsKey: dep},
+			},
+		)
+		if err != nil && err != mgo.ErrNotFound {
+			catcher.Add(errors.Wrapf(err, "problem resolving dependency of task '%s'", t.Id))
+			remaining = append(remaining, dep)
+			continue
+		}
+		t.DependsOn = append(t.DependsOn, resolved)
+	}
+	t.UnresolvedDependencies = remaining
+
+	return catcher.Resolve()
+}
+
 // VersionCost is service level model for representing cost data related to a version.
 // SumTimeTaken is the aggregation of time taken by all tasks associated with a version.
 type VersionCost struct {
@@ -136,6 +222,9 @@ type DistroCost struct {
 	Provider         string                 `json:"provider"`
 	ProviderSettings map[string]interface{} `json:"provider_settings"`
 	NumTasks         int                    `bson:"num_tasks"`
+	// SumWarmPoolCost is the cost of the time the distro's hosts spent idle
+	// in its warm pool, which is not attributed to any task.
+	SumWarmPoolCost float64 `bson:"sum_warm_pool_cost,omitempty"`
 }
 
 // SetBSON allows us to use dependency representation of both
@@ -238,6 +327,11 @@ func (t *Task) satisfiesDependency(depTask *Task) bool {
 // used to check rather than fetching from the database. All queries
 // are cached back into the map for later use.
 func (t *Task) DependenciesMet(depCaches map[string]Task) (bool, error) {
+	// a dependency on another project's task that does not exist yet is
+	// never met
+	if len(t.UnresolvedDependencies) > 0 {
+		return false, nil
+	}
 
 	if len(t.DependsOn) == 0 {
 		return true, nil
@@ -248,7 +342,9 @@ func (t *Task) DependenciesMet(depCaches map[string]Task) (bool, error) {
 	depIdsToQueryFor := make([]string, 0, len(t.DependsOn))
 	for _, dep := range t.DependsOn {
 		if cachedDep, ok := depCaches[dep.TaskId]; !ok {
-			depIdsToQueryFor = append(depIdsToQueryFor, dep.TaskId)
+			if !util.StringSliceContains(depIdsToQueryFor, dep.TaskId) {
+				depIdsToQueryFor = append(depIdsToQueryFor, dep.TaskId)
+			}
 		} else {
 			deps = append(deps, cachedDep)
 		}
@@ -283,6 +379,10 @@ func (t *Task) DependenciesMet(depCaches map[string]Task) (bool, error) {
 // If the cached tasks do not include a dependency specified by one of
 // the tasks, the function returns an error.
 func (t *Task) AllDependenciesSatisfied(cache map[string]Task) (bool, error) {
+	if len(t.UnresolvedDependencies) > 0 {
+		return false, nil
+	}
+
 	if len(t.DependsOn) == 0 {
 		return true, nil
 	}
@@ -792,6 +892,16 @@ func (t *Task) Reset() error {
 	)
 }
 
+// IncAutoRetries records that the task has been run again by an auto_retry
+// policy.
+func (t *Task) IncAutoRetries() error {
+	t.AutoRetries++
+	return UpdateOne(
+		bson.M{IdKey: t.Id},
+		bson.M{"$inc": bson.M{AutoRetriesKey: 1}},
+	)
+}
+
 // Reset sets the task state to be activated, with a new secret,
 // undispatched status and zero time on Start, Scheduled, Dispatch and FinishTime
 func ResetTasks(taskIds []string) error {
@@ -1027,6 +1137,17 @@ func (t *Task) Insert() error {
 
 // Inserts the task into the old_tasks collection
 func (t *Task) Archive() error {
+	return errors.WithStack(t.archive(true))
+}
+
+// ArchiveWithoutRestart inserts the task into the old_tasks collection
+// without counting the new execution as a restart, for tasks that are rerun
+// through no fault of their own.
+func (t *Task) ArchiveWithoutRestart() error {
+	return errors.WithStack(t.archive(false))
+}
+
+func (t *Task) archive(countRestart bool) error {
 	var update bson.M
 
 	if t.DisplayOnly {
@@ -1035,7 +1156,7 @@ func (t *Task) Archive() error {
 			if err != nil {
 				return errors.Wrap(err, "error retrieving execution task")
 			}
-			if err = execTask.Archive(); err != nil {
+			if err = execTask.archive(countRestart); err != nil {
 				return errors.Wrap(err, "error archiving execution task")
 			}
 		}
@@ -1044,7 +1165,7 @@ func (t *Task) Archive() error {
 	// only increment restarts if have a current restarts
 	// this way restarts will never be set for new tasks but will be
 	// maintained for old ones
-	if t.Restarts > 0 {
+	if countRestart && t.Restarts > 0 {
 		update = bson.M{"$inc": bson.M{
 			ExecutionKey: 1,
 			RestartsKey:  1,
@@ -1054,6 +1175,10 @@ func (t *Task) Archive() error {
 			"$inc": bson.M{ExecutionKey: 1},
 		}
 	}
+	// a restart starts a fresh set of automatic retries
+	if countRestart {
+		update["$unset"] = bson.M{AutoRetriesKey: ""}
+	}
 	err := UpdateOne(
 		bson.M{IdKey: t.Id},
 		update)
@@ -1191,11 +1316,23 @@ func FindSchedulable() ([]Task, error) {
 	return Find(db.Query(scheduleableTasksQuery()))
 }
 
+// FindSchedulableWithUnresolvedDependencies returns the tasks that could be
+// scheduled but for dependencies on tasks in other projects that did not
+// exist yet when they were created.
+func FindSchedulableWithUnresolvedDependencies() ([]Task, error) {
+	q := scheduleableTasksQuery()
+	q[bsonutil.GetDottedKeyName(UnresolvedDepsKey, "0")] = bson.M{"$exists": true}
+	return Find(db.Query(q))
+}
+
 func FindRunnable() ([]Task, error) {
 	expectedStatuses := []string{evergreen.TaskSucceeded, evergreen.TaskFailed, ""}
 
+	// tasks with unresolved dependencies are never runnable
+	runnableQuery := scheduleableTasksQuery()
+	runnableQuery[bsonutil.GetDottedKeyName(UnresolvedDepsKey, "0")] = bson.M{"$exists": false}
 	matchActivatedUndispatchedTasks := bson.M{
-		"$match": scheduleableTasksQuery(),
+		"$match": runnableQuery,
 	}
 
 	graphLookupTaskDeps := bson.M{
@@ -119,6 +119,30 @@ func TestDependenciesMet(t *testing.T) {
 			So(met, ShouldBeTrue)
 		})
 
+		Convey("a dependency listed twice should be met once its task is"+
+			" finished", func() {
+			task.DependsOn = []Dependency{depTaskIds[0], depTaskIds[0]}
+			updateTestDepTasks(t)
+			met, err := task.DependenciesMet(map[string]Task{})
+			So(err, ShouldBeNil)
+			So(met, ShouldBeTrue)
+		})
+
+		Convey("unresolved dependencies on other projects' tasks should"+
+			" never be met", func() {
+			task.DependsOn = depTaskIds
+			task.UnresolvedDependencies = []UnresolvedDependency{
+				{Project: "other", Variant: "bv", Name: "compile", Status: evergreen.TaskSucceeded},
+			}
+			updateTestDepTasks(t)
+			met, err := task.DependenciesMet(map[string]Task{})
+			So(err, ShouldBeNil)
+			So(met, ShouldBeFalse)
+			met, err = task.AllDependenciesSatisfied(map[string]Task{})
+			So(err, ShouldBeNil)
+			So(met, ShouldBeFalse)
+		})
+
 		Convey("tasks not in the dependency cache should be pulled into the"+
 			" cache during dependency checking", func() {
 			dependencyCache := make(map[string]Task)
@@ -769,6 +793,8 @@ func TestTaskResultOutcome(t *testing.T) {
 		{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: "system", TimedOut: true}},                           // 6
 		{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: "system", TimedOut: true, Description: "heartbeat"}}, // 7
 		{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{TimedOut: true, Description: "heartbeat"}},                 // 8
+		{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: apimodels.SpotInterruptionType}},                     // 9
+		{Status: evergreen.TaskSucceeded, AutoRetries: 1},                                                                          // 10
 	}
 
 	out := GetResultCounts(tasks)
@@ -776,12 +802,14 @@ func TestTaskResultOutcome(t *testing.T) {
 	assert.Equal(1, out.Inactive)
 	assert.Equal(1, out.Unstarted)
 	assert.Equal(1, out.Started)
-	assert.Equal(1, out.Succeeded)
+	assert.Equal(2, out.Succeeded)
 	assert.Equal(1, out.Failed)
-	assert.Equal(1, out.SystemFailed)
+	assert.Equal(2, out.SystemFailed)
 	assert.Equal(1, out.SystemUnresponsive)
 	assert.Equal(1, out.SystemTimedOut)
 	assert.Equal(1, out.TestTimedOut)
+	assert.Equal(1, out.SpotInterrupted)
+	assert.Equal(1, out.PassedOnRetry)
 
 	//
 
@@ -794,6 +822,11 @@ func TestTaskResultOutcome(t *testing.T) {
 	assert.Equal(1, GetResultCounts([]Task{tasks[6]}).SystemTimedOut)
 	assert.Equal(1, GetResultCounts([]Task{tasks[7]}).SystemUnresponsive)
 	assert.Equal(1, GetResultCounts([]Task{tasks[8]}).TestTimedOut)
+	assert.Equal(1, GetResultCounts([]Task{tasks[9]}).SystemFailed)
+	assert.Equal(1, GetResultCounts([]Task{tasks[9]}).SpotInterrupted)
+	assert.Equal(1, GetResultCounts([]Task{tasks[10]}).Succeeded)
+	assert.Equal(1, GetResultCounts([]Task{tasks[10]}).PassedOnRetry)
+	assert.Zero(GetResultCounts([]Task{tasks[3]}).PassedOnRetry)
 }
 
 func TestDisplayTaskUpdates(t *testing.T) {
@@ -0,0 +1,202 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+)
+
+// TaskGraphNode is a task in a version's dependency graph.
+type TaskGraphNode struct {
+	TaskId       string
+	DisplayName  string
+	BuildVariant string
+	Status       string
+	// DependsOn are the ids of the tasks in the version that the task
+	// depends on.
+	DependsOn        []string
+	ActualDuration   time.Duration
+	ExpectedDuration time.Duration
+	Finished         bool
+	OnCriticalPath   bool
+}
+
+// Duration returns how long the task took if it has finished, and how long
+// it is expected to take otherwise.
+func (n *TaskGraphNode) Duration() time.Duration {
+	if n.Finished {
+		return n.ActualDuration
+	}
+	return n.ExpectedDuration
+}
+
+// TaskGraph is the dependency graph of a version's tasks. The critical path
+// is the chain of dependent tasks with the longest total duration, which
+// bounds how quickly the version can finish however many hosts run it; its
+// total duration is the makespan.
+type TaskGraph struct {
+	VersionId    string
+	Nodes        []TaskGraphNode
+	CriticalPath []string
+	Makespan     time.Duration
+}
+
+// FindTaskGraph builds the dependency graph of the version's tasks.
+func FindTaskGraph(versionId string) (*TaskGraph, error) {
+	tasks, err := task.Find(task.ByVersion(versionId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding tasks of version %s", versionId)
+	}
+	return NewTaskGraph(versionId, tasks)
+}
+
+// NewTaskGraph builds the dependency graph of the tasks and computes its
+// critical path. Display tasks are left out, since they do not run, as are
+// dependencies on tasks that are not in the list. It is an error for the
+// dependencies to contain a cycle.
+func NewTaskGraph(versionId string, tasks []task.Task) (*TaskGraph, error) {
+	g := &TaskGraph{VersionId: versionId, Nodes: []TaskGraphNode{}, CriticalPath: []string{}}
+	ids := map[string]bool{}
+	for _, t := range tasks {
+		if !t.DisplayOnly {
+			ids[t.Id] = true
+		}
+	}
+	for _, t := range tasks {
+		if t.DisplayOnly {
+			continue
+		}
+		node := TaskGraphNode{
+			TaskId:           t.Id,
+			DisplayName:      t.DisplayName,
+			BuildVariant:     t.BuildVariant,
+			Status:           t.Status,
+			DependsOn:        []string{},
+			ActualDuration:   t.TimeTaken,
+			ExpectedDuration: t.ExpectedDuration,
+			Finished:         task.IsFinished(t),
+		}
+		for _, dep := range t.DependsOn {
+			if ids[dep.TaskId] {
+				node.DependsOn = append(node.DependsOn, dep.TaskId)
+			}
+		}
+		sort.Strings(node.DependsOn)
+		g.Nodes = append(g.Nodes, node)
+	}
+	sort.Sort(taskGraphNodes(g.Nodes))
+
+	if err := g.findCriticalPath(); err != nil {
+		return nil, errors.Wrapf(err, "error finding critical path of version %s", versionId)
+	}
+	return g, nil
+}
+
+// taskGraphNodes sorts nodes by variant, then task name.
+type taskGraphNodes []TaskGraphNode
+
+func (n taskGraphNodes) Len() int      { return len(n) }
+func (n taskGraphNodes) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+func (n taskGraphNodes) Less(i, j int) bool {
+	if n[i].BuildVariant != n[j].BuildVariant {
+		return n[i].BuildVariant < n[j].BuildVariant
+	}
+	if n[i].DisplayName != n[j].DisplayName {
+		return n[i].DisplayName < n[j].DisplayName
+	}
+	return n[i].TaskId < n[j].TaskId
+}
+
+// findCriticalPath computes the time at which each task would finish if
+// every task started as soon as its dependencies finished, and marks the
+// chain of tasks leading to the latest finish.
+func (g *TaskGraph) findCriticalPath() error {
+	nodes := map[string]*TaskGraphNode{}
+	for i := range g.Nodes {
+		nodes[g.Nodes[i].TaskId] = &g.Nodes[i]
+	}
+
+	finish := map[string]time.Duration{}
+	previous := map[string]string{}
+	visiting := map[string]bool{}
+	var visit func(id string) error
+	visit = func(id string) error {
+		if _, ok := finish[id]; ok {
+			return nil
+		}
+		if visiting[id] {
+			return errors.Errorf("dependency cycle includes task %s", id)
+		}
+		visiting[id] = true
+		node := nodes[id]
+		start := time.Duration(0)
+		for _, dep := range node.DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+			if _, ok := previous[id]; !ok || finish[dep] > start {
+				start = finish[dep]
+				previous[id] = dep
+			}
+		}
+		finish[id] = start + node.Duration()
+		visiting[id] = false
+		return nil
+	}
+
+	last := ""
+	for _, node := range g.Nodes {
+		if err := visit(node.TaskId); err != nil {
+			return err
+		}
+		if last == "" || finish[node.TaskId] > finish[last] {
+			last = node.TaskId
+		}
+	}
+	if last == "" {
+		return nil
+	}
+
+	g.Makespan = finish[last]
+	for id, ok := last, true; ok; id, ok = previous[id] {
+		nodes[id].OnCriticalPath = true
+		g.CriticalPath = append([]string{id}, g.CriticalPath...)
+	}
+	return nil
+}
+
+// DOT returns the graph in the Graphviz DOT language, with the critical path
+// highlighted.
+func (g *TaskGraph) DOT() string {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "digraph %q {\n", g.VersionId)
+	fmt.Fprintf(buf, "\tlabel=%q;\n", fmt.Sprintf("critical path: %s", g.Makespan))
+	fmt.Fprintln(buf, "\tnode [shape=box];")
+	for _, node := range g.Nodes {
+		attrs := fmt.Sprintf("label=%q", fmt.Sprintf("%s\n%s\n%s (%s)",
+			node.BuildVariant, node.DisplayName, node.Duration(), node.Status))
+		if node.OnCriticalPath {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(buf, "\t%q [%s];\n", node.TaskId, attrs)
+	}
+	criticalEdges := map[[2]string]bool{}
+	for i := 1; i < len(g.CriticalPath); i++ {
+		criticalEdges[[2]string{g.CriticalPath[i-1], g.CriticalPath[i]}] = true
+	}
+	for _, node := range g.Nodes {
+		for _, dep := range node.DependsOn {
+			attrs := ""
+			if criticalEdges[[2]string{dep, node.TaskId}] {
+				attrs = " [color=red]"
+			}
+			fmt.Fprintf(buf, "\t%q -> %q%s;\n", dep, node.TaskId, attrs)
+		}
+	}
+	fmt.Fprintln(buf, "}")
+	return buf.String()
+}
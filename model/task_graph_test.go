@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTaskGraph(t *testing.T) {
+	assert := assert.New(t)
+	tasks := []task.Task{
+		{Id: "compile", DisplayName: "compile", BuildVariant: "linux",
+			Status: evergreen.TaskSucceeded, TimeTaken: 10 * time.Minute, ExpectedDuration: time.Hour},
+		{Id: "lint", DisplayName: "lint", BuildVariant: "linux",
+			Status: evergreen.TaskSucceeded, TimeTaken: 15 * time.Minute},
+		{Id: "unit", DisplayName: "unit", BuildVariant: "linux",
+			Status: evergreen.TaskStarted, ExpectedDuration: 20 * time.Minute,
+			DependsOn: []task.Dependency{{TaskId: "compile"}}},
+		{Id: "integration", DisplayName: "integration", BuildVariant: "linux",
+			Status: evergreen.TaskUndispatched, ExpectedDuration: 30 * time.Minute,
+			DependsOn: []task.Dependency{{TaskId: "compile"}, {TaskId: "lint"}, {TaskId: "other_version"}}},
+		{Id: "package", DisplayName: "package", BuildVariant: "dist",
+			Status: evergreen.TaskUndispatched, ExpectedDuration: 5 * time.Minute,
+			DependsOn: []task.Dependency{{TaskId: "unit"}, {TaskId: "integration"}}},
+		{Id: "display", DisplayName: "display", BuildVariant: "linux", DisplayOnly: true,
+			ExecutionTasks: []string{"unit", "integration"}},
+	}
+
+	g, err := NewTaskGraph("v1", tasks)
+	require.NoError(t, err)
+	require.Len(t, g.Nodes, 5)
+	assert.Equal("package", g.Nodes[0].TaskId)
+	assert.Equal([]string{"compile", "lint"}, g.Nodes[2].DependsOn)
+
+	// the finished lint task took longer than compile, so the path runs
+	// through it rather than through compile's expected duration
+	assert.Equal([]string{"lint", "integration", "package"}, g.CriticalPath)
+	assert.Equal(50*time.Minute, g.Makespan)
+	for _, node := range g.Nodes {
+		assert.Equal(node.TaskId != "compile" && node.TaskId != "unit", node.OnCriticalPath, node.TaskId)
+	}
+
+	dot := g.DOT()
+	assert.Contains(dot, `digraph "v1" {`)
+	assert.Contains(dot, `"lint" -> "integration" [color=red];`)
+	assert.Contains(dot, `"compile" -> "integration";`)
+
+	tasks[0].DependsOn = []task.Dependency{{TaskId: "package"}}
+	_, err = NewTaskGraph("v1", tasks)
+	assert.Error(err)
+
+	g, err = NewTaskGraph("v2", nil)
+	assert.NoError(err)
+	assert.Empty(g.CriticalPath)
+	assert.Zero(g.Makespan)
+}
@@ -20,6 +20,9 @@ import (
 type StatusChanges struct {
 	PatchNewStatus string
 	BuildNewStatus string
+	// TaskRetried is set if the task was reset for another execution by
+	// its auto_retry policy rather than finishing.
+	TaskRetried bool
 }
 
 func SetActiveState(taskId string, caller string, active bool) error {
@@ -104,6 +107,12 @@ func ActivatePreviousTask(taskId, caller string) error {
 
 // reset task finds a task, attempts to archive it, and resets the task and resets the TaskCache in the build as well.
 func resetTask(taskId string) error {
+	return resetTaskExecution(taskId, true)
+}
+
+// resetTaskExecution resets the task, only counting the new execution as a
+// restart of the task if countRestart is set.
+func resetTaskExecution(taskId string, countRestart bool) error {
 	t, err := task.FindOneNoMerge(task.ById(taskId))
 	if err != nil {
 		return errors.WithStack(err)
@@ -111,7 +120,12 @@ func resetTask(taskId string) error {
 	if t.IsPartOfDisplay() {
 		return fmt.Errorf("cannot restart execution task %s because it is part of a display task", t.Id)
 	}
-	if err = t.Archive(); err != nil {
+	if countRestart {
+		err = t.Archive()
+	} else {
+		err = t.ArchiveWithoutRestart()
+	}
+	if err != nil {
 		return errors.Wrap(err, "can't restart task because it can't be archived")
 	}
 
@@ -186,6 +200,42 @@ func TryResetTask(taskId, user, origin string, p *Project, detail *apimodels.Tas
 	return errors.WithStack(err)
 }
 
+// ResetSpotInterruptedTask ends a task whose host is being reclaimed by EC2
+// and restarts it. Since the task did not fail through any fault of its own,
+// the restart neither counts against the task's restarts nor is limited by
+// the maximum number of executions.
+func ResetSpotInterruptedTask(taskId, origin string) error {
+	t, err := task.FindOneNoMerge(task.ById(taskId))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if t == nil {
+		return errors.Errorf("task '%s' not found", taskId)
+	}
+	if t.IsPartOfDisplay() {
+		return fmt.Errorf("cannot restart execution task %s because it is part of a display task", t.Id)
+	}
+
+	detail := &apimodels.TaskEndDetail{
+		Status:      evergreen.TaskFailed,
+		Type:        apimodels.SpotInterruptionType,
+		Description: "spot instance interruption",
+	}
+	if err = t.MarkEnd(time.Now(), detail); err != nil {
+		return errors.Wrap(err, "Error marking task as ended")
+	}
+
+	if err = resetTaskExecution(t.Id, false); err != nil {
+		return err
+	}
+	event.LogTaskRestarted(t.Id, origin)
+
+	if t.DisplayOnly {
+		return t.UpdateDisplayTask()
+	}
+	return nil
+}
+
 func AbortTask(taskId, caller string) error {
 	t, err := task.FindOne(task.ById(taskId))
 	if err != nil {
@@ -367,6 +417,16 @@ func MarkEnd(taskId, caller string, finishTime time.Time, detail *apimodels.Task
 	status := t.ResultStatus()
 	event.LogTaskFinished(t.Id, t.HostId, status)
 
+	// a failure that the task's auto_retry policy retries is not final, so
+	// there is nothing to step back from
+	if shouldAutoRetry(t, p) {
+		if err = autoRetryTask(t); err != nil {
+			return errors.WithStack(err)
+		}
+		updates.TaskRetried = true
+		return nil
+	}
+
 	if t.IsPartOfDisplay() {
 		if err = t.DisplayTask.UpdateDisplayTask(); err != nil {
 			return err
@@ -403,6 +463,55 @@ func MarkEnd(taskId, caller string, finishTime time.Time, detail *apimodels.Task
 	return nil
 }
 
+// shouldAutoRetry returns true if the task failed in a way that its
+// auto_retry policy retries and it has attempts left. Execution tasks of
+// display tasks are never retried, since they cannot be reset on their own.
+func shouldAutoRetry(t *task.Task, p *Project) bool {
+	if p == nil || t.Status != evergreen.TaskFailed || t.IsPartOfDisplay() || t.Execution >= evergreen.MaxTaskExecution {
+		return false
+	}
+	policy := p.FindAutoRetry(t.DisplayName, t.BuildVariant)
+	if policy == nil || t.AutoRetries+1 >= policy.MaxAttempts {
+		return false
+	}
+	if len(policy.OnlyRequesters) > 0 && !util.StringSliceContains(policy.OnlyRequesters, t.Requester) {
+		return false
+	}
+	return len(policy.On) == 0 || util.StringSliceContains(policy.On, autoRetryFailureType(t.Details))
+}
+
+// autoRetryFailureType classifies a task failure as one of the
+// AutoRetryFailureTypes. A timeout is a timeout whichever command timed out.
+func autoRetryFailureType(detail apimodels.TaskEndDetail) string {
+	switch {
+	case detail.TimedOut:
+		return AutoRetryOnTimeout
+	case detail.Type == SystemCommandType:
+		return AutoRetryOnSystemFailed
+	default:
+		return AutoRetryOnTestFailure
+	}
+}
+
+// autoRetryTask runs a failed task again. The failed attempt is kept as an
+// archived execution, and the retry does not count as a restart.
+func autoRetryTask(t *task.Task) error {
+	if err := resetTaskExecution(t.Id, false); err != nil {
+		return errors.Wrapf(err, "error retrying task %s", t.Id)
+	}
+	if err := t.IncAutoRetries(); err != nil {
+		return errors.Wrapf(err, "error counting retry of task %s", t.Id)
+	}
+	event.LogTaskRestarted(t.Id, evergreen.AutoRetryTaskActivator)
+	grip.Info(message.Fields{
+		"message":   "automatically retrying failed task",
+		"task_id":   t.Id,
+		"execution": t.Execution + 1,
+		"attempt":   t.AutoRetries + 1,
+	})
+	return nil
+}
+
 func evalStepback(t *task.Task, p *Project, caller, status string, deactivatePrevious bool) error {
 	if status == evergreen.TaskFailed {
 		var shouldStepBack bool
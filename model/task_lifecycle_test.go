@@ -15,6 +15,7 @@ import (
 	"github.com/evergreen-ci/evergreen/util"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -790,6 +791,133 @@ func TestMarkEnd(t *testing.T) {
 	})
 }
 
+func TestMarkEndAutoRetry(t *testing.T) {
+	assert := assert.New(t) //nolint
+	assert.NoError(db.ClearCollections(task.Collection, task.OldCollection, build.Collection, version.Collection),
+		"Error clearing task and build collections")
+
+	b := &build.Build{
+		Id:      "buildtest",
+		Status:  evergreen.BuildStarted,
+		Version: "abc",
+	}
+	p := &Project{
+		Identifier: "sample",
+		Tasks:      []ProjectTask{{Name: "flaky", AutoRetry: &AutoRetry{MaxAttempts: 2, On: []string{AutoRetryOnTestFailure}}}},
+		BuildVariants: []BuildVariant{
+			{Name: "bv", Tasks: []BuildVariantTaskUnit{{Name: "flaky"}}},
+		},
+	}
+	v := &version.Version{
+		Id:     b.Version,
+		Status: evergreen.VersionStarted,
+	}
+	testTask := task.Task{
+		Id:           "testone",
+		DisplayName:  "flaky",
+		BuildVariant: "bv",
+		Activated:    true,
+		BuildId:      b.Id,
+		Project:      "sample",
+		Status:       evergreen.TaskStarted,
+	}
+	b.Tasks = []build.TaskCache{
+		{
+			Id:     testTask.Id,
+			Status: evergreen.TaskStarted,
+		},
+	}
+	assert.NoError(b.Insert())
+	assert.NoError(testTask.Insert())
+	assert.NoError(v.Insert())
+
+	// the first failure is retried as a new execution
+	updates := StatusChanges{}
+	details := apimodels.TaskEndDetail{Status: evergreen.TaskFailed}
+	assert.NoError(MarkEnd(testTask.Id, "test", time.Now(), &details, p, false, &updates))
+	dbTask, err := task.FindOne(task.ById(testTask.Id))
+	require.NoError(t, err)
+	require.NotNil(t, dbTask)
+	assert.Equal(evergreen.TaskUndispatched, dbTask.Status)
+	assert.Equal(1, dbTask.Execution)
+	assert.Equal(1, dbTask.AutoRetries)
+	assert.Equal(0, dbTask.Restarts)
+	assert.True(updates.TaskRetried)
+	oldTask, err := task.FindOneOld(task.ById(fmt.Sprintf("%s_0", testTask.Id)))
+	require.NoError(t, err)
+	require.NotNil(t, oldTask)
+	assert.Equal(evergreen.TaskFailed, oldTask.Status)
+
+	// the last attempt's failure is final
+	assert.NoError(dbTask.MarkStart(time.Now()))
+	details = apimodels.TaskEndDetail{Status: evergreen.TaskFailed}
+	updates = StatusChanges{}
+	assert.NoError(MarkEnd(testTask.Id, "test", time.Now(), &details, p, false, &updates))
+	assert.False(updates.TaskRetried)
+	dbTask, err = task.FindOne(task.ById(testTask.Id))
+	require.NoError(t, err)
+	require.NotNil(t, dbTask)
+	assert.Equal(evergreen.TaskFailed, dbTask.Status)
+	assert.Equal(1, dbTask.Execution)
+	assert.Equal(evergreen.BuildFailed, updates.BuildNewStatus)
+}
+
+func TestShouldAutoRetry(t *testing.T) {
+	assert := assert.New(t) //nolint
+	p := &Project{
+		Tasks: []ProjectTask{
+			{Name: "compile"},
+			{Name: "test", AutoRetry: &AutoRetry{MaxAttempts: 3, On: []string{AutoRetryOnSystemFailed, AutoRetryOnTimeout}}},
+		},
+		BuildVariants: []BuildVariant{
+			{
+				Name:      "bv",
+				AutoRetry: &AutoRetry{MaxAttempts: 2, OnlyRequesters: []string{evergreen.PatchVersionRequester}},
+				Tasks: []BuildVariantTaskUnit{
+					{Name: "compile"},
+					{Name: "test"},
+				},
+			},
+			{
+				Name:  "bv2",
+				Tasks: []BuildVariantTaskUnit{{Name: "compile"}, {Name: "test", AutoRetry: &AutoRetry{MaxAttempts: 1}}},
+			},
+		},
+	}
+	assert.Equal(2, p.FindAutoRetry("compile", "bv").MaxAttempts)
+	assert.Equal(3, p.FindAutoRetry("test", "bv").MaxAttempts)
+	assert.Nil(p.FindAutoRetry("compile", "bv2"))
+	assert.Equal(1, p.FindAutoRetry("test", "bv2").MaxAttempts)
+
+	failed := func(name, variant, requester string, detail apimodels.TaskEndDetail) *task.Task {
+		return &task.Task{
+			DisplayName:  name,
+			BuildVariant: variant,
+			Requester:    requester,
+			Status:       evergreen.TaskFailed,
+			Details:      detail,
+		}
+	}
+	assert.True(shouldAutoRetry(failed("compile", "bv", evergreen.PatchVersionRequester, apimodels.TaskEndDetail{}), p))
+	assert.False(shouldAutoRetry(failed("compile", "bv", evergreen.RepotrackerVersionRequester, apimodels.TaskEndDetail{}), p))
+	assert.False(shouldAutoRetry(failed("compile", "bv2", evergreen.PatchVersionRequester, apimodels.TaskEndDetail{}), p))
+	assert.False(shouldAutoRetry(failed("test", "bv2", evergreen.PatchVersionRequester, apimodels.TaskEndDetail{}), p))
+	assert.False(shouldAutoRetry(failed("test", "bv", "", apimodels.TaskEndDetail{Type: TestCommandType}), p))
+	assert.True(shouldAutoRetry(failed("test", "bv", "", apimodels.TaskEndDetail{Type: SystemCommandType}), p))
+	assert.True(shouldAutoRetry(failed("test", "bv", "", apimodels.TaskEndDetail{Type: TestCommandType, TimedOut: true}), p))
+
+	retried := failed("test", "bv", "", apimodels.TaskEndDetail{TimedOut: true})
+	retried.AutoRetries = 1
+	assert.True(shouldAutoRetry(retried, p))
+	retried.AutoRetries = 2
+	assert.False(shouldAutoRetry(retried, p))
+
+	succeeded := failed("compile", "bv", evergreen.PatchVersionRequester, apimodels.TaskEndDetail{})
+	succeeded.Status = evergreen.TaskSucceeded
+	assert.False(shouldAutoRetry(succeeded, p))
+	assert.False(shouldAutoRetry(failed("compile", "bv", evergreen.PatchVersionRequester, apimodels.TaskEndDetail{}), nil))
+}
+
 func TestTryResetTask(t *testing.T) {
 	Convey("With a task, a build, version and a project", t, func() {
 		Convey("resetting a task without a max number of executions", func() {
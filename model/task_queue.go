@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
@@ -19,6 +20,14 @@ type TaskQueue struct {
 	Id     bson.ObjectId   `bson:"_id,omitempty" json:"_id"`
 	Distro string          `bson:"distro" json:"distro"`
 	Queue  []TaskQueueItem `bson:"queue" json:"queue"`
+
+	// PinnedTasks and HeldTasks are manual overrides set by an
+	// administrator. Pinned tasks are placed at the front of the queue, in
+	// the order they were pinned, and held tasks are left out of the queue
+	// entirely until they are released. The scheduler only rewrites the
+	// queue itself, so overrides persist across scheduler passes.
+	PinnedTasks []string `bson:"pinned_tasks,omitempty" json:"pinned_tasks,omitempty"`
+	HeldTasks   []string `bson:"held_tasks,omitempty" json:"held_tasks,omitempty"`
 }
 
 type TaskDep struct {
@@ -45,6 +54,8 @@ var (
 	taskQueueIdKey     = bsonutil.MustHaveTag(TaskQueue{}, "Id")
 	taskQueueDistroKey = bsonutil.MustHaveTag(TaskQueue{}, "Distro")
 	taskQueueQueueKey  = bsonutil.MustHaveTag(TaskQueue{}, "Queue")
+	taskQueuePinnedKey = bsonutil.MustHaveTag(TaskQueue{}, "PinnedTasks")
+	taskQueueHeldKey   = bsonutil.MustHaveTag(TaskQueue{}, "HeldTasks")
 
 	// bson fields for the individual task queue items
 	taskQueueItemIdKey           = bsonutil.MustHaveTag(TaskQueueItem{}, "Id")
@@ -69,6 +80,13 @@ func LoadTaskQueue(distro string) (TaskQueueAccessor, error) {
 	return findTaskQueueForDistro(distro)
 }
 
+// FindTaskQueueForDistro returns the task queue document, including any
+// manual overrides, for the given distro. It returns nil if the distro has
+// no queue.
+func FindTaskQueueForDistro(distroId string) (*TaskQueue, error) {
+	return findTaskQueueForDistro(distroId)
+}
+
 func (self *TaskQueue) Length() int {
 	return len(self.Queue)
 }
@@ -208,6 +226,7 @@ func (self *TaskQueue) DequeueTask(taskId string) error {
 			taskId, self.Distro)
 	}
 
+	// a pin only applies until the task is dispatched
 	return errors.WithStack(db.Update(
 		TaskQueuesCollection,
 		bson.M{
@@ -218,7 +237,111 @@ func (self *TaskQueue) DequeueTask(taskId string) error {
 				taskQueueQueueKey: bson.M{
 					taskQueueItemIdKey: taskId,
 				},
+				taskQueuePinnedKey: taskId,
 			},
 		},
 	))
 }
+
+// IsPinned returns true if the task has been pinned to the front of the queue.
+func (self *TaskQueue) IsPinned(taskId string) bool {
+	return util.StringSliceContains(self.PinnedTasks, taskId)
+}
+
+// IsHeld returns true if the task has been held out of the queue.
+func (self *TaskQueue) IsHeld(taskId string) bool {
+	return util.StringSliceContains(self.HeldTasks, taskId)
+}
+
+// PinTaskInQueue marks the task to be placed at the front of the distro's
+// queue on subsequent scheduler passes, releasing any hold on it. If the
+// task is already in the saved queue, it is moved to the front immediately.
+func PinTaskInQueue(distroId, taskId string) error {
+	_, err := db.Upsert(
+		TaskQueuesCollection,
+		bson.M{
+			taskQueueDistroKey: distroId,
+		},
+		bson.M{
+			"$addToSet": bson.M{taskQueuePinnedKey: taskId},
+			"$pull":     bson.M{taskQueueHeldKey: taskId},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "problem pinning task %s in queue for distro %s", taskId, distroId)
+	}
+
+	taskQueue, err := findTaskQueueForDistro(distroId)
+	if err != nil {
+		return errors.Wrapf(err, "problem finding task queue for distro %s", distroId)
+	}
+	if taskQueue == nil {
+		return nil
+	}
+
+	queue := make([]TaskQueueItem, 0, len(taskQueue.Queue))
+	pinned := make(map[string]TaskQueueItem)
+	for _, it := range taskQueue.Queue {
+		if taskQueue.IsPinned(it.Id) {
+			pinned[it.Id] = it
+			continue
+		}
+		queue = append(queue, it)
+	}
+	if len(pinned) == 0 {
+		return nil
+	}
+
+	front := make([]TaskQueueItem, 0, len(pinned))
+	for _, id := range taskQueue.PinnedTasks {
+		if it, ok := pinned[id]; ok {
+			front = append(front, it)
+		}
+	}
+
+	return errors.WithStack(updateTaskQueue(distroId, append(front, queue...)))
+}
+
+// HoldTaskInQueue keeps the task out of the distro's queue, so that it is not
+// dispatched, until it is released. Any pin on the task is removed.
+func HoldTaskInQueue(distroId, taskId string) error {
+	_, err := db.Upsert(
+		TaskQueuesCollection,
+		bson.M{
+			taskQueueDistroKey: distroId,
+		},
+		bson.M{
+			"$addToSet": bson.M{taskQueueHeldKey: taskId},
+			"$pull": bson.M{
+				taskQueuePinnedKey: taskId,
+				taskQueueQueueKey: bson.M{
+					taskQueueItemIdKey: taskId,
+				},
+			},
+		},
+	)
+
+	return errors.Wrapf(err, "problem holding task %s in queue for distro %s", taskId, distroId)
+}
+
+// ReleaseTaskInQueue removes any pin or hold on the task, returning it to its
+// normal position on the next scheduler pass.
+func ReleaseTaskInQueue(distroId, taskId string) error {
+	err := db.Update(
+		TaskQueuesCollection,
+		bson.M{
+			taskQueueDistroKey: distroId,
+		},
+		bson.M{
+			"$pull": bson.M{
+				taskQueuePinnedKey: taskId,
+				taskQueueHeldKey:   taskId,
+			},
+		},
+	)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+
+	return errors.Wrapf(err, "problem releasing task %s in queue for distro %s", taskId, distroId)
+}
@@ -104,3 +104,51 @@ func TestFindTask(t *testing.T) {
 	assert.Equal("six", q.FindTask(TaskSpec{Group: "bar", ProjectID: "aa", Version: "bb", BuildVariant: "a"}).Id)
 	assert.Equal("two", q.FindTask(TaskSpec{Group: "bar", ProjectID: "a", Version: "b", BuildVariant: "a"}).Id)
 }
+
+func TestTaskQueueOverrides(t *testing.T) {
+	assert := assert.New(t) // nolint
+
+	assert.NoError(db.Clear(TaskQueuesCollection))
+	q := &TaskQueue{
+		Distro: "d1",
+		Queue:  []TaskQueueItem{{Id: "one"}, {Id: "two"}, {Id: "three"}},
+	}
+	assert.NoError(q.Save())
+
+	// pinning moves the task to the front of the saved queue
+	assert.NoError(PinTaskInQueue("d1", "three"))
+	q, err := FindTaskQueueForDistro("d1")
+	assert.NoError(err)
+	assert.Equal([]string{"three"}, q.PinnedTasks)
+	assert.Equal("three", q.Queue[0].Id)
+	assert.Equal("one", q.Queue[1].Id)
+
+	// holding removes the task from the queue and its pin
+	assert.NoError(HoldTaskInQueue("d1", "three"))
+	q, err = FindTaskQueueForDistro("d1")
+	assert.NoError(err)
+	assert.Empty(q.PinnedTasks)
+	assert.True(q.IsHeld("three"))
+	assert.Len(q.Queue, 2)
+
+	// saving a new queue keeps the overrides
+	q.Queue = []TaskQueueItem{{Id: "one"}}
+	assert.NoError(q.Save())
+	q, err = FindTaskQueueForDistro("d1")
+	assert.NoError(err)
+	assert.True(q.IsHeld("three"))
+
+	assert.NoError(ReleaseTaskInQueue("d1", "three"))
+	q, err = FindTaskQueueForDistro("d1")
+	assert.NoError(err)
+	assert.False(q.IsHeld("three"))
+
+	// dispatching a pinned task clears its pin
+	assert.NoError(PinTaskInQueue("d1", "one"))
+	q, err = FindTaskQueueForDistro("d1")
+	assert.NoError(err)
+	assert.NoError(q.DequeueTask("one"))
+	q, err = FindTaskQueueForDistro("d1")
+	assert.NoError(err)
+	assert.Empty(q.PinnedTasks)
+}
@@ -22,6 +22,7 @@ var (
 	SettingsKey     = bsonutil.MustHaveTag(DBUser{}, "Settings")
 	APIKeyKey       = bsonutil.MustHaveTag(DBUser{}, "APIKey")
 	PubKeysKey      = bsonutil.MustHaveTag(DBUser{}, "PubKeys")
+	TeamKey         = bsonutil.MustHaveTag(DBUser{}, "Team")
 )
 
 var (
@@ -46,6 +47,11 @@ func ByIds(userIds ...string) db.Q {
 	})
 }
 
+// ByTeam returns a query for all users on the given team.
+func ByTeam(team string) db.Q {
+	return db.Query(bson.M{TeamKey: team})
+}
+
 // FindOne gets one DBUser for the given query.
 func FindOne(query db.Q) (*DBUser, error) {
 	u := &DBUser{}
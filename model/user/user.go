@@ -21,6 +21,7 @@ type DBUser struct {
 	CreatedAt    time.Time    `bson:"created_at"`
 	Settings     UserSettings `bson:"settings"`
 	APIKey       string       `bson:"apikey"`
+	Team         string       `bson:"team,omitempty" json:"team,omitempty"`
 }
 
 type PubKey struct {
@@ -88,6 +89,21 @@ func (u *DBUser) AddPublicKey(keyName, keyValue string) error {
 	return nil
 }
 
+// SetTeam assigns the user to the given team, or removes the user from
+// their team if it is empty.
+func (u *DBUser) SetTeam(team string) error {
+	update := bson.M{"$set": bson.M{TeamKey: team}}
+	if team == "" {
+		update = bson.M{"$unset": bson.M{TeamKey: 1}}
+	}
+	if err := UpdateOne(bson.M{IdKey: u.Id}, update); err != nil {
+		return errors.Wrapf(err, "error setting team for user '%s'", u.Id)
+	}
+
+	u.Team = team
+	return nil
+}
+
 func (u *DBUser) DeletePublicKey(keyName string) error {
 	newUser := DBUser{}
 
@@ -117,3 +117,22 @@ func (s *UserTestSuite) TestDeletePublicKeyThatDoesntExist() {
 	s.NoError(err)
 	s.checkUserNotDestroyed(u, s.users[0])
 }
+
+func (s *UserTestSuite) TestSetTeam() {
+	s.NoError(s.users[0].SetTeam("team0"))
+	s.Equal("team0", s.users[0].Team)
+
+	u, err := FindOne(ById(s.users[0].Id))
+	s.NoError(err)
+	s.Equal("team0", u.Team)
+	s.checkUserNotDestroyed(u, s.users[0])
+
+	users, err := Find(ByTeam("team0"))
+	s.NoError(err)
+	s.Len(users, 1)
+
+	s.NoError(s.users[0].SetTeam(""))
+	u, err = FindOne(ById(s.users[0].Id))
+	s.NoError(err)
+	s.Empty(u.Team)
+}
@@ -40,6 +40,8 @@ var (
 	IdentifierKey          = bsonutil.MustHaveTag(Version{}, "Identifier")
 	RemoteKey              = bsonutil.MustHaveTag(Version{}, "Remote")
 	RemoteURLKey           = bsonutil.MustHaveTag(Version{}, "RemotePath")
+	ConfigUpdateNumberKey  = bsonutil.MustHaveTag(Version{}, "ConfigUpdateNumber")
+	GeneratedByKey         = bsonutil.MustHaveTag(Version{}, "GeneratedBy")
 )
 
 // ById returns a db.Q object which will filter on {_id : <the id param>}
@@ -5,7 +5,9 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/mongodb/anser/bsonutil"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -45,6 +47,15 @@ type Version struct {
 	// this field is omitted in the database
 	Errors   []string `bson:"errors,omitempty" json:"errors,omitempty"`
 	Warnings []string `bson:"warnings,omitempty" json:"warnings,omitempty"`
+	// Parameters are the values of the project's parameters for this
+	// version, which are available to its tasks as expansions.
+	Parameters []patch.Parameter `bson:"parameters,omitempty" json:"parameters,omitempty"`
+
+	// ConfigUpdateNumber is incremented each time tasks generated at runtime
+	// are added to Config, and GeneratedBy lists the tasks that generated
+	// them.
+	ConfigUpdateNumber int      `bson:"config_number,omitempty" json:"config_number,omitempty"`
+	GeneratedBy        []string `bson:"generated_by,omitempty" json:"generated_by,omitempty"`
 }
 
 func (self *Version) UpdateBuildVariants() error {
@@ -58,6 +69,31 @@ func (self *Version) UpdateBuildVariants() error {
 	)
 }
 
+// AddGeneratedConfig replaces the version's config with one that includes
+// the tasks generated by the given task. It returns false without updating
+// the version if its config has been updated since the version was read.
+func (self *Version) AddGeneratedConfig(config, generatorId string) (bool, error) {
+	query := bson.M{IdKey: self.Id, ConfigUpdateNumberKey: self.ConfigUpdateNumber}
+	if self.ConfigUpdateNumber == 0 {
+		query[ConfigUpdateNumberKey] = bson.M{"$in": []interface{}{0, nil}}
+	}
+	err := UpdateOne(query, bson.M{
+		"$set":      bson.M{ConfigKey: config},
+		"$inc":      bson.M{ConfigUpdateNumberKey: 1},
+		"$addToSet": bson.M{GeneratedByKey: generatorId},
+	})
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	self.Config = config
+	self.ConfigUpdateNumber++
+	self.GeneratedBy = append(self.GeneratedBy, generatorId)
+	return true, nil
+}
+
 func (self *Version) Insert() error {
 	return db.Insert(Collection, self)
 }
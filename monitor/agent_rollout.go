@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/notify"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	rolloutWait     = "wait"
+	rolloutAdvance  = "advance"
+	rolloutRollBack = "roll back"
+)
+
+// monitorAgentRollouts is a hostMonitoringFunc responsible for moving active
+// agent rollouts through their stages, rolling them back if the hosts running
+// the new revision system fail too many tasks.
+func monitorAgentRollouts(settings *evergreen.Settings) []error {
+	conf, err := evergreen.GetConfig()
+	if err != nil {
+		return []error{errors.Wrap(err, "error getting agent rollout config")}
+	}
+	rolloutConf := conf.AgentRollout.WithDefaults()
+
+	rollouts, err := host.FindAgentRollouts(host.AgentRolloutsByStatus(host.AgentRolloutActive))
+	if err != nil {
+		return []error{errors.Wrap(err, "error finding active agent rollouts")}
+	}
+
+	var errs []error
+	for i := range rollouts {
+		r := &rollouts[i]
+		health, err := r.Health()
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting health of agent rollout %s", r.ID))
+			continue
+		}
+
+		stageHosts, err := r.StageHosts(rolloutConf)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting hosts of agent rollout %s", r.ID))
+			continue
+		}
+
+		step, reason := nextRolloutStep(r, health, stageHosts, rolloutConf, time.Now())
+		msg := message.Fields{
+			"runner":    RunnerName,
+			"operation": "monitorAgentRollouts",
+			"distro":    r.Distro,
+			"revision":  r.Revision,
+			"stage":     r.Stage,
+			"tasks":     health.Tasks,
+			"failures":  health.SystemFailures,
+		}
+
+		switch step {
+		case rolloutAdvance:
+			msg["message"] = "advancing agent rollout"
+			grip.Info(msg)
+			if err = r.Advance(len(rolloutConf.Stages)); err != nil {
+				errs = append(errs, err)
+			}
+		case rolloutRollBack:
+			msg["message"] = "rolling back agent rollout"
+			msg["reason"] = reason
+			grip.Warning(msg)
+			if err = r.RollBack(reason); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			subject := fmt.Sprintf("Agent revision %s rolled back on distro %s", r.Revision, r.Distro)
+			grip.Error(message.WrapError(notify.NotifyAdmins(subject, reason, settings), message.Fields{
+				"runner":    RunnerName,
+				"operation": "monitorAgentRollouts",
+				"message":   "problem notifying admins of agent rollback",
+				"distro":    r.Distro,
+			}))
+		}
+	}
+
+	return errs
+}
+
+// nextRolloutStep decides whether the rollout should wait, advance to its
+// next stage or be rolled back. Nothing is decided until the hosts running the
+// new revision have finished enough tasks at the current stage. A stage that
+// gives the new revision to none of the distro's hosts cannot gather tasks, so
+// it advances once its time is up.
+func nextRolloutStep(r *host.AgentRollout, health *host.Health, stageHosts int, conf evergreen.AgentRolloutConfig, now time.Time) (string, string) {
+	soaked := now.Sub(r.StageStartTime) >= time.Duration(conf.StageMinutes)*time.Minute
+	if health.Tasks < conf.MinTasks {
+		if stageHosts == 0 && soaked {
+			return rolloutAdvance, ""
+		}
+		return rolloutWait, ""
+	}
+	if health.SystemFailureRate() > conf.MaxSystemFailureRate {
+		return rolloutRollBack, fmt.Sprintf("%d of %d tasks on hosts running agent revision %s system failed",
+			health.SystemFailures, health.Tasks, r.Revision)
+	}
+	if !soaked {
+		return rolloutWait, ""
+	}
+	return rolloutAdvance, ""
+}
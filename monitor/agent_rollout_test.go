@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRolloutStep(t *testing.T) {
+	assert := assert.New(t)
+	conf := evergreen.AgentRolloutConfig{}.WithDefaults()
+	now := time.Now()
+	r := &host.AgentRollout{Revision: "new", StageStartTime: now.Add(-2 * time.Hour)}
+
+	// too few tasks to decide
+	step, _ := nextRolloutStep(r, &host.Health{Tasks: conf.MinTasks - 1, SystemFailures: conf.MinTasks - 1}, 1, conf, now)
+	assert.Equal(rolloutWait, step)
+
+	step, reason := nextRolloutStep(r, &host.Health{Tasks: 20, SystemFailures: 5}, 1, conf, now)
+	assert.Equal(rolloutRollBack, step)
+	assert.Contains(reason, "5 of 20 tasks")
+
+	step, _ = nextRolloutStep(r, &host.Health{Tasks: 20, SystemFailures: 1}, 1, conf, now)
+	assert.Equal(rolloutAdvance, step)
+
+	// healthy, but the stage has not soaked long enough
+	r.StageStartTime = now.Add(-time.Minute)
+	step, _ = nextRolloutStep(r, &host.Health{Tasks: 20}, 1, conf, now)
+	assert.Equal(rolloutWait, step)
+
+	// a stage that gives no host the new revision advances once its time is
+	// up, rather than waiting for tasks that cannot run
+	step, _ = nextRolloutStep(r, &host.Health{}, 0, conf, now)
+	assert.Equal(rolloutWait, step)
+	r.StageStartTime = now.Add(-2 * time.Hour)
+	step, _ = nextRolloutStep(r, &host.Health{}, 0, conf, now)
+	assert.Equal(rolloutAdvance, step)
+	step, _ = nextRolloutStep(r, &host.Health{}, 1, conf, now)
+	assert.Equal(rolloutWait, step)
+}
@@ -84,6 +84,10 @@ func flagIdleHosts(d []distro.Distro, s *evergreen.Settings) ([]host.Host, error
 		return nil, errors.Wrap(err, "error finding free hosts")
 	}
 
+	// hosts of distros with a warm pool can only be terminated once the
+	// distro has more free hosts than its warm pool needs
+	excessWarmHosts := excessWarmPoolHosts(d, freeHosts)
+
 	// go through the hosts, and see if they have idled long enough to
 	// be terminated
 	for _, freeHost := range freeHosts {
@@ -131,6 +135,19 @@ func flagIdleHosts(d []distro.Distro, s *evergreen.Settings) ([]host.Host, error
 
 		// if we haven't heard from the host or it's been idle for longer than the cutoff, we should flag.
 		if communicationTime >= idleTimeCutoff || idleTime >= idleTimeCutoff {
+			if excess, ok := excessWarmHosts[freeHost.Distro.Id]; ok {
+				if excess <= 0 {
+					grip.Debug(message.Fields{
+						"runner":  RunnerName,
+						"message": "not flagging idle host, it is part of the distro's warm pool",
+						"host":    freeHost.Id,
+						"distro":  freeHost.Distro.Id,
+						"idle":    idleTime.String(),
+					})
+					continue
+				}
+				excessWarmHosts[freeHost.Distro.Id]--
+			}
 			idleHosts = append(idleHosts, freeHost)
 		}
 	}
@@ -138,6 +155,23 @@ func flagIdleHosts(d []distro.Distro, s *evergreen.Settings) ([]host.Host, error
 	return idleHosts, nil
 }
 
+// excessWarmPoolHosts returns, for each distro with a warm pool, how many of
+// its free hosts exceed the warm pool's target size.
+func excessWarmPoolHosts(distros []distro.Distro, freeHosts []host.Host) map[string]int {
+	excess := make(map[string]int)
+	for _, d := range distros {
+		if d.WarmPoolSize > 0 {
+			excess[d.Id] = -d.WarmPoolSize
+		}
+	}
+	for _, h := range freeHosts {
+		if _, ok := excess[h.Distro.Id]; ok {
+			excess[h.Distro.Id]++
+		}
+	}
+	return excess
+}
+
 // flagExcessHosts is a hostFlaggingFunc to get all hosts that push their
 // distros over the specified max hosts
 func flagExcessHosts(distros []distro.Distro, s *evergreen.Settings) ([]host.Host, error) {
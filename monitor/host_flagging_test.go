@@ -12,6 +12,7 @@ import (
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/evergreen/util"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestFlaggingDecommissionedHosts(t *testing.T) {
@@ -635,3 +636,25 @@ func TestFlaggingExpiredHosts(t *testing.T) {
 	})
 
 }
+
+func TestExcessWarmPoolHosts(t *testing.T) {
+	assert := assert.New(t)
+
+	distros := []distro.Distro{
+		{Id: "cold"},
+		{Id: "warm", WarmPoolSize: 2},
+		{Id: "short", WarmPoolSize: 3},
+	}
+	freeHosts := []host.Host{
+		{Id: "c1", Distro: distro.Distro{Id: "cold"}},
+		{Id: "w1", Distro: distro.Distro{Id: "warm"}},
+		{Id: "w2", Distro: distro.Distro{Id: "warm"}},
+		{Id: "w3", Distro: distro.Distro{Id: "warm"}},
+		{Id: "s1", Distro: distro.Distro{Id: "short"}},
+	}
+
+	excess := excessWarmPoolHosts(distros, freeHosts)
+	assert.Len(excess, 2)
+	assert.Equal(1, excess["warm"])
+	assert.Equal(-2, excess["short"])
+}
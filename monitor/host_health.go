@@ -0,0 +1,63 @@
+package monitor
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/units"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// monitorStaticHostHealth is a hostMonitoringFunc responsible for quarantining
+// static hosts whose recent tasks or setup attempts have failed too often.
+func monitorStaticHostHealth(settings *evergreen.Settings) []error {
+	conf, err := evergreen.GetConfig()
+	if err != nil {
+		return []error{errors.Wrap(err, "error getting host health config")}
+	}
+	healthConf := conf.HostHealth.WithDefaults()
+	if healthConf.QuarantineDisabled {
+		return nil
+	}
+
+	hosts, err := host.Find(host.RunningStatic)
+	if err != nil {
+		return []error{errors.Wrap(err, "error finding running static hosts")}
+	}
+
+	env := evergreen.GetEnvironment()
+	var errs []error
+	for i := range hosts {
+		h := &hosts[i]
+		health, err := host.GetHealth(h.Id, healthConf.Window)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting health of host %s", h.Id))
+			continue
+		}
+
+		reason := health.QuarantineReason(healthConf)
+		if reason == "" {
+			continue
+		}
+
+		grip.Warning(message.Fields{
+			"runner":    RunnerName,
+			"operation": "monitorStaticHostHealth",
+			"message":   "quarantining unhealthy static host",
+			"host":      h.Id,
+			"distro":    h.Distro.Id,
+			"reason":    reason,
+		})
+
+		if err = h.Quarantine(reason); err != nil {
+			errs = append(errs, errors.Wrapf(err, "error quarantining host %s", h.Id))
+			continue
+		}
+
+		job := units.NewDecoHostNotifyJob(env, h, nil, "host quarantined: "+reason)
+		grip.Critical(env.LocalQueue().Put(job))
+	}
+
+	return errs
+}
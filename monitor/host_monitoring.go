@@ -89,6 +89,11 @@ func monitorReachability(settings *evergreen.Settings) []error {
 
 // check reachability for a single host, and take any necessary action
 func checkHostReachability(host host.Host, settings *evergreen.Settings) error {
+	// stopped spawn hosts are not expected to be reachable
+	if host.Status == evergreen.HostStopped {
+		return nil
+	}
+
 	grip.Info(message.Fields{
 		"runner":    RunnerName,
 		"operation": "monitorReachability",
@@ -0,0 +1,172 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/hostutil"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// maxSSHKeyRotations is the most hosts whose keys are rotated in one pass, so
+// that rotation does not hold up the rest of the monitor.
+const maxSSHKeyRotations = 50
+
+// sshCommandRunner runs a command on a host over SSH, as
+// hostutil.RunSSHCommand does.
+type sshCommandRunner func(ctx context.Context, cmd string, sshOptions []string, h host.Host) (string, error)
+
+// sshKeyStore saves the SSH keys of a host.
+type sshKeyStore interface {
+	SetPendingSSHKey(*host.Host, *host.HostSSHKey) error
+	SetSSHKey(*host.Host, *host.HostSSHKey) error
+}
+
+// dbSSHKeyStore saves SSH keys in the database.
+type dbSSHKeyStore struct{}
+
+func (dbSSHKeyStore) SetPendingSSHKey(h *host.Host, key *host.HostSSHKey) error {
+	return h.SetPendingSSHKey(key)
+}
+
+func (dbSSHKeyStore) SetSSHKey(h *host.Host, key *host.HostSSHKey) error {
+	return h.SetSSHKey(key)
+}
+
+// monitorHostSSHKeys is a hostMonitoringFunc responsible for replacing the
+// SSH keys of running hosts once they are older than the rotation interval.
+func monitorHostSSHKeys(settings *evergreen.Settings) []error {
+	conf := settings.HostSSHKeys.WithDefaults()
+	if !conf.Enabled {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-time.Duration(conf.RotationHours) * time.Hour)
+	hosts, err := host.Find(host.ByRunningWithSSHKeyOlderThan(cutoff).Limit(maxSSHKeyRotations))
+	if err != nil {
+		return []error{errors.Wrap(err, "error finding hosts with SSH keys to rotate")}
+	}
+
+	var errs []error
+	for i := range hosts {
+		h := &hosts[i]
+		if err = rotateHostSSHKey(context.Background(), h, settings, hostutil.RunSSHCommand, dbSSHKeyStore{}); err != nil {
+			errs = append(errs, errors.Wrapf(err, "error rotating SSH key of host %s", h.Id))
+			continue
+		}
+
+		grip.Info(message.Fields{
+			"runner":    RunnerName,
+			"operation": "monitorHostSSHKeys",
+			"message":   "rotated host SSH key",
+			"host":      h.Id,
+			"distro":    h.Distro.Id,
+		})
+	}
+
+	return errs
+}
+
+// rotateHostSSHKey replaces the host's SSH key. The new key is saved as the
+// host's pending key before it is authorized on the host, and becomes the
+// host's key only once it is shown to work. The old key and the distro's key
+// are revoked only after that is saved, so the database always holds a key
+// that is authorized on the host.
+func rotateHostSSHKey(ctx context.Context, h *host.Host, settings *evergreen.Settings, run sshCommandRunner, store sshKeyStore) error {
+	encryptionKey, err := settings.HostSSHKeys.Key()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	newKey, err := host.NewHostSSHKey(encryptionKey)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	newKey.Installed = true
+
+	// a pending key left by an earlier rotation that did not finish may
+	// still be authorized on the host
+	revoked := []string{h.SSHKey.PublicKey}
+	if h.PendingSSHKey != nil {
+		revoked = append(revoked, h.PendingSSHKey.PublicKey)
+	}
+	if err = store.SetPendingSSHKey(h, newKey); err != nil {
+		return errors.Wrap(err, "error saving pending key")
+	}
+
+	sshOptions, err := hostSSHOptions(h, settings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if output, err := run(ctx, hostutil.AuthorizeSSHKeyCommand(newKey.PublicKey), sshOptions, *h); err != nil {
+		return errors.Wrapf(err, "error authorizing new key: %s", output)
+	}
+
+	oldHost := *h
+	rotated := *h
+	rotated.SSHKey = newKey
+	removeUnused := func() {
+		grip.Warning(message.WrapError(cloud.RemoveHostKeyFile(&rotated, settings), message.Fields{
+			"runner":    RunnerName,
+			"operation": "monitorHostSSHKeys",
+			"message":   "problem removing unused SSH key file",
+			"host":      h.Id,
+		}))
+	}
+	sshOptions, err = hostSSHOptions(&rotated, settings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if output, err := run(ctx, "true", sshOptions, rotated); err != nil {
+		removeUnused()
+		return errors.Wrapf(err, "error connecting with the new key: %s", output)
+	}
+	if err = store.SetSSHKey(h, newKey); err != nil {
+		removeUnused()
+		return errors.Wrap(err, "error saving new key")
+	}
+	grip.Warning(message.WrapError(cloud.RemoveHostKeyFile(&oldHost, settings), message.Fields{
+		"runner":    RunnerName,
+		"operation": "monitorHostSSHKeys",
+		"message":   "problem removing old SSH key file",
+		"host":      h.Id,
+	}))
+
+	// the distro's key is revoked along with the old key in case it is still
+	// authorized on the host
+	distroKey, err := hostutil.DistroPublicKey(settings, &h.Distro)
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"runner":    RunnerName,
+			"operation": "monitorHostSSHKeys",
+			"message":   "problem getting distro SSH key to revoke",
+			"host":      h.Id,
+			"distro":    h.Distro.Id,
+		}))
+	} else if distroKey != "" {
+		revoked = append(revoked, distroKey)
+	}
+	if output, err := run(ctx, hostutil.RevokeSSHKeyCommand(revoked...), sshOptions, rotated); err != nil {
+		return errors.Wrapf(err, "error revoking old key with the new key: %s", output)
+	}
+
+	return nil
+}
+
+// hostSSHOptions returns the options for connecting to the host with its own
+// key, and errors rather than falling back to the distro's key.
+func hostSSHOptions(h *host.Host, settings *evergreen.Settings) ([]string, error) {
+	cloudHost, err := cloud.GetCloudHost(h, settings)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get cloud host for %s", h.Id)
+	}
+	if !cloudHost.UsesHostSSHKey() {
+		return nil, errors.Errorf("host %s cannot be connected to with its own SSH key", h.Id)
+	}
+	sshOptions, err := cloudHost.GetSSHOptions()
+	return sshOptions, errors.Wrapf(err, "error getting ssh options for host %s", h.Id)
+}
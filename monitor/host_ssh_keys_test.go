@@ -0,0 +1,152 @@
+package monitor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/hostutil"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSSHKeyStore saves keys on the host without a database.
+type mockSSHKeyStore struct {
+	failSet bool
+}
+
+func (s *mockSSHKeyStore) SetPendingSSHKey(h *host.Host, key *host.HostSSHKey) error {
+	h.PendingSSHKey = key
+	return nil
+}
+
+func (s *mockSSHKeyStore) SetSSHKey(h *host.Host, key *host.HostSSHKey) error {
+	if s.failSet {
+		return errors.New("database is unavailable")
+	}
+	h.SSHKey = key
+	h.PendingSSHKey = nil
+	return nil
+}
+
+func TestRotateHostSSHKey(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "evergreen-host-keys-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	encryptionKey := []byte("0123456789abcdef0123456789abcdef")
+	settings := &evergreen.Settings{
+		HostSSHKeys: evergreen.HostSSHKeysConfig{
+			Enabled:       true,
+			EncryptionKey: base64.StdEncoding.EncodeToString(encryptionKey),
+			KeyDirectory:  dir,
+		},
+	}
+	oldKey, err := host.NewHostSSHKey(encryptionKey)
+	require.NoError(t, err)
+	oldKey.Installed = true
+	newHost := func() *host.Host {
+		return &host.Host{Id: "h1", Provider: evergreen.HostTypeStatic, SSHKey: oldKey}
+	}
+
+	type call struct {
+		cmd     string
+		keyPath string
+	}
+	var calls []call
+	failCall := 0
+	run := func(_ context.Context, cmd string, sshOptions []string, _ host.Host) (string, error) {
+		require.Len(t, sshOptions, 2)
+		calls = append(calls, call{cmd: cmd, keyPath: sshOptions[1]})
+		if len(calls) == failCall {
+			return "Permission denied", errors.New("exit status 255")
+		}
+		return "", nil
+	}
+
+	h := newHost()
+	store := &mockSSHKeyStore{}
+	require.NoError(t, rotateHostSSHKey(context.Background(), h, settings, run, store))
+	newKey := h.SSHKey
+	assert.True(newKey.Installed)
+	assert.NotEqual(oldKey.PublicKey, newKey.PublicKey)
+	assert.Nil(h.PendingSSHKey)
+	require.Len(t, calls, 3)
+	// the new key is authorized with the old key, checked, then the old key
+	// is revoked with the new one
+	assert.Equal(hostutil.AuthorizeSSHKeyCommand(newKey.PublicKey), calls[0].cmd)
+	assert.Equal("true", calls[1].cmd)
+	assert.Equal(hostutil.RevokeSSHKeyCommand(oldKey.PublicKey), calls[2].cmd)
+	assert.NotEqual(calls[0].keyPath, calls[1].keyPath)
+	assert.Equal(calls[1].keyPath, calls[2].keyPath)
+
+	// if the new key does not work, the old key is kept and nothing is
+	// revoked
+	calls = nil
+	failCall = 2
+	h = newHost()
+	assert.Error(rotateHostSSHKey(context.Background(), h, settings, run, store))
+	assert.Len(calls, 2)
+	assert.Equal(oldKey, h.SSHKey)
+	assert.NotNil(h.PendingSSHKey)
+
+	// a pending key left by the failed rotation is revoked by the next one
+	pending := h.PendingSSHKey
+	calls = nil
+	failCall = 0
+	require.NoError(t, rotateHostSSHKey(context.Background(), h, settings, run, store))
+	require.Len(t, calls, 3)
+	assert.Equal(hostutil.RevokeSSHKeyCommand(oldKey.PublicKey, pending.PublicKey), calls[2].cmd)
+
+	// if the new key cannot be saved, the old key is not revoked, so the
+	// host can still be reached with the saved key
+	calls = nil
+	h = newHost()
+	store.failSet = true
+	assert.Error(rotateHostSSHKey(context.Background(), h, settings, run, store))
+	require.Len(t, calls, 2)
+	for _, c := range calls {
+		assert.NotContains(c.cmd, oldKey.PublicKey)
+	}
+	assert.Equal(oldKey, h.SSHKey)
+	assert.NotNil(h.PendingSSHKey)
+	store.failSet = false
+
+	// the distro's key is revoked along with the old key
+	distroKeyPath := filepath.Join(dir, "distro_key")
+	private, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(distroKeyPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(private),
+	}), 0600))
+	settings.Keys = map[string]string{"distro_key": distroKeyPath}
+	h = newHost()
+	h.Distro.SSHKey = "distro_key"
+	distroKey, err := hostutil.DistroPublicKey(settings, &h.Distro)
+	require.NoError(t, err)
+	calls = nil
+	require.NoError(t, rotateHostSSHKey(context.Background(), h, settings, run, store))
+	require.Len(t, calls, 3)
+	assert.Equal(hostutil.RevokeSSHKeyCommand(oldKey.PublicKey, distroKey), calls[2].cmd)
+
+	// the rotation does not fall back to the distro's key
+	calls = nil
+	h = newHost()
+	settings.HostSSHKeys.EncryptionKey = base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+	os.RemoveAll(dir)
+	assert.Error(rotateHostSSHKey(context.Background(), h, settings, run, store))
+	assert.Empty(calls)
+}
@@ -204,13 +204,9 @@ func terminateHost(ctx context.Context, h *host.Host, settings *evergreen.Settin
 }
 
 func runHostTeardown(ctx context.Context, h *host.Host, cloudHost *cloud.CloudHost) error {
-	sshOptions, err := cloudHost.GetSSHOptions()
-	if err != nil {
-		return errors.Wrapf(err, "error getting ssh options for host %s", h.Id)
-	}
 	startTime := time.Now()
 	// run the teardown script with the agent
-	logs, err := hostutil.RunSSHCommand(ctx, hostutil.TearDownCommand(h), sshOptions, *h)
+	logs, err := cloudHost.RunCommand(ctx, hostutil.TearDownCommand(h))
 	if err != nil {
 		event.LogHostTeardown(h.Id, logs, false, time.Since(startTime))
 		return errors.Wrapf(err, "error running teardown script on remote host: %s", logs)
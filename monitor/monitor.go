@@ -37,6 +37,9 @@ var (
 	// the functions the host monitor will run through to do simpler checks
 	defaultHostMonitoringFuncs = []hostMonitoringFunc{
 		monitorReachability,
+		monitorStaticHostHealth,
+		monitorAgentRollouts,
+		monitorHostSSHKeys,
 	}
 
 	// the functions the notifier will use to build notifications that need
@@ -22,6 +22,8 @@ func Agent() cli.Command {
 		logPrefixFlagName        = "log_prefix"
 		statusPortFlagName       = "status_port"
 		cleanupFlagName          = "cleanup"
+		spotFlagName             = "spot"
+		spotURLFlagName          = "spot_interruption_url"
 	)
 
 	return cli.Command{
@@ -58,6 +60,15 @@ func Agent() cli.Command {
 				Name:  cleanupFlagName,
 				Usage: "clean up working directory and processes (do not set for smoke tests)",
 			},
+			cli.BoolFlag{
+				Name:  spotFlagName,
+				Usage: "watch for a notice that the spot host is being reclaimed",
+			},
+			cli.StringFlag{
+				Name:  spotURLFlagName,
+				Value: "http://169.254.169.254/latest/meta-data/spot/instance-action",
+				Usage: "URL of the spot interruption notice",
+			},
 		},
 		Before: mergeBeforeFuncs(
 			func(c *cli.Context) error {
@@ -78,6 +89,9 @@ func Agent() cli.Command {
 				WorkingDirectory: c.String(workingDirectoryFlagName),
 				Cleanup:          c.Bool(cleanupFlagName),
 			}
+			if c.Bool(spotFlagName) {
+				opts.SpotInterruptionURL = c.String(spotURLFlagName)
+			}
 
 			if err := os.MkdirAll(opts.WorkingDirectory, 0777); err != nil {
 				return errors.Wrapf(err, "problem creating working directory '%s'", opts.WorkingDirectory)
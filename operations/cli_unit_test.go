@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGitCmd(t *testing.T) {
@@ -46,3 +48,20 @@ func TestGetHistoryCreateUrlQuery(t *testing.T) {
 		})
 	})
 }
+
+func TestParsePatchParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	params, err := parsePatchParameters([]string{"suite=core", "args=--jobs=4", "empty="})
+	assert.NoError(err)
+	assert.Equal([]patch.Parameter{
+		{Key: "suite", Value: "core"},
+		{Key: "args", Value: "--jobs=4"},
+		{Key: "empty", Value: ""},
+	}, params)
+
+	_, err = parsePatchParameters([]string{"suite"})
+	assert.Error(err)
+	_, err = parsePatchParameters([]string{"=core"})
+	assert.Error(err)
+}
@@ -14,11 +14,14 @@ func Evaluate() cli.Command {
 	const (
 		taskFlagName     = "tasks"
 		variantsFlagName = "variants"
+		diffFlagName     = "diff"
+		matrixFlagName   = "matrix"
 	)
 
 	return cli.Command{
-		Name:  "evaluate",
-		Usage: "reads a project configuration and expands tags and matrix definitions, printing the expanded definitions",
+		Name:      "evaluate",
+		Usage:     "reads a project configuration and expands tags and matrix definitions, printing the expanded definitions",
+		ArgsUsage: "[--diff <revision> [<revision>]] [--matrix]",
 		Flags: addPathFlag(
 			cli.BoolFlag{
 				Name:  taskFlagName,
@@ -27,6 +30,15 @@ func Evaluate() cli.Command {
 			cli.BoolFlag{
 				Name:  variantsFlagName,
 				Usage: "only show variant definitions",
+			},
+			cli.BoolFlag{
+				Name: diffFlagName,
+				Usage: "show how the expanded definitions changed between two revisions of the " +
+					"configuration, or between a revision and the working tree",
+			},
+			cli.BoolFlag{
+				Name:  matrixFlagName,
+				Usage: "show the variants generated by each matrix, with the spec entries and rules that produced them",
 			}),
 		Before: requirePathFlag,
 		Action: func(c *cli.Context) error {
@@ -34,15 +46,20 @@ func Evaluate() cli.Command {
 			showTasks := c.Bool(taskFlagName)
 			showVariants := c.Bool(variantsFlagName)
 
-			configBytes, err := ioutil.ReadFile(path)
-			if err != nil {
-				return errors.Wrap(err, "error reading project config")
+			if c.Bool(diffFlagName) {
+				revisions := c.Args()
+				if len(revisions) < 1 || len(revisions) > 2 {
+					return errors.New("must specify one or two revisions to compare")
+				}
+				return diffConfigRevisions(path, revisions.Get(0), revisions.Get(1))
+			}
+			if c.Bool(matrixFlagName) {
+				return explainMatrices(path)
 			}
 
-			p := &model.Project{}
-			err = model.LoadProjectInto(configBytes, "", p)
+			p, err := loadLocalConfig(path)
 			if err != nil {
-				return errors.Wrap(err, "error loading project")
+				return errors.WithStack(err)
 			}
 
 			var out interface{}
@@ -74,3 +91,55 @@ func Evaluate() cli.Command {
 		},
 	}
 }
+
+// diffConfigRevisions prints the changes to the expanded project
+// configuration between two revisions. If the second revision is empty, the
+// first is compared to the working tree.
+func diffConfigRevisions(path, before, after string) error {
+	beforeProject, err := loadConfigAtRevision(path, before)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	afterProject, err := loadConfigAtRevision(path, after)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	changes := model.DiffProjects(beforeProject, afterProject)
+	if len(changes) == 0 {
+		fmt.Println("No changes.")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	return nil
+}
+
+// explainMatrices prints the cells of each matrix in the configuration,
+// with the spec entries that produced them and the rules that modified them.
+func explainMatrices(path string) error {
+	configBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "error reading project config")
+	}
+	fetch, _ := localProjectFileFetcher(path)
+	explanations, err := model.ExplainProjectMatrices(configBytes, fetch)
+	if err != nil {
+		return errors.Wrap(err, "error evaluating matrices")
+	}
+	if len(explanations) == 0 {
+		fmt.Println("No matrices.")
+		return nil
+	}
+
+	matrix := ""
+	for i, explanation := range explanations {
+		if i == 0 || explanation.Matrix != matrix {
+			matrix = explanation.Matrix
+			fmt.Printf("matrix %s:\n", matrix)
+		}
+		fmt.Print("  " + explanation.String())
+	}
+	return nil
+}
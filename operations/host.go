@@ -10,6 +10,10 @@ func Host() cli.Command {
 			hostCreate(),
 			hostlist(),
 			hostTerminate(),
+			hostStop(),
+			hostStart(),
+			hostUnquarantine(),
+			hostEvents(),
 			hostStatus(),
 			hostSetup(),
 			hostTeardown(),
@@ -2,6 +2,8 @@ package operations
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/mongodb/grip"
@@ -21,8 +23,11 @@ func hostStatus() cli.Command {
 
 func hostCreate() cli.Command {
 	const (
-		distroFlagName = "distro"
-		keyFlagName    = "key"
+		distroFlagName             = "distro"
+		keyFlagName                = "key"
+		homeVolumeSizeFlagName     = "home-volume-size"
+		taskFlagName               = "task"
+		replayUntilFailureFlagName = "replay-until-failure"
 	)
 
 	return cli.Command{
@@ -31,17 +36,37 @@ func hostCreate() cli.Command {
 		Flags: []cli.Flag{
 			cli.StringFlag{
 				Name:  joinFlagNames(distroFlagName, "d"),
-				Usage: "name of an evergreen distro",
+				Usage: "name of an evergreen distro; defaults to the task's distro if --task is given",
 			},
 			cli.StringFlag{
 				Name:  joinFlagNames(keyFlagName, "k"),
 				Usage: "name or value of an public key to use",
 			},
+			cli.IntFlag{
+				Name:  homeVolumeSizeFlagName,
+				Usage: "attach your persistent home volume at ~/volume, creating one of this size in GB if you have none",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(taskFlagName, "t"),
+				Usage: "fetch the source, artifacts, and expansions of this task onto the host",
+			},
+			cli.BoolFlag{
+				Name:  replayUntilFailureFlagName,
+				Usage: "run the task's commands on the host, stopping before the one that failed (requires --task)",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			confPath := c.Parent().String(confFlagName)
-			distro := c.String(distroFlagName)
-			key := c.String(keyFlagName)
+			spawnRequest := &model.HostPostRequest{
+				DistroID:           c.String(distroFlagName),
+				KeyName:            c.String(keyFlagName),
+				TaskID:             c.String(taskFlagName),
+				HomeVolumeSize:     c.Int(homeVolumeSizeFlagName),
+				ReplayUntilFailure: c.Bool(replayUntilFailureFlagName),
+			}
+			if spawnRequest.ReplayUntilFailure && spawnRequest.TaskID == "" {
+				return errors.Errorf("--%s requires --%s", replayUntilFailureFlagName, taskFlagName)
+			}
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -53,7 +78,7 @@ func hostCreate() cli.Command {
 			client := conf.GetRestCommunicator(ctx)
 			defer client.Close()
 
-			host, err := client.CreateSpawnHost(ctx, distro, key)
+			host, err := client.CreateSpawnHost(ctx, spawnRequest)
 			if host == nil {
 				return errors.New("Unable to create a spawn host. Double check that the params and .evergreen.yml are correct")
 			}
@@ -165,3 +190,179 @@ func hostTerminate() cli.Command {
 		},
 	}
 }
+
+func hostStop() cli.Command {
+	return cli.Command{
+		Name:   "stop",
+		Usage:  "stop a running spawn host",
+		Flags:  addHostFlag(),
+		Before: mergeBeforeFuncs(setPlainLogger, requireHostFlag),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSetttings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			err = client.StopSpawnHost(ctx, hostID)
+			if err != nil {
+				return errors.Wrap(err, "problem stopping host")
+			}
+
+			grip.Infof("Stopped host '%s'", hostID)
+
+			return nil
+		},
+	}
+}
+
+func hostStart() cli.Command {
+	return cli.Command{
+		Name:   "start",
+		Usage:  "start a stopped spawn host",
+		Flags:  addHostFlag(),
+		Before: mergeBeforeFuncs(setPlainLogger, requireHostFlag),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSetttings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			err = client.StartSpawnHost(ctx, hostID)
+			if err != nil {
+				return errors.Wrap(err, "problem starting host")
+			}
+
+			grip.Infof("Started host '%s'", hostID)
+
+			return nil
+		},
+	}
+}
+
+func hostUnquarantine() cli.Command {
+	return cli.Command{
+		Name:   "unquarantine",
+		Usage:  "return a quarantined host to service (requires super-user)",
+		Flags:  addHostFlag(),
+		Before: mergeBeforeFuncs(setPlainLogger, requireHostFlag),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSetttings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			err = client.UnquarantineHost(ctx, hostID)
+			if err != nil {
+				return errors.Wrap(err, "problem unquarantining host")
+			}
+
+			grip.Infof("Unquarantined host '%s'", hostID)
+
+			return nil
+		},
+	}
+}
+
+func hostEvents() cli.Command {
+	const (
+		typeFlagName  = "type"
+		limitFlagName = "limit"
+	)
+
+	return cli.Command{
+		Name:  "events",
+		Usage: "print the events of a host, newest first",
+		Flags: addHostFlag(
+			cli.StringSliceFlag{
+				Name:  typeFlagName,
+				Usage: "only print events of this type (e.g. HOST_STATUS_CHANGED); may be repeated",
+			},
+			cli.IntFlag{
+				Name:  limitFlagName,
+				Usage: "print at most this many events (0 prints all)",
+				Value: 50,
+			}),
+		Before: mergeBeforeFuncs(setPlainLogger, requireHostFlag),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+			types := c.StringSlice(typeFlagName)
+			limit := c.Int(limitFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSetttings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			printed := 0
+			errDone := errors.New("printed enough events")
+			err = client.GetHostEvents(ctx, hostID, types, func(events []*model.APIHostEvent) error {
+				for _, e := range events {
+					if limit > 0 && printed >= limit {
+						return errDone
+					}
+					grip.Info(formatHostEvent(e))
+					printed++
+				}
+				return nil
+			})
+			if err != nil && err != errDone {
+				return errors.Wrap(err, "problem getting host events")
+			}
+
+			return nil
+		},
+	}
+}
+
+func formatHostEvent(e *model.APIHostEvent) string {
+	out := fmt.Sprintf("%s %s", time.Time(e.Timestamp).Format(time.RFC3339), e.EventType)
+	details := []struct {
+		name  string
+		value model.APIString
+	}{
+		{"old_status", e.Data.OldStatus},
+		{"new_status", e.Data.NewStatus},
+		{"user", e.Data.User},
+		{"task", e.Data.TaskId},
+		{"task_status", e.Data.TaskStatus},
+		{"hostname", e.Data.Hostname},
+		{"agent_revision", e.Data.AgentRevision},
+		{"monitor_op", e.Data.MonitorOp},
+	}
+	for _, d := range details {
+		if d.value != "" {
+			out += fmt.Sprintf(" %s=%s", d.name, d.value)
+		}
+	}
+	return out
+}
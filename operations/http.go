@@ -357,14 +357,15 @@ func (ac *legacyClient) ListDistros() ([]distro.Distro, error) {
 // the patch object itself.
 func (ac *legacyClient) PutPatch(incomingPatch patchSubmission) (*patch.Patch, error) {
 	data := struct {
-		Description string   `json:"desc"`
-		Project     string   `json:"project"`
-		Patch       string   `json:"patch"`
-		Githash     string   `json:"githash"`
-		Variants    string   `json:"buildvariants"` //TODO make this an array
-		Tasks       []string `json:"tasks"`
-		Finalize    bool     `json:"finalize"`
-		Alias       string   `json:"alias"`
+		Description string            `json:"desc"`
+		Project     string            `json:"project"`
+		Patch       string            `json:"patch"`
+		Githash     string            `json:"githash"`
+		Variants    string            `json:"buildvariants"` //TODO make this an array
+		Tasks       []string          `json:"tasks"`
+		Finalize    bool              `json:"finalize"`
+		Alias       string            `json:"alias"`
+		Parameters  []patch.Parameter `json:"parameters"`
 	}{
 		incomingPatch.description,
 		incomingPatch.projectId,
@@ -374,6 +375,7 @@ func (ac *legacyClient) PutPatch(incomingPatch patchSubmission) (*patch.Patch, e
 		incomingPatch.tasks,
 		incomingPatch.finalize,
 		incomingPatch.alias,
+		incomingPatch.parameters,
 	}
 
 	rPipe, wPipe := io.Pipe()
@@ -277,15 +277,17 @@ func listDistros(ctx context.Context, confPath string, onlyUserSpawnable bool) e
 	return nil
 }
 
-// LoadLocalConfig loads the local project config into a project
+// LoadLocalConfig loads the local project config into a project, reading
+// any files it includes from the local repository
 func loadLocalConfig(filepath string) (*model.Project, error) {
 	configBytes, err := ioutil.ReadFile(filepath)
 	if err != nil {
 		return nil, errors.Wrap(err, "error reading project config")
 	}
 
+	fetch, _ := localProjectFileFetcher(filepath)
 	project := &model.Project{}
-	err = model.LoadProjectInto(configBytes, "", project)
+	err = model.LoadProjectWithIncludes(configBytes, "", fetch, project)
 	if err != nil {
 		return nil, errors.Wrap(err, "error loading project")
 	}
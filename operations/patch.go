@@ -13,6 +13,7 @@ const (
 	patchFinalizeFlagName    = "finalize"
 	patchVerboseFlagName     = "verbose"
 	patchAliasFlagName       = "alias"
+	patchParamFlagName       = "param"
 )
 
 func getPatchFlags(flags ...cli.Flag) []cli.Flag {
@@ -32,6 +33,10 @@ func getPatchFlags(flags ...cli.Flag) []cli.Flag {
 		cli.BoolFlag{
 			Name:  patchVerboseFlagName,
 			Usage: "show patch summary",
+		},
+		cli.StringSliceFlag{
+			Name:  patchParamFlagName,
+			Usage: "override a project parameter, as key=value; may specify more than once",
 		}))
 }
 
@@ -55,6 +60,11 @@ func Patch() cli.Command {
 				Large:       c.Bool(largeFlagName),
 				Alias:       c.String(patchAliasFlagName),
 			}
+			var err error
+			params.Parameters, err = parsePatchParameters(c.StringSlice(patchParamFlagName))
+			if err != nil {
+				return err
+			}
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -118,6 +128,11 @@ func PatchFile() cli.Command {
 				ShowSummary: c.Bool(patchVerboseFlagName),
 				Large:       c.Bool(largeFlagName),
 			}
+			var err error
+			params.Parameters, err = parsePatchParameters(c.StringSlice(patchParamFlagName))
+			if err != nil {
+				return err
+			}
 			diffPath := c.String(diffPathFlagName)
 			base := c.String(baseFlagName)
 
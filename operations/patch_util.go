@@ -53,6 +53,7 @@ type patchParams struct {
 	Finalize    bool
 	Large       bool
 	ShowSummary bool
+	Parameters  []patch.Parameter
 }
 
 type patchSubmission struct {
@@ -64,6 +65,20 @@ type patchSubmission struct {
 	variants    string
 	tasks       []string
 	finalize    bool
+	parameters  []patch.Parameter
+}
+
+// parsePatchParameters parses parameter overrides given as key=value.
+func parsePatchParameters(args []string) ([]patch.Parameter, error) {
+	params := []patch.Parameter{}
+	for _, arg := range args {
+		pair := strings.SplitN(arg, "=", 2)
+		if len(pair) != 2 || pair[0] == "" {
+			return nil, errors.Errorf("parameter '%s' must be given as key=value", arg)
+		}
+		params = append(params, patch.Parameter{Key: pair[0], Value: pair[1]})
+	}
+	return params, nil
 }
 
 func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffData *localDiff) error {
@@ -95,6 +110,7 @@ func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffDa
 		tasks:       p.Tasks,
 		finalize:    p.Finalize,
 		alias:       p.Alias,
+		parameters:  p.Parameters,
 	}
 
 	newPatch, err := ac.PutPatch(patchSub)
@@ -0,0 +1,86 @@
+package operations
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/pkg/errors"
+)
+
+// localProjectFileFetcher returns a fetcher that reads the files included by
+// the project configuration at configPath from the working tree of the
+// repository containing it. Modules are read from where they are checked out
+// in the working tree, at their prefix. The returned counter reports how many
+// files have been read.
+func localProjectFileFetcher(configPath string) (model.ProjectFileFetcher, func() int) {
+	root := filepath.Dir(configPath)
+	if out, err := exec.Command("git", "-C", root, "rev-parse", "--show-toplevel").Output(); err == nil {
+		root = strings.TrimSpace(string(out))
+	}
+
+	count := 0
+	fetch := func(module *model.Module, path string) ([]byte, error) {
+		count++
+		if module == nil {
+			data, err := ioutil.ReadFile(filepath.Join(root, path))
+			return data, errors.Wrapf(err, "error reading '%s'", path)
+		}
+		moduleRoot := filepath.Join(root, module.Prefix, module.Name)
+		data, err := ioutil.ReadFile(filepath.Join(moduleRoot, path))
+		return data, errors.Wrapf(err, "error reading '%s' from module '%s', which must be checked out at '%s'",
+			path, module.Name, moduleRoot)
+	}
+	return fetch, func() int { return count }
+}
+
+// loadConfigAtRevision loads the project configuration at configPath as it
+// was at the given revision of the repository containing it, reading the
+// files it includes at the same revision. Modules are still read from their
+// working trees, since their revisions are not known. An empty revision
+// loads the configuration from the working tree.
+func loadConfigAtRevision(configPath, revision string) (*model.Project, error) {
+	if revision == "" {
+		return loadLocalConfig(configPath)
+	}
+
+	dir := filepath.Dir(configPath)
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "'%s' is not in a git repository", configPath)
+	}
+	root := strings.TrimSpace(string(out))
+	absPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	relPath, err := filepath.Rel(root, absPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	show := func(path string) ([]byte, error) {
+		data, err := exec.Command("git", "-C", root, "show", fmt.Sprintf("%s:%s", revision, filepath.ToSlash(path))).Output()
+		return data, errors.Wrapf(err, "error reading '%s' at revision '%s'", path, revision)
+	}
+	configBytes, err := show(relPath)
+	if err != nil {
+		return nil, err
+	}
+
+	localFetch, _ := localProjectFileFetcher(configPath)
+	fetch := func(module *model.Module, path string) ([]byte, error) {
+		if module != nil {
+			return localFetch(module, path)
+		}
+		return show(path)
+	}
+	project := &model.Project{}
+	if err = model.LoadProjectWithIncludes(configBytes, "", fetch, project); err != nil {
+		return nil, errors.Wrapf(err, "error loading project at revision '%s'", revision)
+	}
+	return project, nil
+}
@@ -2,23 +2,55 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
+	yaml "gopkg.in/yaml.v2"
 )
 
+const (
+	validateLintFlagName = "lint"
+	validateFixFlagName  = "fix"
+	validateJSONFlagName = "json"
+)
+
+// validateResult is the output of the validate command with --json.
+type validateResult struct {
+	Errors []validator.ValidationError `json:"errors"`
+	Lint   []validator.LintIssue       `json:"lint,omitempty"`
+	Fixed  []validator.LintIssue       `json:"fixed,omitempty"`
+}
+
 func Validate() cli.Command {
 	return cli.Command{
-		Name:   "validate",
-		Usage:  "verify that an evergreen project config is valid",
-		Flags:  addPathFlag(),
+		Name:  "validate",
+		Usage: "verify that an evergreen project config is valid",
+		Flags: addPathFlag(
+			cli.BoolFlag{
+				Name:  validateLintFlagName,
+				Usage: "also check the config for style issues",
+			},
+			cli.BoolFlag{
+				Name:  validateFixFlagName,
+				Usage: "check for style issues and rewrite the config to fix those that are safe to fix",
+			},
+			cli.BoolFlag{
+				Name:  validateJSONFlagName,
+				Usage: "print the results as JSON",
+			}),
 		Before: requirePathFlag,
 		Action: func(c *cli.Context) error {
 			confPath := c.Parent().String(confFlagName)
 			path := c.String(pathFlagName)
+			fix := c.Bool(validateFixFlagName)
+			lint := c.Bool(validateLintFlagName) || fix
+			asJSON := c.Bool(validateJSONFlagName)
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -40,26 +72,113 @@ func Validate() cli.Command {
 				return err
 			}
 
-			projErrors, err := ac.ValidateLocalConfig(confFile)
+			// the service cannot read the files that the project includes,
+			// so it is sent the project with them merged in
+			fetch, fetched := localProjectFileFetcher(path)
+			project := &model.Project{}
+			loadErr := model.LoadProjectWithIncludes(confFile, "", fetch, project)
+			result := validateResult{}
+			if loadErr != nil && (fetched() > 0 || lint) {
+				result.Errors = []validator.ValidationError{{Level: validator.Error, Message: loadErr.Error()}}
+				return printValidateResult(result, asJSON)
+			}
+
+			validateFile := confFile
+			if fetched() > 0 {
+				if validateFile, err = yaml.Marshal(project); err != nil {
+					return errors.Wrap(err, "error marshaling project with included files")
+				}
+			}
+
+			result.Errors, err = ac.ValidateLocalConfig(validateFile)
 			if err != nil {
 				return nil
 			}
-			numErrors, numWarnings := 0, 0
-			if len(projErrors) > 0 {
-				for i, e := range projErrors {
-					if e.Level == validator.Warning {
-						numWarnings++
-					} else if e.Level == validator.Error {
-						numErrors++
+
+			if lint {
+				result.Lint = validator.LintProject(project)
+			}
+			if fix && len(result.Lint) > 0 {
+				var fixedFile []byte
+				fixedFile, result.Fixed, err = validator.FixLintIssues(confFile, result.Lint)
+				if err != nil {
+					return errors.Wrap(err, "problem fixing style issues")
+				}
+				if len(result.Fixed) > 0 {
+					var info os.FileInfo
+					if info, err = os.Stat(path); err != nil {
+						return errors.WithStack(err)
 					}
-					fmt.Printf("%v) %v: %v\n\n", i+1, e.Level, e.Message)
+					if err = ioutil.WriteFile(path, fixedFile, info.Mode()); err != nil {
+						return errors.Wrapf(err, "problem writing fixed config to '%s'", path)
+					}
+					result.Lint = unfixedLintIssues(result.Lint, result.Fixed)
 				}
-
-				return errors.Errorf("Project file has %d warnings, %d errors.", numWarnings, numErrors)
 			}
-			fmt.Println("Valid!")
-			return nil
 
+			return printValidateResult(result, asJSON)
 		},
 	}
 }
+
+// unfixedLintIssues returns the issues that are not among those fixed.
+func unfixedLintIssues(issues, fixed []validator.LintIssue) []validator.LintIssue {
+	unfixed := []validator.LintIssue{}
+	for _, issue := range issues {
+		isFixed := false
+		for _, f := range fixed {
+			if f.Rule == issue.Rule && f.Message == issue.Message {
+				isFixed = true
+				break
+			}
+		}
+		if !isFixed {
+			unfixed = append(unfixed, issue)
+		}
+	}
+	return unfixed
+}
+
+// printValidateResult prints the errors and style issues found in the
+// project, returning an error if there were any.
+func printValidateResult(result validateResult, asJSON bool) error {
+	numErrors, numWarnings := 0, 0
+	for _, e := range result.Errors {
+		if e.Level == validator.Warning {
+			numWarnings++
+		} else if e.Level == validator.Error {
+			numErrors++
+		}
+	}
+	var err error
+	if len(result.Errors) > 0 || len(result.Lint) > 0 {
+		err = errors.Errorf("Project file has %d warnings, %d errors, %d style issues.",
+			numWarnings, numErrors, len(result.Lint))
+	}
+
+	if asJSON {
+		if result.Errors == nil {
+			result.Errors = []validator.ValidationError{}
+		}
+		out, jsonErr := json.MarshalIndent(result, "", "  ")
+		if jsonErr != nil {
+			return errors.Wrap(jsonErr, "problem marshaling results")
+		}
+		fmt.Println(string(out))
+		return err
+	}
+
+	for i, e := range result.Errors {
+		fmt.Printf("%v) %v: %v\n\n", i+1, e.Level, e.Message)
+	}
+	for i, issue := range result.Lint {
+		fmt.Printf("%v) STYLE (%v): %v\n\n", len(result.Errors)+i+1, issue.Rule, issue.Message)
+	}
+	for _, issue := range result.Fixed {
+		fmt.Printf("fixed (%v): %v\n", issue.Rule, issue.Message)
+	}
+	if err == nil {
+		fmt.Println("Valid!")
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	versionGraphIDFlagName     = "id"
+	versionGraphFormatFlagName = "format"
+
+	versionGraphFormatText = "text"
+	versionGraphFormatDOT  = "dot"
+	versionGraphFormatJSON = "json"
+)
+
+func VersionGraph() cli.Command {
+	return cli.Command{
+		Name:  "version-graph",
+		Usage: "export the dependency graph of a version's tasks and find its critical path",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(versionGraphIDFlagName, "i"),
+				Usage: "the id of the version",
+			},
+			cli.StringFlag{
+				Name:  versionGraphFormatFlagName,
+				Usage: "print the critical path as 'text', or the whole graph as 'dot' or 'json'",
+				Value: versionGraphFormatText,
+			},
+		},
+		Before: func(c *cli.Context) error {
+			if c.String(versionGraphIDFlagName) == "" {
+				return errors.New("must specify a version id")
+			}
+			format := c.String(versionGraphFormatFlagName)
+			if !util.StringSliceContains([]string{versionGraphFormatText, versionGraphFormatDOT, versionGraphFormatJSON}, format) {
+				return errors.Errorf("'%s' is not a valid format", format)
+			}
+			return nil
+		},
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			versionID := c.String(versionGraphIDFlagName)
+			format := c.String(versionGraphFormatFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSetttings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			apiGraph, err := client.GetVersionTaskGraph(ctx, versionID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if format == versionGraphFormatJSON {
+				out, err := json.MarshalIndent(apiGraph, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "problem marshaling task graph")
+				}
+				fmt.Println(string(out))
+				return nil
+			}
+
+			graphInterface, err := apiGraph.ToService()
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			graph := graphInterface.(*model.TaskGraph)
+			if format == versionGraphFormatDOT {
+				fmt.Print(graph.DOT())
+				return nil
+			}
+
+			printCriticalPath(graph)
+			return nil
+		},
+	}
+}
+
+// printCriticalPath prints the tasks on the graph's critical path, in the
+// order that they must run, with the time each adds to the makespan.
+func printCriticalPath(graph *model.TaskGraph) {
+	nodes := map[string]model.TaskGraphNode{}
+	for _, node := range graph.Nodes {
+		nodes[node.TaskId] = node
+	}
+	fmt.Printf("Critical path of version %s (%s):\n", graph.VersionId, graph.Makespan)
+	for i, id := range graph.CriticalPath {
+		node := nodes[id]
+		estimate := ""
+		if !node.Finished {
+			estimate = ", expected"
+		}
+		fmt.Printf("%4d) %s: %s (%s%s)\n", i+1, node.BuildVariant, node.DisplayName, node.Duration(), estimate)
+	}
+}
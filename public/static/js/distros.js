@@ -19,6 +19,9 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, mciDist
   }, {
     'id': 'static',
     'display': 'Static IP/VM'
+  }, {
+    'id': 'azure',
+    'display': 'Microsoft Azure'
   }, {
     'id': 'docker',
     'display': 'Docker'
@@ -28,6 +31,12 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, mciDist
   }, {
     'id': 'gce',
     'display': 'Google Compute'
+  }, {
+    'id': 'kubernetes',
+    'display': 'Kubernetes'
+  }, {
+    'id': 'libvirt',
+    'display': 'Libvirt/KVM'
   }, {
     'id': 'vsphere',
     'display': 'VMware vSphere'
@@ -174,6 +183,22 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, mciDist
     $scope.activeDistro.settings.mount_points.splice(index, 1);
   }
 
+  $scope.addFleetOption = function() {
+    if ($scope.activeDistro.settings == null) {
+      $scope.activeDistro.settings = {};
+    }
+    if ($scope.activeDistro.settings.fleet_options == null) {
+      $scope.activeDistro.settings.fleet_options = [];
+    }
+    $scope.activeDistro.settings.fleet_options.push({});
+    $scope.scrollElement('#fleet-options-table');
+  }
+
+  $scope.removeFleetOption = function(fleet_option) {
+    var index = $scope.activeDistro.settings.fleet_options.indexOf(fleet_option);
+    $scope.activeDistro.settings.fleet_options.splice(index, 1);
+  }
+
   $scope.addInstanceSSHKey = function(ssh_key) {
     if ($scope.activeDistro.settings == null) {
       $scope.activeDistro.settings = {};
@@ -313,7 +338,9 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, mciDist
 	'ssh_options': $scope.activeDistro.ssh_options,
 	'setup': $scope.activeDistro.setup,
 	'pool_size': $scope.activeDistro.pool_size,
+	'warm_pool_size': $scope.activeDistro.warm_pool_size,
 	'setup_as_sudo' : $scope.activeDistro.setup_as_sudo,
+	'bootstrap_method': $scope.activeDistro.bootstrap_method,
 
       }
       newDistro.settings = _.clone($scope.activeDistro.settings);
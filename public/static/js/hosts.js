@@ -87,6 +87,7 @@ mciModule.controller('HostsCtrl', function($scope, $filter, $window, $location)
     host.host_type = hostDoc.host_type;
     host.distro = hostDoc.distro;
     host.status = hostDoc.status;
+    host.quarantine_reason = hostDoc.quarantine_reason;
     host.id = hostDoc.id;
     host.host = hostDoc.host;
     host.creation_time = hostDoc.creation_time;
@@ -77,9 +77,25 @@ func (gRepoPoller *GithubRepositoryPoller) GetRemoteConfig(projectFileRevision s
 		return nil, thirdparty.FileDecodeError{err.Error()}
 	}
 
+	// included files are read at the same revision. Errors reading them are
+	// returned as is, so that they are handled like errors reading the main
+	// file.
+	var fetchErr error
+	githubFetch := model.GithubProjectFileFetcher(gRepoPoller.OauthToken, projectRef, projectFileRevision)
+	fetch := func(module *model.Module, path string) ([]byte, error) {
+		data, err := githubFetch(module, path)
+		if err != nil && fetchErr == nil {
+			fetchErr = err
+		}
+		return data, err
+	}
+
 	projectConfig = &model.Project{}
-	err = model.LoadProjectInto(projectFileBytes, projectRef.Identifier, projectConfig)
+	err = model.LoadProjectWithIncludes(projectFileBytes, projectRef.Identifier, fetch, projectConfig)
 	if err != nil {
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
 		return nil, thirdparty.YAMLFormatError{err.Error()}
 	}
 
@@ -412,19 +412,33 @@ func (repoTracker *RepoTracker) StoreRevisions(revisions []model.Revision) (newe
 		}
 		v.Config = string(projectYamlBytes)
 
-		// "Ignore" a version if all changes are to ignored files
-		if len(project.Ignore) > 0 {
-			filenames, err := repoTracker.GetChangedFiles(revision)
+		v.Parameters, err = project.ResolveParameters(nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "error resolving project parameters")
+		}
+
+		var filenames []string
+		if len(project.Ignore) > 0 || project.HasPathFilters() {
+			filenames, err = repoTracker.GetChangedFiles(revision)
 			if err != nil {
-				return nil, errors.Wrap(err, "error checking GitHub for ignored files")
-			}
-			if project.IgnoresAllFiles(filenames) {
-				v.Ignored = true
+				return nil, errors.Wrap(err, "error checking GitHub for changed files")
 			}
 		}
 
+		// "Ignore" a version if all changes are to ignored files
+		if project.IgnoresAllFiles(filenames) {
+			v.Ignored = true
+		}
+
+		// only create the tasks that watch the changed files
+		var affected *model.TaskVariantPairs
+		if project.HasPathFilters() {
+			pairs := project.AffectedTVPairs(filenames)
+			affected = &pairs
+		}
+
 		// We rebind newestVersion each iteration, so the last binding will be the newest version
-		err = errors.Wrapf(createVersionItems(v, ref, project),
+		err = errors.Wrapf(createVersionItems(v, ref, project, affected),
 			"Error creating version items for %s in project %s",
 			v.Id, ref.Identifier)
 		if err != nil {
@@ -562,9 +576,9 @@ func NewVersionFromRevision(ref *model.ProjectRef, rev model.Revision) (*version
 	return v, nil
 }
 
-// createVersionItems populates and stores all the tasks and builds for a version according to
-// the given project config.
-func createVersionItems(v *version.Version, ref *model.ProjectRef, project *model.Project) error {
+// createVersionItems creates the builds and tasks for the version and inserts
+// it. If affected is not nil, only the tasks in it are created.
+func createVersionItems(v *version.Version, ref *model.ProjectRef, project *model.Project, affected *model.TaskVariantPairs) error {
 	// generate all task Ids so that we can easily reference them for dependencies
 	taskIds := model.NewTaskIdTable(project, v)
 
@@ -574,7 +588,16 @@ func createVersionItems(v *version.Version, ref *model.ProjectRef, project *mode
 			continue
 		}
 
-		buildId, err := model.CreateBuildFromVersion(project, v, taskIds, buildvariant.Name, false, nil, nil)
+		var taskNames, displayNames []string
+		if affected != nil {
+			taskNames = affected.ExecTasks.TaskNames(buildvariant.Name)
+			displayNames = affected.DisplayTasks.TaskNames(buildvariant.Name)
+			if len(taskNames) == 0 {
+				continue
+			}
+		}
+
+		buildId, err := model.CreateBuildFromVersion(project, v, taskIds, buildvariant.Name, false, taskNames, displayNames)
 		if err != nil {
 			return errors.WithStack(err)
 		}
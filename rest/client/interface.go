@@ -64,6 +64,9 @@ type Communicator interface {
 	// Heartbeat sends a heartbeat to the API server. The server can respond with
 	// an "abort" response. This function returns true if the agent should abort.
 	Heartbeat(context.Context, TaskData) (bool, error)
+	// SpotInterruption tells the API server that the host is a spot
+	// instance which is about to be reclaimed, so that the task is restarted.
+	SpotInterruption(context.Context, TaskData) error
 	// FetchExpansionVars loads expansions for a communicator's task from the API server.
 	FetchExpansionVars(context.Context, TaskData) (*apimodels.ExpansionVars, error)
 	// GetNextTask returns a next task response by getting the next task for a given host.
@@ -89,6 +92,7 @@ type Communicator interface {
 	GetManifest(context.Context, TaskData) (*manifest.Manifest, error)
 	S3Copy(context.Context, TaskData, *apimodels.S3CopyRequest) error
 	KeyValInc(context.Context, TaskData, *model.KeyVal) error
+	GenerateTasks(context.Context, TaskData, *apimodels.GenerateTasksRequest) error
 
 	// these are for the taskdata/json plugin that saves perf data
 	PostJSONData(context.Context, TaskData, string, interface{}) error
@@ -118,11 +122,17 @@ type Communicator interface {
 
 	// Host methods
 	GetHostsByUser(context.Context, string) ([]*restmodel.APIHost, error)
+	UnquarantineHost(context.Context, string) error
+	// GetHostEvents invokes the function on each page of a host's events,
+	// newest first, optionally restricted to the given event types.
+	GetHostEvents(context.Context, string, []string, func([]*restmodel.APIHostEvent) error) error
 
 	// Spawnhost methods
 	//
-	CreateSpawnHost(context.Context, string, string) (*restmodel.APIHost, error)
+	CreateSpawnHost(context.Context, *restmodel.HostPostRequest) (*restmodel.APIHost, error)
 	TerminateSpawnHost(context.Context, string) error
+	StopSpawnHost(context.Context, string) error
+	StartSpawnHost(context.Context, string) error
 	ChangeSpawnHostPassword(context.Context, string, string) error
 	ExtendSpawnHostExpiration(context.Context, string, int) error
 	GetHosts(context.Context, func([]*restmodel.APIHost) error) error
@@ -138,6 +148,9 @@ type Communicator interface {
 	// Delete a key with specified name from the current authenticated user
 	DeletePublicKey(context.Context, string) error
 
+	// GetVersionTaskGraph fetches the dependency graph of a version's tasks
+	GetVersionTaskGraph(context.Context, string) (*restmodel.APITaskGraph, error)
+
 	// List variant/task aliases
 	ListAliases(context.Context, string) ([]model.ProjectAlias, error)
 
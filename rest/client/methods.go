@@ -221,6 +221,23 @@ func (c *communicatorImpl) Heartbeat(ctx context.Context, taskData TaskData) (bo
 	return heartbeatResponse.Abort, nil
 }
 
+// SpotInterruption tells the API server that the host is a spot instance
+// which is about to be reclaimed.
+func (c *communicatorImpl) SpotInterruption(ctx context.Context, taskData TaskData) error {
+	info := requestInfo{
+		method:   post,
+		version:  v1,
+		taskData: &taskData,
+	}
+	info.setTaskPathSuffix("spot_interruption")
+	resp, err := c.retryRequest(ctx, info, struct{}{})
+	if err != nil {
+		return errors.Wrapf(err, "error sending spot interruption for task %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
 // FetchExpansionVars loads expansions for a communicator's task from the API server.
 func (c *communicatorImpl) FetchExpansionVars(ctx context.Context, taskData TaskData) (*apimodels.ExpansionVars, error) {
 	resultVars := &apimodels.ExpansionVars{}
@@ -499,6 +516,22 @@ func (c *communicatorImpl) KeyValInc(ctx context.Context, taskData TaskData, kv
 	return nil
 }
 
+func (c *communicatorImpl) GenerateTasks(ctx context.Context, taskData TaskData, req *apimodels.GenerateTasksRequest) error {
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  v1,
+	}
+	info.setTaskPathSuffix("generate")
+	resp, err := c.retryRequest(ctx, info, req)
+	if err != nil {
+		return errors.Wrapf(err, "problem generating tasks for %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
 func (c *communicatorImpl) PostJSONData(ctx context.Context, taskData TaskData, path string, data interface{}) error {
 	info := requestInfo{
 		method:   post,
@@ -49,9 +49,11 @@ type Mock struct {
 	TimeoutFilename        string
 	HeartbeatShouldAbort   bool
 	HeartbeatShouldErr     bool
+	SpotInterruptionSent   bool
 	TaskExecution          int
 
-	AttachedFiles map[string][]*artifact.File
+	AttachedFiles  map[string][]*artifact.File
+	GeneratedTasks map[string][]*apimodels.GenerateTasksRequest
 
 	// metrics collection
 	ProcInfo map[string][]*message.ProcessInfo
@@ -75,16 +77,17 @@ type endTaskResult struct {
 // NewMock returns a Communicator for testing.
 func NewMock(serverURL string) *Mock {
 	return &Mock{
-		maxAttempts:   defaultMaxAttempts,
-		timeoutStart:  defaultTimeoutStart,
-		timeoutMax:    defaultTimeoutMax,
-		logMessages:   make(map[string][]apimodels.LogMessage),
-		PatchFiles:    make(map[string]string),
-		keyVal:        make(map[string]*serviceModel.KeyVal),
-		ProcInfo:      make(map[string][]*message.ProcessInfo),
-		SysInfo:       make(map[string]*message.SystemInfo),
-		AttachedFiles: make(map[string][]*artifact.File),
-		serverURL:     serverURL,
+		maxAttempts:    defaultMaxAttempts,
+		timeoutStart:   defaultTimeoutStart,
+		timeoutMax:     defaultTimeoutMax,
+		logMessages:    make(map[string][]apimodels.LogMessage),
+		PatchFiles:     make(map[string]string),
+		keyVal:         make(map[string]*serviceModel.KeyVal),
+		ProcInfo:       make(map[string][]*message.ProcessInfo),
+		SysInfo:        make(map[string]*message.SystemInfo),
+		AttachedFiles:  make(map[string][]*artifact.File),
+		GeneratedTasks: make(map[string][]*apimodels.GenerateTasksRequest),
+		serverURL:      serverURL,
 	}
 }
 
@@ -207,6 +210,14 @@ func (c *Mock) Heartbeat(ctx context.Context, td TaskData) (bool, error) {
 	return false, nil
 }
 
+// SpotInterruption records that a spot interruption was sent.
+func (c *Mock) SpotInterruption(ctx context.Context, td TaskData) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.SpotInterruptionSent = true
+	return nil
+}
+
 // FetchExpansionVars returns a mock ExpansionVars.
 func (c *Mock) FetchExpansionVars(ctx context.Context, td TaskData) (*apimodels.ExpansionVars, error) {
 	return &apimodels.ExpansionVars{
@@ -301,18 +312,21 @@ func (c *Mock) GetTaskPatch(ctx context.Context, td TaskData) (*patchmodel.Patch
 // GetHostsByUser will return an array with a single mock host
 func (c *Mock) GetHostsByUser(ctx context.Context, user string) ([]*model.APIHost, error) {
 	hosts := make([]*model.APIHost, 1)
-	host, _ := c.CreateSpawnHost(ctx, "mock_distro", "mock_key")
+	host, _ := c.CreateSpawnHost(ctx, &model.HostPostRequest{
+		DistroID: "mock_distro",
+		KeyName:  "mock_key",
+	})
 	hosts = append(hosts, host)
 	return hosts, nil
 }
 
 // CreateSpawnHost will return a mock host that would have been intended
-func (*Mock) CreateSpawnHost(ctx context.Context, distroID string, keyName string) (*model.APIHost, error) {
+func (*Mock) CreateSpawnHost(ctx context.Context, spawnRequest *model.HostPostRequest) (*model.APIHost, error) {
 	mockHost := &model.APIHost{
 		Id:      model.APIString("mock_host_id"),
 		HostURL: model.APIString("mock_url"),
 		Distro: model.DistroInfo{
-			Id:       model.APIString(distroID),
+			Id:       model.APIString(spawnRequest.DistroID),
 			Provider: evergreen.ProviderNameMock,
 		},
 		Type:        model.APIString("mock_type"),
@@ -328,6 +342,18 @@ func (*Mock) TerminateSpawnHost(ctx context.Context, hostID string) error {
 	return errors.New("(*Mock) TerminateSpawnHost is not implemented")
 }
 
+func (*Mock) StopSpawnHost(ctx context.Context, hostID string) error {
+	return errors.New("(*Mock) StopSpawnHost is not implemented")
+}
+
+func (*Mock) StartSpawnHost(ctx context.Context, hostID string) error {
+	return errors.New("(*Mock) StartSpawnHost is not implemented")
+}
+
+func (*Mock) UnquarantineHost(ctx context.Context, hostID string) error {
+	return errors.New("(*Mock) UnquarantineHost is not implemented")
+}
+
 func (*Mock) ChangeSpawnHostPassword(context.Context, string, string) error {
 	return errors.New("(*Mock) ChangeSpawnHostPassword is not implemented")
 }
@@ -336,10 +362,17 @@ func (*Mock) ExtendSpawnHostExpiration(context.Context, string, int) error {
 	return errors.New("(*Mock) ExtendSpawnHostExpiration is not implemented")
 }
 
+func (*Mock) GetHostEvents(ctx context.Context, hostID string, types []string, f func([]*model.APIHostEvent) error) error {
+	return errors.New("(*Mock) GetHostEvents is not implemented")
+}
+
 // GetHosts will return an array with a single mock host
 func (c *Mock) GetHosts(ctx context.Context, f func([]*model.APIHost) error) error {
 	hosts := make([]*model.APIHost, 1)
-	host, _ := c.CreateSpawnHost(ctx, "mock_distro", "mock_key")
+	host, _ := c.CreateSpawnHost(ctx, &model.HostPostRequest{
+		DistroID: "mock_distro",
+		KeyName:  "mock_key",
+	})
 	hosts = append(hosts, host)
 	err := f(hosts)
 	return err
@@ -385,6 +418,11 @@ func (c *Mock) S3Copy(ctx context.Context, td TaskData, req *apimodels.S3CopyReq
 	return nil
 }
 
+func (c *Mock) GenerateTasks(ctx context.Context, td TaskData, req *apimodels.GenerateTasksRequest) error {
+	c.GeneratedTasks[td.ID] = append(c.GeneratedTasks[td.ID], req)
+	return nil
+}
+
 func (c *Mock) KeyValInc(ctx context.Context, td TaskData, kv *serviceModel.KeyVal) error {
 	if cached, ok := c.keyVal[kv.Key]; ok {
 		*kv = *cached
@@ -470,6 +508,10 @@ func (c *Mock) DeletePublicKey(ctx context.Context, keyName string) error {
 	return errors.New("(c *Mock) DeletePublicKey not implemented")
 }
 
+func (c *Mock) GetVersionTaskGraph(ctx context.Context, versionID string) (*model.APITaskGraph, error) {
+	return nil, errors.New("(c *Mock) GetVersionTaskGraph not implemented")
+}
+
 func (c *Mock) ListAliases(ctx context.Context, keyName string) ([]serviceModel.ProjectAlias, error) {
 	return nil, errors.New("(c *Mock) ListAliases not implemented")
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -60,11 +61,7 @@ func (*communicatorImpl) SetHostStatus()   {}
 func (*communicatorImpl) SetHostStatuses() {}
 
 // CreateSpawnHost will insert an intent host into the DB that will be spawned later by the runner
-func (c *communicatorImpl) CreateSpawnHost(ctx context.Context, distroID string, keyName string) (*model.APIHost, error) {
-	spawnRequest := &model.HostPostRequest{
-		DistroID: distroID,
-		KeyName:  keyName,
-	}
+func (c *communicatorImpl) CreateSpawnHost(ctx context.Context, spawnRequest *model.HostPostRequest) (*model.APIHost, error) {
 	info := requestInfo{
 		method:  post,
 		path:    "hosts",
@@ -107,6 +104,114 @@ func (c *communicatorImpl) TerminateSpawnHost(ctx context.Context, hostID string
 	return nil
 }
 
+func (c *communicatorImpl) StopSpawnHost(ctx context.Context, hostID string) error {
+	info := requestInfo{
+		method:  post,
+		path:    fmt.Sprintf("hosts/%s/stop", hostID),
+		version: apiVersion2,
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return errors.Wrapf(err, "error sending request to stop host")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := rest.APIError{}
+		if err := util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Wrap(err, "problem stopping host and parsing error message")
+		}
+		return errors.Wrap(errMsg, "problem stopping host")
+	}
+
+	return nil
+}
+
+func (c *communicatorImpl) StartSpawnHost(ctx context.Context, hostID string) error {
+	info := requestInfo{
+		method:  post,
+		path:    fmt.Sprintf("hosts/%s/start", hostID),
+		version: apiVersion2,
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return errors.Wrapf(err, "error sending request to start host")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := rest.APIError{}
+		if err := util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Wrap(err, "problem starting host and parsing error message")
+		}
+		return errors.Wrap(errMsg, "problem starting host")
+	}
+
+	return nil
+}
+
+func (c *communicatorImpl) UnquarantineHost(ctx context.Context, hostID string) error {
+	info := requestInfo{
+		method:  post,
+		path:    fmt.Sprintf("hosts/%s/unquarantine", hostID),
+		version: apiVersion2,
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return errors.Wrapf(err, "error sending request to unquarantine host")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := rest.APIError{}
+		if err := util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Wrap(err, "problem unquarantining host and parsing error message")
+		}
+		return errors.Wrap(errMsg, "problem unquarantining host")
+	}
+
+	return nil
+}
+
+// GetHostEvents gathers the events of a host and invokes a function on each
+// page of them
+func (c *communicatorImpl) GetHostEvents(ctx context.Context, hostID string, types []string, f func([]*model.APIHostEvent) error) error {
+	path := fmt.Sprintf("hosts/%s/events", hostID)
+	if len(types) > 0 {
+		path += "?" + url.Values{"type": types}.Encode()
+	}
+	info := requestInfo{
+		method:  get,
+		path:    path,
+		version: apiVersion2,
+	}
+
+	p, err := newPaginatorHelper(&info, c)
+	if err != nil {
+		return err
+	}
+
+	for p.hasMore() {
+		events := []*model.APIHostEvent{}
+		resp, err := p.getNextPage(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "problem getting events for host %s", hostID)
+		}
+
+		err = util.ReadJSONInto(resp.Body, &events)
+		if err != nil {
+			return err
+		}
+
+		err = f(events)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (c *communicatorImpl) ChangeSpawnHostPassword(ctx context.Context, hostID, rdpPassword string) error {
 	info := requestInfo{
 		method:  post,
@@ -400,6 +505,33 @@ func (c *communicatorImpl) DeletePublicKey(ctx context.Context, keyName string)
 	return nil
 }
 
+func (c *communicatorImpl) GetVersionTaskGraph(ctx context.Context, versionID string) (*model.APITaskGraph, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("versions/%s/graph", versionID),
+	}
+
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem fetching task graph of version %s", versionID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := rest.APIError{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Wrap(err, "problem fetching task graph and parsing error message")
+		}
+		return nil, errors.Wrap(errMsg, "problem fetching task graph")
+	}
+
+	graph := &model.APITaskGraph{}
+	if err = util.ReadJSONInto(resp.Body, graph); err != nil {
+		return nil, errors.Wrap(err, "error parsing task graph")
+	}
+	return graph, nil
+}
+
 func (c *communicatorImpl) ListAliases(ctx context.Context, project string) ([]serviceModel.ProjectAlias, error) {
 	path := fmt.Sprintf("alias/%s", project)
 	info := requestInfo{
@@ -2,14 +2,19 @@ package data
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/mongodb/amboy"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 )
 
@@ -103,8 +108,54 @@ func (ac *DBAdminConnector) RestartFailedTasks(queue amboy.Queue, opts model.Res
 	}, nil
 }
 
+// FindUnfinishedAgentRollouts returns the agent rollouts that are active,
+// paused or rolled back
+func (ac *DBAdminConnector) FindUnfinishedAgentRollouts() ([]host.AgentRollout, error) {
+	rollouts, err := host.FindAgentRollouts(host.AgentRolloutsByStatus(
+		host.AgentRolloutActive, host.AgentRolloutPaused, host.AgentRolloutRolledBack))
+	return rollouts, errors.Wrap(err, "error finding agent rollouts")
+}
+
+// FindLatestAgentRollout returns the most recent agent rollout to the distro
+func (ac *DBAdminConnector) FindLatestAgentRollout(distroID string) (*host.AgentRollout, error) {
+	r, err := host.FindOneAgentRollout(host.LatestAgentRollout(distroID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding agent rollout for distro %s", distroID)
+	}
+	if r == nil {
+		return nil, &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no agent rollout found for distro %s", distroID),
+		}
+	}
+	return r, nil
+}
+
+// PauseAgentRollout holds the agent rollout at its current stage
+func (ac *DBAdminConnector) PauseAgentRollout(r *host.AgentRollout, u *user.DBUser) error {
+	grip.Info(message.Fields{
+		"message":  "pausing agent rollout",
+		"distro":   r.Distro,
+		"revision": r.Revision,
+		"user":     u.Id,
+	})
+	return errors.WithStack(r.Pause())
+}
+
+// ResumeAgentRollout continues a paused or rolled back agent rollout
+func (ac *DBAdminConnector) ResumeAgentRollout(r *host.AgentRollout, u *user.DBUser) error {
+	grip.Info(message.Fields{
+		"message":  "resuming agent rollout",
+		"distro":   r.Distro,
+		"revision": r.Revision,
+		"user":     u.Id,
+	})
+	return errors.WithStack(r.Resume())
+}
+
 type MockAdminConnector struct {
-	MockSettings *evergreen.Settings
+	MockSettings      *evergreen.Settings
+	MockAgentRollouts []host.AgentRollout
 }
 
 // GetEvergreenSettings retrieves the admin settings document from the mock connector
@@ -155,3 +206,51 @@ func (ac *MockAdminConnector) RestartFailedTasks(queue amboy.Queue, opts model.R
 		TasksErrored:   nil,
 	}, nil
 }
+
+// FindUnfinishedAgentRollouts returns the mock agent rollouts that are not
+// completed
+func (ac *MockAdminConnector) FindUnfinishedAgentRollouts() ([]host.AgentRollout, error) {
+	rollouts := []host.AgentRollout{}
+	for _, r := range ac.MockAgentRollouts {
+		if r.Status != host.AgentRolloutCompleted {
+			rollouts = append(rollouts, r)
+		}
+	}
+	return rollouts, nil
+}
+
+// FindLatestAgentRollout returns the last mock agent rollout to the distro
+func (ac *MockAdminConnector) FindLatestAgentRollout(distroID string) (*host.AgentRollout, error) {
+	for i := len(ac.MockAgentRollouts) - 1; i >= 0; i-- {
+		if ac.MockAgentRollouts[i].Distro == distroID {
+			return &ac.MockAgentRollouts[i], nil
+		}
+	}
+	return nil, &rest.APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("no agent rollout found for distro %s", distroID),
+	}
+}
+
+// PauseAgentRollout pauses the mock agent rollout
+func (ac *MockAdminConnector) PauseAgentRollout(r *host.AgentRollout, u *user.DBUser) error {
+	if r.Status != host.AgentRolloutActive {
+		return errors.Errorf("agent rollout %s is %s, not active", r.ID, r.Status)
+	}
+	r.Status = host.AgentRolloutPaused
+	return nil
+}
+
+// ResumeAgentRollout resumes the mock agent rollout
+func (ac *MockAdminConnector) ResumeAgentRollout(r *host.AgentRollout, u *user.DBUser) error {
+	switch r.Status {
+	case host.AgentRolloutPaused:
+	case host.AgentRolloutRolledBack:
+		r.Stage = 0
+		r.Reason = ""
+	default:
+		return errors.Errorf("agent rollout %s is %s and cannot be resumed", r.ID, r.Status)
+	}
+	r.Status = host.AgentRolloutActive
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest"
 	"github.com/pkg/errors"
@@ -49,18 +50,26 @@ func (tc *DBDistroConnector) FindCostByDistroId(distroId string,
 		return nil, err
 	}
 
+	// Idle time in the distro's warm pool is not part of any task's cost,
+	// so it is aggregated from the distro's hosts.
+	warmPoolCost, err := host.WarmPoolCostForDistro(distroId, starttime, duration)
+	if err != nil {
+		return nil, err
+	}
+
 	// Account for possible error cases
 	if len(res) > 1 {
 		return nil, errors.Errorf("aggregation query with distro_id %s returned %d results but should only return 1 result", distroId, len(res))
 	}
 	// Aggregation ran but no tasks of given time range was found for this distro.
 	if len(res) == 0 {
-		return &task.DistroCost{DistroId: distroId}, nil
+		return &task.DistroCost{DistroId: distroId, SumWarmPoolCost: warmPoolCost}, nil
 	}
 
 	// Add provider and provider settings of the distro to the
 	// DistroCost model.
 	dc := res[0]
+	dc.SumWarmPoolCost = warmPoolCost
 	dc.Provider = d.Provider
 	dc.ProviderSettings = *(d.ProviderSettings)
 
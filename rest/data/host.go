@@ -7,10 +7,13 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/auth"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/spawn"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/pkg/errors"
 )
 
@@ -55,22 +58,24 @@ func (dbc *DBConnector) FindHostByIdWithOwner(hostID string, user auth.User) (*h
 
 // NewIntentHost is a method to insert an intent host given a distro and a public key
 // The public key can be the name of a saved key or the actual key string
-func (hc *DBHostConnector) NewIntentHost(distroID, keyNameOrVal, taskID, userData string, user *user.DBUser) (*host.Host, error) {
-	keyVal, err := user.GetPublicKey(keyNameOrVal)
+func (hc *DBHostConnector) NewIntentHost(options *restModel.HostPostRequest, user *user.DBUser) (*host.Host, error) {
+	keyVal, err := user.GetPublicKey(options.KeyName)
 	if err != nil {
-		keyVal = keyNameOrVal
+		keyVal = options.KeyName
 	}
 	if keyVal == "" {
 		return nil, errors.New("invalid key")
 	}
 
 	spawnOptions := spawn.Options{
-		Distro:    distroID,
-		UserName:  user.Username(),
-		PublicKey: keyVal,
-		TaskId:    taskID,
-		UserData:  userData,
-		Owner:     user,
+		Distro:             options.DistroID,
+		UserName:           user.Username(),
+		PublicKey:          keyVal,
+		TaskId:             options.TaskID,
+		UserData:           options.UserData,
+		Owner:              user,
+		HomeVolumeSize:     options.HomeVolumeSize,
+		ReplayUntilFailure: options.ReplayUntilFailure,
 	}
 
 	intentHost, err := spawn.CreateHost(spawnOptions)
@@ -101,10 +106,56 @@ func (hc *DBHostConnector) TerminateHost(host *host.Host, user string) error {
 	return errors.WithStack(spawn.TerminateHost(host, evergreen.GetEnvironment().Settings(), user))
 }
 
+func (hc *DBHostConnector) StopHost(host *host.Host, user string) error {
+	return errors.WithStack(spawn.StopHost(host, evergreen.GetEnvironment().Settings(), user))
+}
+
+func (hc *DBHostConnector) StartHost(host *host.Host, user string) error {
+	return errors.WithStack(spawn.StartHost(host, evergreen.GetEnvironment().Settings(), user))
+}
+
+func (hc *DBHostConnector) UnquarantineHost(host *host.Host, user string) error {
+	return errors.WithStack(host.Unquarantine(user))
+}
+
+// FindHostEvents queries the event log for a page of the host's events.
+func (hc *DBHostConnector) FindHostEvents(hostID string, types []string, ts time.Time, limit int, sortAsc bool) ([]event.Event, error) {
+	events, err := event.Find(event.AllLogCollection, event.HostEventsByTime(hostID, types, ts, limit, sortAsc))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem fetching events for host %s", hostID)
+	}
+	return events, nil
+}
+
+// GetHostLifecycleTimings computes the lifecycle timings of a distro's hosts.
+func (hc *DBHostConnector) GetHostLifecycleTimings(distroID string, since time.Time) (*host.LifecycleTimings, error) {
+	return host.GetLifecycleTimings(distroID, since)
+}
+
+// GetSpawnHostQuota returns the user's spawn host quota and current usage.
+func (hc *DBHostConnector) GetSpawnHostQuota(u *user.DBUser) (*spawn.Quota, error) {
+	return spawn.GetQuota(u, evergreen.GetEnvironment().Settings())
+}
+
+// GetSpawnHostMaxExpiration returns the furthest in the future that the
+// user's spawn hosts may expire.
+func (hc *DBHostConnector) GetSpawnHostMaxExpiration(u *user.DBUser) (time.Duration, error) {
+	return spawn.MaxExpiration(u)
+}
+
+// SetSpawnHostUserQuota sets or, if it has no limits, removes the spawn host
+// quota override for a user.
+func (hc *DBHostConnector) SetSpawnHostUserQuota(quota evergreen.SpawnHostQuota) error {
+	return errors.WithStack(evergreen.SetSpawnHostUserQuota(quota))
+}
+
 // MockHostConnector is a struct that implements the Host related methods
 // from the Connector through interactions with he backing database.
 type MockHostConnector struct {
-	CachedHosts []host.Host
+	CachedHosts      []host.Host
+	CachedHostEvents []event.Event
+	CachedTimings    map[string]host.LifecycleTimings
+	SpawnHostQuotas  evergreen.SpawnHostConfig
 }
 
 // FindHostsById searches the mock hosts slice for hosts and returns them
@@ -191,22 +242,24 @@ func (hc *MockHostConnector) FindHostById(id string) (*host.Host, error) {
 
 // NewIntentHost is a method to mock "insert" an intent host given a distro and a public key
 // The public key can be the name of a saved key or the actual key string
-func (hc *MockHostConnector) NewIntentHost(distroID, keyNameOrVal, taskID, userData string, user *user.DBUser) (*host.Host, error) {
-	keyVal, err := user.GetPublicKey(keyNameOrVal)
+func (hc *MockHostConnector) NewIntentHost(options *restModel.HostPostRequest, user *user.DBUser) (*host.Host, error) {
+	keyVal, err := user.GetPublicKey(options.KeyName)
 	if err != nil {
-		keyVal = keyNameOrVal
+		keyVal = options.KeyName
 	}
 	if keyVal == "" {
 		return nil, errors.New("invalid key")
 	}
 
 	spawnOptions := spawn.Options{
-		Distro:    distroID,
-		UserName:  user.Username(),
-		PublicKey: keyVal,
-		TaskId:    taskID,
-		UserData:  userData,
-		Owner:     user,
+		Distro:             options.DistroID,
+		UserName:           user.Username(),
+		PublicKey:          keyVal,
+		TaskId:             options.TaskID,
+		UserData:           options.UserData,
+		Owner:              user,
+		HomeVolumeSize:     options.HomeVolumeSize,
+		ReplayUntilFailure: options.ReplayUntilFailure,
 	}
 
 	intentHost, err := spawn.CreateHost(spawnOptions)
@@ -253,6 +306,58 @@ func (hc *MockHostConnector) TerminateHost(host *host.Host, user string) error {
 	return errors.New("can't find host")
 }
 
+func (hc *MockHostConnector) StopHost(host *host.Host, user string) error {
+	if host.Status != evergreen.HostRunning {
+		return errors.Errorf("Host cannot be stopped from status '%s'", host.Status)
+	}
+	return hc.SetHostStatus(host, evergreen.HostStopped, user)
+}
+
+func (hc *MockHostConnector) StartHost(host *host.Host, user string) error {
+	if host.Status != evergreen.HostStopped {
+		return errors.Errorf("Host cannot be started from status '%s'", host.Status)
+	}
+	return hc.SetHostStatus(host, evergreen.HostRunning, user)
+}
+
+func (hc *MockHostConnector) UnquarantineHost(host *host.Host, user string) error {
+	if host.Status != evergreen.HostQuarantined {
+		return errors.Errorf("host %s is not quarantined", host.Id)
+	}
+	for i := range hc.CachedHosts {
+		if hc.CachedHosts[i].Id == host.Id {
+			hc.CachedHosts[i].QuarantineReason = ""
+			host.QuarantineReason = ""
+		}
+	}
+	return hc.SetHostStatus(host, evergreen.HostRunning, user)
+}
+
+// GetSpawnHostQuota returns the user's quota and the number of cached hosts
+// they started that are not terminated. Team quotas are not mocked.
+func (hc *MockHostConnector) GetSpawnHostQuota(u *user.DBUser) (*spawn.Quota, error) {
+	quota := &spawn.Quota{User: hc.SpawnHostQuotas.UserQuota(u.Id)}
+	for _, h := range hc.CachedHosts {
+		if h.StartedBy == u.Id && h.Status != evergreen.HostTerminated {
+			quota.UserUsage.Hosts++
+		}
+	}
+	return quota, nil
+}
+
+func (hc *MockHostConnector) GetSpawnHostMaxExpiration(u *user.DBUser) (time.Duration, error) {
+	quota := hc.SpawnHostQuotas.UserQuota(u.Id)
+	return time.Duration(quota.MaxExpirationHours) * time.Hour, nil
+}
+
+func (hc *MockHostConnector) SetSpawnHostUserQuota(quota evergreen.SpawnHostQuota) error {
+	if quota.Name == "" {
+		return errors.New("spawn host quota must name a user")
+	}
+	hc.SpawnHostQuotas.SetUserQuota(quota)
+	return nil
+}
+
 func (dbc *MockConnector) FindHostByIdWithOwner(hostID string, user auth.User) (*host.Host, error) {
 	return findHostByIdWithOwner(dbc, hostID, user)
 }
@@ -283,3 +388,45 @@ func findHostByIdWithOwner(c Connector, hostID string, user auth.User) (*host.Ho
 
 	return host, nil
 }
+
+// FindHostEvents searches the cached host events for a page of the host's
+// events. Assumes CachedHostEvents is sorted by increasing time.
+func (hc *MockHostConnector) FindHostEvents(hostID string, types []string, ts time.Time, limit int, sortAsc bool) ([]event.Event, error) {
+	events := []event.Event{}
+	if limit <= 0 {
+		return events, nil
+	}
+	matches := func(e event.Event) bool {
+		return e.ResourceId == hostID && (len(types) == 0 || util.StringSliceContains(types, e.EventType))
+	}
+	if sortAsc {
+		for i := 0; i < len(hc.CachedHostEvents); i++ {
+			e := hc.CachedHostEvents[i]
+			if matches(e) && e.Timestamp.After(ts) {
+				events = append(events, e)
+				if len(events) == limit {
+					break
+				}
+			}
+		}
+	} else {
+		for i := len(hc.CachedHostEvents) - 1; i >= 0; i-- {
+			e := hc.CachedHostEvents[i]
+			if matches(e) && !e.Timestamp.After(ts) {
+				events = append(events, e)
+				if len(events) == limit {
+					break
+				}
+			}
+		}
+	}
+	return events, nil
+}
+
+// GetHostLifecycleTimings returns the cached timings for the distro.
+func (hc *MockHostConnector) GetHostLifecycleTimings(distroID string, since time.Time) (*host.LifecycleTimings, error) {
+	timings := hc.CachedTimings[distroID]
+	timings.Distro = distroID
+	timings.Since = since
+	return &timings, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/user"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/stretchr/testify/suite"
@@ -212,7 +213,10 @@ func (s *HostConnectorSuite) TestSpawnHost() {
 	s.NoError(testUser.Insert())
 
 	//note this is the real DB host connector, not the mock
-	intentHost, err := (&DBHostConnector{}).NewIntentHost(testDistroID, testPublicKeyName, "", "", testUser)
+	intentHost, err := (&DBHostConnector{}).NewIntentHost(&restModel.HostPostRequest{
+		DistroID: testDistroID,
+		KeyName:  testPublicKeyName,
+	}, testUser)
 	s.NotNil(intentHost)
 	s.NoError(err)
 	foundHost, err := host.FindOne(host.ById(intentHost.Id))
@@ -13,6 +13,7 @@ type DBConnector struct {
 	DBTaskConnector
 	DBContextConnector
 	DBDistroConnector
+	DBTaskQueueConnector
 	DBHostConnector
 	DBTestConnector
 	DBMetricsConnector
@@ -44,6 +45,7 @@ type MockConnector struct {
 	MockTaskConnector
 	MockContextConnector
 	MockDistroConnector
+	MockTaskQueueConnector
 	MockHostConnector
 	MockTestConnector
 	MockMetricsConnector
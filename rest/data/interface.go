@@ -8,6 +8,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -15,6 +16,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/spawn"
 	"github.com/google/go-github/github"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/grip/message"
@@ -95,8 +97,16 @@ type Connector interface {
 	// started by
 	FindHostByIdWithOwner(string, auth.User) (*host.Host, error)
 
+	// FindHostEvents returns up to the given number of a host's events of the
+	// given types, starting at the given time. Events are sorted newest
+	// first unless the last argument is true.
+	FindHostEvents(string, []string, time.Time, int, bool) ([]event.Event, error)
+	// GetHostLifecycleTimings summarizes how long the hosts of a distro
+	// created since the given time took to start running and be provisioned.
+	GetHostLifecycleTimings(string, time.Time) (*host.LifecycleTimings, error)
+
 	// NewIntentHost is a method to insert an intent host given a distro and the name of a saved public key
-	NewIntentHost(string, string, string, string, *user.DBUser) (*host.Host, error)
+	NewIntentHost(*restModel.HostPostRequest, *user.DBUser) (*host.Host, error)
 
 	// FetchContext is a method to fetch a context given a series of identifiers.
 	FetchContext(string, string, string, string, string) (model.Context, error)
@@ -104,6 +114,16 @@ type Connector interface {
 	// FindAllDistros is a method to find a sorted list of all distros.
 	FindAllDistros() ([]distro.Distro, error)
 
+	// FindTaskQueueForDistro returns the current task queue for a distro,
+	// and CountHostsForDistro the number of hosts available to work on it.
+	FindTaskQueueForDistro(string) (*model.TaskQueue, error)
+	CountHostsForDistro(string) (int, error)
+	// PinTaskInQueue, HoldTaskInQueue and ReleaseTaskInQueue set and
+	// clear manual overrides on a distro's task queue.
+	PinTaskInQueue(string, string) error
+	HoldTaskInQueue(string, string) error
+	ReleaseTaskInQueue(string, string) error
+
 	// FindTaskSystemMetrics and FindTaskProcessMetrics provide
 	// access to the metrics data collected by agents during task execution
 	FindTaskSystemMetrics(string, time.Time, int, int) ([]*message.SystemInfo, error)
@@ -118,6 +138,9 @@ type Connector interface {
 
 	// FindVersionById returns version given its ID.
 	FindVersionById(string) (*version.Version, error)
+	// FindTaskGraphByVersionId returns the dependency graph of the tasks of
+	// a version given its ID, with its critical path.
+	FindTaskGraphByVersionId(string) (*model.TaskGraph, error)
 
 	// FindPatchesByProject provides access to the patches corresponding to the input project ID
 	// as ordered by creation time.
@@ -127,6 +150,9 @@ type Connector interface {
 
 	// FindPatchById fetches the patch corresponding to the input patch ID.
 	FindPatchById(string) (*patch.Patch, error)
+	// GetPatchConfigDiff returns the changes that the patch corresponding to
+	// the input patch ID makes to its base version's project configuration.
+	GetPatchConfigDiff(string) ([]model.ProjectChange, error)
 
 	// AbortVersion aborts all tasks of a version given its ID.
 	AbortVersion(string) error
@@ -153,6 +179,16 @@ type Connector interface {
 	// SetAdminBanner sets set the service flags in the system-wide settings document
 	SetServiceFlags(evergreen.ServiceFlags, *user.DBUser) error
 	RestartFailedTasks(amboy.Queue, model.RestartTaskOptions) (*restModel.RestartTasksResponse, error)
+	// FindUnfinishedAgentRollouts returns the agent rollouts that are
+	// active, paused or rolled back.
+	FindUnfinishedAgentRollouts() ([]host.AgentRollout, error)
+	// FindLatestAgentRollout returns the most recent agent rollout to the
+	// given distro.
+	FindLatestAgentRollout(string) (*host.AgentRollout, error)
+	// PauseAgentRollout and ResumeAgentRollout hold and continue the given
+	// agent rollout.
+	PauseAgentRollout(*host.AgentRollout, *user.DBUser) error
+	ResumeAgentRollout(*host.AgentRollout, *user.DBUser) error
 
 	FindCostTaskByProject(string, string, time.Time, time.Time, int, int) ([]task.Task, error)
 
@@ -163,6 +199,9 @@ type Connector interface {
 
 	AddPublicKey(*user.DBUser, string, string) error
 	DeletePublicKey(*user.DBUser, string) error
+	// SetUserTeam assigns the user with the given ID to a team, removing
+	// them from their team if the team is empty.
+	SetUserTeam(string, string) error
 
 	AddPatchIntent(patch.Intent, amboy.Queue) error
 
@@ -172,6 +211,25 @@ type Connector interface {
 	// TerminateHost terminates the given host via the cloud provider's API
 	TerminateHost(*host.Host, string) error
 
+	// StopHost stops the given running host via the cloud provider's API
+	StopHost(*host.Host, string) error
+
+	// StartHost starts the given stopped host via the cloud provider's API
+	StartHost(*host.Host, string) error
+
+	// UnquarantineHost returns the given quarantined host to service
+	UnquarantineHost(*host.Host, string) error
+
+	// GetSpawnHostQuota returns the spawn host quota of the given user,
+	// and the user's usage counted against it.
+	GetSpawnHostQuota(*user.DBUser) (*spawn.Quota, error)
+	// GetSpawnHostMaxExpiration returns the furthest in the future that
+	// the given user's spawn hosts may expire.
+	GetSpawnHostMaxExpiration(*user.DBUser) (time.Duration, error)
+	// SetSpawnHostUserQuota sets the spawn host quota override for the user
+	// named in the quota, removing it if the quota sets no limits.
+	SetSpawnHostUserQuota(evergreen.SpawnHostQuota) error
+
 	// FindProjectAliases queries the database to find all aliases.
 	FindProjectAliases(string) ([]model.ProjectAlias, error)
 
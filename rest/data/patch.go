@@ -8,6 +8,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest"
 	"github.com/google/go-github/github"
 	"github.com/pkg/errors"
@@ -44,6 +45,46 @@ func (pc *DBPatchConnector) FindPatchById(patchId string) (*patch.Patch, error)
 	return p, nil
 }
 
+// GetPatchConfigDiff compares the patch's project configuration to the
+// configuration of the version at its base revision.
+func (pc *DBPatchConnector) GetPatchConfigDiff(patchId string) ([]model.ProjectChange, error) {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return nil, err
+	}
+	baseVersion, err := version.FindOne(version.ByProjectIdAndRevision(p.Project, p.Githash))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding base version of patch %s", patchId)
+	}
+	if baseVersion == nil {
+		return nil, &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("base version of patch %s not found", patchId),
+		}
+	}
+	return diffPatchConfig(p, baseVersion.Config)
+}
+
+// diffPatchConfig compares the patch's project configuration to the base
+// configuration.
+func diffPatchConfig(p *patch.Patch, baseConfig string) ([]model.ProjectChange, error) {
+	if p.PatchedConfig == "" {
+		return nil, &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("patch %s has no project configuration", p.Id.Hex()),
+		}
+	}
+	base := &model.Project{}
+	if err := model.LoadProjectInto([]byte(baseConfig), p.Project, base); err != nil {
+		return nil, errors.Wrap(err, "problem loading base project configuration")
+	}
+	patched := &model.Project{}
+	if err := model.LoadProjectInto([]byte(p.PatchedConfig), p.Project, patched); err != nil {
+		return nil, errors.Wrap(err, "problem loading patched project configuration")
+	}
+	return model.DiffProjects(base, patched), nil
+}
+
 // AbortPatch uses the service level CancelPatch method to abort a single patch
 // with matching Id.
 func (pc *DBPatchConnector) AbortPatch(patchId string, user string) error {
@@ -113,6 +154,10 @@ type MockPatchConnector struct {
 	CachedPatches  []patch.Patch
 	CachedAborted  map[string]string
 	CachedPriority map[string]int64
+
+	// CachedBaseConfigs maps the base revisions of patches to the project
+	// configurations at those revisions.
+	CachedBaseConfigs map[string]string
 }
 
 // FindPatchesByProject queries the cached patches splice for the matching patches.
@@ -159,6 +204,23 @@ func (pc *MockPatchConnector) FindPatchById(patchId string) (*patch.Patch, error
 	}
 }
 
+// GetPatchConfigDiff compares the patch's project configuration to the
+// configuration in CachedBaseConfigs for its base revision.
+func (pc *MockPatchConnector) GetPatchConfigDiff(patchId string) ([]model.ProjectChange, error) {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return nil, err
+	}
+	baseConfig, ok := pc.CachedBaseConfigs[p.Githash]
+	if !ok {
+		return nil, &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("base version of patch %s not found", patchId),
+		}
+	}
+	return diffPatchConfig(p, baseConfig)
+}
+
 // AbortPatch sets the value of patchId in CachedAborted to user.
 func (pc *MockPatchConnector) AbortPatch(patchId string, user string) error {
 	var foundPatch *patch.Patch
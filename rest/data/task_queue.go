@@ -0,0 +1,124 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/pkg/errors"
+)
+
+// DBTaskQueueConnector is a struct that implements the task queue related
+// methods from the Connector through interactions with the backing database.
+type DBTaskQueueConnector struct{}
+
+// FindTaskQueueForDistro queries the database for the task queue of the
+// given distro, including any manual overrides.
+func (tqc *DBTaskQueueConnector) FindTaskQueueForDistro(distroId string) (*model.TaskQueue, error) {
+	taskQueue, err := model.FindTaskQueueForDistro(distroId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding task queue for distro %s", distroId)
+	}
+	if taskQueue == nil {
+		return nil, &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task queue for distro %s not found", distroId),
+		}
+	}
+	return taskQueue, nil
+}
+
+// CountHostsForDistro returns the number of working hosts of the distro,
+// which is used to estimate when queued tasks will start.
+func (tqc *DBTaskQueueConnector) CountHostsForDistro(distroId string) (int, error) {
+	return host.Count(host.ByDistroId(distroId))
+}
+
+// PinTaskInQueue pins the task to the front of the distro's queue.
+func (tqc *DBTaskQueueConnector) PinTaskInQueue(distroId, taskId string) error {
+	return model.PinTaskInQueue(distroId, taskId)
+}
+
+// HoldTaskInQueue holds the task out of the distro's queue.
+func (tqc *DBTaskQueueConnector) HoldTaskInQueue(distroId, taskId string) error {
+	return model.HoldTaskInQueue(distroId, taskId)
+}
+
+// ReleaseTaskInQueue removes any pin or hold on the task in the distro's queue.
+func (tqc *DBTaskQueueConnector) ReleaseTaskInQueue(distroId, taskId string) error {
+	return model.ReleaseTaskInQueue(distroId, taskId)
+}
+
+// MockTaskQueueConnector is a struct that implements mock versions of
+// task queue related methods for testing.
+type MockTaskQueueConnector struct {
+	CachedTaskQueues []model.TaskQueue
+	CachedHostCounts map[string]int
+}
+
+// FindTaskQueueForDistro returns the cached task queue for the distro.
+func (tqc *MockTaskQueueConnector) FindTaskQueueForDistro(distroId string) (*model.TaskQueue, error) {
+	for i := range tqc.CachedTaskQueues {
+		if tqc.CachedTaskQueues[i].Distro == distroId {
+			return &tqc.CachedTaskQueues[i], nil
+		}
+	}
+	return nil, &rest.APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("task queue for distro %s not found", distroId),
+	}
+}
+
+// CountHostsForDistro returns the cached host count for the distro.
+func (tqc *MockTaskQueueConnector) CountHostsForDistro(distroId string) (int, error) {
+	return tqc.CachedHostCounts[distroId], nil
+}
+
+// PinTaskInQueue records the pin on the cached task queue.
+func (tqc *MockTaskQueueConnector) PinTaskInQueue(distroId, taskId string) error {
+	tq, err := tqc.FindTaskQueueForDistro(distroId)
+	if err != nil {
+		return err
+	}
+	tq.HeldTasks = removeString(tq.HeldTasks, taskId)
+	if !tq.IsPinned(taskId) {
+		tq.PinnedTasks = append(tq.PinnedTasks, taskId)
+	}
+	return nil
+}
+
+// HoldTaskInQueue records the hold on the cached task queue.
+func (tqc *MockTaskQueueConnector) HoldTaskInQueue(distroId, taskId string) error {
+	tq, err := tqc.FindTaskQueueForDistro(distroId)
+	if err != nil {
+		return err
+	}
+	tq.PinnedTasks = removeString(tq.PinnedTasks, taskId)
+	if !tq.IsHeld(taskId) {
+		tq.HeldTasks = append(tq.HeldTasks, taskId)
+	}
+	return nil
+}
+
+// ReleaseTaskInQueue removes the pin or hold from the cached task queue.
+func (tqc *MockTaskQueueConnector) ReleaseTaskInQueue(distroId, taskId string) error {
+	tq, err := tqc.FindTaskQueueForDistro(distroId)
+	if err != nil {
+		return err
+	}
+	tq.PinnedTasks = removeString(tq.PinnedTasks, taskId)
+	tq.HeldTasks = removeString(tq.HeldTasks, taskId)
+	return nil
+}
+
+func removeString(in []string, str string) []string {
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s != str {
+			out = append(out, s)
+		}
+	}
+	return out
+}
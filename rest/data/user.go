@@ -2,10 +2,12 @@ package data
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest"
 	"github.com/pkg/errors"
 )
 
@@ -31,6 +33,21 @@ func (u *DBUserConnector) DeletePublicKey(user *user.DBUser, keyName string) err
 	return user.DeletePublicKey(keyName)
 }
 
+// SetUserTeam assigns the user with the given ID to a team.
+func (u *DBUserConnector) SetUserTeam(userId, team string) error {
+	dbUser, err := user.FindOne(user.ById(userId))
+	if err != nil {
+		return errors.Wrapf(err, "error finding user '%s'", userId)
+	}
+	if dbUser == nil {
+		return &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("user '%s' not found", userId),
+		}
+	}
+	return dbUser.SetTeam(team)
+}
+
 // MockUserConnector stores a cached set of users that are queried against by the
 // implementations of the UserConnector interface's functions.
 type MockUserConnector struct {
@@ -86,3 +103,15 @@ func (muc *MockUserConnector) DeletePublicKey(u *user.DBUser, keyName string) er
 	cu.PubKeys = newKeys
 	return nil
 }
+
+func (muc *MockUserConnector) SetUserTeam(userId, team string) error {
+	u, ok := muc.CachedUsers[userId]
+	if !ok {
+		return &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("user '%s' not found", userId),
+		}
+	}
+	u.Team = team
+	return nil
+}
@@ -54,6 +54,15 @@ func (vc *DBVersionConnector) FindVersionById(versionId string) (*version.Versio
 	return v, nil
 }
 
+// FindTaskGraphByVersionId builds the dependency graph of the tasks of the
+// version with the given versionId.
+func (vc *DBVersionConnector) FindTaskGraphByVersionId(versionId string) (*model.TaskGraph, error) {
+	if _, err := vc.FindVersionById(versionId); err != nil {
+		return nil, err
+	}
+	return model.FindTaskGraph(versionId)
+}
+
 // AbortVersion aborts all tasks of a version given its ID.
 // It wraps the service level AbortVersion.
 func (vc *DBVersionConnector) AbortVersion(versionId string) error {
@@ -130,6 +139,21 @@ func (mvc *MockVersionConnector) FindVersionById(versionId string) (*version.Ver
 	}
 }
 
+// FindTaskGraphByVersionId is the mock implementation of the function for the
+// Connector interface. It builds the graph from the cached tasks of the version.
+func (mvc *MockVersionConnector) FindTaskGraphByVersionId(versionId string) (*model.TaskGraph, error) {
+	if _, err := mvc.FindVersionById(versionId); err != nil {
+		return nil, err
+	}
+	tasks := []task.Task{}
+	for _, t := range mvc.CachedTasks {
+		if t.Version == versionId {
+			tasks = append(tasks, t)
+		}
+	}
+	return model.NewTaskGraph(versionId, tasks)
+}
+
 // AbortVersion aborts all tasks of a version given its ID. Specifically, it sets the
 // Aborted key of the tasks to true if they are currently in abortable statuses.
 func (mvc *MockVersionConnector) AbortVersion(versionId string) error {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/pkg/errors"
 )
 
@@ -150,3 +151,39 @@ func (rtr *RestartTasksResponse) BuildFromService(h interface{}) error {
 func (rtr *RestartTasksResponse) ToService() (interface{}, error) {
 	return nil, errors.New("ToService not implemented for RestartTasksResponse")
 }
+
+// APIAgentRollout is the model of a staged rollout of an agent revision to a
+// distro, as returned by the /admin/agent_rollouts routes
+type APIAgentRollout struct {
+	Distro           APIString `json:"distro"`
+	Revision         APIString `json:"revision"`
+	PreviousRevision APIString `json:"previous_revision"`
+	Stage            int       `json:"stage"`
+	StageStartTime   APITime   `json:"stage_start_time"`
+	Status           APIString `json:"status"`
+	Reason           APIString `json:"reason"`
+	CreateTime       APITime   `json:"create_time"`
+}
+
+// BuildFromService builds a model from the service layer
+func (ar *APIAgentRollout) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case *host.AgentRollout:
+		ar.Distro = APIString(v.Distro)
+		ar.Revision = APIString(v.Revision)
+		ar.PreviousRevision = APIString(v.PreviousRevision)
+		ar.Stage = v.Stage
+		ar.StageStartTime = NewTime(v.StageStartTime)
+		ar.Status = APIString(v.Status)
+		ar.Reason = APIString(v.Reason)
+		ar.CreateTime = NewTime(v.CreateTime)
+	default:
+		return errors.Errorf("%T is not a supported agent rollout type", h)
+	}
+	return nil
+}
+
+// ToService is not implemented for agent rollouts
+func (ar *APIAgentRollout) ToService() (interface{}, error) {
+	return nil, errors.New("ToService not implemented for APIAgentRollout")
+}
@@ -40,6 +40,7 @@ type APIDistroCost struct {
 	InstanceType  APIString   `json:"instance_type,omitempty"`
 	EstimatedCost float64     `json:"estimated_cost"`
 	NumTasks      int         `json:"num_tasks"`
+	WarmPoolCost  float64     `json:"warm_pool_cost"`
 }
 
 // BuildFromService converts from a service level task by loading the data
@@ -52,6 +53,7 @@ func (apiDistroCost *APIDistroCost) BuildFromService(h interface{}) error {
 		apiDistroCost.Provider = APIString(v.Provider)
 		apiDistroCost.EstimatedCost = v.SumEstimatedCost
 		apiDistroCost.NumTasks = v.NumTasks
+		apiDistroCost.WarmPoolCost = v.SumWarmPoolCost
 
 		// InstanceType field is only set if the provider is ec2 or ec2-spot.
 		// It will default to an empty string for other providers.
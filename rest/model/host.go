@@ -3,8 +3,12 @@ package model
 import (
 	"fmt"
 
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/spawn"
+	"github.com/pkg/errors"
 )
 
 // APIHost is the model to be returned by the API whenever hosts are fetched.
@@ -23,8 +27,12 @@ type APIHost struct {
 
 // HostPostRequest is a struct that holds the format of a POST request to /hosts
 type HostPostRequest struct {
-	DistroID string `json:"distro"`
-	KeyName  string `json:"keyname"`
+	DistroID           string `json:"distro"`
+	KeyName            string `json:"keyname"`
+	TaskID             string `json:"task_id"`
+	UserData           string `json:"userdata"`
+	HomeVolumeSize     int    `json:"home_volume_size"`
+	ReplayUntilFailure bool   `json:"replay_until_failure"`
 }
 
 type DistroInfo struct {
@@ -114,3 +122,172 @@ type APISpawnHostModify struct {
 	RDPPwd   APIString `json:"rdp_pwd"`
 	AddHours APIString `json:"add_hours"`
 }
+
+// APISpawnHostQuota is the spawn host quota of a user, and of their team if it
+// has one, along with the usage counted against each.
+type APISpawnHostQuota struct {
+	User APISpawnHostLimits  `json:"user"`
+	Team *APISpawnHostLimits `json:"team,omitempty"`
+}
+
+// APISpawnHostLimits holds the limits of a single spawn host quota. Limits of
+// zero are not enforced. Hosts and MonthlyCost are the current usage.
+type APISpawnHostLimits struct {
+	Name               APIString `json:"name"`
+	MaxHosts           int       `json:"max_hosts"`
+	MaxMonthlyCost     float64   `json:"max_monthly_cost"`
+	MaxExpirationHours int       `json:"max_expiration_hours"`
+	Hosts              int       `json:"hosts"`
+	MonthlyCost        float64   `json:"monthly_cost"`
+}
+
+// BuildFromService converts from a spawn.Quota to an APISpawnHostQuota.
+func (apiQuota *APISpawnHostQuota) BuildFromService(h interface{}) error {
+	v, ok := h.(*spawn.Quota)
+	if !ok {
+		return errors.Errorf("incorrect type when converting spawn host quota: %T", h)
+	}
+
+	apiQuota.User = buildSpawnHostLimits(v.User, v.UserUsage)
+	if v.Team != nil {
+		team := buildSpawnHostLimits(*v.Team, v.TeamUsage)
+		apiQuota.Team = &team
+	}
+	return nil
+}
+
+// ToService is not implemented for APISpawnHostQuota.
+func (apiQuota *APISpawnHostQuota) ToService() (interface{}, error) {
+	return nil, errors.New("ToService() is not implemented for APISpawnHostQuota")
+}
+
+// ToService returns the limits as an evergreen.SpawnHostQuota. Usage is
+// ignored.
+func (limits *APISpawnHostLimits) ToService() (interface{}, error) {
+	return evergreen.SpawnHostQuota{
+		Name:               string(limits.Name),
+		MaxHosts:           limits.MaxHosts,
+		MaxMonthlyCost:     limits.MaxMonthlyCost,
+		MaxExpirationHours: limits.MaxExpirationHours,
+	}, nil
+}
+
+func buildSpawnHostLimits(quota evergreen.SpawnHostQuota, usage spawn.QuotaUsage) APISpawnHostLimits {
+	return APISpawnHostLimits{
+		Name:               APIString(quota.Name),
+		MaxHosts:           quota.MaxHosts,
+		MaxMonthlyCost:     quota.MaxMonthlyCost,
+		MaxExpirationHours: quota.MaxExpirationHours,
+		Hosts:              usage.Hosts,
+		MonthlyCost:        usage.MonthlyCost,
+	}
+}
+
+// APIHostEvent is the model of a single event in a host's history.
+type APIHostEvent struct {
+	Timestamp APITime       `json:"timestamp"`
+	EventType APIString     `json:"event_type"`
+	Data      HostEventData `json:"data"`
+}
+
+// HostEventData holds the details of a host event. Fields that do not apply
+// to an event's type are left empty.
+type HostEventData struct {
+	AgentRevision APIString   `json:"agent_revision,omitempty"`
+	OldStatus     APIString   `json:"old_status,omitempty"`
+	NewStatus     APIString   `json:"new_status,omitempty"`
+	Logs          APIString   `json:"logs,omitempty"`
+	Hostname      APIString   `json:"hostname,omitempty"`
+	TaskId        APIString   `json:"task_id,omitempty"`
+	TaskPid       APIString   `json:"task_pid,omitempty"`
+	TaskStatus    APIString   `json:"task_status,omitempty"`
+	Execution     APIString   `json:"execution,omitempty"`
+	MonitorOp     APIString   `json:"monitor_op,omitempty"`
+	User          APIString   `json:"user,omitempty"`
+	Successful    bool        `json:"successful,omitempty"`
+	Duration      APIDuration `json:"duration,omitempty"`
+}
+
+// BuildFromService converts from a service level event to an APIHostEvent.
+func (apiEvent *APIHostEvent) BuildFromService(h interface{}) error {
+	e, ok := h.(*event.Event)
+	if !ok {
+		return errors.Errorf("%T is not a supported host event type", h)
+	}
+	apiEvent.Timestamp = NewTime(e.Timestamp)
+	apiEvent.EventType = APIString(e.EventType)
+
+	data, ok := e.Data.Data.(*event.HostEventData)
+	if !ok {
+		return errors.Errorf("event of type %s does not hold host event data", e.EventType)
+	}
+	apiEvent.Data = HostEventData{
+		AgentRevision: APIString(data.AgentRevision),
+		OldStatus:     APIString(data.OldStatus),
+		NewStatus:     APIString(data.NewStatus),
+		Logs:          APIString(data.Logs),
+		Hostname:      APIString(data.Hostname),
+		TaskId:        APIString(data.TaskId),
+		TaskPid:       APIString(data.TaskPid),
+		TaskStatus:    APIString(data.TaskStatus),
+		Execution:     APIString(data.Execution),
+		MonitorOp:     APIString(data.MonitorOp),
+		User:          APIString(data.User),
+		Successful:    data.Successful,
+		Duration:      NewAPIDuration(data.Duration),
+	}
+	return nil
+}
+
+// ToService returns a service layer event using the data from APIHostEvent.
+func (apiEvent *APIHostEvent) ToService() (interface{}, error) {
+	return nil, errors.New("ToService not implemented for APIHostEvent")
+}
+
+// APIHostLifecycleTimings is the model of how long a distro's hosts took to
+// come up. Durations are in milliseconds.
+type APIHostLifecycleTimings struct {
+	Distro               APIString        `json:"distro"`
+	Since                APITime          `json:"since"`
+	Hosts                int              `json:"hosts"`
+	RequestToRunning     APIDurationStats `json:"request_to_running"`
+	RunningToProvisioned APIDurationStats `json:"running_to_provisioned"`
+}
+
+// APIDurationStats summarizes a set of durations.
+type APIDurationStats struct {
+	Count  int         `json:"count"`
+	Mean   APIDuration `json:"mean"`
+	Median APIDuration `json:"median"`
+	P90    APIDuration `json:"p90"`
+	Max    APIDuration `json:"max"`
+}
+
+// BuildFromService converts from service level lifecycle timings.
+func (apiTimings *APIHostLifecycleTimings) BuildFromService(h interface{}) error {
+	timings, ok := h.(*host.LifecycleTimings)
+	if !ok {
+		return errors.Errorf("%T is not a supported lifecycle timings type", h)
+	}
+	apiTimings.Distro = APIString(timings.Distro)
+	apiTimings.Since = NewTime(timings.Since)
+	apiTimings.Hosts = timings.Hosts
+	apiTimings.RequestToRunning = buildDurationStats(timings.RequestToRunning)
+	apiTimings.RunningToProvisioned = buildDurationStats(timings.RunningToProvisioned)
+	return nil
+}
+
+// ToService is not implemented for lifecycle timings.
+func (apiTimings *APIHostLifecycleTimings) ToService() (interface{}, error) {
+	return nil, errors.New("ToService not implemented for APIHostLifecycleTimings")
+}
+
+func buildDurationStats(stats host.DurationStats) APIDurationStats {
+	return APIDurationStats{
+		Count:  stats.Count,
+		Mean:   NewAPIDuration(stats.Mean),
+		Median: NewAPIDuration(stats.Median),
+		P90:    NewAPIDuration(stats.P90),
+		Max:    NewAPIDuration(stats.Max),
+	}
+}
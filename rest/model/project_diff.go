@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/pkg/errors"
+)
+
+// APIProjectChange is the model to be returned by the API for a change that
+// a patch makes to its project's configuration.
+type APIProjectChange struct {
+	Kind    APIString   `json:"kind"`
+	Name    APIString   `json:"name"`
+	Change  APIString   `json:"change"`
+	Details []APIString `json:"details"`
+}
+
+// BuildFromService converts from a service level ProjectChange to an
+// APIProjectChange.
+func (apiChange *APIProjectChange) BuildFromService(h interface{}) error {
+	v, ok := h.(model.ProjectChange)
+	if !ok {
+		return errors.Errorf("incorrect type when converting project change type")
+	}
+	apiChange.Kind = APIString(v.Kind)
+	apiChange.Name = APIString(v.Name)
+	apiChange.Change = APIString(v.Change)
+	apiChange.Details = []APIString{}
+	for _, detail := range v.Details {
+		apiChange.Details = append(apiChange.Details, APIString(detail))
+	}
+	return nil
+}
+
+// ToService is not implemented for APIProjectChange.
+func (apiChange *APIProjectChange) ToService() (interface{}, error) {
+	return nil, errors.Errorf("ToService() is not implemented for APIProjectChange")
+}
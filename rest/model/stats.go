@@ -18,6 +18,7 @@ type APITaskStats struct {
 	SystemUnresponsive int `json:"system-unresponsive"`
 	SystemTimedOut     int `json:"system-timed-out"`
 	TestTimedOut       int `json:"test-timed-out"`
+	PassedOnRetry      int `json:"passed-on-retry"`
 }
 
 // BuildFromService converts from service level structs to an APITaskStats.
@@ -34,6 +35,7 @@ func (apiStatus *APITaskStats) BuildFromService(h interface{}) error {
 		apiStatus.SystemUnresponsive = v.SystemUnresponsive
 		apiStatus.SystemTimedOut = v.SystemTimedOut
 		apiStatus.TestTimedOut = v.TestTimedOut
+		apiStatus.PassedOnRetry = v.PassedOnRetry
 	default:
 		return errors.Errorf("incorrect type when converting result counts (%T)", v)
 	}
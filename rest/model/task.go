@@ -35,6 +35,7 @@ type APITask struct {
 	DisplayName        APIString        `json:"display_name"`
 	HostId             APIString        `json:"host_id"`
 	Restarts           int              `json:"restarts"`
+	AutoRetries        int              `json:"auto_retries"`
 	Execution          int              `json:"execution"`
 	Order              int              `json:"order"`
 	Status             APIString        `json:"status"`
@@ -96,6 +97,7 @@ func (at *APITask) BuildFromService(t interface{}) error {
 			DisplayName:   APIString(v.DisplayName),
 			HostId:        APIString(v.HostId),
 			Restarts:      v.Restarts,
+			AutoRetries:   v.AutoRetries,
 			Execution:     v.Execution,
 			Order:         v.RevisionOrderNumber,
 			Details: apiTaskEndDetail{
@@ -154,6 +156,7 @@ func (ad *APITask) ToService() (interface{}, error) {
 		DisplayName:         string(ad.DisplayName),
 		HostId:              string(ad.HostId),
 		Restarts:            ad.Restarts,
+		AutoRetries:         ad.AutoRetries,
 		Execution:           ad.Execution,
 		RevisionOrderNumber: ad.Order,
 		Details: apimodels.TaskEndDetail{
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/pkg/errors"
+)
+
+// APITaskGraph is the model to be returned by the API when fetching the
+// dependency graph of a version's tasks.
+type APITaskGraph struct {
+	VersionId    APIString          `json:"version_id"`
+	Tasks        []APITaskGraphNode `json:"tasks"`
+	CriticalPath []APIString        `json:"critical_path"`
+	Makespan     APIDuration        `json:"makespan_ms"`
+}
+
+// APITaskGraphNode is a task in an APITaskGraph.
+type APITaskGraphNode struct {
+	TaskId           APIString   `json:"task_id"`
+	DisplayName      APIString   `json:"display_name"`
+	BuildVariant     APIString   `json:"build_variant"`
+	Status           APIString   `json:"status"`
+	DependsOn        []APIString `json:"depends_on"`
+	ActualDuration   APIDuration `json:"actual_duration_ms"`
+	ExpectedDuration APIDuration `json:"expected_duration_ms"`
+	Finished         bool        `json:"finished"`
+	OnCriticalPath   bool        `json:"on_critical_path"`
+}
+
+// BuildFromService converts from a service level task graph.
+func (g *APITaskGraph) BuildFromService(h interface{}) error {
+	v, ok := h.(*model.TaskGraph)
+	if !ok {
+		return errors.Errorf("incorrect type when converting task graph: %T", h)
+	}
+	g.VersionId = APIString(v.VersionId)
+	g.Makespan = NewAPIDuration(v.Makespan)
+	g.CriticalPath = []APIString{}
+	for _, id := range v.CriticalPath {
+		g.CriticalPath = append(g.CriticalPath, APIString(id))
+	}
+	g.Tasks = []APITaskGraphNode{}
+	for _, node := range v.Nodes {
+		apiNode := APITaskGraphNode{
+			TaskId:           APIString(node.TaskId),
+			DisplayName:      APIString(node.DisplayName),
+			BuildVariant:     APIString(node.BuildVariant),
+			Status:           APIString(node.Status),
+			DependsOn:        []APIString{},
+			ActualDuration:   NewAPIDuration(node.ActualDuration),
+			ExpectedDuration: NewAPIDuration(node.ExpectedDuration),
+			Finished:         node.Finished,
+			OnCriticalPath:   node.OnCriticalPath,
+		}
+		for _, dep := range node.DependsOn {
+			apiNode.DependsOn = append(apiNode.DependsOn, APIString(dep))
+		}
+		g.Tasks = append(g.Tasks, apiNode)
+	}
+	return nil
+}
+
+// ToService returns a service layer task graph using the data from the
+// APITaskGraph.
+func (g *APITaskGraph) ToService() (interface{}, error) {
+	graph := &model.TaskGraph{
+		VersionId:    string(g.VersionId),
+		Makespan:     g.Makespan.ToDuration(),
+		CriticalPath: []string{},
+		Nodes:        []model.TaskGraphNode{},
+	}
+	for _, id := range g.CriticalPath {
+		graph.CriticalPath = append(graph.CriticalPath, string(id))
+	}
+	for _, apiNode := range g.Tasks {
+		node := model.TaskGraphNode{
+			TaskId:           string(apiNode.TaskId),
+			DisplayName:      string(apiNode.DisplayName),
+			BuildVariant:     string(apiNode.BuildVariant),
+			Status:           string(apiNode.Status),
+			DependsOn:        []string{},
+			ActualDuration:   apiNode.ActualDuration.ToDuration(),
+			ExpectedDuration: apiNode.ExpectedDuration.ToDuration(),
+			Finished:         apiNode.Finished,
+			OnCriticalPath:   apiNode.OnCriticalPath,
+		}
+		for _, dep := range apiNode.DependsOn {
+			node.DependsOn = append(node.DependsOn, string(dep))
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	return graph, nil
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+)
+
+// APITaskQueueItem is the model to be returned by the API when a distro's
+// task queue is fetched. Held tasks are not part of the queue and have no
+// position or expected start time.
+type APITaskQueueItem struct {
+	Id                APIString   `json:"id"`
+	DisplayName       APIString   `json:"display_name"`
+	BuildVariant      APIString   `json:"build_variant"`
+	Version           APIString   `json:"version"`
+	Project           APIString   `json:"project"`
+	Requester         APIString   `json:"requester"`
+	Priority          int64       `json:"priority"`
+	Position          int         `json:"position"`
+	ExpectedDuration  APIDuration `json:"expected_duration_ms"`
+	ExpectedStartTime APITime     `json:"expected_start_time"`
+	Pinned            bool        `json:"pinned"`
+	Held              bool        `json:"held"`
+	Reason            APIString   `json:"reason"`
+}
+
+// BuildFromService converts from a service level task queue item, or a held
+// task, to an APITaskQueueItem.
+func (item *APITaskQueueItem) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case model.TaskQueueItem:
+		item.Id = APIString(v.Id)
+		item.DisplayName = APIString(v.DisplayName)
+		item.BuildVariant = APIString(v.BuildVariant)
+		item.Version = APIString(v.Version)
+		item.Project = APIString(v.Project)
+		item.Requester = APIString(v.Requester)
+		item.Priority = v.Priority
+		item.ExpectedDuration = NewAPIDuration(v.ExpectedDuration)
+	case task.Task:
+		item.Id = APIString(v.Id)
+		item.DisplayName = APIString(v.DisplayName)
+		item.BuildVariant = APIString(v.BuildVariant)
+		item.Version = APIString(v.Version)
+		item.Project = APIString(v.Project)
+		item.Requester = APIString(v.Requester)
+		item.Priority = v.Priority
+		item.ExpectedDuration = NewAPIDuration(v.ExpectedDuration)
+	default:
+		return errors.Errorf("incorrect type when converting task queue item type")
+	}
+	return nil
+}
+
+// ToService returns a service layer task queue item using the data from
+// APITaskQueueItem.
+func (item *APITaskQueueItem) ToService() (interface{}, error) {
+	return nil, errors.Errorf("ToService() is not implemented for APITaskQueueItem")
+}
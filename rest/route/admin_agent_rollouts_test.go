@@ -0,0 +1,63 @@
+package route
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentRolloutRoutes(t *testing.T) {
+	assert := assert.New(t)
+	sc := &data.MockConnector{}
+	sc.MockAdminConnector.MockAgentRollouts = []host.AgentRollout{
+		{ID: "d1_old", Distro: "d1", Revision: "old", Status: host.AgentRolloutCompleted},
+		{ID: "d1_new", Distro: "d1", Revision: "new", PreviousRevision: "old", Status: host.AgentRolloutActive, Stage: 1},
+		{ID: "d2_new", Distro: "d2", Revision: "new", PreviousRevision: "old", Status: host.AgentRolloutRolledBack, Stage: 1, Reason: "too many failures"},
+	}
+	ctx := context.WithValue(context.Background(), evergreen.RequestUser, &user.DBUser{Id: "user"})
+
+	// listing returns only unfinished rollouts
+	routeManager := getAgentRolloutsRouteManager("/admin/agent_rollouts", 2)
+	getHandler := routeManager.Methods[0].RequestHandler
+	assert.IsType(&SuperUserAuthenticator{}, routeManager.Methods[0].Authenticator)
+	resp, err := getHandler.Execute(ctx, sc)
+	assert.NoError(err)
+	assert.Len(resp.Result, 2)
+	assert.Equal(model.APIString("d1"), resp.Result[0].(*model.APIAgentRollout).Distro)
+
+	pause := getAgentRolloutPauseRouteManager(true)("/admin/agent_rollouts/{distro_id}/pause", 2).Methods[0].RequestHandler.Handler()
+	resume := getAgentRolloutPauseRouteManager(false)("/admin/agent_rollouts/{distro_id}/resume", 2).Methods[0].RequestHandler.Handler()
+
+	// pausing and resuming act on the distro's latest rollout
+	pause.(*agentRolloutPauseHandler).distroID = "d1"
+	resp, err = pause.Execute(ctx, sc)
+	assert.NoError(err)
+	assert.Equal(model.APIString(host.AgentRolloutPaused), resp.Result[0].(*model.APIAgentRollout).Status)
+	assert.Equal(host.AgentRolloutPaused, sc.MockAdminConnector.MockAgentRollouts[1].Status)
+
+	_, err = pause.Execute(ctx, sc)
+	assert.Error(err)
+
+	resume.(*agentRolloutPauseHandler).distroID = "d1"
+	_, err = resume.Execute(ctx, sc)
+	assert.NoError(err)
+	assert.Equal(host.AgentRolloutActive, sc.MockAdminConnector.MockAgentRollouts[1].Status)
+	assert.Equal(1, sc.MockAdminConnector.MockAgentRollouts[1].Stage)
+
+	// resuming a rolled back rollout starts it over
+	resume.(*agentRolloutPauseHandler).distroID = "d2"
+	_, err = resume.Execute(ctx, sc)
+	assert.NoError(err)
+	assert.Equal(host.AgentRolloutActive, sc.MockAdminConnector.MockAgentRollouts[2].Status)
+	assert.Equal(0, sc.MockAdminConnector.MockAgentRollouts[2].Stage)
+
+	resume.(*agentRolloutPauseHandler).distroID = "d3"
+	_, err = resume.Execute(ctx, sc)
+	assert.Error(err)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
+	"github.com/gorilla/mux"
 	"github.com/mongodb/amboy"
 	"github.com/pkg/errors"
 )
@@ -308,3 +309,113 @@ func (h *restartHandler) Execute(ctx context.Context, sc data.Connector) (Respon
 		Result: []model.Model{restartModel},
 	}, nil
 }
+
+// this manages the /admin/agent_rollouts route, which lists the agent rollouts
+// that have not completed
+func getAgentRolloutsRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &SuperUserAuthenticator{},
+				RequestHandler:    &agentRolloutsGetHandler{},
+				MethodType:        http.MethodGet,
+			},
+		},
+	}
+}
+
+type agentRolloutsGetHandler struct{}
+
+func (h *agentRolloutsGetHandler) Handler() RequestHandler {
+	return &agentRolloutsGetHandler{}
+}
+
+func (h *agentRolloutsGetHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *agentRolloutsGetHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	rollouts, err := sc.FindUnfinishedAgentRollouts()
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+
+	result := ResponseData{Result: []model.Model{}}
+	for i := range rollouts {
+		rolloutModel := &model.APIAgentRollout{}
+		if err = rolloutModel.BuildFromService(&rollouts[i]); err != nil {
+			return ResponseData{}, errors.Wrap(err, "API model error")
+		}
+		result.Result = append(result.Result, rolloutModel)
+	}
+	return result, nil
+}
+
+// this manages the /admin/agent_rollouts/{distro_id}/pause and
+// /admin/agent_rollouts/{distro_id}/resume routes, which hold and continue the
+// latest agent rollout to a distro
+func getAgentRolloutPauseRouteManager(pause bool) routeManagerFactory {
+	return func(route string, version int) *RouteManager {
+		return &RouteManager{
+			Route:   route,
+			Version: version,
+			Methods: []MethodHandler{
+				{
+					PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+					Authenticator:     &SuperUserAuthenticator{},
+					RequestHandler:    &agentRolloutPauseHandler{pause: pause},
+					MethodType:        http.MethodPost,
+				},
+			},
+		}
+	}
+}
+
+type agentRolloutPauseHandler struct {
+	distroID string
+	pause    bool
+}
+
+func (h *agentRolloutPauseHandler) Handler() RequestHandler {
+	return &agentRolloutPauseHandler{pause: h.pause}
+}
+
+func (h *agentRolloutPauseHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	h.distroID = mux.Vars(r)["distro_id"]
+	return nil
+}
+
+func (h *agentRolloutPauseHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	rollout, err := sc.FindLatestAgentRollout(h.distroID)
+	if err != nil {
+		return ResponseData{}, err
+	}
+
+	if h.pause {
+		err = sc.PauseAgentRollout(rollout, u)
+	} else {
+		err = sc.ResumeAgentRollout(rollout, u)
+	}
+	if err != nil {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	rolloutModel := &model.APIAgentRollout{}
+	if err = rolloutModel.BuildFromService(rollout); err != nil {
+		return ResponseData{}, errors.Wrap(err, "API model error")
+	}
+	return ResponseData{
+		Result: []model.Model{rolloutModel},
+	}, nil
+}
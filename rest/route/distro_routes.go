@@ -2,11 +2,19 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/evergreen-ci/evergreen"
+	dataModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/rest"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/gorilla/mux"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 )
 
@@ -55,3 +63,317 @@ func (dgh *distroGetHandler) Execute(ctx context.Context, sc data.Connector) (Re
 		Result: models,
 	}, nil
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for the task queue of a distro
+//
+//    /distros/{distro_id}/queue
+
+func getDistroQueueRouteManager(route string, version int) *RouteManager {
+	h := &distroQueueGetHandler{}
+	return &RouteManager{
+		Route: route,
+		Methods: []MethodHandler{
+			{
+				Authenticator:  &NoAuthAuthenticator{},
+				RequestHandler: h.Handler(),
+				MethodType:     http.MethodGet,
+			},
+		},
+		Version: version,
+	}
+}
+
+type distroQueueGetHandler struct {
+	distroId string
+}
+
+func (h *distroQueueGetHandler) Handler() RequestHandler {
+	return &distroQueueGetHandler{}
+}
+
+func (h *distroQueueGetHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	h.distroId = mux.Vars(r)["distro_id"]
+	if h.distroId == "" {
+		return rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "distro id cannot be empty",
+		}
+	}
+	return nil
+}
+
+// Execute returns the queued tasks in order, each annotated with its
+// position, when it is expected to start given the distro's current hosts,
+// and why it is in that position, followed by any held tasks.
+func (h *distroQueueGetHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	taskQueue, err := sc.FindTaskQueueForDistro(h.distroId)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+
+	numHosts, err := sc.CountHostsForDistro(h.distroId)
+	if err != nil {
+		return ResponseData{}, errors.Wrap(err, "Database error")
+	}
+	if numHosts < 1 {
+		numHosts = 1
+	}
+
+	now := time.Now()
+	var queuedDuration time.Duration
+	models := make([]model.Model, 0, len(taskQueue.Queue)+len(taskQueue.HeldTasks))
+	for i, it := range taskQueue.Queue {
+		item := &model.APITaskQueueItem{}
+		if err = item.BuildFromService(it); err != nil {
+			return ResponseData{}, errors.Wrap(err, "API model error")
+		}
+		item.Position = i + 1
+		item.Pinned = taskQueue.IsPinned(it.Id)
+		item.ExpectedStartTime = model.NewTime(now.Add(queuedDuration / time.Duration(numHosts)))
+		item.Reason = model.APIString(queuePositionReason(taskQueue, it))
+		queuedDuration += it.ExpectedDuration
+
+		models = append(models, item)
+	}
+
+	if len(taskQueue.HeldTasks) > 0 {
+		heldTasks, err := sc.FindTasksByIds(taskQueue.HeldTasks)
+		if err != nil {
+			if apiErr, ok := err.(*rest.APIError); !ok || apiErr.StatusCode != http.StatusNotFound {
+				return ResponseData{}, errors.Wrap(err, "Database error")
+			}
+		}
+		for _, t := range heldTasks {
+			if !taskQueue.IsHeld(t.Id) {
+				continue
+			}
+			item := &model.APITaskQueueItem{}
+			if err = item.BuildFromService(t); err != nil {
+				return ResponseData{}, errors.Wrap(err, "API model error")
+			}
+			item.Held = true
+			item.Reason = "held by an administrator"
+
+			models = append(models, item)
+		}
+	}
+
+	return ResponseData{
+		Result: models,
+	}, nil
+}
+
+// queuePositionReason explains, in terms of the rules the scheduler's
+// prioritizer applies, why a task is at its position in the queue.
+func queuePositionReason(taskQueue *dataModel.TaskQueue, it dataModel.TaskQueueItem) string {
+	switch {
+	case taskQueue.IsPinned(it.Id):
+		return "pinned to the front of the queue by an administrator"
+	case it.Priority > evergreen.MaxTaskPriority:
+		return fmt.Sprintf("high priority task (priority %d), placed ahead of all other tasks", it.Priority)
+	case it.Requester == evergreen.RepotrackerVersionRequester:
+		return fmt.Sprintf("mainline task (priority %d), interleaved with patch tasks "+
+			"and ordered by priority, number of dependents, age and runtime", it.Priority)
+	case evergreen.IsPatchRequester(it.Requester):
+		return fmt.Sprintf("patch task (priority %d), interleaved with mainline tasks "+
+			"and ordered by priority, number of dependents, age and runtime", it.Priority)
+	default:
+		return fmt.Sprintf("task with requester '%s'", it.Requester)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handlers for manually reordering the task queue of a distro
+//
+//    /distros/{distro_id}/queue/{task_id}/pin
+//    /distros/{distro_id}/queue/{task_id}/hold
+//    /distros/{distro_id}/queue/{task_id}/release
+
+const (
+	queueActionPin     = "pin"
+	queueActionHold    = "hold"
+	queueActionRelease = "release"
+)
+
+func getDistroQueuePinRouteManager(route string, version int) *RouteManager {
+	return makeDistroQueueModifyRouteManager(route, version, queueActionPin)
+}
+
+func getDistroQueueHoldRouteManager(route string, version int) *RouteManager {
+	return makeDistroQueueModifyRouteManager(route, version, queueActionHold)
+}
+
+func getDistroQueueReleaseRouteManager(route string, version int) *RouteManager {
+	return makeDistroQueueModifyRouteManager(route, version, queueActionRelease)
+}
+
+func makeDistroQueueModifyRouteManager(route string, version int, action string) *RouteManager {
+	h := &distroQueueModifyHandler{action: action}
+	return &RouteManager{
+		Route: route,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &SuperUserAuthenticator{},
+				RequestHandler:    h.Handler(),
+				MethodType:        http.MethodPost,
+			},
+		},
+		Version: version,
+	}
+}
+
+type distroQueueModifyHandler struct {
+	action   string
+	distroId string
+	taskId   string
+}
+
+func (h *distroQueueModifyHandler) Handler() RequestHandler {
+	return &distroQueueModifyHandler{action: h.action}
+}
+
+func (h *distroQueueModifyHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	vars := mux.Vars(r)
+	h.distroId = vars["distro_id"]
+	h.taskId = vars["task_id"]
+	if h.distroId == "" || h.taskId == "" {
+		return rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "distro id and task id cannot be empty",
+		}
+	}
+	return nil
+}
+
+func (h *distroQueueModifyHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	t, err := sc.FindTaskById(h.taskId)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+	if t == nil {
+		return ResponseData{}, rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task %s not found", h.taskId),
+		}
+	}
+	if t.DistroId != h.distroId {
+		return ResponseData{}, rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("task %s runs on distro '%s', not '%s'", h.taskId, t.DistroId, h.distroId),
+		}
+	}
+
+	switch h.action {
+	case queueActionPin:
+		err = sc.PinTaskInQueue(h.distroId, h.taskId)
+	case queueActionHold:
+		err = sc.HoldTaskInQueue(h.distroId, h.taskId)
+	case queueActionRelease:
+		err = sc.ReleaseTaskInQueue(h.distroId, h.taskId)
+	default:
+		err = errors.Errorf("unknown queue action '%s'", h.action)
+	}
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = &rest.APIError{
+				StatusCode: http.StatusInternalServerError,
+				Message:    err.Error(),
+			}
+		}
+		return ResponseData{}, err
+	}
+
+	grip.Info(message.Fields{
+		"message": "task queue manually modified",
+		"action":  h.action,
+		"distro":  h.distroId,
+		"task":    h.taskId,
+		"user":    MustHaveUser(ctx).Id,
+	})
+
+	return ResponseData{}, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /distros/{distro_id}/host_timings
+
+const defaultHostTimingsHours = 7 * 24
+
+func getDistroHostTimingsRouteManager(route string, version int) *RouteManager {
+	h := &distroHostTimingsHandler{}
+	return &RouteManager{
+		Route: route,
+		Methods: []MethodHandler{
+			{
+				Authenticator:  &NoAuthAuthenticator{},
+				RequestHandler: h.Handler(),
+				MethodType:     http.MethodGet,
+			},
+		},
+		Version: version,
+	}
+}
+
+type distroHostTimingsHandler struct {
+	distroId string
+	since    time.Time
+}
+
+func (h *distroHostTimingsHandler) Handler() RequestHandler {
+	return &distroHostTimingsHandler{}
+}
+
+func (h *distroHostTimingsHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	h.distroId = mux.Vars(r)["distro_id"]
+	if h.distroId == "" {
+		return rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "distro id cannot be empty",
+		}
+	}
+
+	hours := defaultHostTimingsHours
+	if param := r.URL.Query().Get("hours"); param != "" {
+		var err error
+		hours, err = strconv.Atoi(param)
+		if err != nil || hours <= 0 {
+			return rest.APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("Value '%s' provided for 'hours' must be a positive integer", param),
+			}
+		}
+	}
+	h.since = time.Now().Add(-time.Duration(hours) * time.Hour)
+	return nil
+}
+
+// Execute summarizes how long the distro's hosts created in the requested
+// window took to start running and to be provisioned.
+func (h *distroHostTimingsHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	timings, err := sc.GetHostLifecycleTimings(h.distroId, h.since)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+
+	timingsModel := &model.APIHostLifecycleTimings{}
+	if err = timingsModel.BuildFromService(timings); err != nil {
+		return ResponseData{}, errors.Wrap(err, "API model error")
+	}
+	return ResponseData{
+		Result: []model.Model{timingsModel},
+	}, nil
+}
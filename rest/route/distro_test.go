@@ -0,0 +1,157 @@
+package route
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// Tests for distro task queue routes
+
+type DistroQueueSuite struct {
+	sc *data.MockConnector
+
+	suite.Suite
+}
+
+func TestDistroQueueSuite(t *testing.T) {
+	suite.Run(t, new(DistroQueueSuite))
+}
+
+func (s *DistroQueueSuite) SetupTest() {
+	s.sc = &data.MockConnector{
+		MockTaskConnector: data.MockTaskConnector{
+			CachedTasks: []task.Task{
+				{Id: "t1", DistroId: "d1"},
+				{Id: "t2", DistroId: "d1"},
+				{Id: "t3", DistroId: "d1"},
+				{Id: "held", DisplayName: "compile", DistroId: "d1"},
+				{Id: "other", DistroId: "d2"},
+			},
+		},
+		MockTaskQueueConnector: data.MockTaskQueueConnector{
+			CachedTaskQueues: []serviceModel.TaskQueue{
+				{
+					Distro: "d1",
+					Queue: []serviceModel.TaskQueueItem{
+						{Id: "t1", ExpectedDuration: time.Hour, Requester: evergreen.RepotrackerVersionRequester},
+						{Id: "t2", ExpectedDuration: time.Hour, Requester: evergreen.PatchVersionRequester},
+						{Id: "t3", ExpectedDuration: time.Hour, Requester: evergreen.RepotrackerVersionRequester},
+					},
+					PinnedTasks: []string{"t1"},
+					HeldTasks:   []string{"held"},
+				},
+			},
+			CachedHostCounts: map[string]int{"d1": 2},
+		},
+	}
+}
+
+func (s *DistroQueueSuite) TestGetQueue() {
+	rm := getDistroQueueRouteManager("", 2)
+	handler := rm.Methods[0].Handler().(*distroQueueGetHandler)
+	handler.distroId = "d1"
+
+	res, err := handler.Execute(context.Background(), s.sc)
+	s.Require().NoError(err)
+	s.Require().Len(res.Result, 4)
+
+	first := res.Result[0].(*model.APITaskQueueItem)
+	s.Equal(model.APIString("t1"), first.Id)
+	s.Equal(1, first.Position)
+	s.True(first.Pinned)
+	s.Contains(string(first.Reason), "pinned")
+
+	second := res.Result[1].(*model.APITaskQueueItem)
+	s.Equal(2, second.Position)
+	s.False(second.Pinned)
+	s.Contains(string(second.Reason), "patch task")
+
+	// two hosts work through one hour of queued tasks in half an hour
+	third := res.Result[2].(*model.APITaskQueueItem)
+	s.WithinDuration(time.Time(first.ExpectedStartTime).Add(time.Hour), time.Time(third.ExpectedStartTime), time.Second)
+
+	held := res.Result[3].(*model.APITaskQueueItem)
+	s.Equal(model.APIString("held"), held.Id)
+	s.True(held.Held)
+	s.Equal(0, held.Position)
+}
+
+func (s *DistroQueueSuite) TestGetQueueForMissingDistro() {
+	handler := &distroQueueGetHandler{distroId: "missing"}
+	_, err := handler.Execute(context.Background(), s.sc)
+	s.Error(err)
+}
+
+func (s *DistroQueueSuite) TestModifyQueue() {
+	ctx := context.WithValue(context.Background(), evergreen.RequestUser, &user.DBUser{Id: "admin"})
+	tq := &s.sc.MockTaskQueueConnector.CachedTaskQueues[0]
+
+	handler := getDistroQueuePinRouteManager("", 2).Methods[0].Handler().(*distroQueueModifyHandler)
+	handler.distroId = "d1"
+	handler.taskId = "held"
+	_, err := handler.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Equal([]string{"t1", "held"}, tq.PinnedTasks)
+	s.Empty(tq.HeldTasks)
+
+	handler = getDistroQueueHoldRouteManager("", 2).Methods[0].Handler().(*distroQueueModifyHandler)
+	handler.distroId = "d1"
+	handler.taskId = "t1"
+	_, err = handler.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Equal([]string{"held"}, tq.PinnedTasks)
+	s.Equal([]string{"t1"}, tq.HeldTasks)
+
+	handler = getDistroQueueReleaseRouteManager("", 2).Methods[0].Handler().(*distroQueueModifyHandler)
+	handler.distroId = "d1"
+	handler.taskId = "t1"
+	_, err = handler.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Empty(tq.HeldTasks)
+
+	handler.taskId = "nonexistent"
+	_, err = handler.Execute(ctx, s.sc)
+	s.Error(err)
+
+	// tasks from another distro's queue can't be moved into this one
+	handler = getDistroQueuePinRouteManager("", 2).Methods[0].Handler().(*distroQueueModifyHandler)
+	handler.distroId = "d1"
+	handler.taskId = "other"
+	_, err = handler.Execute(ctx, s.sc)
+	s.Error(err)
+	s.Equal([]string{"held"}, tq.PinnedTasks)
+}
+
+func TestDistroHostTimings(t *testing.T) {
+	assert := assert.New(t)
+	sc := &data.MockConnector{}
+	sc.MockHostConnector.CachedTimings = map[string]host.LifecycleTimings{
+		"d1": {
+			Hosts:            3,
+			RequestToRunning: host.DurationStats{Count: 2, Mean: 3 * time.Minute, Max: 4 * time.Minute},
+		},
+	}
+
+	h := &distroHostTimingsHandler{distroId: "d1", since: time.Now().Add(-time.Hour)}
+	resp, err := h.Execute(context.Background(), sc)
+	assert.NoError(err)
+	assert.Len(resp.Result, 1)
+	timings := resp.Result[0].(*model.APIHostLifecycleTimings)
+	assert.Equal(model.APIString("d1"), timings.Distro)
+	assert.Equal(3, timings.Hosts)
+	assert.Equal(model.APIDuration(180000), timings.RequestToRunning.Mean)
+	assert.Equal(0, timings.RunningToProvisioned.Count)
+}
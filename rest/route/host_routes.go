@@ -25,8 +25,7 @@ type hostGetHandler struct {
 }
 
 type hostPostHandler struct {
-	Distro  string `json:"distro"`
-	KeyName string `json:"keyname"`
+	options model.HostPostRequest
 }
 
 func getHostRouteManager(route string, version int) *RouteManager {
@@ -307,13 +306,13 @@ func (hph *hostPostHandler) Handler() RequestHandler {
 }
 
 func (hph *hostPostHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
-	return errors.WithStack(util.ReadJSONInto(r.Body, hph))
+	return errors.WithStack(util.ReadJSONInto(r.Body, &hph.options))
 }
 
 func (hph *hostPostHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
 	user := MustHaveUser(ctx)
 
-	intentHost, err := sc.NewIntentHost(hph.Distro, hph.KeyName, "", "", user)
+	intentHost, err := sc.NewIntentHost(&hph.options, user)
 	if err != nil {
 		if _, ok := err.(*rest.APIError); !ok {
 			err = errors.Wrap(err, "error spawning host")
@@ -399,6 +398,171 @@ func (h *hostTerminateHandler) Execute(ctx context.Context, sc data.Connector) (
 	return ResponseData{}, nil
 }
 
+func getHostStopRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodPost,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &hostStopHandler{},
+			},
+		},
+	}
+}
+
+type hostStopHandler struct {
+	hostID string
+}
+
+func (h *hostStopHandler) Handler() RequestHandler {
+	return &hostStopHandler{}
+}
+
+func (h *hostStopHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(mux.Vars(r)["host_id"])
+
+	return err
+}
+
+func (h *hostStopHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	host, err := sc.FindHostByIdWithOwner(h.hostID, u)
+	if err != nil {
+		return ResponseData{}, err
+	}
+
+	if host.Status != evergreen.HostRunning {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Host %s is not running", host.Id),
+		}
+	}
+
+	if err := sc.StopHost(host, u.Id); err != nil {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+
+	return ResponseData{}, nil
+}
+
+func getHostStartRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodPost,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &hostStartHandler{},
+			},
+		},
+	}
+}
+
+type hostStartHandler struct {
+	hostID string
+}
+
+func (h *hostStartHandler) Handler() RequestHandler {
+	return &hostStartHandler{}
+}
+
+func (h *hostStartHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(mux.Vars(r)["host_id"])
+
+	return err
+}
+
+func (h *hostStartHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	host, err := sc.FindHostByIdWithOwner(h.hostID, u)
+	if err != nil {
+		return ResponseData{}, err
+	}
+
+	if host.Status != evergreen.HostStopped {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Host %s is not stopped", host.Id),
+		}
+	}
+
+	if err := sc.StartHost(host, u.Id); err != nil {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+
+	return ResponseData{}, nil
+}
+
+func getHostUnquarantineRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodPost,
+				Authenticator:     &SuperUserAuthenticator{},
+				RequestHandler:    &hostUnquarantineHandler{},
+			},
+		},
+	}
+}
+
+type hostUnquarantineHandler struct {
+	hostID string
+}
+
+func (h *hostUnquarantineHandler) Handler() RequestHandler {
+	return &hostUnquarantineHandler{}
+}
+
+func (h *hostUnquarantineHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(mux.Vars(r)["host_id"])
+
+	return err
+}
+
+func (h *hostUnquarantineHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	host, err := sc.FindHostById(h.hostID)
+	if err != nil {
+		return ResponseData{}, err
+	}
+
+	if host.Status != evergreen.HostQuarantined {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Host %s is not quarantined", host.Id),
+		}
+	}
+
+	if err := sc.UnquarantineHost(host, u.Id); err != nil {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+
+	return ResponseData{}, nil
+}
+
 func getHostChangeRDPPasswordRouteManager(route string, version int) *RouteManager {
 	return &RouteManager{
 		Route:   route,
@@ -527,13 +691,6 @@ func (h *hostExtendExpirationHandler) ParseAndValidate(ctx context.Context, r *h
 			Message:    "must add more than 0 hours to expiration",
 		}
 	}
-	if h.addHours > spawn.MaxExpirationDurationHours {
-		return &rest.APIError{
-			StatusCode: http.StatusBadRequest,
-			Message:    fmt.Sprintf("cannot add more than %s", spawn.MaxExpirationDurationHours.String()),
-		}
-	}
-
 	return nil
 }
 
@@ -551,8 +708,19 @@ func (h *hostExtendExpirationHandler) Execute(ctx context.Context, sc data.Conne
 		}
 	}
 
+	maxExpiration, err := sc.GetSpawnHostMaxExpiration(u)
+	if err != nil {
+		return ResponseData{}, errors.Wrap(err, "error getting spawn host quota")
+	}
+	if h.addHours > maxExpiration {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("cannot add more than %s", maxExpiration.String()),
+		}
+	}
+
 	var newExp time.Time
-	newExp, err = spawn.MakeExtendedHostExpiration(host, h.addHours)
+	newExp, err = spawn.MakeExtendedHostExpiration(host, h.addHours, maxExpiration)
 	if err != nil {
 		return ResponseData{}, &rest.APIError{
 			StatusCode: http.StatusBadRequest,
@@ -570,6 +738,140 @@ func (h *hostExtendExpirationHandler) Execute(ctx context.Context, sc data.Conne
 	return ResponseData{}, nil
 }
 
+////////////////////////////////////////////////////////////////////////
+//
+// GET /hosts/{host_id}/events
+
+func getHostEventsRouteManager(route string, version int) *RouteManager {
+	h := &hostEventsHandler{}
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodGet,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    h.Handler(),
+			},
+		},
+	}
+}
+
+type hostEventsArgs struct {
+	hostID string
+	types  []string
+}
+
+type hostEventsHandler struct {
+	PaginationExecutor
+}
+
+func (h *hostEventsHandler) Handler() RequestHandler {
+	return &hostEventsHandler{PaginationExecutor{
+		KeyQueryParam:   "start_at",
+		LimitQueryParam: "limit",
+		Paginator:       hostEventsPaginator,
+		Args:            hostEventsArgs{},
+	}}
+}
+
+func (h *hostEventsHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	hostID, err := validateHostID(mux.Vars(r)["host_id"])
+	if err != nil {
+		return err
+	}
+
+	h.Args = hostEventsArgs{hostID: hostID, types: parseHostEventTypes(r.URL.Query()["type"])}
+
+	return h.PaginationExecutor.ParseAndValidate(ctx, r)
+}
+
+// parseHostEventTypes collects the event types from type params, which may
+// be repeated or comma separated.
+func parseHostEventTypes(params []string) []string {
+	types := []string{}
+	for _, param := range params {
+		for _, t := range strings.Split(param, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
+func hostEventsPaginator(key string, limit int, args interface{}, sc data.Connector) ([]model.Model, *PageResult, error) {
+	hostArgs := args.(hostEventsArgs)
+	var ts time.Time
+	var err error
+	if key == "" {
+		ts = time.Now()
+	} else {
+		ts, err = time.ParseInLocation(model.APITimeFormat, key, time.UTC)
+		if err != nil {
+			return []model.Model{}, nil, &rest.APIError{
+				Message:    fmt.Sprintf("problem parsing time from '%s' (%s)", key, err.Error()),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	// events are displayed in reverse chronological order
+	events, err := sc.FindHostEvents(hostArgs.hostID, hostArgs.types, ts, limit*2, false)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return []model.Model{}, nil, err
+	}
+	if len(events) == 0 {
+		// distinguish a host with no matching events from a missing host
+		if _, err = sc.FindHostById(hostArgs.hostID); err != nil {
+			return []model.Model{}, nil, err
+		}
+	}
+
+	prevEvents, err := sc.FindHostEvents(hostArgs.hostID, hostArgs.types, ts, limit, true)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return []model.Model{}, nil, err
+	}
+
+	pages := &PageResult{}
+	if len(events) > limit {
+		pages.Next = &Page{
+			Relation: "next",
+			Key:      model.NewTime(events[limit].Timestamp).String(),
+			Limit:    limit,
+		}
+		events = events[:limit]
+	}
+	if len(prevEvents) >= 1 {
+		pages.Prev = &Page{
+			Relation: "prev",
+			Key:      model.NewTime(prevEvents[len(prevEvents)-1].Timestamp).String(),
+			Limit:    len(prevEvents),
+		}
+	}
+
+	models := []model.Model{}
+	for i := range events {
+		eventModel := &model.APIHostEvent{}
+		if err = eventModel.BuildFromService(&events[i]); err != nil {
+			return []model.Model{}, nil, &rest.APIError{
+				Message:    "problem converting host event",
+				StatusCode: http.StatusInternalServerError,
+			}
+		}
+		models = append(models, eventModel)
+	}
+
+	return models, pages, nil
+}
+
 func validateHostID(hostID string) (string, error) {
 	if strings.TrimSpace(hostID) == "" {
 		return "", &rest.APIError{
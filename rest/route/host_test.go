@@ -11,6 +11,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest"
@@ -176,6 +177,108 @@ func (s *hostTerminateHostHandlerSuite) TestRegularUserCannotTerminateAnyHost()
 	s.Equal(evergreen.HostRunning, s.sc.CachedHosts[1].Status)
 }
 
+type hostStopStartHandlerSuite struct {
+	sc *data.MockConnector
+	suite.Suite
+}
+
+func TestHostStopStartHandler(t *testing.T) {
+	s := &hostStopStartHandlerSuite{}
+	suite.Run(t, s)
+}
+
+func (s *hostStopStartHandlerSuite) SetupTest() {
+	s.sc = getMockHostsConnector()
+}
+
+func (s *hostStopStartHandlerSuite) TestStopThenStart() {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, evergreen.RequestUser, s.sc.MockUserConnector.CachedUsers["user0"])
+
+	stop := getHostStopRouteManager("", 2).Methods[0].Handler().(*hostStopHandler)
+	stop.hostID = "host2"
+	_, err := stop.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Equal(evergreen.HostStopped, s.sc.CachedHosts[1].Status)
+
+	// a stopped host can't be stopped again
+	_, err = stop.Execute(ctx, s.sc)
+	s.Error(err)
+
+	start := getHostStartRouteManager("", 2).Methods[0].Handler().(*hostStartHandler)
+	start.hostID = "host2"
+	_, err = start.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Equal(evergreen.HostRunning, s.sc.CachedHosts[1].Status)
+}
+
+func (s *hostStopStartHandlerSuite) TestStartRunningHostFails() {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, evergreen.RequestUser, s.sc.MockUserConnector.CachedUsers["user0"])
+
+	h := getHostStartRouteManager("", 2).Methods[0].Handler().(*hostStartHandler)
+	h.hostID = "host2"
+	_, err := h.Execute(ctx, s.sc)
+	s.Error(err)
+	apiErr, ok := err.(*rest.APIError)
+	s.True(ok)
+	s.Equal(http.StatusBadRequest, apiErr.StatusCode)
+	s.Equal(evergreen.HostRunning, s.sc.CachedHosts[1].Status)
+}
+
+func (s *hostStopStartHandlerSuite) TestRegularUserCannotStopAnyHost() {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, evergreen.RequestUser, s.sc.MockUserConnector.CachedUsers["user1"])
+
+	h := getHostStopRouteManager("", 2).Methods[0].Handler().(*hostStopHandler)
+	h.hostID = "host2"
+	_, err := h.Execute(ctx, s.sc)
+	s.Error(err)
+	s.Equal(evergreen.HostRunning, s.sc.CachedHosts[1].Status)
+}
+
+type hostUnquarantineHandlerSuite struct {
+	sc *data.MockConnector
+	suite.Suite
+}
+
+func TestHostUnquarantineHandler(t *testing.T) {
+	suite.Run(t, new(hostUnquarantineHandlerSuite))
+}
+
+func (s *hostUnquarantineHandlerSuite) SetupTest() {
+	s.sc = getMockHostsConnector()
+	s.sc.CachedHosts[3].Status = evergreen.HostQuarantined
+	s.sc.CachedHosts[3].QuarantineReason = "5 of the last 5 tasks system failed"
+}
+
+func (s *hostUnquarantineHandlerSuite) TestUnquarantine() {
+	ctx := context.WithValue(context.Background(), evergreen.RequestUser, s.sc.MockUserConnector.CachedUsers["root"])
+
+	h := getHostUnquarantineRouteManager("", 2).Methods[0].Handler().(*hostUnquarantineHandler)
+	h.hostID = "host4"
+	_, err := h.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Equal(evergreen.HostRunning, s.sc.CachedHosts[3].Status)
+	s.Empty(s.sc.CachedHosts[3].QuarantineReason)
+
+	// a host that is no longer quarantined can't be unquarantined again
+	_, err = h.Execute(ctx, s.sc)
+	s.Error(err)
+	apiErr, ok := err.(*rest.APIError)
+	s.True(ok)
+	s.Equal(http.StatusBadRequest, apiErr.StatusCode)
+}
+
+func (s *hostUnquarantineHandlerSuite) TestUnquarantineMissingHost() {
+	ctx := context.WithValue(context.Background(), evergreen.RequestUser, s.sc.MockUserConnector.CachedUsers["root"])
+
+	h := getHostUnquarantineRouteManager("", 2).Methods[0].Handler().(*hostUnquarantineHandler)
+	h.hostID = "nonexistent"
+	_, err := h.Execute(ctx, s.sc)
+	s.Error(err)
+}
+
 type hostChangeRDPPasswordHandlerSuite struct {
 	rm *RouteManager
 	sc *data.MockConnector
@@ -500,3 +603,81 @@ func getMockHostsConnector() *data.MockConnector {
 	connector.SetSuperUsers([]string{"root"})
 	return connector
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// Tests for GET /hosts/{host_id}/events
+
+type hostEventsHandlerSuite struct {
+	sc  *data.MockConnector
+	now time.Time
+	suite.Suite
+}
+
+func TestHostEventsHandler(t *testing.T) {
+	suite.Run(t, new(hostEventsHandlerSuite))
+}
+
+func (s *hostEventsHandlerSuite) SetupTest() {
+	s.sc = getMockHostsConnector()
+	s.now = time.Now().Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		eventType := event.EventHostRunningTaskSet
+		if i%2 == 0 {
+			eventType = event.EventTaskFinished
+		}
+		s.sc.MockHostConnector.CachedHostEvents = append(s.sc.MockHostConnector.CachedHostEvents, event.Event{
+			ResourceId: "host2",
+			EventType:  eventType,
+			Timestamp:  s.now.Add(time.Duration(i-5) * time.Minute),
+			Data:       event.DataWrapper{Data: &event.HostEventData{TaskId: fmt.Sprintf("task%d", i)}},
+		})
+	}
+}
+
+func (s *hostEventsHandlerSuite) TestPaginatesNewestFirst() {
+	models, pages, err := hostEventsPaginator("", 2, hostEventsArgs{hostID: "host2"}, s.sc)
+	s.NoError(err)
+	s.Require().Len(models, 2)
+	s.Equal(model.APIString("task4"), models[0].(*model.APIHostEvent).Data.TaskId)
+	s.Equal(model.APIString("task3"), models[1].(*model.APIHostEvent).Data.TaskId)
+	s.Require().NotNil(pages.Next)
+	s.Nil(pages.Prev)
+
+	models, pages, err = hostEventsPaginator(pages.Next.Key, 2, hostEventsArgs{hostID: "host2"}, s.sc)
+	s.NoError(err)
+	s.Require().Len(models, 2)
+	s.Equal(model.APIString("task2"), models[0].(*model.APIHostEvent).Data.TaskId)
+	s.NotNil(pages.Next)
+	s.Require().NotNil(pages.Prev)
+	s.Equal(2, pages.Prev.Limit)
+}
+
+func (s *hostEventsHandlerSuite) TestFiltersByType() {
+	s.Equal([]string{event.EventTaskFinished, event.EventHostCreated, event.EventHostProvisioned},
+		parseHostEventTypes([]string{"HOST_TASK_FINISHED, HOST_CREATED", "HOST_PROVISIONED", ""}))
+
+	args := hostEventsArgs{hostID: "host2", types: []string{event.EventTaskFinished}}
+	models, pages, err := hostEventsPaginator("", 10, args, s.sc)
+	s.NoError(err)
+	s.Len(models, 3)
+	s.Nil(pages.Next)
+	for _, m := range models {
+		s.Equal(model.APIString(event.EventTaskFinished), m.(*model.APIHostEvent).EventType)
+	}
+}
+
+func (s *hostEventsHandlerSuite) TestHostWithoutEvents() {
+	models, _, err := hostEventsPaginator("", 10, hostEventsArgs{hostID: "host1"}, s.sc)
+	s.NoError(err)
+	s.Len(models, 0)
+
+	_, _, err = hostEventsPaginator("", 10, hostEventsArgs{hostID: "nonexistent"}, s.sc)
+	s.Error(err)
+	apiErr, ok := err.(*rest.APIError)
+	s.Require().True(ok)
+	s.Equal(http.StatusNotFound, apiErr.StatusCode)
+
+	_, _, err = hostEventsPaginator("yesterday", 10, hostEventsArgs{hostID: "host2"}, s.sc)
+	s.Error(err)
+}
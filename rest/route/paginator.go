@@ -46,6 +46,7 @@ type PaginationExecutor struct {
 
 	limit int
 	key   string
+	query url.Values
 }
 
 // PaginationMetadata is a struct that contains all of the information for
@@ -55,6 +56,10 @@ type PaginationMetadata struct {
 
 	KeyQueryParam   string
 	LimitQueryParam string
+
+	// Query holds any other query params of the request, which are carried
+	// over to the links to the other pages.
+	Query url.Values
 }
 
 // Page contains the information about a single page of the resource.
@@ -87,6 +92,7 @@ func (pe *PaginationExecutor) Execute(_ context.Context, sc data.Connector) (Res
 		Pages:           pages,
 		KeyQueryParam:   pe.KeyQueryParam,
 		LimitQueryParam: pe.LimitQueryParam,
+		Query:           pe.query,
 	}
 
 	rd := ResponseData{
@@ -100,6 +106,7 @@ func (pe *PaginationExecutor) Execute(_ context.Context, sc data.Connector) (Res
 // and sets them on the PaginationExecutor.
 func (pe *PaginationExecutor) ParseAndValidate(_ context.Context, r *http.Request) error {
 	vals := r.URL.Query()
+	pe.query = vals
 	if k, ok := vals[pe.KeyQueryParam]; ok && len(k) > 0 {
 		pe.key = k[0]
 	}
@@ -207,6 +214,7 @@ func (pm *PaginationMetadata) MakeHeader(w http.ResponseWriter, apiURL, route st
 		return err
 	}
 	baseURL.Path = path.Clean(fmt.Sprintf("/%s", route))
+	baseURL.RawQuery = pm.Query.Encode()
 
 	b := bytes.Buffer{}
 	if pm.Pages.Next != nil {
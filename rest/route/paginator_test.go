@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMakePaginationHeader(t *testing.T) {
@@ -305,3 +306,26 @@ func TestPaginatorAdditionalArgs(t *testing.T) {
 		})
 	})
 }
+
+func TestMakePaginationHeaderKeepsQuery(t *testing.T) {
+	assert := assert.New(t)
+	w := httptest.NewRecorder()
+
+	pm := &PaginationMetadata{
+		Pages: &PageResult{
+			Next: &Page{Key: "nextkey", Relation: "next", Limit: 10},
+		},
+		KeyQueryParam:   "key",
+		LimitQueryParam: "limit",
+		Query:           url.Values{"type": []string{"a", "b"}, "key": []string{"oldkey"}},
+	}
+	assert.NoError(pm.MakeHeader(w, "http://evergreenurl.com", "test/route"))
+
+	matches := linkMatcher.FindStringSubmatch(w.Header().Get(evergreen.RoutePaginatorNextPageHeaderKey))
+	assert.Len(matches, 3)
+	u, err := url.Parse(matches[1])
+	assert.NoError(err)
+	assert.Equal([]string{"a", "b"}, u.Query()["type"])
+	assert.Equal("nextkey", u.Query().Get("key"))
+	assert.Equal("10", u.Query().Get("limit"))
+}
@@ -525,3 +525,60 @@ func (p *patchRestartHandler) Execute(ctx context.Context, sc data.Connector) (R
 		Result: []model.Model{patchModel},
 	}, nil
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for the changes a patch makes to its project configuration
+//
+//    /patches/{patch_id}/config_diff
+
+func getPatchConfigDiffManager(route string, version int) *RouteManager {
+	p := &patchConfigDiffHandler{}
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				MethodType:     http.MethodGet,
+				Authenticator:  &NoAuthAuthenticator{},
+				RequestHandler: p.Handler(),
+			},
+		},
+	}
+}
+
+type patchConfigDiffHandler struct {
+	patchId string
+}
+
+func (p *patchConfigDiffHandler) Handler() RequestHandler {
+	return &patchConfigDiffHandler{}
+}
+
+func (p *patchConfigDiffHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	vars := mux.Vars(r)
+	p.patchId = vars["patch_id"]
+	return nil
+}
+
+func (p *patchConfigDiffHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	changes, err := sc.GetPatchConfigDiff(p.patchId)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Config diff error")
+		}
+		return ResponseData{}, err
+	}
+
+	models := []model.Model{}
+	for _, change := range changes {
+		changeModel := &model.APIProjectChange{}
+		if err = changeModel.BuildFromService(change); err != nil {
+			return ResponseData{}, errors.Wrap(err, "API model error")
+		}
+		models = append(models, changeModel)
+	}
+	return ResponseData{
+		Result: models,
+	}, nil
+}
@@ -468,3 +468,55 @@ func executePatchesByUserRequest(user string, ts time.Time, limit int, sc *data.
 
 	return pe.Execute(context.TODO(), sc)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// Tests for patch config diff route
+
+type PatchConfigDiffSuite struct {
+	sc     *data.MockConnector
+	objIds []bson.ObjectId
+
+	suite.Suite
+}
+
+func TestPatchConfigDiffSuite(t *testing.T) {
+	suite.Run(t, new(PatchConfigDiffSuite))
+}
+
+func (s *PatchConfigDiffSuite) SetupSuite() {
+	s.objIds = []bson.ObjectId{bson.NewObjectId(), bson.NewObjectId()}
+	baseConfig := "tasks:\n- name: compile\nbuildvariants:\n- name: ubuntu\n  tasks:\n  - name: compile\n"
+	patchedConfig := baseConfig + "- name: windows\n  tasks:\n  - name: compile\n"
+
+	s.sc = &data.MockConnector{
+		MockPatchConnector: data.MockPatchConnector{
+			CachedPatches: []patch.Patch{
+				{Id: s.objIds[0], Project: "evergreen", Githash: "abc", PatchedConfig: patchedConfig},
+				{Id: s.objIds[1], Project: "evergreen", Githash: "def", PatchedConfig: patchedConfig},
+			},
+			CachedBaseConfigs: map[string]string{"abc": baseConfig},
+		},
+	}
+}
+
+func (s *PatchConfigDiffSuite) TestConfigDiff() {
+	rm := getPatchConfigDiffManager("", 2)
+	(rm.Methods[0].RequestHandler).(*patchConfigDiffHandler).patchId = s.objIds[0].Hex()
+	res, err := rm.Methods[0].Execute(context.Background(), s.sc)
+	s.NoError(err)
+	s.Require().Len(res.Result, 1)
+	change, ok := (res.Result[0]).(*model.APIProjectChange)
+	s.True(ok)
+	s.Equal(model.APIString("buildvariant"), change.Kind)
+	s.Equal(model.APIString("windows"), change.Name)
+	s.Equal(model.APIString("added"), change.Change)
+}
+
+func (s *PatchConfigDiffSuite) TestConfigDiffWithoutBaseVersion() {
+	rm := getPatchConfigDiffManager("", 2)
+	(rm.Methods[0].RequestHandler).(*patchConfigDiffHandler).patchId = s.objIds[1].Hex()
+	res, err := rm.Methods[0].Execute(context.Background(), s.sc)
+	s.Error(err)
+	s.Len(res.Result, 0)
+}
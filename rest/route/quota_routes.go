@@ -0,0 +1,146 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/auth"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+func getUserQuotaRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &userQuotaGetHandler{},
+				MethodType:        http.MethodGet,
+			},
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &SuperUserAuthenticator{},
+				RequestHandler:    &userQuotaPostHandler{},
+				MethodType:        http.MethodPost,
+			},
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /users/{user_id}/quota
+
+type userQuotaGetHandler struct {
+	userID string
+}
+
+func (h *userQuotaGetHandler) Handler() RequestHandler {
+	return &userQuotaGetHandler{}
+}
+
+func (h *userQuotaGetHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	h.userID = mux.Vars(r)["user_id"]
+	return nil
+}
+
+func (h *userQuotaGetHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	// only superusers may see the quotas of other users
+	if u.Id != h.userID && !auth.IsSuperUser(sc.GetSuperUsers(), u) {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("user %s not found", h.userID),
+		}
+	}
+
+	target := u
+	if u.Id != h.userID {
+		found, err := sc.FindUserById(h.userID)
+		if err != nil {
+			return ResponseData{}, errors.Wrapf(err, "error finding user %s", h.userID)
+		}
+		dbUser, ok := found.(*user.DBUser)
+		if !ok || dbUser == nil {
+			return ResponseData{}, &rest.APIError{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("user %s not found", h.userID),
+			}
+		}
+		target = dbUser
+	}
+
+	quota, err := sc.GetSpawnHostQuota(target)
+	if err != nil {
+		return ResponseData{}, errors.Wrapf(err, "error getting spawn host quota for user %s", h.userID)
+	}
+
+	apiQuota := &model.APISpawnHostQuota{}
+	if err = apiQuota.BuildFromService(quota); err != nil {
+		return ResponseData{}, errors.Wrap(err, "API model error")
+	}
+
+	return ResponseData{
+		Result: []model.Model{apiQuota},
+	}, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /users/{user_id}/quota
+
+type userQuotaPostHandler struct {
+	quota evergreen.SpawnHostQuota
+}
+
+func (h *userQuotaPostHandler) Handler() RequestHandler {
+	return &userQuotaPostHandler{}
+}
+
+func (h *userQuotaPostHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	limits := model.APISpawnHostLimits{}
+	if err := util.ReadJSONInto(body, &limits); err != nil {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("failed to unmarshal quota: %s", err),
+		}
+	}
+	limits.Name = model.APIString(mux.Vars(r)["user_id"])
+
+	quota, err := limits.ToService()
+	if err != nil {
+		return errors.Wrap(err, "API model error")
+	}
+	h.quota = quota.(evergreen.SpawnHostQuota)
+
+	if h.quota.MaxHosts < 0 || h.quota.MaxMonthlyCost < 0 || h.quota.MaxExpirationHours < 0 {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "quota limits must not be negative",
+		}
+	}
+
+	return nil
+}
+
+func (h *userQuotaPostHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	if err := sc.SetSpawnHostUserQuota(h.quota); err != nil {
+		return ResponseData{}, errors.Wrapf(err, "error setting spawn host quota for user %s", h.quota.Name)
+	}
+
+	return ResponseData{}, nil
+}
@@ -0,0 +1,118 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type userQuotaHandlerSuite struct {
+	rm *RouteManager
+	sc *data.MockConnector
+	suite.Suite
+}
+
+func TestUserQuotaHandler(t *testing.T) {
+	suite.Run(t, new(userQuotaHandlerSuite))
+}
+
+func (s *userQuotaHandlerSuite) SetupTest() {
+	s.rm = getUserQuotaRouteManager("", 2)
+	s.sc = getMockHostsConnector()
+}
+
+func (s *userQuotaHandlerSuite) getQuota(requester, userID string) (*model.APISpawnHostQuota, error) {
+	h := s.rm.Methods[0].Handler().(*userQuotaGetHandler)
+	h.userID = userID
+
+	ctx := context.WithValue(context.Background(), evergreen.RequestUser, s.sc.MockUserConnector.CachedUsers[requester])
+	data, err := h.Execute(ctx, s.sc)
+	if err != nil {
+		return nil, err
+	}
+	s.Require().Len(data.Result, 1)
+	return data.Result[0].(*model.APISpawnHostQuota), nil
+}
+
+func (s *userQuotaHandlerSuite) TestGetOwnQuota() {
+	quota, err := s.getQuota("user0", "user0")
+	s.NoError(err)
+	s.Equal(model.APIString("user0"), quota.User.Name)
+	s.Equal(evergreen.DefaultSpawnHostsPerUser, quota.User.MaxHosts)
+	s.Equal(evergreen.DefaultSpawnHostExpirationHours, quota.User.MaxExpirationHours)
+	s.Equal(3, quota.User.Hosts)
+	s.Nil(quota.Team)
+}
+
+func (s *userQuotaHandlerSuite) TestRegularUserCannotGetOtherUsersQuota() {
+	_, err := s.getQuota("user1", "user0")
+	s.Error(err)
+	s.IsType(new(rest.APIError), err)
+	s.Equal(http.StatusNotFound, err.(*rest.APIError).StatusCode)
+}
+
+func (s *userQuotaHandlerSuite) TestSuperUserCanGetAnyQuota() {
+	quota, err := s.getQuota("root", "user0")
+	s.NoError(err)
+	s.Equal(model.APIString("user0"), quota.User.Name)
+
+	_, err = s.getQuota("root", "nobody")
+	s.Error(err)
+}
+
+func (s *userQuotaHandlerSuite) TestSetQuota() {
+	r, err := http.NewRequest(http.MethodPost, "/users/user0/quota", bytes.NewReader([]byte(`{"max_hosts": 5, "max_monthly_cost": 100.5}`)))
+	s.NoError(err)
+
+	h := s.rm.Methods[1].Handler().(*userQuotaPostHandler)
+	s.NoError(h.ParseAndValidate(context.Background(), r))
+	s.Equal(5, h.quota.MaxHosts)
+	s.Equal(100.5, h.quota.MaxMonthlyCost)
+
+	h.quota.Name = "user0"
+	_, err = h.Execute(context.Background(), s.sc)
+	s.NoError(err)
+
+	quota, err := s.getQuota("user0", "user0")
+	s.NoError(err)
+	s.Equal(5, quota.User.MaxHosts)
+	s.Equal(100.5, quota.User.MaxMonthlyCost)
+	s.Equal(evergreen.DefaultSpawnHostExpirationHours, quota.User.MaxExpirationHours)
+}
+
+func (s *userQuotaHandlerSuite) TestSetNegativeQuotaFails() {
+	r, err := http.NewRequest(http.MethodPost, "/users/user0/quota", bytes.NewReader([]byte(`{"max_hosts": -1}`)))
+	s.NoError(err)
+
+	h := s.rm.Methods[1].Handler().(*userQuotaPostHandler)
+	err = h.ParseAndValidate(context.Background(), r)
+	s.Error(err)
+	s.IsType(new(rest.APIError), err)
+}
+
+func (s *userQuotaHandlerSuite) TestSetTeam() {
+	r, err := http.NewRequest(http.MethodPost, "/users/user0/team", bytes.NewReader([]byte(`{"team": "team0"}`)))
+	s.NoError(err)
+
+	h := getUserTeamRouteManager("", 2).Methods[0].Handler().(*userTeamPostHandler)
+	s.NoError(h.ParseAndValidate(context.Background(), r))
+	s.Equal("team0", h.team)
+
+	h.userID = "user0"
+	_, err = h.Execute(context.Background(), s.sc)
+	s.NoError(err)
+	s.Equal("team0", s.sc.MockUserConnector.CachedUsers["user0"].Team)
+
+	h.userID = "nobody"
+	_, err = h.Execute(context.Background(), s.sc)
+	s.Error(err)
+	s.IsType(new(rest.APIError), err)
+	s.Equal(http.StatusNotFound, err.(*rest.APIError).StatusCode)
+}
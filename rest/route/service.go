@@ -27,6 +27,9 @@ func GetHandler(r *mux.Router, sc data.Connector, queue amboy.Queue, githubSecre
 	routes := map[string]routeManagerFactory{
 		"/":                                                    getPlaceHolderManger,
 		"/admin":                                               getAdminSettingsManager,
+		"/admin/agent_rollouts":                                getAgentRolloutsRouteManager,
+		"/admin/agent_rollouts/{distro_id}/pause":              getAgentRolloutPauseRouteManager(true),
+		"/admin/agent_rollouts/{distro_id}/resume":             getAgentRolloutPauseRouteManager(false),
 		"/admin/banner":                                        getBannerRouteManager,
 		"/admin/service_flags":                                 getServiceFlagsRouteManager,
 		"/admin/restart":                                       getRestartRouteManager(queue),
@@ -35,16 +38,28 @@ func GetHandler(r *mux.Router, sc data.Connector, queue amboy.Queue, githubSecre
 		"/builds/{build_id}/restart":                           getBuildRestartManager,
 		"/builds/{build_id}/tasks":                             getTasksByBuildRouteManager,
 		"/distros":                                             getDistroRouteManager,
+		"/distros/{distro_id}/host_timings":                    getDistroHostTimingsRouteManager,
+		"/distros/{distro_id}/queue":                           getDistroQueueRouteManager,
+		"/distros/{distro_id}/queue/{task_id}/pin":             getDistroQueuePinRouteManager,
+		"/distros/{distro_id}/queue/{task_id}/hold":            getDistroQueueHoldRouteManager,
+		"/distros/{distro_id}/queue/{task_id}/release":         getDistroQueueReleaseRouteManager,
 		"/hosts":                                               getHostRouteManager,
 		"/hosts/{host_id}":                                     getHostIDRouteManager,
 		"/hosts/{host_id}/change_password":                     getHostChangeRDPPasswordRouteManager,
+		"/hosts/{host_id}/events":                              getHostEventsRouteManager,
 		"/hosts/{host_id}/extend_expiration":                   getHostExtendExpirationRouteManager,
+		"/hosts/{host_id}/start":                               getHostStartRouteManager,
+		"/hosts/{host_id}/stop":                                getHostStopRouteManager,
 		"/hosts/{host_id}/terminate":                           getHostTerminateRouteManager,
+		"/hosts/{host_id}/unquarantine":                        getHostUnquarantineRouteManager,
 		"/patches/{patch_id}":                                  getPatchByIdManager,
 		"/users/{user_id}/patches":                             getPatchesByUserManager,
 		"/users/{user_id}/hosts":                               getHostsByUserManager,
+		"/users/{user_id}/quota":                               getUserQuotaRouteManager,
+		"/users/{user_id}/team":                                getUserTeamRouteManager,
 		"/patches/{patch_id}/abort":                            getPatchAbortManager,
 		"/patches/{patch_id}/restart":                          getPatchRestartManager,
+		"/patches/{patch_id}/config_diff":                      getPatchConfigDiffManager,
 		"/projects":                                            getProjectRouteManager,
 		"/projects/{project_id}/patches":                       getPatchesByProjectManager,
 		"/projects/{project_id}/revisions/{commit_hash}/tasks": getTasksByProjectAndCommitRouteManager,
@@ -59,6 +74,7 @@ func GetHandler(r *mux.Router, sc data.Connector, queue amboy.Queue, githubSecre
 		"/cost/project/{project_id}/tasks":                     getCostTaskByProjectRouteManager,
 		"/versions/{version_id}":                               getVersionIdRouteManager,
 		"/versions/{version_id}/builds":                        getBuildsForVersionRouteManager,
+		"/versions/{version_id}/graph":                         getVersionGraphRouteManager,
 		"/versions/{version_id}/abort":                         getAbortVersionRouteManager,
 		"/versions/{version_id}/restart":                       getRestartVersionRouteManager,
 		"/status/hosts/distros":                                getHostStatsByDistroManager,
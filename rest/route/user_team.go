@@ -0,0 +1,71 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+func getUserTeamRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &SuperUserAuthenticator{},
+				RequestHandler:    &userTeamPostHandler{},
+				MethodType:        http.MethodPost,
+			},
+		},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /users/{user_id}/team
+
+type userTeamPostHandler struct {
+	userID string
+	team   string
+}
+
+func (h *userTeamPostHandler) Handler() RequestHandler {
+	return &userTeamPostHandler{}
+}
+
+func (h *userTeamPostHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	input := struct {
+		Team string `json:"team"`
+	}{}
+	if err := util.ReadJSONInto(body, &input); err != nil {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("failed to unmarshal team: %s", err),
+		}
+	}
+	h.userID = mux.Vars(r)["user_id"]
+	h.team = input.Team
+
+	return nil
+}
+
+func (h *userTeamPostHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	if err := sc.SetUserTeam(h.userID, h.team); err != nil {
+		if _, ok := err.(*rest.APIError); ok {
+			return ResponseData{}, err
+		}
+		return ResponseData{}, errors.Wrapf(err, "error setting team for user %s", h.userID)
+	}
+
+	return ResponseData{}, nil
+}
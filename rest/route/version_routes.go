@@ -286,3 +286,59 @@ func (h *versionRestartHandler) Execute(ctx context.Context, sc data.Connector)
 		Result: []model.Model{versionModel},
 	}, err
 }
+
+// versionGraphHandler is a RequestHandler for fetching the dependency graph
+// of a version's tasks and its critical path.
+type versionGraphHandler struct {
+	versionId string
+}
+
+func getVersionGraphRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route: route,
+		Methods: []MethodHandler{
+			{
+				Authenticator:  &NoAuthAuthenticator{},
+				RequestHandler: &versionGraphHandler{},
+				MethodType:     http.MethodGet,
+			},
+		},
+		Version: version,
+	}
+}
+
+// Handler returns a pointer to a new versionGraphHandler.
+func (h *versionGraphHandler) Handler() RequestHandler {
+	return &versionGraphHandler{}
+}
+
+// ParseAndValidate fetches the versionId from the http request.
+func (h *versionGraphHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	h.versionId = getVersionIdFromRequest(r)
+
+	if h.versionId == "" {
+		return errors.New("request data incomplete")
+	}
+
+	return nil
+}
+
+// Execute calls the data FindTaskGraphByVersionId function and returns the
+// version's task graph.
+func (h *versionGraphHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	graph, err := sc.FindTaskGraphByVersionId(h.versionId)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+
+	graphModel := &model.APITaskGraph{}
+	if err = graphModel.BuildFromService(graph); err != nil {
+		return ResponseData{}, errors.Wrap(err, "API model error")
+	}
+	return ResponseData{
+		Result: []model.Model{graphModel},
+	}, nil
+}
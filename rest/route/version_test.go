@@ -12,6 +12,8 @@ import (
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -193,3 +195,35 @@ func (s *VersionSuite) TestRestartVersion() {
 	s.Equal(model.APIString(versionId), h.Id)
 	s.Equal("caller1", s.versionData.CachedRestartedVersions["versionId"])
 }
+
+// TestVersionGraph tests the route for fetching a version's task graph.
+func TestVersionGraph(t *testing.T) {
+	assert := assert.New(t)
+	sc := &data.MockConnector{
+		MockVersionConnector: data.MockVersionConnector{
+			CachedVersions: []version.Version{{Id: "v1"}},
+			CachedTasks: []task.Task{
+				{Id: "compile", Version: "v1", BuildVariant: "bv", DisplayName: "compile",
+					Status: evergreen.TaskSucceeded, TimeTaken: 2 * time.Minute},
+				{Id: "test", Version: "v1", BuildVariant: "bv", DisplayName: "test",
+					Status: evergreen.TaskUndispatched, ExpectedDuration: 5 * time.Minute,
+					DependsOn: []task.Dependency{{TaskId: "compile"}}},
+				{Id: "other", Version: "v2", BuildVariant: "bv", DisplayName: "other"},
+			},
+		},
+	}
+
+	handler := &versionGraphHandler{versionId: "v1"}
+	res, err := handler.Execute(context.TODO(), sc)
+	assert.NoError(err)
+	require.Len(t, res.Result, 1)
+	graph, ok := (res.Result[0]).(*model.APITaskGraph)
+	require.True(t, ok)
+	assert.Len(graph.Tasks, 2)
+	assert.Equal([]model.APIString{"compile", "test"}, graph.CriticalPath)
+	assert.Equal(model.NewAPIDuration(7*time.Minute), graph.Makespan)
+
+	handler = &versionGraphHandler{versionId: "v3"}
+	_, err = handler.Execute(context.TODO(), sc)
+	assert.Error(err)
+}
@@ -62,6 +62,11 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		})
 	}
 
+	grip.Warning(message.WrapError(resolveCrossProjectDependencies(), message.Fields{
+		"message": "problem resolving cross-project dependencies",
+		"runner":  RunnerName,
+	}))
+
 	startAt := time.Now()
 	runnableTasks, err := s.FindRunnableTasks()
 	if err != nil {
@@ -274,11 +279,25 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		return errors.Wrap(err, "Error spawning new hosts")
 	}
 
+	// top up the warm pools of distros that have them
+	warmHostsNeeded := warmPoolHostsNeeded(distrosByName, hostsByDistro, taskQueueItems, newHostsNeeded)
+	warmHostsSpawned, err := s.spawnHostsWithOptions(ctx, warmHostsNeeded, cloud.HostOptions{
+		UserName: evergreen.User,
+		WarmPool: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error spawning warm pool hosts")
+	}
+	for distroId, hosts := range warmHostsSpawned {
+		hostsSpawned[distroId] = append(hostsSpawned[distroId], hosts...)
+	}
+
 	grip.Info(message.Fields{
 		"message":     "hosts spawned",
 		"num_distros": len(hostsSpawned),
 		"runner":      RunnerName,
 		"allocations": newHostsNeeded,
+		"warm_pool":   warmHostsNeeded,
 	})
 
 	for distro, hosts := range hostsSpawned {
@@ -515,6 +534,15 @@ func (s *Scheduler) splitTasksByDistro(tasksToSplit []task.Task) (
 // distro -> number of hosts to spawn for the distro.
 // Returns a map of distro -> hosts spawned, and an error if one occurs.
 func (s *Scheduler) spawnHosts(ctx context.Context, newHostsNeeded map[string]int) (map[string][]host.Host, error) {
+	return s.spawnHostsWithOptions(ctx, newHostsNeeded, cloud.HostOptions{
+		UserName: evergreen.User,
+		UserHost: false,
+	})
+}
+
+// spawnHostsWithOptions is spawnHosts with the given options for the new
+// hosts, which allows the scheduler to mark hosts started for warm pools.
+func (s *Scheduler) spawnHostsWithOptions(ctx context.Context, newHostsNeeded map[string]int, hostOptions cloud.HostOptions) (map[string][]host.Host, error) {
 	startTime := time.Now()
 
 	// loop over the distros, spawning up the appropriate number of hosts
@@ -575,11 +603,6 @@ func (s *Scheduler) spawnHosts(ctx context.Context, newHostsNeeded map[string]in
 				continue
 			}
 
-			hostOptions := cloud.HostOptions{
-				UserName: evergreen.User,
-				UserHost: false,
-			}
-
 			intentHost := cloud.NewIntent(*d, cloudManager.GetInstanceName(d), d.Provider, hostOptions)
 			if err := intentHost.Insert(); err != nil {
 				err = errors.Wrapf(err, "Could not insert intent host '%s'", intentHost.Id)
@@ -607,6 +630,7 @@ func (s *Scheduler) spawnHosts(ctx context.Context, newHostsNeeded map[string]in
 			"runner":    RunnerName,
 			"distro":    distroId,
 			"number":    numHostsToSpawn,
+			"warm_pool": hostOptions.WarmPool,
 			"operation": "spawning instances",
 			"span":      time.Since(distroStartTime).String(),
 			"duration":  time.Since(distroStartTime),
@@ -256,6 +256,23 @@ func ParallelTaskFinder() ([]task.Task, error) {
 	return runnabletasks, nil
 }
 
+// resolveCrossProjectDependencies resolves the dependencies of schedulable
+// tasks on tasks in other projects that did not exist when they were
+// created, so that the task finders can check them.
+func resolveCrossProjectDependencies() error {
+	tasks, err := task.FindSchedulableWithUnresolvedDependencies()
+	if err != nil {
+		return errors.Wrap(err, "problem finding tasks with unresolved dependencies")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for i := range tasks {
+		catcher.Add(tasks[i].ResolveDependencies())
+	}
+
+	return catcher.Resolve()
+}
+
 func getProjectRefCache() (map[string]model.ProjectRef, error) {
 	out := map[string]model.ProjectRef{}
 	refs, err := model.FindAllProjectRefs()
@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -136,9 +137,46 @@ func (prioritizer *CmpBasedTaskPrioritizer) PrioritizeTasks(
 
 	comparator.tasks = comparator.mergeTasks(settings, &prioritizedTaskQueues)
 
+	taskQueue, err := model.FindTaskQueueForDistro(distroId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error finding queue overrides for distro %s", distroId)
+	}
+	comparator.tasks = applyQueueOverrides(comparator.tasks, taskQueue)
+
 	return comparator.tasks, nil
 }
 
+// applyQueueOverrides moves tasks that an administrator has pinned to the
+// front of the prioritized list, in the order they were pinned, and removes
+// tasks that have been held.
+func applyQueueOverrides(tasks []task.Task, taskQueue *model.TaskQueue) []task.Task {
+	if taskQueue == nil || (len(taskQueue.PinnedTasks) == 0 && len(taskQueue.HeldTasks) == 0) {
+		return tasks
+	}
+
+	pinned := make(map[string]task.Task)
+	rest := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		switch {
+		case taskQueue.IsHeld(t.Id):
+			continue
+		case taskQueue.IsPinned(t.Id):
+			pinned[t.Id] = t
+		default:
+			rest = append(rest, t)
+		}
+	}
+
+	out := make([]task.Task, 0, len(pinned)+len(rest))
+	for _, id := range taskQueue.PinnedTasks {
+		if t, ok := pinned[id]; ok {
+			out = append(out, t)
+		}
+	}
+
+	return append(out, rest...)
+}
+
 // Run all of the setup functions necessary for prioritizing the tasks.
 // Returns an error if any of the setup funcs return an error.
 func (self *CmpBasedTaskComparator) setupForSortingTasks(distroId string) error {
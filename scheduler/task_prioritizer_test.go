@@ -4,9 +4,11 @@ import (
 	"testing"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/testutil"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
 )
 
 var taskComparatorTestConf = testutil.TestConfig()
@@ -348,3 +350,22 @@ func TestCmpBasedTaskComparator(t *testing.T) {
 	})
 
 }
+
+func TestApplyQueueOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	tasks := []task.Task{{Id: "t1"}, {Id: "t2"}, {Id: "t3"}, {Id: "t4"}}
+
+	assert.Equal(tasks, applyQueueOverrides(tasks, nil))
+	assert.Equal(tasks, applyQueueOverrides(tasks, &model.TaskQueue{}))
+
+	out := applyQueueOverrides(tasks, &model.TaskQueue{
+		PinnedTasks: []string{"t4", "missing", "t3"},
+		HeldTasks:   []string{"t1"},
+	})
+	if assert.Len(out, 3) {
+		assert.Equal("t4", out[0].Id)
+		assert.Equal("t3", out[1].Id)
+		assert.Equal("t2", out[2].Id)
+	}
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+)
+
+// warmPoolHostsNeeded returns, for each distro with a warm pool, the number
+// of hosts to start in addition to those needed for its queued tasks so that
+// the distro keeps its warm pool of idle, provisioned hosts. Queued tasks are
+// counted against the distro's idle hosts first, since those hosts will be
+// dispatched the tasks before any newly started hosts are ready.
+func warmPoolHostsNeeded(distros map[string]distro.Distro, existingDistroHosts map[string][]host.Host,
+	taskQueueItems map[string][]model.TaskQueueItem, newHostsNeeded map[string]int) map[string]int {
+
+	warmHostsNeeded := make(map[string]int)
+	for distroId, d := range distros {
+		if d.WarmPoolSize <= 0 {
+			continue
+		}
+
+		idle := 0
+		for _, h := range existingDistroHosts[distroId] {
+			if h.RunningTask == "" {
+				idle++
+			}
+		}
+
+		// idle hosts left over once the queue has been dispatched
+		idle -= len(taskQueueItems[distroId])
+		if idle < 0 {
+			idle = 0
+		}
+
+		needed := d.WarmPoolSize - idle
+
+		// don't start more hosts than the distro allows
+		total := len(existingDistroHosts[distroId]) + newHostsNeeded[distroId]
+		if total+needed > d.PoolSize {
+			needed = d.PoolSize - total
+		}
+
+		if needed > 0 {
+			warmHostsNeeded[distroId] = needed
+		}
+	}
+
+	return warmHostsNeeded
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarmPoolHostsNeeded(t *testing.T) {
+	assert := assert.New(t)
+
+	distros := map[string]distro.Distro{
+		"none":  {Id: "none", PoolSize: 10},
+		"empty": {Id: "empty", PoolSize: 10, WarmPoolSize: 3},
+		"warm":  {Id: "warm", PoolSize: 10, WarmPoolSize: 3},
+		"busy":  {Id: "busy", PoolSize: 10, WarmPoolSize: 2},
+		"full":  {Id: "full", PoolSize: 3, WarmPoolSize: 2},
+	}
+	hosts := map[string][]host.Host{
+		"warm": {{Id: "w1"}, {Id: "w2"}, {Id: "w3"}, {Id: "w4", RunningTask: "t"}},
+		"busy": {{Id: "b1"}, {Id: "b2"}},
+		"full": {{Id: "f1", RunningTask: "t"}, {Id: "f2", RunningTask: "t"}},
+	}
+	queues := map[string][]model.TaskQueueItem{
+		"busy": {{Id: "t1"}, {Id: "t2"}, {Id: "t3"}},
+	}
+	newHostsNeeded := map[string]int{"busy": 1}
+
+	needed := warmPoolHostsNeeded(distros, hosts, queues, newHostsNeeded)
+
+	// distros without a warm pool, or whose pool is full, need no hosts
+	assert.NotContains(needed, "none")
+	assert.NotContains(needed, "warm")
+	// an empty pool is filled
+	assert.Equal(3, needed["empty"])
+	// the idle hosts are dispatched the queue first, so the pool is empty
+	assert.Equal(2, needed["busy"])
+	// the pool is limited by the distro's maximum number of hosts
+	assert.Equal(1, needed["full"])
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/notify"
 	"github.com/evergreen-ci/evergreen/rest/route"
+	"github.com/evergreen-ci/evergreen/taskrunner"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/evergreen-ci/render"
@@ -28,6 +29,7 @@ import (
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
 )
 
 type (
@@ -519,6 +521,21 @@ func (as *APIServer) hostReady(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// hosts that bootstrap from user data were given a secret to report with
+	if hostObj.Distro.BootstrapsWithUserData() && r.Header.Get(evergreen.HostSecretHeader) != hostObj.Secret {
+		as.LoggedError(w, r, http.StatusConflict, errors.Errorf("Invalid host secret for host %v", hostObj.Id))
+		return
+	}
+
+	// a host that bootstraps from user data may report back before hostinit
+	// sees it running and moves it into the provisioning status
+	if hostObj.Distro.BootstrapsWithUserData() && hostObj.Status == evergreen.HostStarting {
+		if err = hostObj.SetInitializing(); err != nil && err != mgo.ErrNotFound {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
 	// if the host failed
 	setupSuccess := mux.Vars(r)["status"]
 	if setupSuccess == evergreen.HostStatusFailed {
@@ -575,12 +592,44 @@ func (as *APIServer) hostReady(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// hosts that bootstrap from user data are never checked for readiness
+	// over SSH, which is where the DNS name is otherwise recorded
+	if hostObj.Host == "" && dns != "" {
+		if err = hostObj.SetDNSName(dns); err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
 	// mark host as provisioned
 	if err := hostObj.MarkAsProvisioned(); err != nil {
 		as.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
+	// the bootstrap script starts the agent revision chosen when the host was
+	// spawned once it has reported success. Its report counts as the host's
+	// first communication, so that the task runner only starts an agent on
+	// the host if the bootstrapped one never checks in.
+	if hostObj.Distro.BootstrapsWithUserData() {
+		if err := hostObj.UpdateLastCommunicated(); err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if hostObj.AgentRevision == "" {
+			agentRevision, err := taskrunner.NewTaskRunner(&as.Settings).HostGateway.GetAgentRevision()
+			if err != nil {
+				as.LoggedError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			if err = hostObj.SetAgentRevision(agentRevision); err != nil {
+				as.LoggedError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+		}
+		event.LogHostAgentDeployed(hostObj.Id)
+	}
+
 	grip.Infof("Successfully marked host '%s' with dns '%s' as provisioned", hostObj.Id, dns)
 }
 
@@ -769,6 +818,7 @@ func (as *APIServer) AttachRoutes(root *mux.Router) {
 
 	taskRouter.HandleFunc("/log", as.checkTask(true, as.checkHost(as.AppendTaskLog))).Methods("POST")
 	taskRouter.HandleFunc("/heartbeat", as.checkTask(true, as.checkHost(as.Heartbeat))).Methods("POST")
+	taskRouter.HandleFunc("/spot_interruption", as.checkTask(true, as.checkHost(as.SpotInterruption))).Methods("POST")
 	taskRouter.HandleFunc("/results", as.checkTask(true, as.checkHost(as.AttachResults))).Methods("POST")
 	taskRouter.HandleFunc("/test_logs", as.checkTask(true, as.checkHost(as.AttachTestLog))).Methods("POST")
 	taskRouter.HandleFunc("/files", as.checkTask(false, as.checkHost(as.AttachFiles))).Methods("POST")
@@ -784,6 +834,7 @@ func (as *APIServer) AttachRoutes(root *mux.Router) {
 	taskRouter.HandleFunc("/git/patchfile/{patchfile_id}", as.checkTask(false, as.gitServePatchFile)).Methods("GET")
 	taskRouter.HandleFunc("/git/patch", as.checkTask(false, as.gitServePatch)).Methods("GET")
 	taskRouter.HandleFunc("/keyval/inc", as.checkTask(false, as.keyValPluginInc)).Methods("POST")
+	taskRouter.HandleFunc("/generate", as.checkTask(false, as.generateTasks)).Methods("POST")
 	taskRouter.HandleFunc("/manifest/load", as.checkTask(false, as.manifestLoadHandler)).Methods("GET")
 	taskRouter.HandleFunc("/s3Copy/s3Copy", as.checkTask(false, as.s3copyPlugin)).Methods("POST")
 
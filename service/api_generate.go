@@ -0,0 +1,99 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/evergreen/validator"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// generateTasksMaxAttempts is the number of times to try adding generated
+// configuration to a version whose config is being updated concurrently by
+// another task.
+const generateTasksMaxAttempts = 10
+
+// generateTasks merges the project configuration generated by a task into
+// its version's config, and creates the generated builds and tasks.
+func (as *APIServer) generateTasks(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+
+	req := &apimodels.GenerateTasksRequest{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), req); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Files) == 0 {
+		as.LoggedError(w, r, http.StatusBadRequest, errors.New("no generated files to add"))
+		return
+	}
+
+	for attempt := 1; attempt <= generateTasksMaxAttempts; attempt++ {
+		v, err := version.FindOne(version.ById(t.Version))
+		if err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrapf(err, "error finding version %s", t.Version))
+			return
+		}
+		if v == nil {
+			as.LoggedError(w, r, http.StatusNotFound, errors.Errorf("version %s not found", t.Version))
+			return
+		}
+
+		g, err := model.MergeGeneratedProject(v, t, req.Files, req.DependOnGenerator)
+		if err != nil {
+			as.LoggedError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if g.Config != "" {
+			validationErrs, err := validator.CheckProjectSyntax(g.Project)
+			if err != nil {
+				as.LoggedError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			validationErrs = append(validationErrs, validator.CheckProjectSemantics(g.Project)...)
+			var messages []string
+			for _, e := range validationErrs {
+				if e.Level == validator.Error {
+					messages = append(messages, e.Message)
+				}
+			}
+			if len(messages) > 0 {
+				as.LoggedError(w, r, http.StatusBadRequest, errors.Errorf("generated project is invalid:\n\t%s",
+					strings.Join(messages, "\n\t")))
+				return
+			}
+
+			updated, err := v.AddGeneratedConfig(g.Config, t.Id)
+			if err != nil {
+				as.LoggedError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			if !updated {
+				grip.Info(message.Fields{
+					"message": "version config changed while generating tasks, retrying",
+					"task":    t.Id,
+					"version": v.Id,
+					"attempt": attempt,
+				})
+				continue
+			}
+		}
+
+		if err = model.AddGeneratedBuildsAndTasks(v, g.Project, g.Tasks); err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		as.WriteJSON(w, http.StatusOK, "tasks generated")
+		return
+	}
+
+	as.LoggedError(w, r, http.StatusConflict, errors.Errorf("could not update version %s after %d attempts",
+		t.Version, generateTasksMaxAttempts))
+}
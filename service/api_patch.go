@@ -44,7 +44,7 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		finalize := strings.ToLower(r.FormValue("finalize")) == "true"
 
 		var err error
-		intent, err = patch.NewCliIntent(dbUser.Id, r.FormValue("project"), r.FormValue("githash"), r.FormValue("module"), patchContent, r.FormValue("desc"), finalize, variants, []string{}, "")
+		intent, err = patch.NewCliIntent(dbUser.Id, r.FormValue("project"), r.FormValue("githash"), r.FormValue("module"), patchContent, r.FormValue("desc"), finalize, variants, []string{}, "", nil)
 		if err != nil {
 			as.LoggedError(w, r, http.StatusBadRequest, err)
 			return
@@ -52,14 +52,15 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 
 	} else {
 		data := struct {
-			Description string   `json:"desc"`
-			Project     string   `json:"project"`
-			Patch       string   `json:"patch"`
-			Githash     string   `json:"githash"`
-			Variants    string   `json:"buildvariants"`
-			Tasks       []string `json:"tasks"`
-			Finalize    bool     `json:"finalize"`
-			Alias       string   `json:"alias"`
+			Description string            `json:"desc"`
+			Project     string            `json:"project"`
+			Patch       string            `json:"patch"`
+			Githash     string            `json:"githash"`
+			Variants    string            `json:"buildvariants"`
+			Tasks       []string          `json:"tasks"`
+			Finalize    bool              `json:"finalize"`
+			Alias       string            `json:"alias"`
+			Parameters  []patch.Parameter `json:"parameters"`
 		}{}
 		if err := util.ReadJSONInto(util.NewRequestReader(r), &data); err != nil {
 			as.LoggedError(w, r, http.StatusBadRequest, err)
@@ -72,7 +73,7 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		variants := strings.Split(data.Variants, ",")
 
 		var err error
-		intent, err = patch.NewCliIntent(dbUser.Id, data.Project, data.Githash, r.FormValue("module"), data.Patch, data.Description, data.Finalize, variants, data.Tasks, data.Alias)
+		intent, err = patch.NewCliIntent(dbUser.Id, data.Project, data.Githash, r.FormValue("module"), data.Patch, data.Description, data.Finalize, variants, data.Tasks, data.Alias, data.Parameters)
 		if err != nil {
 			as.LoggedError(w, r, http.StatusBadRequest, err)
 			return
@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/notify"
 	"github.com/evergreen-ci/evergreen/rest/data"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/gorilla/mux"
 	"github.com/mongodb/grip"
@@ -64,7 +65,11 @@ func (as *APIServer) requestHost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	hc := &data.DBHostConnector{}
-	spawnHost, err := hc.NewIntentHost(hostRequest.Distro, hostRequest.PublicKey, "", hostRequest.UserData, user)
+	spawnHost, err := hc.NewIntentHost(&restModel.HostPostRequest{
+		DistroID: hostRequest.Distro,
+		KeyName:  hostRequest.PublicKey,
+		UserData: hostRequest.UserData,
+	}, user)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -84,6 +84,13 @@ func validateTaskEndDetails(details *apimodels.TaskEndDetail) bool {
 		details.Status == evergreen.TaskUndispatched
 }
 
+// targetAgentRevision returns the agent revision the host should run, which
+// depends on how far the latest revision has been rolled out to its distro.
+func (as *APIServer) targetAgentRevision(h *host.Host, conf evergreen.AgentRolloutConfig) (string, error) {
+	taskRunnerInstance := taskrunner.NewTaskRunner(&as.Settings)
+	return taskRunnerInstance.HostGateway.TargetAgentRevision(h, conf)
+}
+
 // checkHostHealth checks that host is running and creates a task response that is sent back to the agent after the task ends.
 func checkHostHealth(h *host.Host, agentRevision string) (bool, string) {
 	if h.Status != evergreen.HostRunning {
@@ -91,8 +98,8 @@ func checkHostHealth(h *host.Host, agentRevision string) (bool, string) {
 			h.Id, h.Status)
 	}
 	if h.AgentRevision != agentRevision {
-		return true, fmt.Sprintf("agent should be rebuilt:"+
-			"host has agent revision %s and latest revision is %s",
+		return true, fmt.Sprintf("agent should be rebuilt: "+
+			"host has agent revision %s and target revision is %s",
 			h.AgentRevision, agentRevision)
 	}
 	return false, ""
@@ -189,34 +196,43 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// task cost calculations have no impact on task results, so do them in their own goroutine
-	go as.updateTaskCost(t, currentHost, finishTime)
-
-	if !evergreen.IsPatchRequester(t.Requester) {
-		if t.IsPartOfDisplay() {
-			parent := t.DisplayTask
-			if task.IsFinished(*parent) {
-				grip.Error(errors.Wrapf(alerts.RunTaskFailureTriggers(parent.Id),
-					"processing alert triggers for display task %s", parent.Id))
+	// a task that was retried has already been reset for its next
+	// execution, so its failure, cost and duration are not recorded against it
+	if !updates.TaskRetried {
+		// task cost calculations have no impact on task results, so do them in their own goroutine
+		go as.updateTaskCost(t, currentHost, finishTime)
+
+		if !evergreen.IsPatchRequester(t.Requester) {
+			if t.IsPartOfDisplay() {
+				parent := t.DisplayTask
+				if task.IsFinished(*parent) {
+					grip.Error(errors.Wrapf(alerts.RunTaskFailureTriggers(parent.Id),
+						"processing alert triggers for display task %s", parent.Id))
+				}
+			} else {
+				grip.Infoln("Processing alert triggers for task", t.Id)
+
+				grip.Error(errors.Wrapf(alerts.RunTaskFailureTriggers(t.Id),
+					"processing alert triggers for task %s", t.Id))
 			}
-		} else {
-			grip.Infoln("Processing alert triggers for task", t.Id)
+		}
+		// TODO(EVG-223) process patch-specific triggers
 
-			grip.Error(errors.Wrapf(alerts.RunTaskFailureTriggers(t.Id),
-				"processing alert triggers for task %s", t.Id))
+		// update the bookkeeping entry for the task
+		err = bookkeeping.UpdateExpectedDuration(t, t.TimeTaken)
+		if err != nil {
+			grip.Errorln("Error updating expected duration:", err)
 		}
 	}
-	// TODO(EVG-223) process patch-specific triggers
-
-	// update the bookkeeping entry for the task
-	err = bookkeeping.UpdateExpectedDuration(t, t.TimeTaken)
+	adminSettings, err := evergreen.GetConfig()
 	if err != nil {
-		grip.Errorln("Error updating expected duration:", err)
+		grip.Errorf("error retrieving admin settings %+v", err)
+		as.WriteJSON(w, http.StatusInternalServerError, err)
+		return
 	}
-	taskRunnerInstance := taskrunner.NewTaskRunner(&as.Settings)
-	agentRevision, err := taskRunnerInstance.HostGateway.GetAgentRevision()
+	agentRevision, err := as.targetAgentRevision(currentHost, adminSettings.AgentRollout)
 	if err != nil {
-		grip.Errorf("error getting current agent revision %+v", err)
+		grip.Errorf("error getting target agent revision %+v", err)
 		as.WriteJSON(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -258,6 +274,46 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// SpotInterruption is called by the agent when EC2 gives notice that the
+// agent's spot host is about to be reclaimed. The task is restarted without
+// counting against its restarts, and the host is decommissioned so that it is
+// terminated instead of being given another task.
+func (as *APIServer) SpotInterruption(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+	currentHost := MustHaveHost(r)
+
+	if err := currentHost.SetDecommissioned(evergreen.User); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError,
+			errors.Wrapf(err, "error decommissioning host %s", currentHost.Id))
+		return
+	}
+
+	if err := currentHost.ClearRunningTask(t.Id, time.Now()); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError,
+			errors.Wrapf(err, "error clearing running task %s for host %s", t.Id, currentHost.Id))
+		return
+	}
+
+	taskId := t.Id
+	if t.IsPartOfDisplay() {
+		taskId = t.DisplayTask.Id
+	}
+	if err := model.ResetSpotInterruptedTask(taskId, APIServerLockTitle); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError,
+			errors.Wrapf(err, "error restarting task %s", taskId))
+		return
+	}
+
+	grip.Info(message.Fields{
+		"message": "restarted task interrupted by spot instance reclamation",
+		"task":    t.Id,
+		"host":    currentHost.Id,
+		"distro":  currentHost.Distro.Id,
+	})
+
+	as.WriteJSON(w, http.StatusOK, struct{}{})
+}
+
 // updateTaskCost determines a task's cost based on the host it ran on. Hosts that
 // are unable to calculate their own costs will not set a task's Cost field. Errors
 // are logged but not returned, since any number of API failures could happen and
@@ -282,6 +338,43 @@ func (as *APIServer) updateTaskCost(t *task.Task, h *host.Host, finishTime time.
 	}
 }
 
+// removeFromWarmPool takes a host that has been dispatched its first task out
+// of its distro's warm pool, and records the cost of the time it spent idle
+// there so that it is attributed to the distro rather than the task. As with
+// task costs, errors are logged but not returned.
+func (as *APIServer) removeFromWarmPool(h *host.Host, dispatchTime time.Time) {
+	cost := 0.0
+	manager, err := cloud.GetCloudManager(h.Provider, &as.Settings)
+	if err != nil {
+		grip.Errorf("Error loading provider for host %s warm pool cost calculation: %+v", h.Id, err)
+	} else if cost, err = cloud.WarmPoolCost(h, manager, dispatchTime); err != nil {
+		grip.Errorf("Error calculating warm pool cost for host %s: %+v", h.Id, err)
+	}
+
+	if err = h.LeaveWarmPool(cost); err != nil {
+		grip.Errorf("Error removing host %s from warm pool: %+v", h.Id, err)
+	}
+}
+
+// warmPoolPollWindow is how recently a warm pool host's agent must have asked
+// for a task for dispatch to count on it taking one.
+const warmPoolPollWindow = time.Minute
+
+// yieldToWarmPool reports whether a host outside its distro's warm pool should
+// be given no task, because there are at least as many idle warm pool hosts
+// still asking for tasks as there are tasks queued, so that the warm pool's
+// hosts are handed tasks first.
+func yieldToWarmPool(h *host.Host, queueLength int, now time.Time) (bool, error) {
+	if h.WarmPool || h.Distro.WarmPoolSize <= 0 || queueLength == 0 {
+		return false, nil
+	}
+	idle, err := host.Count(host.ByIdleWarmPoolForDistro(h.Distro.Id, now.Add(-warmPoolPollWindow)))
+	if err != nil {
+		return false, errors.Wrapf(err, "error counting idle warm pool hosts for distro %s", h.Distro.Id)
+	}
+	return idle >= queueLength, nil
+}
+
 // assignNextAvailableTask gets the next task from the queue and sets the running task field
 // of currentHost.
 func assignNextAvailableTask(taskQueue model.TaskQueueAccessor, currentHost *host.Host, spec model.TaskSpec) (*task.Task, error) {
@@ -372,6 +465,7 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		err = errors.Wrap(err, "error retrieving admin settings")
 		grip.Error(err)
 		as.WriteJSON(w, http.StatusInternalServerError, err)
+		return
 	}
 	if adminSettings.ServiceFlags.TaskDispatchDisabled {
 		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), "task dispatch is disabled, returning no task")
@@ -379,11 +473,10 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	taskRunnerInstance := taskrunner.NewTaskRunner(&as.Settings)
 	// check host health before getting next task
-	agentRevision, err := taskRunnerInstance.HostGateway.GetAgentRevision()
+	agentRevision, err := as.targetAgentRevision(h, adminSettings.AgentRollout)
 	if err != nil {
-		grip.Errorf("error getting current agent revision %+v", err)
+		grip.Errorf("error getting target agent revision %+v", err)
 		as.WriteJSON(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -471,6 +564,19 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		as.WriteJSON(w, http.StatusOK, response)
 		return
 	}
+	// leave the queued tasks to the distro's warm pool if it can take them
+	yield, err := yieldToWarmPool(h, taskQueue.Length(), time.Now())
+	if err != nil {
+		grip.Error(err)
+		as.WriteJSON(w, http.StatusInternalServerError, err)
+		return
+	}
+	if yield {
+		grip.Debugf("host %s leaving tasks to the warm pool of distro %s", h.Id, h.Distro.Id)
+		as.WriteJSON(w, http.StatusOK, response)
+		return
+	}
+
 	// assign the task to a host and retrieve the task
 	nextTask, err := assignNextAvailableTask(taskQueue, h, groupSpec)
 	if err != nil {
@@ -493,6 +599,9 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		as.WriteJSON(w, http.StatusInternalServerError, err)
 		return
 	}
+	if h.WarmPool {
+		as.removeFromWarmPool(h, time.Now())
+	}
 	response.TaskId = nextTask.Id
 	response.TaskSecret = nextTask.Secret
 	grip.Infof("assigned task %s to host %s", nextTask.Id, h.Id)
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
@@ -478,6 +479,42 @@ func TestNextTask(t *testing.T) {
 	})
 }
 
+func TestYieldToWarmPool(t *testing.T) {
+	Convey("With a distro that keeps a warm pool", t, func() {
+		So(db.ClearCollections(host.Collection), ShouldBeNil)
+		now := time.Now()
+		d := distro.Distro{Id: "warm", WarmPoolSize: 2}
+		for i, lastCommunicated := range []time.Time{now, now.Add(-time.Hour)} {
+			warmHost := host.Host{
+				Id:                    fmt.Sprintf("warm%d", i),
+				Distro:                d,
+				Status:                evergreen.HostRunning,
+				WarmPool:              true,
+				LastCommunicationTime: lastCommunicated,
+			}
+			So(warmHost.Insert(), ShouldBeNil)
+		}
+		h := &host.Host{Id: "cold", Distro: d, Status: evergreen.HostRunning}
+
+		Convey("other hosts wait while the warm pool's polling hosts can take the queue", func() {
+			yield, err := yieldToWarmPool(h, 1, now)
+			So(err, ShouldBeNil)
+			So(yield, ShouldBeTrue)
+		})
+		Convey("other hosts take tasks the warm pool cannot", func() {
+			yield, err := yieldToWarmPool(h, 2, now)
+			So(err, ShouldBeNil)
+			So(yield, ShouldBeFalse)
+		})
+		Convey("warm pool hosts never wait", func() {
+			h.WarmPool = true
+			yield, err := yieldToWarmPool(h, 1, now)
+			So(err, ShouldBeNil)
+			So(yield, ShouldBeFalse)
+		})
+	})
+}
+
 func TestValidateTaskEndDetails(t *testing.T) {
 	Convey("With a set of end details with different statuses", t, func() {
 		details := apimodels.TaskEndDetail{}
@@ -130,7 +130,12 @@ func (uis *UIServer) modifyHost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		err := h.SetStatus(newStatus, u.Id)
+		var err error
+		if currentStatus == evergreen.HostQuarantined && newStatus == evergreen.HostRunning {
+			err = h.Unquarantine(u.Id)
+		} else {
+			err = h.SetStatus(newStatus, u.Id)
+		}
 		if err != nil {
 			uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "Error updating host"))
 			return
@@ -182,7 +187,12 @@ func (uis *UIServer) modifyHosts(w http.ResponseWriter, r *http.Request) {
 		numHostsUpdated := 0
 
 		for _, host := range hosts {
-			err := host.SetStatus(newStatus, user.Id)
+			var err error
+			if host.Status == evergreen.HostQuarantined && newStatus == evergreen.HostRunning {
+				err = host.Unquarantine(user.Id)
+			} else {
+				err = host.SetStatus(newStatus, user.Id)
+			}
 			if err != nil {
 				uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "Error updating host"))
 				return
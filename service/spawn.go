@@ -48,12 +48,19 @@ func (uis *UIServer) spawnPage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	settings, err := evergreen.GetConfig()
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "Error getting spawn host quotas"))
+		return
+	}
+	maxHosts := settings.SpawnHost.UserQuota(MustHaveUser(r).Id).MaxHosts
+
 	uis.WriteHTML(w, http.StatusOK, struct {
 		Distro          *distro.Distro
 		Task            *task.Task
 		MaxHostsPerUser int
 		ViewData
-	}{spawnDistro, spawnTask, spawn.MaxPerUser, uis.GetCommonViewData(w, r, false, true)}, "base", "spawned_hosts.html", "base_angular.html", "menu.html")
+	}{spawnDistro, spawnTask, maxHosts, uis.GetCommonViewData(w, r, false, true)}, "base", "spawned_hosts.html", "base_angular.html", "menu.html")
 }
 
 func (uis *UIServer) getSpawnedHosts(w http.ResponseWriter, r *http.Request) {
@@ -105,6 +112,8 @@ func (uis *UIServer) requestNewHost(w http.ResponseWriter, r *http.Request) {
 		PublicKey string `json:"public_key"`
 		SaveKey   bool   `json:"save_key"`
 		UserData  string `json:"userdata"`
+
+		HomeVolumeSize int `json:"home_volume_size"`
 	}{}
 
 	if err := util.ReadJSONInto(util.NewRequestReader(r), &putParams); err != nil {
@@ -121,7 +130,13 @@ func (uis *UIServer) requestNewHost(w http.ResponseWriter, r *http.Request) {
 		PushFlash(uis.CookieStore, r, w, NewSuccessFlash("Public key successfully saved."))
 	}
 	hc := &data.DBHostConnector{}
-	spawnHost, err := hc.NewIntentHost(putParams.Distro, putParams.PublicKey, putParams.Task, putParams.UserData, authedUser)
+	spawnHost, err := hc.NewIntentHost(&restModel.HostPostRequest{
+		DistroID:       putParams.Distro,
+		KeyName:        putParams.PublicKey,
+		TaskID:         putParams.Task,
+		UserData:       putParams.UserData,
+		HomeVolumeSize: putParams.HomeVolumeSize,
+	}, authedUser)
 
 	if err != nil {
 		uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "Error spawning host"))
@@ -200,8 +215,14 @@ func (uis *UIServer) modifySpawnHost(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "bad hours param", http.StatusBadRequest)
 			return
 		}
+		var maxExpiration time.Duration
+		maxExpiration, err = spawn.MaxExpiration(u)
+		if err != nil {
+			uis.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
 		var futureExpiration time.Time
-		futureExpiration, err = spawn.MakeExtendedHostExpiration(h, time.Duration(addtHours)*time.Hour)
+		futureExpiration, err = spawn.MakeExtendedHostExpiration(h, time.Duration(addtHours)*time.Hour, maxExpiration)
 		if err != nil {
 			uis.LoggedError(w, r, http.StatusBadRequest, err)
 			return
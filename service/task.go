@@ -44,6 +44,7 @@ type uiTaskData struct {
 	TaskWaiting      string                  `json:"task_waiting"`
 	Activated        bool                    `json:"activated"`
 	Restarts         int                     `json:"restarts"`
+	AutoRetries      int                     `json:"auto_retries"`
 	Execution        int                     `json:"execution"`
 	TotalExecutions  int                     `json:"total_executions"`
 	StartTime        int64                   `json:"start_time"`
@@ -102,6 +103,7 @@ type uiDep struct {
 	RequiredStatus string                  `json:"required"`
 	Activated      bool                    `json:"activated"`
 	BuildVariant   string                  `json:"build_variant"`
+	Project        string                  `json:"project"`
 	Details        apimodels.TaskEndDetail `json:"task_end_details"`
 	Recursive      bool                    `json:"recursive"`
 	TaskWaiting    string                  `json:"task_waiting"`
@@ -207,6 +209,7 @@ func (uis *UIServer) taskPage(w http.ResponseWriter, r *http.Request) {
 		BuildId:             projCtx.Task.BuildId,
 		Activated:           projCtx.Task.Activated,
 		Restarts:            projCtx.Task.Restarts,
+		AutoRetries:         projCtx.Task.AutoRetries,
 		Execution:           projCtx.Task.Execution,
 		Requester:           projCtx.Task.Requester,
 		StartTime:           projCtx.Task.StartTime.UnixNano(),
@@ -361,7 +364,7 @@ func getTaskDependencies(t *task.Task) ([]uiDep, string, error) {
 		depIds = append(depIds, dep.TaskId)
 	}
 	dependencies, err := task.Find(task.ByIds(depIds).WithFields(task.DisplayNameKey, task.StatusKey,
-		task.ActivatedKey, task.BuildVariantKey, task.DetailsKey, task.DependsOnKey))
+		task.ActivatedKey, task.BuildVariantKey, task.ProjectKey, task.DetailsKey, task.DependsOnKey))
 	if err != nil {
 		return nil, "", err
 	}
@@ -378,6 +381,7 @@ func getTaskDependencies(t *task.Task) ([]uiDep, string, error) {
 					RequiredStatus: dep.Status,
 					Activated:      depTask.Activated,
 					BuildVariant:   depTask.BuildVariant,
+					Project:        depTask.Project,
 					Details:        depTask.Details,
 					//TODO EVG-614: add "Recursive: dep.Recursive," once Task.DependsOn includes all recursive dependencies
 				}
@@ -427,7 +431,7 @@ func addRecDeps(tasks map[string]task.Task, uiDeps map[string]uiDep, done map[st
 	}
 
 	deps, err := task.Find(task.ByIds(depIds).WithFields(task.DisplayNameKey, task.StatusKey, task.ActivatedKey,
-		task.BuildVariantKey, task.DetailsKey, task.DependsOnKey))
+		task.BuildVariantKey, task.ProjectKey, task.DetailsKey, task.DependsOnKey))
 
 	if err != nil {
 		return err
@@ -451,6 +455,7 @@ func addRecDeps(tasks map[string]task.Task, uiDeps map[string]uiDep, done map[st
 						RequiredStatus: dep.Status,
 						Activated:      depTask.Activated,
 						BuildVariant:   depTask.BuildVariant,
+						Project:        depTask.Project,
 						Details:        depTask.Details,
 						Recursive:      true,
 					}
@@ -0,0 +1,184 @@
+package spawn
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// QuotaUsage is the spawn host usage counted against a quota: the number of
+// hosts that are not terminated, and the cost of all hosts since the start of
+// the month.
+type QuotaUsage struct {
+	Hosts       int
+	MonthlyCost float64
+}
+
+// Quota is a user's spawn host quota and usage, along with those of the
+// user's team if the team has a quota.
+type Quota struct {
+	User      evergreen.SpawnHostQuota
+	UserUsage QuotaUsage
+	Team      *evergreen.SpawnHostQuota
+	TeamUsage QuotaUsage
+}
+
+// GetQuota looks up the quotas that apply to the given user and computes the
+// usage counted against them.
+func GetQuota(u *user.DBUser, settings *evergreen.Settings) (*Quota, error) {
+	quota, err := getQuotaLimits(u)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	now := time.Now()
+	quota.UserUsage, err = getUsage([]string{u.Id}, settings, now)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if quota.Team == nil {
+		return quota, nil
+	}
+
+	members, err := user.Find(user.ByTeam(u.Team))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding users on team %s", u.Team)
+	}
+	ids := []string{u.Id}
+	for _, member := range members {
+		if member.Id != u.Id {
+			ids = append(ids, member.Id)
+		}
+	}
+	quota.TeamUsage, err = getUsage(ids, settings, now)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return quota, nil
+}
+
+// MaxExpiration returns the furthest in the future that a host spawned by the
+// given user may expire.
+func MaxExpiration(u *user.DBUser) (time.Duration, error) {
+	quota, err := getQuotaLimits(u)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return quota.MaxExpiration(), nil
+}
+
+// getQuotaLimits looks up the quotas that apply to the given user, without
+// computing any usage. Quotas are read from the database so that changes made
+// by admins take effect without restarting.
+func getQuotaLimits(u *user.DBUser) (*Quota, error) {
+	conf, err := evergreen.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting spawn host quotas")
+	}
+
+	quota := &Quota{User: conf.SpawnHost.UserQuota(u.Id)}
+	if teamQuota, ok := conf.SpawnHost.TeamQuota(u.Team); ok {
+		quota.Team = &teamQuota
+	}
+	return quota, nil
+}
+
+// Check returns an error if spawning another host would exceed the quota.
+func (q *Quota) Check() error {
+	if err := checkQuota(q.User, q.UserUsage); err != nil {
+		return errors.Wrapf(err, "user %s", q.User.Name)
+	}
+	if q.Team != nil {
+		if err := checkQuota(*q.Team, q.TeamUsage); err != nil {
+			return errors.Wrapf(err, "team %s", q.Team.Name)
+		}
+	}
+	return nil
+}
+
+// MaxExpiration returns the furthest in the future that a host may expire,
+// which is the stricter of the user's and team's limits.
+func (q *Quota) MaxExpiration() time.Duration {
+	hours := q.User.MaxExpirationHours
+	if q.Team != nil && q.Team.MaxExpirationHours != 0 && q.Team.MaxExpirationHours < hours {
+		hours = q.Team.MaxExpirationHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func checkQuota(quota evergreen.SpawnHostQuota, usage QuotaUsage) error {
+	if quota.MaxHosts != 0 && usage.Hosts >= quota.MaxHosts {
+		return errors.Errorf("is already running the max allowed number of spawn hosts (%d of %d)",
+			usage.Hosts, quota.MaxHosts)
+	}
+	if quota.MaxMonthlyCost != 0 && usage.MonthlyCost >= quota.MaxMonthlyCost {
+		return errors.Errorf("has already spent the max allowed monthly spawn host cost ($%.2f of $%.2f)",
+			usage.MonthlyCost, quota.MaxMonthlyCost)
+	}
+	return nil
+}
+
+// getUsage computes the spawn host usage of the given users. Hosts whose
+// provider cannot calculate costs do not count towards the monthly cost, and
+// errors calculating the cost of a host are logged rather than returned.
+func getUsage(users []string, settings *evergreen.Settings, now time.Time) (QuotaUsage, error) {
+	usage := QuotaUsage{}
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	hosts, err := host.Find(host.ByUsersActiveSince(users, monthStart))
+	if err != nil {
+		return usage, errors.Wrap(err, "error finding spawn hosts")
+	}
+
+	for i := range hosts {
+		h := &hosts[i]
+		if h.Status != evergreen.HostTerminated {
+			usage.Hosts++
+		}
+
+		if h.StartTime.IsZero() {
+			continue
+		}
+		start, end := h.StartTime, now
+		if start.Before(monthStart) {
+			start = monthStart
+		}
+		if h.Status == evergreen.HostTerminated {
+			end = h.TerminationTime
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		manager, err := cloud.GetCloudManager(h.Provider, settings)
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "error loading provider for spawn host cost",
+				"host":    h.Id,
+			}))
+			continue
+		}
+		calc, ok := manager.(cloud.CloudCostCalculator)
+		if !ok {
+			continue
+		}
+		cost, err := calc.CostForDuration(h, start, end)
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "error calculating spawn host cost",
+				"host":    h.Id,
+			}))
+			continue
+		}
+		usage.MonthlyCost += cost
+	}
+
+	return usage, nil
+}
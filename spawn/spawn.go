@@ -15,6 +15,7 @@ import (
 	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/subprocess"
 	"github.com/evergreen-ci/evergreen/util"
@@ -35,9 +36,7 @@ var passwordRegexps = []*regexp.Regexp{
 }
 
 const (
-	MaxPerUser                 = 3
-	DefaultExpiration          = 24 * time.Hour
-	MaxExpirationDurationHours = 24 * time.Hour * 7 // 7 days
+	DefaultExpiration = 24 * time.Hour
 )
 
 // Options holds the required parameters for spawning a host.
@@ -48,6 +47,17 @@ type Options struct {
 	UserData  string
 	TaskId    string
 	Owner     *user.DBUser
+
+	// HomeVolumeSize, if non-zero, attaches the owner's persistent home
+	// volume to the host, creating one of this size in GB if needed.
+	HomeVolumeSize int
+
+	// ReplayUntilFailure runs the commands of the failed task given by
+	// TaskId on the host, stopping before the command that failed.
+	ReplayUntilFailure bool
+
+	// quota is looked up during validation and limits the host's expiration.
+	quota *Quota
 }
 
 // Validate returns an instance of BadOptionsErr if the SpawnOptions object contains invalid
@@ -58,6 +68,25 @@ func (so *Options) validate() error {
 		return errors.New("spawn options include nil user")
 	}
 
+	if so.TaskId != "" {
+		t, err := task.FindOne(task.ById(so.TaskId))
+		if err != nil {
+			return errors.Wrapf(err, "error finding task %s", so.TaskId)
+		}
+		if t == nil {
+			return errors.Errorf("Invalid spawn options: task %s not found", so.TaskId)
+		}
+		if so.ReplayUntilFailure && t.Status != evergreen.TaskFailed {
+			return errors.Errorf("Invalid spawn options: task %s did not fail, so it can't be replayed until failure", so.TaskId)
+		}
+		// spawn the host in the task's distro unless told otherwise
+		if so.Distro == "" {
+			so.Distro = t.DistroId
+		}
+	} else if so.ReplayUntilFailure {
+		return errors.New("Invalid spawn options: a task must be given to replay until failure")
+	}
+
 	d, err := distro.FindOne(distro.ById(so.Distro))
 	if err != nil {
 		return errors.Errorf("Invalid spawn options: distro %v", so.Distro)
@@ -67,15 +96,17 @@ func (so *Options) validate() error {
 		return errors.Errorf("Invalid spawn options: spawning not allowed for distro  %v", so.Distro)
 	}
 
-	// if the user already has too many active spawned hosts, deny the request
-	activeSpawnedHosts, err := host.Find(host.ByUserWithRunningStatus(so.UserName))
-	if err != nil {
-		return errors.Wrap(err, "Error occurred finding user's current hosts")
+	if so.HomeVolumeSize < 0 {
+		return errors.Errorf("Invalid spawn options: home volume size %d is negative", so.HomeVolumeSize)
 	}
 
-	if len(activeSpawnedHosts) >= MaxPerUser {
-		return errors.Errorf("User is already running the max allowed number of spawn hosts (%d of %d)",
-			len(activeSpawnedHosts), MaxPerUser)
+	// if the user or their team is already at their quota, deny the request
+	so.quota, err = GetQuota(so.Owner, evergreen.GetEnvironment().Settings())
+	if err != nil {
+		return errors.Wrap(err, "Error occurred finding user's spawn host quota")
+	}
+	if err = so.quota.Check(); err != nil {
+		return errors.Wrap(err, "Spawn host quota exceeded")
 	}
 
 	// validate public key
@@ -159,11 +190,17 @@ func CreateHost(so Options) (*host.Host, error) {
 
 	// spawn the host
 	provisionOptions := &host.ProvisionOptions{
-		LoadCLI: true,
-		TaskId:  so.TaskId,
-		OwnerId: so.Owner.Id,
+		LoadCLI:        true,
+		TaskId:         so.TaskId,
+		OwnerId:        so.Owner.Id,
+		HomeVolumeSize: so.HomeVolumeSize,
+
+		ReplayUntilFailure: so.ReplayUntilFailure,
 	}
 	expiration := DefaultExpiration
+	if maxExpiration := so.quota.MaxExpiration(); expiration > maxExpiration {
+		expiration = maxExpiration
+	}
 	hostOptions := cloud.HostOptions{
 		ProvisionOptions:   provisionOptions,
 		UserName:           so.UserName,
@@ -267,11 +304,37 @@ func TerminateHost(host *host.Host, settings *evergreen.Settings, user string) e
 	return nil
 }
 
-func MakeExtendedHostExpiration(host *host.Host, extendBy time.Duration) (time.Time, error) {
+// StopHost stops a running spawn host, if its provider supports it.
+func StopHost(host *host.Host, settings *evergreen.Settings, user string) error {
+	if host.Status != evergreen.HostRunning {
+		return errors.Errorf("Host cannot be stopped from status '%s'", host.Status)
+	}
+	cloudHost, err := cloud.GetCloudHost(host, settings)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(cloudHost.StopInstance(user))
+}
+
+// StartHost starts a stopped spawn host.
+func StartHost(host *host.Host, settings *evergreen.Settings, user string) error {
+	if host.Status != evergreen.HostStopped {
+		return errors.Errorf("Host cannot be started from status '%s'", host.Status)
+	}
+	cloudHost, err := cloud.GetCloudHost(host, settings)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(cloudHost.StartInstance(user))
+}
+
+// MakeExtendedHostExpiration returns the host's expiration time extended by
+// the given duration, unless that is more than maxExpiration in the future.
+func MakeExtendedHostExpiration(host *host.Host, extendBy, maxExpiration time.Duration) (time.Time, error) {
 	newExp := host.ExpirationTime.Add(extendBy)
 	remainingDuration := newExp.Sub(time.Now()) //nolint
-	if remainingDuration > MaxExpirationDurationHours {
-		return time.Time{}, errors.Errorf("Can not extend host '%s' expiration by '%s'. Maximum host duration is limited to %s", host.Id, extendBy.String(), MaxExpirationDurationHours.String())
+	if remainingDuration > maxExpiration {
+		return time.Time{}, errors.Errorf("Can not extend host '%s' expiration by '%s'. Maximum host duration is limited to %s", host.Id, extendBy.String(), maxExpiration.String())
 	}
 
 	return newExp, nil
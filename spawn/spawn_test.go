@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,7 +35,7 @@ func TestMakeExtendedHostExpiration(t *testing.T) {
 		ExpirationTime: time.Now().Add(12 * time.Hour),
 	}
 
-	expTime, err := MakeExtendedHostExpiration(&h, time.Hour)
+	expTime, err := MakeExtendedHostExpiration(&h, time.Hour, 24*7*time.Hour)
 	assert.NotZero(expTime)
 	assert.NoError(err, expTime.Format(time.RFC3339))
 }
@@ -46,7 +47,34 @@ func TestMakeExtendedHostExpirationFailsBeyondOneWeek(t *testing.T) {
 		ExpirationTime: time.Now().Add(12 * time.Hour),
 	}
 
-	expTime, err := MakeExtendedHostExpiration(&h, 24*7*time.Hour)
+	expTime, err := MakeExtendedHostExpiration(&h, 24*7*time.Hour, 24*7*time.Hour)
 	assert.Zero(expTime)
 	assert.Error(err, expTime.Format(time.RFC3339))
 }
+
+func TestQuotaCheck(t *testing.T) {
+	assert := assert.New(t) // nolint
+
+	quota := &Quota{
+		User:      evergreen.SpawnHostQuota{Name: "user", MaxHosts: 2, MaxExpirationHours: 48},
+		UserUsage: QuotaUsage{Hosts: 1, MonthlyCost: 1000},
+	}
+	assert.NoError(quota.Check())
+	assert.Equal(48*time.Hour, quota.MaxExpiration())
+
+	quota.UserUsage.Hosts = 2
+	assert.Error(quota.Check())
+
+	quota.UserUsage.Hosts = 1
+	quota.User.MaxMonthlyCost = 100
+	assert.Error(quota.Check())
+
+	quota.User.MaxMonthlyCost = 0
+	quota.Team = &evergreen.SpawnHostQuota{Name: "team", MaxMonthlyCost: 500, MaxExpirationHours: 24}
+	quota.TeamUsage = QuotaUsage{Hosts: 10, MonthlyCost: 499}
+	assert.NoError(quota.Check())
+	assert.Equal(24*time.Hour, quota.MaxExpiration())
+
+	quota.TeamUsage.MonthlyCost = 500
+	assert.Error(quota.Check())
+}
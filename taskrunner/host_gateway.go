@@ -3,8 +3,9 @@ package taskrunner
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -25,7 +26,6 @@ import (
 const (
 	// SSHTimeout defines the timeout for the SSH commands in this package.
 	sshTimeout = 30 * time.Second
-	agentFile  = "agent"
 )
 
 // HostGateway is responsible for kicking off tasks on remote machines.
@@ -35,6 +35,8 @@ type HostGateway interface {
 	StartAgentOnHost(context.Context, *evergreen.Settings, host.Host) error
 	// gets the current revision of the agent
 	GetAgentRevision() (string, error)
+	// gets the revision of the agent the host should run
+	TargetAgentRevision(*host.Host, evergreen.AgentRolloutConfig) (string, error)
 }
 
 // Implementation of the HostGateway that builds and copies over the MCI
@@ -78,15 +80,10 @@ func getHostMessage(h host.Host) message.Fields {
 // machine. Returns an error if any step along the way fails.
 func (agbh *AgentHostGateway) StartAgentOnHost(ctx context.Context, settings *evergreen.Settings, hostObj host.Host) error {
 
-	// get the host's SSH options
 	cloudHost, err := cloud.GetCloudHost(&hostObj, settings)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to get cloud host for %s", hostObj.Id)
 	}
-	sshOptions, err := cloudHost.GetSSHOptions()
-	if err != nil {
-		return errors.Wrapf(err, "Error getting ssh options for host %s", hostObj.Id)
-	}
 
 	d, err := distro.FindOne(distro.ById(hostObj.Distro.Id))
 	if err != nil {
@@ -98,8 +95,16 @@ func (agbh *AgentHostGateway) StartAgentOnHost(ctx context.Context, settings *ev
 	grip.Info(message.Fields{"runner": RunnerName,
 		"message": "prepping host for agent",
 		"host":    hostObj.Id})
-	agentRevision, err := agbh.prepRemoteHost(ctx, hostObj, sshOptions, settings)
+	currentRevision, err := agbh.GetAgentRevision()
 	if err != nil {
+		return errors.WithStack(err)
+	}
+	agentRevision, err := agbh.TargetAgentRevision(&hostObj, settings.AgentRollout)
+	if err != nil {
+		return errors.Wrapf(err, "error getting target agent revision for host %s", hostObj.Id)
+	}
+	curlCmd := hostutil.CurlAgentCommand(settings.Ui.Url, agentRevision, currentRevision, &hostObj)
+	if err = agbh.prepRemoteHost(ctx, cloudHost, curlCmd); err != nil {
 		return errors.Wrapf(err, "error prepping remote host %s", hostObj.Id)
 	}
 	grip.Info(message.Fields{"runner": RunnerName, "message": "prepping host finished successfully", "host": hostObj.Id})
@@ -113,7 +118,7 @@ func (agbh *AgentHostGateway) StartAgentOnHost(ctx context.Context, settings *ev
 
 	// Start agent to listen for tasks
 	grip.Info(getHostMessage(hostObj))
-	if err = startAgentOnRemote(settings, &hostObj, sshOptions); err != nil {
+	if err = startAgentOnRemote(ctx, settings, cloudHost); err != nil {
 		// mark the host's provisioning as failed
 		if err = hostObj.SetUnprovisioned(); err != nil {
 			grip.Error(message.WrapError(err, message.Fields{
@@ -152,20 +157,117 @@ func (agbh *AgentHostGateway) GetAgentRevision() (string, error) {
 	return strings.TrimSpace(string(hashBytes)), nil
 }
 
+// TargetAgentRevision returns the agent revision the host should run, which
+// depends on how far the current revision has been rolled out to its distro.
+// Hosts get the current revision if the build of an earlier one is not kept.
+func (agbh *AgentHostGateway) TargetAgentRevision(h *host.Host, conf evergreen.AgentRolloutConfig) (string, error) {
+	current, err := agbh.GetAgentRevision()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	target, err := host.TargetAgentRevision(h, current, conf)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if target == current {
+		return current, nil
+	}
+
+	path := filepath.Join(agbh.ExecutablesDir, hostutil.RevisionExecutableSubPath(target, &h.Distro))
+	if _, err = os.Stat(path); err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"runner":   RunnerName,
+			"message":  "build of earlier agent revision is not available, using current revision",
+			"host":     h.Id,
+			"distro":   h.Distro.Id,
+			"revision": target,
+			"current":  current,
+		}))
+		return current, nil
+	}
+	return target, nil
+}
+
+// archiveAgentBuild keeps a copy of the current agent builds in a
+// subdirectory of the executables directory named for their revision, so
+// that hosts can still download them while a later revision is rolled out.
+func (agbh *AgentHostGateway) archiveAgentBuild() error {
+	revision, err := agbh.GetAgentRevision()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	archiveDir := filepath.Join(agbh.ExecutablesDir, revision)
+	if _, err = os.Stat(archiveDir); err == nil {
+		return nil
+	}
+
+	tempDir, err := ioutil.TempDir(agbh.ExecutablesDir, "."+revision)
+	if err != nil {
+		return errors.Wrap(err, "error creating agent archive directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	archDirs, err := ioutil.ReadDir(agbh.ExecutablesDir)
+	if err != nil {
+		return errors.Wrap(err, "error reading executables directory")
+	}
+	for _, archDir := range archDirs {
+		if !archDir.IsDir() {
+			continue
+		}
+		for _, name := range []string{"evergreen", "evergreen.exe"} {
+			src := filepath.Join(agbh.ExecutablesDir, archDir.Name(), name)
+			if _, err = os.Stat(src); err != nil {
+				continue
+			}
+			if err = copyExecutable(src, filepath.Join(tempDir, archDir.Name(), name)); err != nil {
+				return errors.Wrapf(err, "error archiving agent build %s", src)
+			}
+		}
+	}
+
+	return errors.Wrapf(os.Rename(tempDir, archiveDir), "error archiving agent revision %s", revision)
+}
+
+// copyExecutable copies the executable at src to dest, creating dest's
+// directory if needed.
+func copyExecutable(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer in.Close()
+
+	if err = os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		grip.Warning(out.Close())
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(out.Close())
+}
+
 // Prepare the remote machine to run a task.
-func (agbh *AgentHostGateway) prepRemoteHost(ctx context.Context, hostObj host.Host, sshOptions []string, settings *evergreen.Settings) (string, error) {
+func (agbh *AgentHostGateway) prepRemoteHost(ctx context.Context, cloudHost *cloud.CloudHost, curlCmd string) error {
+	hostObj := cloudHost.Host
+
 	// copy over the correct agent binary to the remote host
-	if logs, err := hostutil.RunSSHCommand(ctx, hostutil.CurlCommand(settings.Ui.Url, &hostObj), sshOptions, hostObj); err != nil {
-		return "", errors.Wrapf(err, "error downloading agent binary on remote host: %s", logs)
+	if logs, err := cloudHost.RunCommand(ctx, curlCmd); err != nil {
+		return errors.Wrapf(err, "error downloading agent binary on remote host: %s", logs)
 	}
 
 	// return early if we do not need to run the setup script
 	if hostObj.Distro.Setup == "" {
-		return agbh.GetAgentRevision()
+		return nil
 	}
 
 	// run the setup script with the agent
-	if logs, err := hostutil.RunSSHCommand(ctx, hostutil.SetupCommand(&hostObj), sshOptions, hostObj); err != nil {
+	if logs, err := cloudHost.RunCommand(ctx, hostutil.SetupCommand(hostObj)); err != nil {
 		event.LogProvisionFailed(hostObj.Id, logs)
 
 		grip.Error(message.WrapError(err, message.Fields{
@@ -178,36 +280,41 @@ func (agbh *AgentHostGateway) prepRemoteHost(ctx context.Context, hostObj host.H
 
 		// there is no guarantee setup scripts are idempotent, so we terminate the host if the setup script fails
 		if disableErr := hostObj.DisablePoisonedHost(true); disableErr != nil {
-			return "", errors.Wrapf(disableErr, "error terminating host %s", hostObj.Id)
+			return errors.Wrapf(disableErr, "error terminating host %s", hostObj.Id)
 		}
 
-		return "", errors.Wrapf(err, "error running setup script on remote host: %s", logs)
+		return errors.Wrapf(err, "error running setup script on remote host: %s", logs)
 	}
 
-	return agbh.GetAgentRevision()
+	return nil
 }
 
 // Start the agent process on the specified remote host.
-func startAgentOnRemote(settings *evergreen.Settings, hostObj *host.Host, sshOptions []string) error {
-	// the path to the agent binary on the remote machine
-	pathToExecutable := filepath.Join("~", "evergreen")
-	if hostutil.IsWindows(&hostObj.Distro) {
-		pathToExecutable += ".exe"
+func startAgentOnRemote(ctx context.Context, settings *evergreen.Settings, cloudHost *cloud.CloudHost) error {
+	hostObj := cloudHost.Host
+
+	// hosts without SSH get the agent started in the background through
+	// their provider
+	if cloudHost.UsesCommandRunner() {
+		grip.Info(message.Fields{
+			"message": "starting agent on host through provider",
+			"host":    hostObj.Id,
+			"runner":  RunnerName,
+		})
+		if logs, err := cloudHost.RunCommand(ctx, hostutil.BackgroundAgentCommand(settings, hostObj)); err != nil {
+			return errors.Wrapf(err, "error starting agent (%v): %v", hostObj.Id, logs)
+		}
+		event.LogHostAgentDeployed(hostObj.Id)
+		return nil
 	}
 
-	agentCmdParts := []string{
-		pathToExecutable,
-		"agent",
-		fmt.Sprintf("--api_server='%s'", settings.ApiUrl),
-		fmt.Sprintf("--host_id='%s'", hostObj.Id),
-		fmt.Sprintf("--host_secret='%s'", hostObj.Secret),
-		fmt.Sprintf("--log_prefix='%s'", filepath.Join(hostObj.Distro.WorkDir, agentFile)),
-		fmt.Sprintf("--working_directory='%s'", hostObj.Distro.WorkDir),
-		"--cleanup",
+	sshOptions, err := cloudHost.GetSSHOptions()
+	if err != nil {
+		return errors.Wrapf(err, "Error getting ssh options for host %s", hostObj.Id)
 	}
 
 	// build the command to run on the remote machine
-	remoteCmd := strings.Join(agentCmdParts, " ")
+	remoteCmd := hostutil.AgentCommand(settings, hostObj)
 	grip.Info(message.Fields{
 		"message": "starting agent on host",
 		"host":    hostObj.Id,
@@ -222,19 +329,7 @@ func startAgentOnRemote(settings *evergreen.Settings, hostObj *host.Host, sshOpt
 	}
 
 	// run the command to kick off the agent remotely
-	env := map[string]string{}
-	if sumoEndpoint, ok := settings.Credentials["sumologic"]; ok {
-		env["GRIP_SUMO_ENDPOINT"] = sumoEndpoint
-	}
-
-	if settings.Splunk.Populated() {
-		env["GRIP_SPLUNK_SERVER_URL"] = settings.Splunk.ServerURL
-		env["GRIP_SPLUNK_CLIENT_TOKEN"] = settings.Splunk.Token
-
-		if settings.Splunk.Channel != "" {
-			env["GRIP_SPLUNK_CHANNEL"] = settings.Splunk.Channel
-		}
-	}
+	env := hostutil.AgentEnv(settings)
 
 	startAgentCmd := subprocess.NewRemoteCommand(
 		remoteCmd,
@@ -260,7 +355,7 @@ func startAgentOnRemote(settings *evergreen.Settings, hostObj *host.Host, sshOpt
 		return errors.Wrap(err, "problem configuring command output")
 	}
 
-	ctx, cancel := context.WithTimeout(context.TODO(), sshTimeout)
+	ctx, cancel := context.WithTimeout(ctx, sshTimeout)
 	defer cancel()
 	err = startAgentCmd.Run(ctx)
 
@@ -0,0 +1,57 @@
+package taskrunner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveAgentBuild(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "clients")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(os.MkdirAll(filepath.Join(dir, "linux_amd64"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "linux_amd64", "evergreen"), []byte("linux"), 0755))
+	require.NoError(os.MkdirAll(filepath.Join(dir, "windows_amd64"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "windows_amd64", "evergreen.exe"), []byte("windows"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "version"), []byte("abc\n"), 0644))
+
+	gateway := &AgentHostGateway{ExecutablesDir: dir}
+	assert.NoError(gateway.archiveAgentBuild())
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "abc", "linux_amd64", "evergreen"))
+	assert.NoError(err)
+	assert.Equal("linux", string(data))
+	data, err = ioutil.ReadFile(filepath.Join(dir, "abc", "windows_amd64", "evergreen.exe"))
+	assert.NoError(err)
+	assert.Equal("windows", string(data))
+
+	// a newer build does not overwrite the archive
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "linux_amd64", "evergreen"), []byte("newer"), 0755))
+	assert.NoError(gateway.archiveAgentBuild())
+	data, err = ioutil.ReadFile(filepath.Join(dir, "abc", "linux_amd64", "evergreen"))
+	assert.NoError(err)
+	assert.Equal("linux", string(data))
+
+	// the archive is not archived itself with the next revision
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "version"), []byte("def\n"), 0644))
+	assert.NoError(gateway.archiveAgentBuild())
+	_, err = os.Stat(filepath.Join(dir, "def", "abc"))
+	assert.True(os.IsNotExist(err))
+
+	h := &host.Host{Id: "h", Distro: distro.Distro{Arch: "linux_amd64"}}
+	rev, err := gateway.TargetAgentRevision(h, evergreen.AgentRolloutConfig{Disabled: true})
+	assert.NoError(err)
+	assert.Equal("def", rev)
+}
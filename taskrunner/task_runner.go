@@ -51,6 +51,13 @@ func (tr *TaskRunner) Run() error {
 	ctx, cancel := context.WithCancel(context.TODO())
 	defer cancel()
 
+	if gateway, ok := tr.HostGateway.(*AgentHostGateway); ok {
+		grip.Warning(message.WrapError(gateway.archiveAgentBuild(), message.Fields{
+			"runner":  RunnerName,
+			"message": "problem archiving agent build",
+		}))
+	}
+
 	grip.Info(message.Fields{
 		"runner":     RunnerName,
 		"free_hosts": len(freeHosts),
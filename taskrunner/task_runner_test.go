@@ -28,6 +28,10 @@ func (self *MockHostGateway) GetAgentRevision() (string, error) {
 	return agtRevision, nil
 }
 
+func (self *MockHostGateway) TargetAgentRevision(h *host.Host, conf evergreen.AgentRolloutConfig) (string, error) {
+	return agtRevision, nil
+}
+
 func (self *MockHostGateway) StartAgentOnHost(ctx context.Context, settings *evergreen.Settings,
 	targetHost host.Host) error {
 	return nil
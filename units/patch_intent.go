@@ -191,6 +191,11 @@ func (j *patchIntentProcessor) finishPatch(patchDoc *patch.Patch, githubOauthTok
 		}
 	}
 
+	// verify that the parameters are declared and valid
+	if _, err = project.ResolveParameters(patchDoc.Parameters); err != nil {
+		return errors.Wrap(err, "invalid patch parameters")
+	}
+
 	// add the project config
 	projectYamlBytes, err := yaml.Marshal(project)
 	if err != nil {
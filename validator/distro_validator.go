@@ -19,6 +19,8 @@ var distroSyntaxValidators = []distroValidator{
 	ensureValidSSHOptions,
 	ensureValidExpansions,
 	ensureStaticHostsAreNotSpawnable,
+	ensureValidWarmPoolSize,
+	ensureValidBootstrapMethod,
 }
 
 // CheckDistro checks if the distro configuration syntax is valid. Returns
@@ -56,6 +58,48 @@ func ensureStaticHostsAreNotSpawnable(d *distro.Distro, s *evergreen.Settings) [
 	return nil
 }
 
+// ensureValidWarmPoolSize makes sure that the warm pool fits within the
+// distro's maximum number of hosts, and that static distros have none.
+func ensureValidWarmPoolSize(d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	if d.WarmPoolSize == 0 {
+		return nil
+	}
+	if d.WarmPoolSize < 0 {
+		return []ValidationError{{Error, fmt.Sprintf("warm pool size for distro %s cannot be negative", d.Id)}}
+	}
+	if d.Provider == evergreen.ProviderNameStatic {
+		return []ValidationError{{Error, fmt.Sprintf("static distro %s cannot have a warm pool", d.Id)}}
+	}
+	if d.WarmPoolSize > d.PoolSize {
+		return []ValidationError{{Error, fmt.Sprintf("warm pool size for distro %s cannot exceed its maximum number of hosts (%d)", d.Id, d.PoolSize)}}
+	}
+	return nil
+}
+
+// ensureValidBootstrapMethod makes sure that the distro's bootstrap method is
+// known, and that hosts only bootstrap from user data if their provider can
+// pass it to them. Spawn hosts are provisioned over SSH, so spawnable distros
+// must be as well.
+func ensureValidBootstrapMethod(d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	switch d.BootstrapMethod {
+	case "", distro.BootstrapMethodSSH:
+		return nil
+	case distro.BootstrapMethodUserData:
+		if !cloud.SupportsUserData(d.Provider) {
+			return []ValidationError{{Error, fmt.Sprintf("provider '%s' for distro %s does not support bootstrapping from user data", d.Provider, d.Id)}}
+		}
+		if d.IsWindows() {
+			return []ValidationError{{Error, fmt.Sprintf("windows distro %s cannot bootstrap from user data", d.Id)}}
+		}
+		if d.SpawnAllowed {
+			return []ValidationError{{Error, fmt.Sprintf("spawnable distro %s cannot bootstrap from user data", d.Id)}}
+		}
+		return nil
+	default:
+		return []ValidationError{{Error, fmt.Sprintf("'%s' is not a valid bootstrap method for distro %s", d.BootstrapMethod, d.Id)}}
+	}
+}
+
 // ensureHasRequiredFields check that the distro configuration has all the required fields
 func ensureHasRequiredFields(d *distro.Distro, s *evergreen.Settings) []ValidationError {
 	errs := []ValidationError{}
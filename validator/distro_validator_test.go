@@ -250,3 +250,27 @@ func TestEnsureNonZeroID(t *testing.T) {
 	assert.Nil(ensureHasNonZeroID(&distro.Distro{Id: "foo"}, conf))
 	assert.Nil(ensureHasNonZeroID(&distro.Distro{Id: " "}, conf))
 }
+
+func TestEnsureValidWarmPoolSize(t *testing.T) {
+	assert := assert.New(t) // nolint
+
+	assert.Nil(ensureValidWarmPoolSize(&distro.Distro{Id: "foo", PoolSize: 10}, conf))
+	assert.Nil(ensureValidWarmPoolSize(&distro.Distro{Id: "foo", PoolSize: 10, WarmPoolSize: 10}, conf))
+
+	assert.NotNil(ensureValidWarmPoolSize(&distro.Distro{Id: "foo", PoolSize: 10, WarmPoolSize: -1}, conf))
+	assert.NotNil(ensureValidWarmPoolSize(&distro.Distro{Id: "foo", PoolSize: 10, WarmPoolSize: 11}, conf))
+	assert.NotNil(ensureValidWarmPoolSize(&distro.Distro{Id: "foo", Provider: evergreen.ProviderNameStatic, WarmPoolSize: 1}, conf))
+}
+
+func TestEnsureValidBootstrapMethod(t *testing.T) {
+	assert := assert.New(t) // nolint
+
+	assert.Nil(ensureValidBootstrapMethod(&distro.Distro{Id: "foo", Provider: evergreen.ProviderNameStatic}, conf))
+	assert.Nil(ensureValidBootstrapMethod(&distro.Distro{Id: "foo", Provider: evergreen.ProviderNameStatic, BootstrapMethod: distro.BootstrapMethodSSH}, conf))
+	assert.Nil(ensureValidBootstrapMethod(&distro.Distro{Id: "foo", Arch: "linux_amd64", Provider: evergreen.ProviderNameEc2OnDemand, BootstrapMethod: distro.BootstrapMethodUserData}, conf))
+
+	assert.NotNil(ensureValidBootstrapMethod(&distro.Distro{Id: "foo", Provider: evergreen.ProviderNameStatic, BootstrapMethod: distro.BootstrapMethodUserData}, conf))
+	assert.NotNil(ensureValidBootstrapMethod(&distro.Distro{Id: "foo", Arch: "windows_amd64", Provider: evergreen.ProviderNameEc2OnDemand, BootstrapMethod: distro.BootstrapMethodUserData}, conf))
+	assert.NotNil(ensureValidBootstrapMethod(&distro.Distro{Id: "foo", Arch: "linux_amd64", Provider: evergreen.ProviderNameEc2OnDemand, BootstrapMethod: distro.BootstrapMethodUserData, SpawnAllowed: true}, conf))
+	assert.NotNil(ensureValidBootstrapMethod(&distro.Distro{Id: "foo", Provider: evergreen.ProviderNameEc2OnDemand, BootstrapMethod: "carrier-pigeon"}, conf))
+}
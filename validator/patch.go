@@ -46,6 +46,9 @@ func GetPatchedProject(p *patch.Patch, githubOauthToken string) (*model.Project,
 	}
 
 	project := &model.Project{}
+	// files included by the project are read at the same revision, with the
+	// patch's changes applied to them
+	fetch := model.PatchProjectFileFetcher(p, githubOauthToken, projectRef)
 
 	// if the patched config exists, use that as the project file bytes.
 	if p.PatchedConfig != "" {
@@ -54,7 +57,7 @@ func GetPatchedProject(p *patch.Patch, githubOauthToken string) (*model.Project,
 
 	// apply remote configuration patch if needed
 	if p.ConfigChanged(projectRef.RemotePath) && p.PatchedConfig == "" {
-		project, err = model.MakePatchedConfig(p, projectRef.RemotePath, string(projectFileBytes))
+		project, err = model.MakePatchedConfig(p, projectRef.RemotePath, string(projectFileBytes), fetch)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Could not patch remote configuration file")
 		}
@@ -74,7 +77,7 @@ func GetPatchedProject(p *patch.Patch, githubOauthToken string) (*model.Project,
 		}
 	} else {
 		// configuration is not patched
-		if err = model.LoadProjectInto(projectFileBytes, projectRef.Identifier, project); err != nil {
+		if err = model.LoadProjectWithIncludes(projectFileBytes, projectRef.Identifier, fetch, project); err != nil {
 			return nil, errors.WithStack(err)
 		}
 	}
@@ -0,0 +1,726 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Lint rules check a project for style issues that do not stop it from
+// running. Each issue names the rule that found it, and a project may turn
+// rules off with its lint_ignore list:
+//
+//	lint_ignore: ["undefined-expansion"]
+//
+// Some issues can be fixed by rewriting the project's configuration file.
+// Only fixes that do not change what the project runs are made: deprecated
+// commands, which do nothing, are removed, as are unused functions, unless
+// the project generates tasks that could call them.
+const (
+	LintUnusedFunction     = "unused-function"
+	LintDuplicateCommands  = "duplicate-commands"
+	LintUnusedTask         = "unused-task"
+	LintEmptyVariant       = "empty-variant"
+	LintUndefinedExpansion = "undefined-expansion"
+	LintDeprecatedCommand  = "deprecated-command"
+)
+
+// LintRules are the IDs of the lint rules, in the order they are checked.
+var LintRules = []string{
+	LintUnusedFunction,
+	LintDuplicateCommands,
+	LintUnusedTask,
+	LintEmptyVariant,
+	LintUndefinedExpansion,
+	LintDeprecatedCommand,
+}
+
+// builtinExpansions are the expansions that evergreen sets for every task.
+var builtinExpansions = []string{
+	"author",
+	"branch_name",
+	"build_id",
+	"build_variant",
+	"created_at",
+	"distro_id",
+	"execution",
+	"github_author",
+	"github_org",
+	"github_pr_number",
+	"github_repo",
+	"is_patch",
+	"project",
+	"revision",
+	"revision_order_id",
+	"task_id",
+	"task_name",
+	"version_id",
+	"workdir",
+}
+
+// expansionNameRegex matches the expansions in a string, capturing the
+// expansion's name and, if it has one, its default.
+var expansionNameRegex = regexp.MustCompile(`\$\{([^}|]*)(\|[^}]*)?\}`)
+
+// LintIssue is a style issue found by a lint rule.
+type LintIssue struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Fixable bool   `json:"fixable"`
+
+	// where the issue can be fixed
+	block    commandBlock
+	function string
+	command  string
+}
+
+func (li LintIssue) String() string {
+	return fmt.Sprintf("%s: %s", li.Rule, li.Message)
+}
+
+type projectLinter func(*model.Project) []LintIssue
+
+var projectLinters = map[string]projectLinter{
+	LintUnusedFunction:     lintUnusedFunctions,
+	LintDuplicateCommands:  lintDuplicateCommands,
+	LintUnusedTask:         lintUnusedTasks,
+	LintEmptyVariant:       lintEmptyVariants,
+	LintUndefinedExpansion: lintUndefinedExpansions,
+	LintDeprecatedCommand:  lintDeprecatedCommands,
+}
+
+// LintProject returns the style issues in the project found by the rules it
+// does not ignore.
+func LintProject(project *model.Project) []LintIssue {
+	issues := []LintIssue{}
+	for _, rule := range LintRules {
+		if util.StringSliceContains(project.LintIgnore, rule) {
+			continue
+		}
+		issues = append(issues, projectLinters[rule](project)...)
+	}
+	return issues
+}
+
+// commandBlock is a list of commands in the project, which is identified by
+// the section of the configuration file it is defined in, the name of the
+// function, task or task group it belongs to, and for task groups, the
+// group's block.
+type commandBlock struct {
+	section  string
+	name     string
+	block    string
+	commands []model.PluginCommandConf
+}
+
+func (b commandBlock) String() string {
+	switch b.section {
+	case "functions":
+		return fmt.Sprintf("function '%s'", b.name)
+	case "tasks":
+		return fmt.Sprintf("task '%s'", b.name)
+	case "task_groups":
+		return fmt.Sprintf("task group '%s' %s", b.name, b.block)
+	}
+	return b.section
+}
+
+// projectCommandBlocks returns every list of commands in the project.
+func projectCommandBlocks(project *model.Project) []commandBlock {
+	var blocks []commandBlock
+	add := func(b commandBlock, set *model.YAMLCommandSet) {
+		if set != nil {
+			b.commands = set.List()
+			blocks = append(blocks, b)
+		}
+	}
+	add(commandBlock{section: "pre"}, project.Pre)
+	add(commandBlock{section: "post"}, project.Post)
+	add(commandBlock{section: "timeout"}, project.Timeout)
+
+	functionNames := make([]string, 0, len(project.Functions))
+	for name := range project.Functions {
+		functionNames = append(functionNames, name)
+	}
+	sort.Strings(functionNames)
+	for _, name := range functionNames {
+		add(commandBlock{section: "functions", name: name}, project.Functions[name])
+	}
+
+	for _, t := range project.Tasks {
+		blocks = append(blocks, commandBlock{section: "tasks", name: t.Name, commands: t.Commands})
+	}
+
+	for _, tg := range project.TaskGroups {
+		add(commandBlock{section: "task_groups", name: tg.Name, block: "setup_group"}, tg.SetupGroup)
+		add(commandBlock{section: "task_groups", name: tg.Name, block: "teardown_group"}, tg.TeardownGroup)
+		add(commandBlock{section: "task_groups", name: tg.Name, block: "setup_task"}, tg.SetupTask)
+		add(commandBlock{section: "task_groups", name: tg.Name, block: "teardown_task"}, tg.TeardownTask)
+		add(commandBlock{section: "task_groups", name: tg.Name, block: "timeout"}, tg.Timeout)
+	}
+	return blocks
+}
+
+// projectGeneratesTasks returns true if the project has a generate.tasks
+// command, whose tasks may use anything defined in the project.
+func projectGeneratesTasks(project *model.Project) bool {
+	for _, b := range projectCommandBlocks(project) {
+		for _, cmd := range b.commands {
+			if cmd.Command == "generate.tasks" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lintUnusedFunctions finds functions that no command calls.
+func lintUnusedFunctions(project *model.Project) []LintIssue {
+	called := map[string]bool{}
+	for _, b := range projectCommandBlocks(project) {
+		for _, cmd := range b.commands {
+			if cmd.Function != "" {
+				called[cmd.Function] = true
+			}
+		}
+	}
+	fixable := !projectGeneratesTasks(project)
+
+	issues := []LintIssue{}
+	for _, b := range projectCommandBlocks(project) {
+		if b.section != "functions" || called[b.name] {
+			continue
+		}
+		issues = append(issues, LintIssue{
+			Rule:     LintUnusedFunction,
+			Message:  fmt.Sprintf("function '%s' is never called", b.name),
+			Fixable:  fixable,
+			function: b.name,
+		})
+	}
+	return issues
+}
+
+// lintDuplicateCommands finds lists of commands that are repeated, which
+// could be defined once as a function.
+func lintDuplicateCommands(project *model.Project) []LintIssue {
+	var keys []string
+	duplicates := map[string][]commandBlock{}
+	for _, b := range projectCommandBlocks(project) {
+		if len(b.commands) < 2 {
+			continue
+		}
+		onlyFunctions := true
+		for _, cmd := range b.commands {
+			onlyFunctions = onlyFunctions && cmd.Function != ""
+		}
+		if onlyFunctions {
+			continue
+		}
+		out, err := yaml.Marshal(b.commands)
+		if err != nil {
+			continue
+		}
+		key := string(out)
+		if _, ok := duplicates[key]; !ok {
+			keys = append(keys, key)
+		}
+		duplicates[key] = append(duplicates[key], b)
+	}
+
+	issues := []LintIssue{}
+	for _, key := range keys {
+		blocks := duplicates[key]
+		if len(blocks) < 2 {
+			continue
+		}
+		var function string
+		var names []string
+		for _, b := range blocks {
+			if b.section == "functions" && function == "" {
+				function = b.name
+				continue
+			}
+			names = append(names, b.String())
+		}
+		verb := "have"
+		if len(names) == 1 {
+			verb = "has"
+		}
+		message := fmt.Sprintf("%s %s the same commands, which could be a function",
+			strings.Join(names, ", "), verb)
+		if function != "" {
+			message = fmt.Sprintf("%s %s the same commands as function '%s', which could be called instead",
+				strings.Join(names, ", "), verb, function)
+		}
+		issues = append(issues, LintIssue{Rule: LintDuplicateCommands, Message: message})
+	}
+	return issues
+}
+
+// lintUnusedTasks finds tasks that are not run on any variant.
+func lintUnusedTasks(project *model.Project) []LintIssue {
+	used := map[string]bool{}
+	for _, bv := range project.BuildVariants {
+		for _, t := range bv.Tasks {
+			used[t.Name] = true
+			if tg := project.FindTaskGroup(t.Name); tg != nil {
+				for _, name := range tg.Tasks {
+					used[name] = true
+				}
+			}
+		}
+	}
+
+	issues := []LintIssue{}
+	for _, t := range project.Tasks {
+		if !used[t.Name] {
+			issues = append(issues, LintIssue{
+				Rule:    LintUnusedTask,
+				Message: fmt.Sprintf("task '%s' is not in any buildvariant", t.Name),
+			})
+		}
+	}
+	return issues
+}
+
+// lintEmptyVariants finds variants that have no tasks.
+func lintEmptyVariants(project *model.Project) []LintIssue {
+	issues := []LintIssue{}
+	for _, bv := range project.BuildVariants {
+		if len(bv.Tasks) == 0 {
+			issues = append(issues, LintIssue{
+				Rule:    LintEmptyVariant,
+				Message: fmt.Sprintf("buildvariant '%s' has no tasks", bv.Name),
+			})
+		}
+	}
+	return issues
+}
+
+// lintUndefinedExpansions finds expansions that commands use without a
+// default, but that are not set by evergreen or defined anywhere in the
+// project. Project variables are defined outside of the project's
+// configuration, so projects that use them may need to ignore this rule.
+func lintUndefinedExpansions(project *model.Project) []LintIssue {
+	defined := map[string]bool{}
+	for _, name := range builtinExpansions {
+		defined[name] = true
+	}
+	for _, bv := range project.BuildVariants {
+		for name := range bv.Expansions {
+			defined[name] = true
+		}
+	}
+	for _, param := range project.Parameters {
+		defined[param.Name] = true
+	}
+	for _, module := range project.Modules {
+		defined[module.Name+"_rev"] = true
+	}
+
+	blocks := projectCommandBlocks(project)
+	for _, b := range blocks {
+		for _, cmd := range b.commands {
+			for name := range cmd.Vars {
+				defined[name] = true
+			}
+			switch cmd.Command {
+			case "expansions.update":
+				// expansions read from a file cannot be known
+				if _, ok := cmd.Params["file"]; ok {
+					return []LintIssue{}
+				}
+				updates, _ := cmd.Params["updates"].([]interface{})
+				for _, update := range updates {
+					if key, ok := paramValue(update, "key").(string); ok {
+						defined[key] = true
+					}
+				}
+			case "keyval.inc":
+				if destination, ok := cmd.Params["destination"].(string); ok {
+					defined[destination] = true
+				}
+			}
+		}
+	}
+
+	issues := []LintIssue{}
+	reported := map[string]bool{}
+	for _, b := range blocks {
+		for _, cmd := range b.commands {
+			var used []string
+			collectExpansions(cmd.Params, &used)
+			for _, value := range cmd.Vars {
+				collectExpansions(value, &used)
+			}
+			for _, name := range used {
+				if defined[name] || reported[b.String()+name] {
+					continue
+				}
+				reported[b.String()+name] = true
+				issues = append(issues, LintIssue{
+					Rule:    LintUndefinedExpansion,
+					Message: fmt.Sprintf("%s uses expansion '%s', which is not defined", b, name),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// collectExpansions adds the names of the expansions without defaults in
+// the value, which may be a string or nested maps and lists of them.
+func collectExpansions(value interface{}, names *[]string) {
+	switch v := value.(type) {
+	case string:
+		for _, match := range expansionNameRegex.FindAllStringSubmatch(v, -1) {
+			if match[2] == "" {
+				*names = append(*names, match[1])
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectExpansions(item, names)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			collectExpansions(item, names)
+		}
+	case map[interface{}]interface{}:
+		for _, item := range v {
+			collectExpansions(item, names)
+		}
+	}
+}
+
+// paramValue returns the value of a key in a map parsed from YAML.
+func paramValue(m interface{}, key string) interface{} {
+	switch v := m.(type) {
+	case map[string]interface{}:
+		return v[key]
+	case map[interface{}]interface{}:
+		return v[key]
+	}
+	return nil
+}
+
+// lintDeprecatedCommands finds commands that are deprecated.
+func lintDeprecatedCommands(project *model.Project) []LintIssue {
+	deprecated := command.DeprecatedCommandNames()
+	issues := []LintIssue{}
+	for _, b := range projectCommandBlocks(project) {
+		reported := map[string]bool{}
+		for _, cmd := range b.commands {
+			if !util.StringSliceContains(deprecated, cmd.Command) || reported[cmd.Command] {
+				continue
+			}
+			reported[cmd.Command] = true
+			issues = append(issues, LintIssue{
+				Rule:    LintDeprecatedCommand,
+				Message: fmt.Sprintf("%s uses command '%s', which is deprecated and does nothing", b, cmd.Command),
+				Fixable: true,
+				block:   b,
+				command: cmd.Command,
+			})
+		}
+	}
+	return issues
+}
+
+// FixLintIssues fixes the fixable issues in the project configuration file,
+// returning the new file and the issues that were fixed. Only the lines of
+// unused functions and deprecated commands are removed, so the rest of the
+// file, including its comments and anchors, is kept as it is. Issues in files
+// that the project includes, or in parts of the file written in flow style or
+// with aliases, are not fixed.
+func FixLintIssues(data []byte, issues []LintIssue) ([]byte, []LintIssue, error) {
+	if err := yaml.Unmarshal(data, &yaml.MapSlice{}); err != nil {
+		return nil, nil, errors.Wrap(err, "error parsing project configuration")
+	}
+
+	lines := strings.Split(string(data), "\n")
+	fixed := []LintIssue{}
+	for _, issue := range issues {
+		if !issue.Fixable {
+			continue
+		}
+		var ok bool
+		switch issue.Rule {
+		case LintUnusedFunction:
+			lines, ok = removeFunction(lines, issue.function)
+		case LintDeprecatedCommand:
+			lines, ok = removeCommand(lines, issue.block, issue.command)
+		}
+		if ok {
+			fixed = append(fixed, issue)
+		}
+	}
+	if len(fixed) == 0 {
+		return data, fixed, nil
+	}
+
+	out := []byte(strings.Join(lines, "\n"))
+	if err := yaml.Unmarshal(out, &yaml.MapSlice{}); err != nil {
+		return nil, nil, errors.Wrap(err, "error parsing fixed project configuration")
+	}
+	return out, fixed, nil
+}
+
+// The fixes edit the lines of the file's block style YAML directly. A block
+// is a mapping entry or sequence item along with the more indented lines
+// under it.
+
+// yamlIndent returns the indentation of the line, or -1 if it is blank or
+// only a comment.
+func yamlIndent(line string) int {
+	trimmed := strings.TrimLeft(line, " ")
+	if strings.TrimSpace(trimmed) == "" || strings.HasPrefix(trimmed, "#") {
+		return -1
+	}
+	return len(line) - len(trimmed)
+}
+
+// yamlItem returns whether the line is a sequence item, along with the
+// indentation of the item's content.
+func yamlItem(line string) (bool, int) {
+	n := yamlIndent(line)
+	if n < 0 {
+		return false, -1
+	}
+	rest := strings.TrimRight(line[n:], "\r")
+	if rest != "-" && !strings.HasPrefix(rest, "- ") {
+		return false, -1
+	}
+	return true, len(line) - len(strings.TrimLeft(line[n+1:], " "))
+}
+
+// yamlKey returns the key of the mapping entry on the line and its value on
+// the same line, which is empty if the value is a block under it.
+func yamlKey(line string) (string, string, bool) {
+	content := strings.TrimSpace(line)
+	if isItem, indent := yamlItem(line); isItem {
+		content = strings.TrimSpace(line[indent:])
+	}
+	i := strings.Index(content, ":")
+	if i <= 0 || (i+1 < len(content) && content[i+1] != ' ') {
+		return "", "", false
+	}
+	value := strings.TrimSpace(content[i+1:])
+	if strings.HasPrefix(value, "#") {
+		value = ""
+	} else if j := strings.Index(value, " #"); j >= 0 {
+		value = strings.TrimSpace(value[:j])
+	}
+	return strings.Trim(content[:i], `"'`), strings.Trim(value, `"'`), true
+}
+
+// yamlBlockEnd returns the index after the last line of the block starting
+// at the line, whose key or item is at the given indent. A key's sequence may
+// be at the key's own indent. Blank lines and comments at the end of the
+// block are left out of it.
+func yamlBlockEnd(lines []string, start, indent int, isKey bool) int {
+	end := start + 1
+	for i := start + 1; i < len(lines); i++ {
+		n := yamlIndent(lines[i])
+		if n < 0 {
+			continue
+		}
+		if isItem, _ := yamlItem(lines[i]); n < indent || (n == indent && !(isKey && isItem)) {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+// yamlBlockStart returns the first line of the comments directly above the
+// block starting at the line, whose key or item is at the given indent, which
+// are taken to describe it.
+func yamlBlockStart(lines []string, start, indent int) int {
+	for start > 0 {
+		prev := lines[start-1]
+		if !strings.HasPrefix(strings.TrimLeft(prev, " "), "#") || len(prev)-len(strings.TrimLeft(prev, " ")) != indent {
+			break
+		}
+		start--
+	}
+	return start
+}
+
+// yamlMappingKey returns the line of the key in the mapping that starts at
+// the first line in the range holding content, along with the key's indent,
+// or -1 if the mapping does not have the key.
+func yamlMappingKey(lines []string, start, end int, key string) (int, int) {
+	indent := -1
+	for i := start; i < end; i++ {
+		n := yamlIndent(lines[i])
+		if n < 0 {
+			continue
+		}
+		isItem, itemIndent := yamlItem(lines[i])
+		if indent < 0 {
+			if isItem && strings.TrimSpace(lines[i][itemIndent:]) == "" {
+				// the item's mapping starts on the next line
+				continue
+			}
+			indent = n
+			if isItem {
+				// the mapping is a sequence item's, starting on its line
+				indent = itemIndent
+			}
+		} else if n != indent || isItem {
+			continue
+		}
+		if k, _, ok := yamlKey(lines[i]); ok && k == key {
+			return i, indent
+		}
+	}
+	return -1, -1
+}
+
+// yamlMappingValue returns the value on the line of the key in the mapping,
+// if the mapping has the key.
+func yamlMappingValue(lines []string, start, end int, key string) string {
+	i, _ := yamlMappingKey(lines, start, end, key)
+	if i < 0 {
+		return ""
+	}
+	_, value, _ := yamlKey(lines[i])
+	return value
+}
+
+// yamlItems returns the lines that start the items of the sequence in the
+// range, or nil if the range does not hold a sequence.
+func yamlItems(lines []string, start, end int) []int {
+	items := []int{}
+	indent := -1
+	for i := start; i < end; i++ {
+		n := yamlIndent(lines[i])
+		if n < 0 {
+			continue
+		}
+		if indent < 0 {
+			indent = n
+		}
+		if isItem, _ := yamlItem(lines[i]); n == indent {
+			if !isItem {
+				return nil
+			}
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+// yamlIsAnchor returns whether the value is only an anchor for the block
+// under it.
+func yamlIsAnchor(value string) bool {
+	return strings.HasPrefix(value, "&") && !strings.ContainsAny(value, " \t")
+}
+
+// removeLines returns the lines without those in the range.
+func removeLines(lines []string, start, end int) []string {
+	out := make([]string, 0, len(lines)-(end-start))
+	out = append(out, lines[:start]...)
+	return append(out, lines[end:]...)
+}
+
+// emptyBlockValue sets the value of the key on the line to an empty
+// sequence, after its anchor if it has one.
+func emptyBlockValue(line string) string {
+	i := strings.Index(line, ":")
+	if _, value, _ := yamlKey(line); value != "" {
+		j := i + 1 + strings.Index(line[i+1:], value) + len(value)
+		return line[:j] + " []" + line[j:]
+	}
+	return line[:i+1] + " []" + line[i+1:]
+}
+
+func removeFunction(lines []string, name string) ([]string, bool) {
+	i, indent := yamlMappingKey(lines, 0, len(lines), "functions")
+	if i < 0 {
+		return lines, false
+	}
+	end := yamlBlockEnd(lines, i, indent, true)
+	j, fnIndent := yamlMappingKey(lines, i+1, end, name)
+	if j < 0 || fnIndent <= indent {
+		return lines, false
+	}
+	return removeLines(lines, yamlBlockStart(lines, j, fnIndent), yamlBlockEnd(lines, j, fnIndent, true)), true
+}
+
+// removeCommand removes the command from the block's commands in the file.
+func removeCommand(lines []string, b commandBlock, name string) ([]string, bool) {
+	i, indent := yamlMappingKey(lines, 0, len(lines), b.section)
+	if i < 0 {
+		return lines, false
+	}
+	sectionEnd := yamlBlockEnd(lines, i, indent, true)
+
+	// find the mapping that holds the block's commands, and their key in it
+	start, end, key := 0, len(lines), b.section
+	switch b.section {
+	case "functions":
+		start, end, key = i+1, sectionEnd, b.name
+	case "tasks", "task_groups":
+		start = -1
+		for _, item := range yamlItems(lines, i+1, sectionEnd) {
+			itemEnd := yamlBlockEnd(lines, item, yamlIndent(lines[item]), false)
+			if yamlMappingValue(lines, item, itemEnd, "name") == b.name {
+				start, end = item, itemEnd
+				break
+			}
+		}
+		key = "commands"
+		if b.section == "task_groups" {
+			key = b.block
+		}
+	}
+	if start < 0 {
+		return lines, false
+	}
+	j, keyIndent := yamlMappingKey(lines, start, end, key)
+	if j < 0 {
+		return lines, false
+	}
+	// the commands may be anchored, but not an alias or in flow style
+	if _, value, _ := yamlKey(lines[j]); value != "" && !yamlIsAnchor(value) {
+		return lines, false
+	}
+	valueEnd := yamlBlockEnd(lines, j, keyIndent, true)
+
+	items := yamlItems(lines, j+1, valueEnd)
+	if items == nil {
+		// a single command
+		if yamlMappingValue(lines, j+1, valueEnd, "command") != name {
+			return lines, false
+		}
+		lines = removeLines(lines, j+1, valueEnd)
+		lines[j] = emptyBlockValue(lines[j])
+		return lines, true
+	}
+
+	removed := 0
+	for k := len(items) - 1; k >= 0; k-- {
+		itemEnd := yamlBlockEnd(lines, items[k], yamlIndent(lines[items[k]]), false)
+		if yamlMappingValue(lines, items[k], itemEnd, "command") == name {
+			lines = removeLines(lines, yamlBlockStart(lines, items[k], yamlIndent(lines[items[k]])), itemEnd)
+			removed++
+		}
+	}
+	if removed == 0 {
+		return lines, false
+	}
+	if removed == len(items) {
+		lines[j] = emptyBlockValue(lines[j])
+	}
+	return lines, true
+}
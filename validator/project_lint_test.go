@@ -0,0 +1,200 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lintProject = `
+functions:
+  fetch:
+    - command: git.get_project
+      params:
+        directory: src
+    - command: git.apply_patch
+  unused:
+    command: shell.exec
+    params:
+      script: echo unused
+  compile_steps:
+    - command: shell.exec
+      params:
+        script: make ${target}
+    - command: shell.exec
+      params:
+        script: make install
+tasks:
+- name: compile
+  commands:
+  - func: fetch
+  - func: compile_steps
+- name: compile_again
+  commands:
+  - command: shell.exec
+    params:
+      script: make ${target}
+  - command: shell.exec
+    params:
+      script: make install
+- name: test
+  commands:
+  - command: shell.track
+  - func: fetch
+    vars:
+      suite: core
+  - command: shell.exec
+    params:
+      script: ./test ${suite} ${jobs|4} ${workdir} ${undefined}
+- name: orphan
+  commands:
+  - command: shell.exec
+buildvariants:
+- name: linux
+  expansions:
+    target: all
+  tasks:
+  - name: compile
+  - name: compile_again
+  - name: test
+- name: empty
+`
+
+const lintProjectWithComments = `# the project's functions
+functions:
+  # fetches the source
+  fetch: &fetch_commands
+    - command: git.get_project  # into src
+      params: &src
+        directory: src
+    - command: git.apply_patch
+
+  # never called
+  unused:
+    command: shell.exec
+    params:
+      script: echo unused
+  fetch_again: *fetch_commands
+tasks:
+- name: compile
+  commands:
+  - command: shell.track
+- name: test
+  commands:
+  - func: fetch
+  - func: fetch_again
+  - command: shell.exec
+    params: *src
+buildvariants:
+- name: linux
+  tasks: ["compile", "test"]
+`
+
+func lintMessages(issues []LintIssue, rule string) []string {
+	messages := []string{}
+	for _, issue := range issues {
+		if issue.Rule == rule {
+			messages = append(messages, issue.Message)
+		}
+	}
+	return messages
+}
+
+func TestLintProject(t *testing.T) {
+	assert := assert.New(t)
+	project := &model.Project{}
+	require.NoError(t, model.LoadProjectInto([]byte(lintProject), "", project))
+
+	issues := LintProject(project)
+	assert.Equal([]string{"function 'unused' is never called"}, lintMessages(issues, LintUnusedFunction))
+	assert.Equal([]string{"task 'compile_again' has the same commands as function 'compile_steps', which could be called instead"},
+		lintMessages(issues, LintDuplicateCommands))
+	assert.Equal([]string{"task 'orphan' is not in any buildvariant"}, lintMessages(issues, LintUnusedTask))
+	assert.Equal([]string{"buildvariant 'empty' has no tasks"}, lintMessages(issues, LintEmptyVariant))
+	assert.Equal([]string{"task 'test' uses expansion 'undefined', which is not defined"},
+		lintMessages(issues, LintUndefinedExpansion))
+	assert.Equal([]string{
+		"function 'fetch' uses command 'git.apply_patch', which is deprecated and does nothing",
+		"task 'test' uses command 'shell.track', which is deprecated and does nothing",
+	}, lintMessages(issues, LintDeprecatedCommand))
+	for _, issue := range issues {
+		assert.Equal(issue.Rule == LintUnusedFunction || issue.Rule == LintDeprecatedCommand, issue.Fixable, issue.String())
+	}
+
+	project.LintIgnore = []string{LintUndefinedExpansion, LintEmptyVariant}
+	issues = LintProject(project)
+	assert.Empty(lintMessages(issues, LintUndefinedExpansion))
+	assert.Empty(lintMessages(issues, LintEmptyVariant))
+	assert.NotEmpty(lintMessages(issues, LintUnusedTask))
+}
+
+func TestFixLintIssues(t *testing.T) {
+	assert := assert.New(t)
+	project := &model.Project{}
+	require.NoError(t, model.LoadProjectInto([]byte(lintProject), "", project))
+
+	fixedFile, fixed, err := FixLintIssues([]byte(lintProject), LintProject(project))
+	require.NoError(t, err)
+	assert.Len(fixed, 3)
+
+	fixedProject := &model.Project{}
+	require.NoError(t, model.LoadProjectInto(fixedFile, "", fixedProject))
+	issues := LintProject(fixedProject)
+	assert.Empty(lintMessages(issues, LintUnusedFunction))
+	assert.Empty(lintMessages(issues, LintDeprecatedCommand))
+	assert.NotEmpty(lintMessages(issues, LintUnusedTask))
+	assert.Len(fixedProject.Functions["fetch"].List(), 1)
+	assert.Len(fixedProject.FindProjectTask("test").Commands, 2)
+	assert.Len(fixedProject.BuildVariants, 2)
+
+	// nothing left to fix leaves the file as it is
+	out, fixed, err := FixLintIssues(fixedFile, issues)
+	require.NoError(t, err)
+	assert.Empty(fixed)
+	assert.Equal(fixedFile, out)
+
+	// only the fixed lines change, so comments and anchors are kept, and
+	// commands in aliases are left alone
+	project = &model.Project{}
+	require.NoError(t, model.LoadProjectInto([]byte(lintProjectWithComments), "", project))
+	fixedFile, fixed, err = FixLintIssues([]byte(lintProjectWithComments), LintProject(project))
+	require.NoError(t, err)
+	assert.Len(fixed, 3)
+	assert.Equal(`# the project's functions
+functions:
+  # fetches the source
+  fetch: &fetch_commands
+    - command: git.get_project  # into src
+      params: &src
+        directory: src
+
+  fetch_again: *fetch_commands
+tasks:
+- name: compile
+  commands: []
+- name: test
+  commands:
+  - func: fetch
+  - func: fetch_again
+  - command: shell.exec
+    params: *src
+buildvariants:
+- name: linux
+  tasks: ["compile", "test"]
+`, string(fixedFile))
+	require.NoError(t, model.LoadProjectInto(fixedFile, "", &model.Project{}))
+
+	// emptied blocks keep their anchors
+	lines, ok := removeCommand([]string{"pre: &setup  # runs first", "  - command: shell.track", ""},
+		commandBlock{section: "pre"}, "shell.track")
+	assert.True(ok)
+	assert.Equal([]string{"pre: &setup []  # runs first", ""}, lines)
+
+	// functions are kept if generated tasks could call them
+	project.Tasks[0].Commands = append(project.Tasks[0].Commands, model.PluginCommandConf{Command: "generate.tasks"})
+	for _, issue := range lintUnusedFunctions(project) {
+		assert.False(issue.Fixable)
+	}
+}
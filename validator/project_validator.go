@@ -54,6 +54,8 @@ var projectSyntaxValidators = []projectValidator{
 	validateProjectTaskNames,
 	validateProjectTaskIdsAndTags,
 	validateTaskGroups,
+	validateParameters,
+	validateAutoRetry,
 }
 
 // Functions used to validate the semantics of a project configuration file.
@@ -62,6 +64,7 @@ var projectSyntaxValidators = []projectValidator{
 var projectSemanticValidators = []projectValidator{
 	checkTaskCommands,
 	checkTaskGroups,
+	checkParameterExpansions,
 }
 
 func (vr ValidationError) Error() string {
@@ -195,6 +198,10 @@ func dependencyCycleExists(node model.TVPair, visited map[model.TVPair]bool,
 	depNodes := []model.TVPair{}
 	// build a list of all possible dependency nodes for the task
 	for _, dep := range task.DependsOn {
+		// tasks in other projects cannot depend on this project's tasks
+		if dep.IsCrossProject() {
+			continue
+		}
 		if dep.Variant != model.AllVariants {
 			// handle regular dependencies
 			dn := model.TVPair{TaskName: dep.Name}
@@ -628,6 +635,15 @@ func validateProjectTaskIdsAndTags(project *model.Project) []ValidationError {
 	return errs
 }
 
+// validDependencyStatus returns true if a dependency may require the status.
+func validDependencyStatus(status string) bool {
+	switch status {
+	case evergreen.TaskSucceeded, evergreen.TaskFailed, model.AllStatuses, "":
+		return true
+	}
+	return false
+}
+
 // Makes sure that the dependencies for the tasks have the correct fields,
 // and that the fields reference valid tasks.
 func verifyTaskRequirements(project *model.Project) []ValidationError {
@@ -668,6 +684,18 @@ func verifyTaskDependencies(project *model.Project) []ValidationError {
 		depNames := map[model.TVPair]bool{}
 
 		for _, dep := range task.DependsOn {
+			if dep.IsCrossProject() {
+				// the other project's tasks are not known here, so
+				// only the status is checked
+				if !validDependencyStatus(dep.Status) {
+					errs = append(errs,
+						ValidationError{
+							Message: fmt.Sprintf("project '%v' contains an invalid dependency status for task '%v': %v",
+								project.Identifier, task.Name, dep.Status)})
+				}
+				continue
+			}
+
 			// make sure the dependency is not specified more than once
 			if depNames[model.TVPair{dep.Name, dep.Variant}] {
 				errs = append(errs,
@@ -681,10 +709,7 @@ func verifyTaskDependencies(project *model.Project) []ValidationError {
 			depNames[model.TVPair{dep.Name, dep.Variant}] = true
 
 			// check that the status is valid
-			switch dep.Status {
-			case evergreen.TaskSucceeded, evergreen.TaskFailed, model.AllStatuses, "":
-				// these are all valid
-			default:
+			if !validDependencyStatus(dep.Status) {
 				errs = append(errs,
 					ValidationError{
 						Message: fmt.Sprintf("project '%v' contains an invalid dependency status for task '%v': %v",
@@ -768,3 +793,120 @@ func checkTaskGroups(p *model.Project) []ValidationError {
 	}
 	return errs
 }
+
+// validateParameters checks that parameters have unique names that can be
+// used as expansions, valid types, and defaults, if any, of their type.
+func validateParameters(p *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	names := map[string]bool{}
+	for _, param := range p.Parameters {
+		if !model.ValidParameterName(param.Name) {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("parameter name '%s' must be a letter or underscore followed by letters, digits or underscores", param.Name),
+				Level:   Error,
+			})
+		}
+		if names[param.Name] {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("parameter '%s' is declared more than once", param.Name),
+				Level:   Error,
+			})
+		}
+		names[param.Name] = true
+
+		if !util.StringSliceContains(model.ValidParameterTypes, param.GetType()) {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("parameter '%s' has invalid type '%s', must be one of: %s",
+					param.Name, param.Type, strings.Join(model.ValidParameterTypes, ", ")),
+				Level: Error,
+			})
+			continue
+		}
+		if param.Default == "" {
+			continue
+		}
+		if err := param.ValidateValue(param.Default); err != nil {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("default of parameter '%s' is invalid: %s", param.Name, err.Error()),
+				Level:   Error,
+			})
+		}
+	}
+	return errs
+}
+
+// checkParameterExpansions warns about build variant expansions with the same
+// name as a parameter, since the parameter's value takes precedence.
+func checkParameterExpansions(p *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	for _, bv := range p.BuildVariants {
+		for _, param := range p.Parameters {
+			if _, ok := bv.Expansions[param.Name]; ok {
+				errs = append(errs, ValidationError{
+					Message: fmt.Sprintf("expansion '%s' in buildvariant '%s' is overridden by the parameter of the same name",
+						param.Name, bv.Name),
+					Level: Warning,
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// validateAutoRetry checks the auto_retry policies of tasks and build
+// variants.
+func validateAutoRetry(p *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	for _, t := range p.Tasks {
+		errs = append(errs, validateAutoRetryPolicy(fmt.Sprintf("task '%s'", t.Name), t.AutoRetry)...)
+	}
+	for _, bv := range p.BuildVariants {
+		errs = append(errs, validateAutoRetryPolicy(fmt.Sprintf("buildvariant '%s'", bv.Name), bv.AutoRetry)...)
+		for _, bvt := range bv.Tasks {
+			errs = append(errs, validateAutoRetryPolicy(
+				fmt.Sprintf("task '%s' in buildvariant '%s'", bvt.Name, bv.Name), bvt.AutoRetry)...)
+		}
+	}
+	return errs
+}
+
+// validateAutoRetryPolicy checks that a policy runs a task a number of times
+// that Evergreen allows, and that it retries kinds of failures and
+// requesters that exist.
+func validateAutoRetryPolicy(owner string, policy *model.AutoRetry) []ValidationError {
+	errs := []ValidationError{}
+	if policy == nil {
+		return errs
+	}
+	maxAttempts := evergreen.MaxTaskExecution + 1
+	if policy.MaxAttempts < 1 || policy.MaxAttempts > maxAttempts {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("auto_retry of %s must have max_attempts between 1 and %d", owner, maxAttempts),
+			Level:   Error,
+		})
+	}
+	for _, failure := range policy.On {
+		if !util.StringSliceContains(model.AutoRetryFailureTypes, failure) {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("auto_retry of %s cannot retry on '%s', must be one of: %s",
+					owner, failure, strings.Join(model.AutoRetryFailureTypes, ", ")),
+				Level: Error,
+			})
+		}
+	}
+	requesters := []string{
+		evergreen.RepotrackerVersionRequester,
+		evergreen.PatchVersionRequester,
+		evergreen.GithubPRRequester,
+	}
+	for _, requester := range policy.OnlyRequesters {
+		if !util.StringSliceContains(requesters, requester) {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("auto_retry of %s has invalid requester '%s', must be one of: %s",
+					owner, requester, strings.Join(requesters, ", ")),
+				Level: Error,
+			})
+		}
+	}
+	return errs
+}
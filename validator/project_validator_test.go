@@ -119,6 +119,32 @@ func TestVerifyTaskDependencies(t *testing.T) {
 			}
 			So(verifyTaskDependencies(project), ShouldResemble, []ValidationError{})
 		})
+
+		Convey("dependencies on tasks in other projects should only have their status checked", func() {
+			project := &model.Project{
+				Tasks: []model.ProjectTask{
+					{
+						Name: "testOne",
+						DependsOn: []model.TaskUnitDependency{
+							{Name: "package", Variant: "linux", Project: "other"},
+							{Name: "package", Variant: "linux", Project: "another"},
+						},
+					},
+					{
+						Name:      "testTwo",
+						DependsOn: []model.TaskUnitDependency{{Name: "package", Variant: "linux", Project: "other", Status: "flibbertyjibbit"}},
+					},
+				},
+				BuildVariants: []model.BuildVariant{
+					{
+						Name:  "linux",
+						Tasks: []model.BuildVariantTaskUnit{{Name: "testOne"}, {Name: "testTwo"}},
+					},
+				},
+			}
+			So(len(verifyTaskDependencies(project)), ShouldEqual, 1)
+			So(checkDependencyGraph(project), ShouldResemble, []ValidationError{})
+		})
 	})
 }
 
@@ -1551,3 +1577,77 @@ buildvariants:
 	assert.Contains(validationErrs[0].Message, "task group example_task_group has max number of hosts greater than half the number of tasks")
 	assert.Equal(validationErrs[0].Level, Warning)
 }
+
+func TestValidateParameters(t *testing.T) {
+	assert := assert.New(t)
+	yml := `
+parameters:
+- name: suite
+  default: core
+- name: jobs
+  type: int
+  default: 4
+- name: fast
+  type: bool
+- name: jobs
+  type: int
+- name: bad-name
+- name: ratio
+  type: float
+  default: half
+- name: mode
+  type: enum
+tasks:
+- name: compile
+buildvariants:
+- name: bv
+  expansions:
+    suite: sharded
+  tasks:
+  - name: compile
+`
+	proj := model.Project{}
+	assert.NoError(model.LoadProjectInto([]byte(yml), "", &proj))
+
+	validationErrs := validateParameters(&proj)
+	assert.Len(validationErrs, 4)
+	assert.Contains(validationErrs[0].Message, "parameter 'jobs' is declared more than once")
+	assert.Contains(validationErrs[1].Message, "parameter name 'bad-name'")
+	assert.Contains(validationErrs[2].Message, "default of parameter 'ratio' is invalid")
+	assert.Contains(validationErrs[3].Message, "parameter 'mode' has invalid type 'enum'")
+
+	validationErrs = checkParameterExpansions(&proj)
+	assert.Len(validationErrs, 1)
+	assert.Equal(Warning, validationErrs[0].Level)
+	assert.Contains(validationErrs[0].Message, "expansion 'suite' in buildvariant 'bv'")
+}
+
+func TestValidateAutoRetry(t *testing.T) {
+	assert := assert.New(t)
+	yml := `
+tasks:
+- name: compile
+  auto_retry:
+    max_attempts: 2
+    on: [system-failed, flaky]
+- name: test
+buildvariants:
+- name: bv
+  auto_retry:
+    max_attempts: 3
+    only_requesters: [patch_request, nightly]
+  tasks:
+  - name: compile
+  - name: test
+    auto_retry:
+      max_attempts: 10
+`
+	proj := model.Project{}
+	assert.NoError(model.LoadProjectInto([]byte(yml), "", &proj))
+
+	validationErrs := validateAutoRetry(&proj)
+	assert.Len(validationErrs, 3)
+	assert.Contains(validationErrs[0].Message, "auto_retry of task 'compile' cannot retry on 'flaky'")
+	assert.Contains(validationErrs[1].Message, "auto_retry of buildvariant 'bv' has invalid requester 'nightly'")
+	assert.Contains(validationErrs[2].Message, "auto_retry of task 'test' in buildvariant 'bv' must have max_attempts between 1 and 4")
+}